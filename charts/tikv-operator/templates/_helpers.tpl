@@ -61,3 +61,69 @@ Create the name of the service account to use
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+The comma separated namespaces of the TikvClusters managed by the operator, the namespace
+of the release is managed if the operator is not cluster scoped and no namespace is set
+*/}}
+{{- define "tikv-operator.watchNamespaces" -}}
+{{- if .Values.watchNamespaces }}
+{{- join "," .Values.watchNamespaces }}
+{{- else if not .Values.clusterScoped }}
+{{- .Release.Namespace }}
+{{- end }}
+{{- end }}
+
+{{/*
+The RBAC rules of the namespaced resources managed by the operator
+*/}}
+{{- define "tikv-operator.namespacedRules" -}}
+- apiGroups:
+  - tikv.org
+  resources:
+  - '*'
+  verbs:
+  - '*'
+- apiGroups:
+  - 'apps'
+  resources:
+  - 'statefulsets'
+  - 'deployments'
+  verbs:
+  - '*'
+- apiGroups:
+  - ''
+  resources:
+  - 'events'
+  - 'pods'
+  - 'persistentvolumeclaims'
+  - 'services'
+  - 'endpoints'
+  - 'configmaps'
+  - 'secrets'
+  - 'serviceaccounts'
+  verbs:
+  - '*'
+- apiGroups:
+  - 'rbac.authorization.k8s.io'
+  resources:
+  - 'roles'
+  - 'rolebindings'
+  verbs:
+  - '*'
+- apiGroups:
+  - 'policy'
+  resources:
+  - 'poddisruptionbudgets'
+  verbs:
+  - '*'
+- apiGroups:
+  - 'snapshot.storage.k8s.io'
+  resources:
+  - 'volumesnapshots'
+  verbs:
+  - 'get'
+  - 'list'
+  - 'create'
+  - 'update'
+{{- end }}
@@ -0,0 +1,187 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var htmlEscaper = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
+// apiType is a struct type of the API package
+type apiType struct {
+	name   string
+	doc    string
+	fields []*ast.Field
+}
+
+// generateDocs renders the API reference of the types in the Go package in dir
+// which are reachable from the root type, in the order they are reached
+func generateDocs(dir, groupVersion, root string) ([]byte, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && !strings.HasPrefix(info.Name(), "zz_generated")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]*apiType{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					doc := ts.Doc
+					if doc == nil {
+						doc = gen.Doc
+					}
+					types[ts.Name.Name] = &apiType{name: ts.Name.Name, doc: description(doc), fields: st.Fields.List}
+				}
+			}
+		}
+	}
+	if _, ok := types[root]; !ok {
+		return nil, fmt.Errorf("type %s is not found in %s", root, dir)
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# API Reference\n\n")
+	fmt.Fprintf(buf, "This file is generated from the Go types by hack/update-crd-groups.sh, do not edit it.\n\n")
+	fmt.Fprintf(buf, "## %s\n\n", groupVersion)
+
+	visited := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		t := types[queue[0]]
+		queue = queue[1:]
+
+		fmt.Fprintf(buf, "### %s\n\n", t.name)
+		if t.doc != "" {
+			fmt.Fprintf(buf, "%s\n\n", t.doc)
+		}
+		if len(t.fields) == 0 {
+			continue
+		}
+		fmt.Fprintf(buf, "| Field | Type | Description |\n")
+		fmt.Fprintf(buf, "| ----- | ---- | ----------- |\n")
+		for _, field := range t.fields {
+			name, inline := fieldName(field)
+			if name == "-" || (len(field.Names) > 0 && !field.Names[0].IsExported()) {
+				continue
+			}
+			desc := description(field.Doc)
+			if inline {
+				name = exprString(field.Type)
+				desc = strings.TrimSpace(fmt.Sprintf("(Members of `%s` are embedded into this type.) %s", exprString(field.Type), desc))
+			}
+			var refs []string
+			typeName := renderType(field.Type, types, &refs)
+			for _, ref := range refs {
+				if !visited[ref] {
+					visited[ref] = true
+					queue = append(queue, ref)
+				}
+			}
+			fmt.Fprintf(buf, "| `%s` | %s | %s |\n", name, typeName, strings.Replace(desc, "|", "\\|", -1))
+		}
+		fmt.Fprintf(buf, "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// renderType renders the type expression, the types of the API package are
+// linked to their sections and appended to refs
+func renderType(expr ast.Expr, types map[string]*apiType, refs *[]string) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, ok := types[e.Name]; ok {
+			*refs = append(*refs, e.Name)
+			return fmt.Sprintf("[%s](#%s)", e.Name, strings.ToLower(e.Name))
+		}
+		return fmt.Sprintf("`%s`", e.Name)
+	case *ast.StarExpr:
+		return "*" + renderType(e.X, types, refs)
+	case *ast.ArrayType:
+		return "[]" + renderType(e.Elt, types, refs)
+	case *ast.MapType:
+		return fmt.Sprintf("map[%s]%s", renderType(e.Key, types, refs), renderType(e.Value, types, refs))
+	}
+	return fmt.Sprintf("`%s`", exprString(expr))
+}
+
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(e.Elt)
+	case *ast.MapType:
+		return fmt.Sprintf("map[%s]%s", exprString(e.Key), exprString(e.Value))
+	case *ast.InterfaceType:
+		return "interface{}"
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+// fieldName returns the name of the field in the JSON encoding, and whether the
+// field is inlined into the parent
+func fieldName(field *ast.Field) (string, bool) {
+	tag := ""
+	if field.Tag != nil {
+		unquoted, _ := strconv.Unquote(field.Tag.Value)
+		tag = strings.Split(reflect.StructTag(unquoted).Get("json"), ",")[0]
+	}
+	if tag != "" {
+		return tag, false
+	}
+	if len(field.Names) == 0 {
+		return "", true
+	}
+	return field.Names[0].Name, false
+}
+
+// description joins the lines of the comment without the markers
+func description(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "+") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return htmlEscaper.Replace(strings.Join(lines, " "))
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// crd-gen generates the CRD manifests and the API reference of the tikv-operator, run by
+// hack/update-crd-groups.sh
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/crd"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	outputDir = flag.String("output-dir", "manifests", "The directory to write the CRD manifests to")
+	docsFile  = flag.String("docs-file", "docs/api-references/docs.md", "The file to write the API reference to")
+	apiDir    = flag.String("api-dir", "pkg/apis/tikv/v1beta1", "The directory of the Go types of the API reference")
+)
+
+func main() {
+	flag.Parse()
+
+	crdV1beta1, err := crd.TikvClusterCRDV1beta1(crd.DefaultConversionWebhook)
+	if err != nil {
+		klog.Fatal(err)
+	}
+	manifests := []struct {
+		filename string
+		header   string
+		obj      interface{}
+	}{
+		{"crd.yaml", "# For Kubernetes 1.16 and later.\n", crd.TikvClusterCRD(crd.DefaultConversionWebhook)},
+		{"crd.v1beta1.yaml", "# For Kubernetes 1.15, the conversion webhook and the pruning of this CRD are not\n# supported before 1.15.\n", crdV1beta1},
+	}
+	for _, m := range manifests {
+		data, err := marshal(m.obj)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		filename := filepath.Join(*outputDir, m.filename)
+		if err := ioutil.WriteFile(filename, append([]byte(m.header), data...), 0644); err != nil {
+			klog.Fatal(err)
+		}
+	}
+
+	docs, err := generateDocs(*apiDir, v1beta1.SchemeGroupVersion.String(), "TikvCluster")
+	if err != nil {
+		klog.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(*docsFile), 0755); err != nil {
+		klog.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*docsFile, docs, 0644); err != nil {
+		klog.Fatal(err)
+	}
+}
+
+// marshal encodes obj to YAML without the status and the empty creationTimestamp
+func marshal(obj interface{}) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "status")
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	return yaml.Marshal(fields)
+}
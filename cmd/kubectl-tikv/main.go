@@ -0,0 +1,27 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+
+	"github.com/tikv/tikv-operator/pkg/tkctl"
+)
+
+func main() {
+	command := tkctl.NewTkctlCommand(os.Stdout)
+	if err := command.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -17,27 +17,34 @@ import (
 	"flag"
 	"net/http"
 	_ "net/http/pprof"
+	"os"
 	"time"
 
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
 	"github.com/tikv/tikv-operator/pkg/discovery/server"
+	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/verflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/component-base/logs"
 	"k8s.io/component-base/version"
 	"k8s.io/klog"
 )
 
 var (
-	printVersion bool
-	port         int
+	printVersion   bool
+	port           int
+	resyncDuration time.Duration
 )
 
 func init() {
 	flag.BoolVar(&printVersion, "version", false, "Show version and quit")
 	flag.IntVar(&port, "port", 10261, "The port that the tidb discovery's http service runs on (default 10261)")
+	flag.DurationVar(&resyncDuration, "resync-duration", 30*time.Minute, "Resync time of informer")
 	flag.Parse()
 }
 
@@ -65,8 +72,22 @@ func main() {
 		klog.Fatalf("failed to get kubernetes Clientset: %v", err)
 	}
 
+	// only PD pods in the namespace of the discovery are watched to verify the requests
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeCli, resyncDuration,
+		kubeinformers.WithNamespace(os.Getenv("MY_POD_NAMESPACE")),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = label.New().PD().String()
+		}))
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	podLister := podInformer.Lister()
+	stopCh := make(chan struct{})
+	kubeInformerFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, podInformer.Informer().HasSynced) {
+		klog.Fatal("failed to sync the pod informer")
+	}
+
 	go wait.Forever(func() {
-		server.StartServer(cli, kubeCli, port)
+		server.StartServer(cli, kubeCli, podLister, port)
 	}, 5*time.Second)
 	klog.Fatal(http.ListenAndServe(":6060", nil))
 }
@@ -16,9 +16,11 @@ package app
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -26,16 +28,26 @@ import (
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
 	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
 	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/controller/nodemaintenance"
 	"github.com/tikv/tikv-operator/pkg/controller/tikvcluster"
+	"github.com/tikv/tikv-operator/pkg/crd"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/manager/member"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
 	"github.com/tikv/tikv-operator/pkg/scheme"
 	"github.com/tikv/tikv-operator/pkg/verflag"
+	"github.com/tikv/tikv-operator/pkg/webhook"
+	webhookpod "github.com/tikv/tikv-operator/pkg/webhook/pod"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/apiserver/pkg/util/term"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
@@ -52,6 +64,17 @@ var (
 	autoFailover       bool
 	pdFailoverPeriod   time.Duration
 	tikvFailoverPeriod time.Duration
+	nodeMaintenance    bool
+	maintenanceTaint   string
+	admissionWebhook   bool
+	conversionWebhook  bool
+	installCRDs        bool
+	webhookService     string
+	watchNamespaces    []string
+	clusterSelector    string
+	leaderElectionName string
+	webhookPort        int
+	webhookCertDir     string
 	leaseDuration      = 15 * time.Second
 	renewDuration      = 5 * time.Second
 	retryPeriod        = 3 * time.Second
@@ -62,9 +85,20 @@ var (
 // TODO organize via component config/option
 func initFlags(fs *flag.FlagSet) {
 	fs.IntVar(&workers, "workers", 5, "The number of workers that are allowed to sync concurrently. Larger number = more responsive management, but more CPU (and network) load")
-	fs.BoolVar(&autoFailover, "auto-failover", true, "Auto failover")
-	fs.DurationVar(&pdFailoverPeriod, "pd-failover-period", time.Duration(5*time.Minute), "PD failover period default(5m)")
-	fs.DurationVar(&tikvFailoverPeriod, "tikv-failover-period", time.Duration(5*time.Minute), "TiKV failover period default(5m)")
+	fs.BoolVar(&autoFailover, "auto-failover", true, "Auto failover, the default of .spec.pd.failover.enabled and .spec.tikv.failover.enabled")
+	fs.DurationVar(&pdFailoverPeriod, "pd-failover-period", time.Duration(5*time.Minute), "PD failover period default(5m), the default of .spec.pd.failover.period")
+	fs.DurationVar(&tikvFailoverPeriod, "tikv-failover-period", time.Duration(5*time.Minute), "TiKV failover period default(5m), the default of .spec.tikv.failover.period")
+	fs.BoolVar(&nodeMaintenance, "node-maintenance", true, "Evict TiKV region leaders from the nodes which are cordoned or tainted for maintenance")
+	fs.StringVar(&maintenanceTaint, "node-maintenance-taint-key", label.NodeMaintenanceTaintKey, "The taint key which marks a node under maintenance")
+	fs.BoolVar(&admissionWebhook, "admission-webhook", false, "Serve the admission webhook which holds the evictions of TiKV pods until their region leaders are evicted")
+	fs.BoolVar(&conversionWebhook, "conversion-webhook", false, "Serve the CRD conversion webhook which converts the TikvClusters between v1alpha1 and v1beta1")
+	fs.BoolVar(&installCRDs, "install-crds", false, "Install or upgrade the CRDs of the operator at startup")
+	fs.StringVar(&webhookService, "webhook-service", crd.DefaultConversionWebhook.Namespace+"/"+crd.DefaultConversionWebhook.Name, "The <namespace>/<name> of the webhook service of the operator, it is set as the conversion webhook of the installed CRDs")
+	fs.IntVar(&webhookPort, "webhook-port", 6443, "The port of the webhook server")
+	fs.StringVar(&webhookCertDir, "webhook-cert-dir", "/etc/webhook/certs", "The directory which contains tls.crt and tls.key of the webhook server, and optionally ca.crt which is set as the CA bundle of the conversion webhook of the installed CRDs")
+	fs.StringSliceVar(&watchNamespaces, "watch-namespaces", nil, "The comma separated namespaces of the TikvClusters to manage, all namespaces are managed if it is empty")
+	fs.StringVar(&clusterSelector, "cluster-selector", "", "The label selector of the TikvClusters to manage, all TikvClusters are managed if it is empty")
+	fs.StringVar(&leaderElectionName, "leader-election-name", "tikv-controller-manager", "The name of the leader election lock in the namespace of the operator, it must be unique among the operators in the same namespace")
 	fs.DurationVar(&controller.ResyncDuration, "resync-duration", time.Duration(30*time.Second), "Resync time of informer")
 	fs.StringVar(&controller.PDDiscoveryImage, "pd-discovery-image", "tikv/tikv-operator:latest", "The image of the PD discovery service")
 }
@@ -100,17 +134,52 @@ func Run(stopCh <-chan struct{}) error {
 		klog.Fatalf("failed to get the generic kube-apiserver client: %v", err)
 	}
 
-	var informerFactory informers.SharedInformerFactory
-	var kubeInformerFactory kubeinformers.SharedInformerFactory
-	var options []informers.SharedInformerOption
-	var kubeoptions []kubeinformers.SharedInformerOption
-	informerFactory = informers.NewSharedInformerFactoryWithOptions(cli, controller.ResyncDuration, options...)
-	kubeInformerFactory = kubeinformers.NewSharedInformerFactoryWithOptions(kubeCli, controller.ResyncDuration, kubeoptions...)
+	apiextensionsCli, err := apiextensionsclientset.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("failed to get the apiextensions Clientset: %v", err)
+	}
+	webhookNamespace, webhookName, err := cache.SplitMetaNamespaceKey(webhookService)
+	if err != nil {
+		klog.Fatalf("invalid webhook service %q: %v", webhookService, err)
+	}
+	conversion := crd.ConversionWebhook{Namespace: webhookNamespace, Name: webhookName}
+	if installCRDs {
+		// the CA bundle is optional, e.g. it may be injected by cert-manager
+		conversion.CABundle, err = ioutil.ReadFile(filepath.Join(webhookCertDir, "ca.crt"))
+		if err != nil && !os.IsNotExist(err) {
+			klog.Fatalf("failed to read the CA bundle of the webhook: %v", err)
+		}
+		if err := crd.Install(apiextensionsCli, conversion); err != nil {
+			klog.Fatalf("failed to install the CRDs: %v", err)
+		}
+	}
+	// the TikvClusters stored in v1alpha1 can not be read if the CRD converts them by
+	// the webhook of this operator which is not served
+	if !conversionWebhook {
+		required, err := crd.ConversionWebhookRequired(apiextensionsCli, conversion)
+		if err != nil {
+			klog.Warningf("failed to check the conversion webhook of the CRDs: %v", err)
+		} else if required {
+			klog.Fatalf("the CRDs convert the TikvClusters stored in v1alpha1 by the webhook service %s, --conversion-webhook must be enabled", webhookService)
+		}
+	}
+
+	if _, err := labels.Parse(clusterSelector); err != nil {
+		klog.Fatalf("invalid cluster selector %q: %v", clusterSelector, err)
+	}
+	// the node annotations of node maintenance are shared by all the TiKV stores on the node
+	if nodeMaintenance && (len(watchNamespaces) > 0 || clusterSelector != "") {
+		klog.Warning("node maintenance is disabled as it requires the operator to manage all TikvClusters")
+		nodeMaintenance = false
+	}
+	scopes := newInformerScopes(cli, kubeCli)
+	// the nodes and the PVs are cached once for all the scopes
+	clusterKubeInformerFactory := controller.NewClusterKubeInformerFactory(kubeCli, controller.ResyncDuration, watchNamespaces)
 
 	rl := resourcelock.EndpointsLock{
 		EndpointsMeta: metav1.ObjectMeta{
 			Namespace: ns,
-			Name:      "tikv-controller-manager",
+			Name:      leaderElectionName,
 		},
 		Client: kubeCli.CoreV1(),
 		LockConfig: resourcelock.ResourceLockConfig{
@@ -123,27 +192,33 @@ func Run(stopCh <-chan struct{}) error {
 	defer cancel()
 
 	onStarted := func(ctx context.Context) {
-		_ = genericCli
-		tcController := tikvcluster.NewController(kubeCli, cli, genericCli, informerFactory, kubeInformerFactory, autoFailover, pdFailoverPeriod, tikvFailoverPeriod)
+		controllers := []interface {
+			Run(workers int, stopCh <-chan struct{})
+		}{}
+		for _, scope := range scopes {
+			controllers = append(controllers, tikvcluster.NewController(kubeCli, cli, genericCli, scope.informerFactory, scope.kubeInformerFactory, clusterKubeInformerFactory, autoFailover, pdFailoverPeriod, tikvFailoverPeriod))
+			if nodeMaintenance {
+				controllers = append(controllers, nodemaintenance.NewController(kubeCli, scope.informerFactory, scope.kubeInformerFactory, clusterKubeInformerFactory, maintenanceTaint))
+			}
+		}
 
 		// Start informer factories after all controller are initialized.
-		informerFactory.Start(ctx.Done())
-		kubeInformerFactory.Start(ctx.Done())
-
-		// Wait for all started informers' cache were synced.
-		for v, synced := range informerFactory.WaitForCacheSync(wait.NeverStop) {
-			if !synced {
-				klog.Fatalf("error syncing informer for %v", v)
-			}
+		for _, scope := range scopes {
+			scope.start(ctx.Done())
 		}
-		for v, synced := range kubeInformerFactory.WaitForCacheSync(wait.NeverStop) {
+		clusterKubeInformerFactory.Start(ctx.Done())
+		for v, synced := range clusterKubeInformerFactory.WaitForCacheSync(wait.NeverStop) {
 			if !synced {
 				klog.Fatalf("error syncing informer for %v", v)
 			}
 		}
 		klog.Infof("cache of informer factories sync successfully")
 
-		wait.Forever(func() { tcController.Run(workers, ctx.Done()) }, waitDuration)
+		for _, c := range controllers {
+			c := c
+			go wait.Forever(func() { c.Run(workers, ctx.Done()) }, waitDuration)
+		}
+		<-ctx.Done()
 	}
 
 	onStopped := func() {
@@ -164,11 +239,95 @@ func Run(stopCh <-chan struct{}) error {
 		})
 	}, waitDuration)
 
+	// the webhooks are served by all instances
+	handlers := map[string]http.Handler{}
+	if admissionWebhook {
+		// the admission webhook does not share the informers started by the leader
+		pdControl := pdapi.NewDefaultPDControl(kubeCli)
+		admits := map[string]webhook.AdmitFunc{}
+		for _, scope := range newInformerScopes(cli, kubeCli) {
+			tcLister := scope.informerFactory.Tikv().V1beta1().TikvClusters().Lister()
+			podLister := scope.kubeInformerFactory.Core().V1().Pods().Lister()
+			pvcLister := controller.NewFallbackPVCLister(kubeCli, scope.kubeInformerFactory.Core().V1().PersistentVolumeClaims().Lister())
+			podControl := controller.NewRealPodControl(kubeCli, pdControl, podLister, &record.FakeRecorder{})
+			pvcControl := controller.NewRealPVCControl(kubeCli, &record.FakeRecorder{}, pvcLister)
+			evictionAdmitter := webhookpod.NewEvictionAdmitter(pdControl, podControl, pvcControl, tcLister, podLister, pvcLister, member.EvictLeaderTimeout)
+			scope.start(controllerCtx.Done())
+			admits[scope.namespace] = evictionAdmitter.AdmitEviction
+		}
+		handlers[webhook.PodEvictionPath] = webhook.AdmissionHandler(webhook.NamespacedAdmitFunc(admits))
+	}
+	if conversionWebhook {
+		conversionHandler, err := webhook.NewConversionHandler()
+		if err != nil {
+			klog.Fatalf("failed to create the conversion webhook: %v", err)
+		}
+		handlers[webhook.ConversionPath] = conversionHandler
+	}
+	if len(handlers) > 0 {
+		go func() {
+			klog.Fatal(webhook.StartServer(webhookPort, webhookCertDir, handlers))
+		}()
+	}
+
 	healthz.InstallHandler(http.DefaultServeMux)
 	klog.Fatal(http.ListenAndServe(":6060", nil))
 	return nil
 }
 
+// informerScope is the informer factories of a namespace watched by the operator
+type informerScope struct {
+	namespace           string
+	informerFactory     informers.SharedInformerFactory
+	kubeInformerFactory kubeinformers.SharedInformerFactory
+}
+
+// newInformerScopes returns the informer scopes of the watched namespaces, or a scope of
+// all namespaces if no namespace is specified. The TikvCluster informers only watch the
+// TikvClusters selected by the cluster selector. The other namespaced objects, e.g. pods
+// and PVCs, are cached for all the TikvClusters in the namespaces: the selected clusters
+// change as the TikvClusters are labeled, while the label selector of an informer is fixed
+// once it is started, and the objects only get the labels of the TikvCluster instance, not
+// the labels matched by the cluster selector. The cluster-scoped objects, i.e. nodes and
+// persistent volumes, are cached by a factory shared by the scopes.
+func newInformerScopes(cli versioned.Interface, kubeCli kubernetes.Interface) []*informerScope {
+	namespaces := watchNamespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	scopes := []*informerScope{}
+	for _, ns := range namespaces {
+		options := []informers.SharedInformerOption{informers.WithNamespace(ns)}
+		if clusterSelector != "" {
+			options = append(options, informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+				opts.LabelSelector = clusterSelector
+			}))
+		}
+		scopes = append(scopes, &informerScope{
+			namespace:           ns,
+			informerFactory:     informers.NewSharedInformerFactoryWithOptions(cli, controller.ResyncDuration, options...),
+			kubeInformerFactory: controller.NewKubeInformerFactory(kubeCli, controller.ResyncDuration, ns),
+		})
+	}
+	return scopes
+}
+
+// start starts the informers of the scope and waits for their caches to be synced
+func (s *informerScope) start(stopCh <-chan struct{}) {
+	s.informerFactory.Start(stopCh)
+	s.kubeInformerFactory.Start(stopCh)
+	for v, synced := range s.informerFactory.WaitForCacheSync(wait.NeverStop) {
+		if !synced {
+			klog.Fatalf("error syncing informer for %v", v)
+		}
+	}
+	for v, synced := range s.kubeInformerFactory.WaitForCacheSync(wait.NeverStop) {
+		if !synced {
+			klog.Fatalf("error syncing informer for %v", v)
+		}
+	}
+}
+
 func NewControllerManagerCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:  "controller-manager",
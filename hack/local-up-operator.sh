@@ -166,7 +166,7 @@ if ! $KUBECTL_BIN get ns "$NAMESPACE" &>/dev/null; then
 fi
 
 echo "info: installing crds"
-$KUBECTL_BIN apply -f manifests/crd.v1beta1.yaml
+$KUBECTL_BIN apply -f manifests/crd.yaml
 
 echo "info: deploying tikv-operator"
 helm_args=(
@@ -175,6 +175,7 @@ helm_args=(
     --set-string image.repository=${IMAGE_REPO}/tikv-operator
     --set-string image.tag=${IMAGE_TAG}
     --set image.args={-v=4}
+    --set conversionWebhook.enabled=false
     ${RELEASE_NAME}
     ./charts/tikv-operator
 )
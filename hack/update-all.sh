@@ -21,4 +21,5 @@ ROOT=$(unset CDPATH && cd $(dirname "${BASH_SOURCE[0]}")/.. && pwd)
 cd $ROOT
 
 hack/update-codegen.sh
+hack/update-crd-groups.sh
 hack/update-toc.sh
@@ -29,5 +29,5 @@ CODEGEN_PKG=${CODEGEN_PKG:-$(ls -d -1 ./vendor/k8s.io/code-generator 2>/dev/null
 bash "${CODEGEN_PKG}"/generate-groups.sh "deepcopy,client,informer,lister" \
     github.com/tikv/tikv-operator/pkg/client \
     github.com/tikv/tikv-operator/pkg/apis \
-    tikv:v1alpha1 \
+    tikv:v1alpha1,v1beta1 \
     --go-header-file ./hack/boilerplate/boilerplate.generatego.txt
@@ -22,4 +22,5 @@ cd $ROOT
 
 hack/verify-boilerplate.sh
 hack/verify-codegen.sh
+hack/verify-crd-groups.sh
 hack/verify-toc.sh
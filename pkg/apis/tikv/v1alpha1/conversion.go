@@ -0,0 +1,204 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/label"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts the TikvCluster to the hub version v1beta1. The cluster-level
+// pod settings are resolved into each component, the deprecated image is split into
+// the base image and the version. If the spec can't be restored from v1beta1, it is
+// recorded in an annotation.
+func (tc *TikvCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.TikvCluster)
+	dst.ObjectMeta = *tc.ObjectMeta.DeepCopy()
+	dst.Spec = v1beta1.TikvClusterSpec{}
+	if err := convertSpecTo(&tc.Spec, &dst.Spec); err != nil {
+		return err
+	}
+	dst.Status = v1beta1.TikvClusterStatus{}
+	if err := convertByJSON(&tc.Status, &dst.Status); err != nil {
+		return err
+	}
+
+	restored := &TikvClusterSpec{}
+	if err := convertSpecFrom(&dst.Spec, restored); err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(restored, &tc.Spec) {
+		delete(dst.Annotations, label.AnnV1alpha1Spec)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+		return nil
+	}
+	data, err := json.Marshal(&tc.Spec)
+	if err != nil {
+		return err
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[label.AnnV1alpha1Spec] = string(data)
+	return nil
+}
+
+// ConvertFrom converts the TikvCluster from the hub version v1beta1. The spec recorded
+// by ConvertTo is restored if the v1beta1 spec has not been changed since then.
+func (tc *TikvCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.TikvCluster)
+	tc.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	tc.Spec = TikvClusterSpec{}
+	if err := convertSpecFrom(&src.Spec, &tc.Spec); err != nil {
+		return err
+	}
+	tc.Status = TikvClusterStatus{}
+	if err := convertByJSON(&src.Status, &tc.Status); err != nil {
+		return err
+	}
+
+	data, ok := tc.Annotations[label.AnnV1alpha1Spec]
+	if !ok {
+		return nil
+	}
+	delete(tc.Annotations, label.AnnV1alpha1Spec)
+	if len(tc.Annotations) == 0 {
+		tc.Annotations = nil
+	}
+	recorded := &TikvClusterSpec{}
+	if err := json.Unmarshal([]byte(data), recorded); err != nil {
+		// the annotation is only a hint, the converted spec is still valid
+		return nil
+	}
+	spec := &v1beta1.TikvClusterSpec{}
+	if err := convertSpecTo(recorded, spec); err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(spec, &src.Spec) {
+		tc.Spec = *recorded
+	}
+	return nil
+}
+
+func convertSpecTo(src *TikvClusterSpec, dst *v1beta1.TikvClusterSpec) error {
+	// the fields which are not changed in v1beta1 are copied by their JSON encodings
+	if err := convertByJSON(src, dst); err != nil {
+		return err
+	}
+	convertComponentSpecTo(src, &src.PD.ComponentSpec, src.PD.BaseImage, &dst.PD.ComponentSpec, &dst.PD.BaseImage)
+	convertComponentSpecTo(src, &src.TiKV.ComponentSpec, src.TiKV.BaseImage, &dst.TiKV.ComponentSpec, &dst.TiKV.BaseImage)
+	return nil
+}
+
+// convertComponentSpecTo resolves the image and the cluster-level settings of the
+// component as componentAccessorImpl does
+func convertComponentSpecTo(cluster *TikvClusterSpec, src *ComponentSpec, baseImage string, dst *v1beta1.ComponentSpec, dstBaseImage *string) {
+	version := src.Version
+	if version == nil {
+		version = &cluster.Version
+	}
+	*dstBaseImage, dst.Version = baseImage, *version
+	if baseImage == "" && src.Image != "" {
+		*dstBaseImage, dst.Version = splitImage(src.Image)
+	}
+
+	dst.ImagePullPolicy = src.ImagePullPolicy
+	if dst.ImagePullPolicy == nil && cluster.ImagePullPolicy != "" {
+		pp := cluster.ImagePullPolicy
+		dst.ImagePullPolicy = &pp
+	}
+	dst.HostNetwork = src.HostNetwork
+	if dst.HostNetwork == nil {
+		dst.HostNetwork = cluster.HostNetwork
+	}
+	dst.Affinity = src.Affinity
+	if dst.Affinity == nil {
+		dst.Affinity = cluster.Affinity
+	}
+	dst.PriorityClassName = src.PriorityClassName
+	if dst.PriorityClassName == nil {
+		dst.PriorityClassName = cluster.PriorityClassName
+	}
+	dst.SchedulerName = src.SchedulerName
+	if dst.SchedulerName == nil && cluster.SchedulerName != "" {
+		name := cluster.SchedulerName
+		dst.SchedulerName = &name
+	}
+	dst.NodeSelector = mergeStringMaps(cluster.NodeSelector, src.NodeSelector)
+	dst.Annotations = mergeStringMaps(cluster.Annotations, src.Annotations)
+	dst.Tolerations = src.Tolerations
+	if len(dst.Tolerations) == 0 {
+		dst.Tolerations = cluster.Tolerations
+	}
+	dst.ConfigUpdateStrategy = (*v1beta1.ConfigUpdateStrategy)(src.ConfigUpdateStrategy)
+	if dst.ConfigUpdateStrategy == nil && cluster.ConfigUpdateStrategy != "" {
+		strategy := v1beta1.ConfigUpdateStrategy(cluster.ConfigUpdateStrategy)
+		dst.ConfigUpdateStrategy = &strategy
+	}
+}
+
+func convertSpecFrom(src *v1beta1.TikvClusterSpec, dst *TikvClusterSpec) error {
+	if err := convertByJSON(src, dst); err != nil {
+		return err
+	}
+	// the version of TiKV is the cluster-level version, PD overrides it if they differ
+	dst.Version = src.TiKV.Version
+	dst.TiKV.Version = nil
+	dst.PD.Version = nil
+	if src.PD.Version != src.TiKV.Version {
+		version := src.PD.Version
+		dst.PD.Version = &version
+	}
+	return nil
+}
+
+// splitImage splits the image into the repository and the tag or digest
+func splitImage(image string) (string, string) {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[:i], image[i+1:]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
+func mergeStringMaps(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	merged := map[string]string{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+func convertByJSON(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
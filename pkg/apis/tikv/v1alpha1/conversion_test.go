@@ -0,0 +1,222 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+func TestConvertTo(t *testing.T) {
+	alwaysPull := corev1.PullAlways
+	tests := []struct {
+		name   string
+		spec   TikvClusterSpec
+		expect func(*GomegaWithT, *v1beta1.TikvCluster)
+	}{
+		{
+			name: "cluster-level version",
+			spec: TikvClusterSpec{
+				Version: "v4.0.0",
+				PD:      PDSpec{BaseImage: "pingcap/pd"},
+				TiKV:    TiKVSpec{BaseImage: "pingcap/tikv"},
+			},
+			expect: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.PDImage()).To(Equal("pingcap/pd:v4.0.0"))
+				g.Expect(tc.TiKVImage()).To(Equal("pingcap/tikv:v4.0.0"))
+				g.Expect(tc.Annotations).NotTo(HaveKey(label.AnnV1alpha1Spec))
+			},
+		},
+		{
+			name: "component version",
+			spec: TikvClusterSpec{
+				Version: "v4.0.0",
+				PD:      PDSpec{BaseImage: "pingcap/pd", ComponentSpec: ComponentSpec{Version: pointer.StringPtr("v4.0.1")}},
+				TiKV:    TiKVSpec{BaseImage: "pingcap/tikv"},
+			},
+			expect: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.PDImage()).To(Equal("pingcap/pd:v4.0.1"))
+				g.Expect(tc.TiKVImage()).To(Equal("pingcap/tikv:v4.0.0"))
+				g.Expect(tc.Annotations).NotTo(HaveKey(label.AnnV1alpha1Spec))
+			},
+		},
+		{
+			name: "deprecated image",
+			spec: TikvClusterSpec{
+				PD:   PDSpec{ComponentSpec: ComponentSpec{Image: "localhost:5000/pd@sha256:abcd"}},
+				TiKV: TiKVSpec{ComponentSpec: ComponentSpec{Image: "localhost:5000/tikv"}},
+			},
+			expect: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.PDImage()).To(Equal("localhost:5000/pd@sha256:abcd"))
+				g.Expect(tc.TiKVImage()).To(Equal("localhost:5000/tikv:latest"))
+				g.Expect(tc.Annotations).To(HaveKey(label.AnnV1alpha1Spec))
+			},
+		},
+		{
+			name: "cluster-level pod settings",
+			spec: TikvClusterSpec{
+				Version:         "v4.0.0",
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				SchedulerName:   "tikv-scheduler",
+				NodeSelector:    map[string]string{"zone": "a", "disk": "ssd"},
+				Tolerations:     []corev1.Toleration{{Key: "dedicated"}},
+				PD:              PDSpec{BaseImage: "pingcap/pd"},
+				TiKV: TiKVSpec{
+					BaseImage: "pingcap/tikv",
+					ComponentSpec: ComponentSpec{
+						ImagePullPolicy: &alwaysPull,
+						NodeSelector:    map[string]string{"disk": "nvme"},
+					},
+				},
+			},
+			expect: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.BasePDSpec().ImagePullPolicy()).To(Equal(corev1.PullIfNotPresent))
+				g.Expect(tc.BaseTiKVSpec().ImagePullPolicy()).To(Equal(corev1.PullAlways))
+				g.Expect(tc.BasePDSpec().SchedulerName()).To(Equal("tikv-scheduler"))
+				g.Expect(tc.BasePDSpec().NodeSelector()).To(Equal(map[string]string{"zone": "a", "disk": "ssd"}))
+				g.Expect(tc.BaseTiKVSpec().NodeSelector()).To(Equal(map[string]string{"zone": "a", "disk": "nvme"}))
+				g.Expect(tc.BaseTiKVSpec().Tolerations()).To(Equal([]corev1.Toleration{{Key: "dedicated"}}))
+				g.Expect(tc.Annotations).To(HaveKey(label.AnnV1alpha1Spec))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			tc := &TikvCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"},
+				Spec:       test.spec,
+			}
+			hub := &v1beta1.TikvCluster{}
+			g.Expect(tc.ConvertTo(hub)).To(Succeed())
+			g.Expect(hub.Name).To(Equal("demo"))
+			test.expect(g, hub)
+
+			restored := &TikvCluster{}
+			g.Expect(restored.ConvertFrom(hub)).To(Succeed())
+			g.Expect(apiequality.Semantic.DeepEqual(restored, tc)).To(BeTrue())
+		})
+	}
+}
+
+func TestConvertFromChangedHub(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := &TikvCluster{
+		Spec: TikvClusterSpec{
+			Version:       "v4.0.0",
+			SchedulerName: "tikv-scheduler",
+			PD:            PDSpec{BaseImage: "pingcap/pd"},
+			TiKV:          TiKVSpec{BaseImage: "pingcap/tikv"},
+		},
+	}
+	hub := &v1beta1.TikvCluster{}
+	g.Expect(tc.ConvertTo(hub)).To(Succeed())
+
+	// the recorded spec is outdated once the hub is changed
+	hub.Spec.TiKV.Version = "v4.0.1"
+	restored := &TikvCluster{}
+	g.Expect(restored.ConvertFrom(hub)).To(Succeed())
+	g.Expect(restored.Annotations).To(BeNil())
+	g.Expect(restored.Spec.SchedulerName).To(BeEmpty())
+	g.Expect(*restored.Spec.PD.SchedulerName).To(Equal("tikv-scheduler"))
+	g.Expect(restored.TiKVImage()).To(Equal("pingcap/tikv:v4.0.1"))
+	g.Expect(restored.PDImage()).To(Equal("pingcap/pd:v4.0.0"))
+}
+
+// fuzzer generates the values which survive the JSON round trip as the apiserver does
+func fuzzer() *fuzz.Fuzzer {
+	return fuzz.New().NilChance(0.3).NumElements(0, 2).Funcs(
+		func(q *resource.Quantity, c fuzz.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1000), resource.DecimalSI)
+		},
+		func(t *metav1.Time, c fuzz.Continue) {
+			*t = metav1.Unix(c.Int63n(1e9), 0)
+		},
+		func(d *metav1.Duration, c fuzz.Continue) {
+			d.Duration = time.Duration(c.Int63n(1e6)) * time.Second
+		},
+		func(i *intstr.IntOrString, c fuzz.Continue) {
+			if c.RandBool() {
+				*i = intstr.FromInt(c.Intn(100))
+			} else {
+				*i = intstr.FromString(c.RandString())
+			}
+		},
+		// a pointer to a nil slice or map is encoded as null which is decoded as a nil pointer
+		func(s *PDSchedulerConfigs, c fuzz.Continue) {
+			c.Fuzz((*[]PDSchedulerConfig)(s))
+			if *s == nil {
+				*s = PDSchedulerConfigs{}
+			}
+		},
+		func(s *v1beta1.PDSchedulerConfigs, c fuzz.Continue) {
+			c.Fuzz((*[]v1beta1.PDSchedulerConfig)(s))
+			if *s == nil {
+				*s = v1beta1.PDSchedulerConfigs{}
+			}
+		},
+		func(m *PDLabelPropertyConfig, c fuzz.Continue) {
+			c.Fuzz((*map[string]PDStoreLabels)(m))
+			if *m == nil {
+				*m = PDLabelPropertyConfig{}
+			}
+		},
+		func(m *v1beta1.PDLabelPropertyConfig, c fuzz.Continue) {
+			c.Fuzz((*map[string]v1beta1.PDStoreLabels)(m))
+			if *m == nil {
+				*m = v1beta1.PDLabelPropertyConfig{}
+			}
+		},
+		// the apiVersion and kind are set by the conversion webhook
+		func(m *metav1.TypeMeta, c fuzz.Continue) {},
+		func(m *metav1.ObjectMeta, c fuzz.Continue) {
+			m.Name = c.RandString()
+			m.Namespace = c.RandString()
+		},
+	)
+}
+
+func TestFuzzyRoundTrip(t *testing.T) {
+	g := NewGomegaWithT(t)
+	f := fuzzer()
+	for i := 0; i < 100; i++ {
+		tc := &TikvCluster{}
+		f.Fuzz(tc)
+		hub := &v1beta1.TikvCluster{}
+		g.Expect(tc.ConvertTo(hub)).To(Succeed())
+		restored := &TikvCluster{}
+		g.Expect(restored.ConvertFrom(hub)).To(Succeed())
+		g.Expect(apiequality.Semantic.DeepEqual(restored, tc)).To(BeTrue(), "v1alpha1 -> v1beta1 -> v1alpha1")
+
+		hub = &v1beta1.TikvCluster{}
+		f.Fuzz(hub)
+		tc = &TikvCluster{}
+		g.Expect(tc.ConvertFrom(hub)).To(Succeed())
+		restoredHub := &v1beta1.TikvCluster{}
+		g.Expect(tc.ConvertTo(restoredHub)).To(Succeed())
+		g.Expect(apiequality.Semantic.DeepEqual(restoredHub, hub)).To(BeTrue(), "v1beta1 -> v1alpha1 -> v1beta1")
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/tikv/tikv-operator/pkg/label"
@@ -27,6 +28,8 @@ import (
 const (
 	defaultHelperImage = "busybox:1.26.2"
 	defaultTimeZone    = "UTC"
+
+	defaultTiKVUnsafeRecoveryDownThreshold = time.Hour
 )
 
 func (tc *TikvCluster) PDImage() string {
@@ -117,6 +120,35 @@ func (tc *TikvCluster) PDStsDesiredReplicas() int32 {
 	return tc.Spec.PD.Replicas + int32(len(tc.Status.PD.FailureMembers))
 }
 
+// PDInitialMembers returns the number of PD members expected to bootstrap
+// the cluster, it is the total of the desired replicas of the PD group managed
+// by the operator and the given replicas of the other PD groups joining the
+// cluster, and excludes the replicas added by failover.
+func (tc *TikvCluster) PDInitialMembers(groupReplicas ...int32) int32 {
+	// the PD cluster is bootstrapped by a single member during the recovery
+	if tc.PDRecreatingMember() {
+		return 1
+	}
+	total := tc.Spec.PD.Replicas
+	for _, replicas := range groupReplicas {
+		total += replicas
+	}
+	return total
+}
+
+// PDRecovering returns true if the PD quorum-loss recovery is in progress
+func (tc *TikvCluster) PDRecovering() bool {
+	r := tc.Status.PD.Recovery
+	return r != nil && r.Phase != PDRecoveryComplete && r.Phase != PDRecoveryFailed
+}
+
+// PDRecreatingMember returns true if the PD cluster is being recreated with
+// a single member during the recovery
+func (tc *TikvCluster) PDRecreatingMember() bool {
+	r := tc.Status.PD.Recovery
+	return r != nil && (r.Phase == PDRecoveryPrepare || r.Phase == PDRecoveryRecreateMember)
+}
+
 func (tc *TikvCluster) PDStsActualReplicas() int32 {
 	stsStatus := tc.Status.PD.StatefulSet
 	if stsStatus == nil {
@@ -152,7 +184,24 @@ func (tc *TikvCluster) TiKVAllStoresReady() bool {
 }
 
 func (tc *TikvCluster) TiKVStsDesiredReplicas() int32 {
-	return tc.Spec.TiKV.Replicas + int32(len(tc.Status.TiKV.FailureStores))
+	replicas := tc.Spec.TiKV.Replicas
+	for _, store := range tc.Status.TiKV.FailureStores {
+		// the failure store to be replaced keeps its pod
+		if !store.Replace {
+			replicas++
+		}
+	}
+	return replicas
+}
+
+// TiKVActualReplicasWithoutFailover returns the replicas of the TiKV StatefulSet excluding
+// the ones added by failover
+func (tc *TikvCluster) TiKVActualReplicasWithoutFailover() int32 {
+	replicas := tc.TiKVStsActualReplicas() - (tc.TiKVStsDesiredReplicas() - tc.Spec.TiKV.Replicas)
+	if replicas < 0 {
+		return 0
+	}
+	return replicas
 }
 
 func (tc *TikvCluster) TiKVStsActualReplicas() int32 {
@@ -213,7 +262,67 @@ func (tc *TikvCluster) TiKVUpgrading() bool {
 	return tc.Status.TiKV.Phase == UpgradePhase
 }
 
+// TiKVUnsafeRecoveryDownThreshold returns the minimum duration the failure stores must
+// have been Down before they are removed by the online unsafe recovery
+func (tc *TikvCluster) TiKVUnsafeRecoveryDownThreshold() time.Duration {
+	if tc.Spec.TiKV.UnsafeRecoveryDownThreshold == nil {
+		return defaultTiKVUnsafeRecoveryDownThreshold
+	}
+	return tc.Spec.TiKV.UnsafeRecoveryDownThreshold.Duration
+}
+
+// TiKVUnsafeRecovering returns true if the online unsafe recovery is in progress
+func (tc *TikvCluster) TiKVUnsafeRecovering() bool {
+	r := tc.Status.TiKV.UnsafeRecovery
+	return r != nil && r.Phase == TiKVUnsafeRecoveryRunning
+}
+
+// TiKVSnapshotSetCreating returns the name of the snapshot set being created, or empty
+func (tc *TikvCluster) TiKVSnapshotSetCreating() string {
+	for name, set := range tc.Status.TiKV.SnapshotSets {
+		if set.Phase == TiKVSnapshotSetCreating {
+			return name
+		}
+	}
+	return ""
+}
+
+// IsTiKVCloned returns true if the TiKV volumes are cloned from a snapshot set
+func (tc *TikvCluster) IsTiKVCloned() bool {
+	return tc.Spec.TiKV.DataSource != nil
+}
+
+// TiKVMasterKeyRotated returns true if all the stores run with the current master key
+func (tc *TikvCluster) TiKVMasterKeyRotated() bool {
+	e := tc.Status.TiKV.Encryption
+	if e == nil {
+		return true
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.MasterKeyGeneration != e.KeyGeneration {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPDExternal returns true if the TikvCluster joins an external PD cluster
+// instead of managing the PD members itself
+func (tc *TikvCluster) IsPDExternal() bool {
+	return len(tc.Spec.PD.ExternalEndpoints) > 0
+}
+
+// IsPDExternalTLSEnabled returns true if the external PD cluster is accessed over https
+func (tc *TikvCluster) IsPDExternalTLSEnabled() bool {
+	return tc.IsPDExternal() && tc.Spec.PD.ExternalTLSClientSecretName != nil && *tc.Spec.PD.ExternalTLSClientSecretName != ""
+}
+
 func (tc *TikvCluster) PDIsAvailable() bool {
+	// the availability of the external PD cluster is unknown, TiKV is synced
+	// anyway and fails on the requests to PD if it is not available
+	if tc.IsPDExternal() {
+		return true
+	}
 	lowerLimit := tc.Spec.PD.Replicas/2 + 1
 	if int32(len(tc.Status.PD.Members)) < lowerLimit {
 		return false
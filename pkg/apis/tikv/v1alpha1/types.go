@@ -18,6 +18,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -163,6 +164,18 @@ type TikvClusterSpec struct {
 	// Optional: Defaults to UTC
 	// +optional
 	Timezone string `json:"timezone,omitempty"`
+
+	// ClusterDomain is the Kubernetes cluster domain, e.g. cluster.local. The PD
+	// members advertise fully-qualified domain names under it if it is set.
+	// Optional: Defaults to the short name <pod>.<service>.<namespace>.svc
+	// +optional
+	ClusterDomain string `json:"clusterDomain,omitempty"`
+
+	// MaintenanceWindows are the time windows in which the disruptive operations, i.e.
+	// upgrades, rolling restarts and scale-in, are allowed. The failover is always allowed.
+	// Optional: Defaults to no window, the disruptive operations are always allowed
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // TikvClusterStatus represents the current status of a tikv cluster.
@@ -173,6 +186,47 @@ type TikvClusterStatus struct {
 	// Represents the latest available observations of a tikv cluster's state.
 	// +optional
 	Conditions []TikvClusterCondition `json:"conditions,omitempty"`
+	// Maintenance is the status of the maintenance windows, it is only set if
+	// .spec.maintenanceWindows is set
+	// +optional
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// MaintenanceWindow is a recurring time window for the disruptive operations
+type MaintenanceWindow struct {
+	// Schedule is the start time of the window in the cron format, e.g. "0 2 * * 6",
+	// it is in the time zone of .spec.timezone
+	Schedule string `json:"schedule"`
+
+	// Duration is the length of the window, e.g. 4h
+	Duration metav1.Duration `json:"duration"`
+}
+
+// DisruptiveOperation is an operation which restarts or removes the pods
+type DisruptiveOperation string
+
+const (
+	// PDUpgradeOperation upgrades or rolling restarts the PD pods
+	PDUpgradeOperation DisruptiveOperation = "PDUpgrade"
+	// TiKVUpgradeOperation upgrades or rolling restarts the TiKV pods
+	TiKVUpgradeOperation DisruptiveOperation = "TiKVUpgrade"
+	// PDScaleInOperation removes PD pods, including the ones added by the failover
+	PDScaleInOperation DisruptiveOperation = "PDScaleIn"
+	// TiKVScaleInOperation removes TiKV pods
+	TiKVScaleInOperation DisruptiveOperation = "TiKVScaleIn"
+)
+
+// MaintenanceStatus is the status of the maintenance windows
+type MaintenanceStatus struct {
+	// InWindow is true if the cluster was synced in a maintenance window
+	InWindow bool `json:"inWindow"`
+	// NextWindow is the start time of the next maintenance window
+	// +optional
+	NextWindow *metav1.Time `json:"nextWindow,omitempty"`
+	// PendingOperations are the disruptive operations waiting for the next window
+	// +optional
+	PendingOperations []DisruptiveOperation `json:"pendingOperations,omitempty"`
 }
 
 // TikvClusterCondition describes the state of a tikv cluster at a certain point.
@@ -206,12 +260,31 @@ const (
 	// - All TiKV stores are up.
 	// - All TiFlash stores are up.
 	TikvClusterReady TikvClusterConditionType = "Ready"
+	// TiKVScaleInBlocked indicates that the scale-in of TiKV is blocked by the pre-flight
+	// checks, i.e. the remaining stores could not hold the data or the region replicas.
+	TiKVScaleInBlocked TikvClusterConditionType = "TiKVScaleInBlocked"
+	// PDDisruptionBlocked indicates that the PodDisruptionBudget of PD allows no voluntary
+	// disruption, i.e. node drains are blocked because the PD quorum can't tolerate a loss.
+	PDDisruptionBlocked TikvClusterConditionType = "PDDisruptionBlocked"
+	// TiKVDisruptionBlocked indicates that the PodDisruptionBudget of TiKV allows no voluntary
+	// disruption, i.e. node drains are blocked because the region replicas can't tolerate a loss.
+	TiKVDisruptionBlocked TikvClusterConditionType = "TiKVDisruptionBlocked"
+	// ExternalStoresDeletionBlocked indicates that the deletion of the external stores is
+	// blocked by the pre-flight checks of the scale-in.
+	ExternalStoresDeletionBlocked TikvClusterConditionType = "ExternalStoresDeletionBlocked"
 )
 
 // +k8s:openapi-gen=true
 // DiscoverySpec contains details of Discovery members
 type DiscoverySpec struct {
 	corev1.ResourceRequirements `json:",inline"`
+
+	// The desired replicas of the discovery service, the bootstrap state of PD cluster
+	// is persisted in a ConfigMap, so it is safe to run multiple replicas
+	// Optional: Defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -236,11 +309,16 @@ type PDSpec struct {
 	Service *ServiceSpec `json:"service,omitempty"`
 
 	// MaxFailoverCount limit the max replicas could be added in failover, 0 means no failover.
+	// Deprecated: use failover.maxCount instead
 	// Optional: Defaults to 3
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	MaxFailoverCount *int32 `json:"maxFailoverCount,omitempty"`
 
+	// Failover is the failover policy of PD, it overrides the flags of the controller manager
+	// +optional
+	Failover *FailoverSpec `json:"failover,omitempty"`
+
 	// The storageClassName of the persistent volume for PD data storage.
 	// Defaults to Kubernetes default storage class.
 	// +optional
@@ -254,6 +332,90 @@ type PDSpec struct {
 	// which used by Dashboard.
 	// +optional
 	TLSClientSecretName *string `json:"tlsClientSecretName,omitempty"`
+
+	// ExternalEndpoints are the client urls of an existing PD cluster which is not
+	// managed by this TikvCluster, e.g. https://10.0.1.1:2379. If it is set, the PD
+	// members are not created and TiKV joins the external PD cluster.
+	// +optional
+	ExternalEndpoints []string `json:"externalEndpoints,omitempty"`
+
+	// ExternalTLSClientSecretName is the name of secret which stores the CA certificate
+	// (ca.crt), the client certificate (tls.crt) and key (tls.key) to access the external
+	// PD cluster over https. It is also used by TiKV as the cluster certificate.
+	// +optional
+	ExternalTLSClientSecretName *string `json:"externalTLSClientSecretName,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of PD cluster.
+	// Optional: Defaults to a PodDisruptionBudget which keeps the PD quorum
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// FailoverSpec is the failover policy of a component
+type FailoverSpec struct {
+	// Enabled enables the failover of the component
+	// Optional: Defaults to the --auto-failover flag of the controller manager
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Period is the duration a PD member must have been unhealthy or a TiKV store must
+	// have been Down before it is failed over
+	// Optional: Defaults to the --pd-failover-period or --tikv-failover-period flag of
+	// the controller manager
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+
+	// MaxCount limit the max replicas could be added in failover, 0 means no failover
+	// Optional: Defaults to maxFailoverCount
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxCount *int32 `json:"maxCount,omitempty"`
+
+	// ReplaceFailedStore replaces the failed TiKV store by a new store of the same pod on
+	// a new PVC instead of adding a new replica, e.g. for the dead disks of the local PVs.
+	// The failed store is deleted from the PD cluster, once it becomes Tombstone, its PVC
+	// and pod are deleted. A new replica is added instead if deleting the store fails the
+	// checks of a scale-in, e.g. fewer Up stores than max-replicas would remain. It is only supported by TiKV, the failed PD members are always replaced.
+	// Optional: Defaults to false
+	// +optional
+	ReplaceFailedStore *bool `json:"replaceFailedStore,omitempty"`
+
+	// NodeLossTimeout is the duration a pod must have been unschedulable because the node
+	// of its local PV is gone before its PVC is deleted, so that the pod is rescheduled on
+	// a new PV. The PD member or the TiKV store of the pod is deleted from the PD cluster
+	// first, the TiKV store must be Down or Tombstone and the remaining stores must pass
+	// the pre-flight checks of the scale-in. The cleanup is skipped if the failover is
+	// disabled.
+	// Optional: Defaults to 0, which disables the cleanup
+	// +optional
+	NodeLossTimeout *metav1.Duration `json:"nodeLossTimeout,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// ScalePolicy is the scaling policy of a component
+type ScalePolicy struct {
+	// ScaleOutParallelism is the max number of members added at a time when scaling out
+	// Optional: Defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ScaleOutParallelism *int32 `json:"scaleOutParallelism,omitempty"`
+
+	// ScaleInParallelism is the max number of members removed at a time when scaling in,
+	// the stores of them are deleted from the PD cluster concurrently
+	// Optional: Defaults to 1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ScaleInParallelism *int32 `json:"scaleInParallelism,omitempty"`
+
+	// ScaleInHighWaterMarkPercent is the max used ratio in percent of the capacity of the
+	// remaining Up stores, the stores are not deleted from the PD cluster when scaling in
+	// if the data would exceed it
+	// Optional: Defaults to 80, the low-space-ratio of PD
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ScaleInHighWaterMarkPercent *int32 `json:"scaleInHighWaterMarkPercent,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -282,11 +444,20 @@ type TiKVSpec struct {
 	Privileged *bool `json:"privileged,omitempty"`
 
 	// MaxFailoverCount limit the max replicas could be added in failover, 0 means no failover
+	// Deprecated: use failover.maxCount instead
 	// Optional: Defaults to 3
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	MaxFailoverCount *int32 `json:"maxFailoverCount,omitempty"`
 
+	// Failover is the failover policy of TiKV, it overrides the flags of the controller manager
+	// +optional
+	Failover *FailoverSpec `json:"failover,omitempty"`
+
+	// ScalePolicy is the parallelism of scaling out and scaling in TiKV
+	// +optional
+	ScalePolicy *ScalePolicy `json:"scalePolicy,omitempty"`
+
 	// The storageClassName of the persistent volume for TiKV data storage.
 	// Defaults to Kubernetes default storage class.
 	// +optional
@@ -295,6 +466,79 @@ type TiKVSpec struct {
 	// Config is the Configuration of tikv-servers
 	// +optional
 	Config *TiKVConfig `json:"config,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of TiKV cluster.
+	// Optional: Defaults to a PodDisruptionBudget which keeps the majority of region replicas
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// ManageExternalStores enables the operator to sync the stores which are not created
+	// by the operator to .status.tikv.externalStores, and to delete the ones listed in the
+	// annotation tikv.tikv.org/delete-external-stores. The pods of these stores are never
+	// touched by the operator.
+	// Optional: Defaults to false
+	// +optional
+	ManageExternalStores bool `json:"manageExternalStores,omitempty"`
+
+	// UnsafeRecoveryDownThreshold is the minimum duration the failure stores must have been
+	// Down before the online unsafe recovery triggered by the annotation
+	// tikv.org/tikv-unsafe-recovery removes them
+	// Optional: Defaults to 1h
+	// +optional
+	UnsafeRecoveryDownThreshold *metav1.Duration `json:"unsafeRecoveryDownThreshold,omitempty"`
+
+	// VolumeSnapshotClassName is the VolumeSnapshotClass of the snapshots of TiKV volumes
+	// created on the annotation tikv.org/volume-snapshot
+	// Optional: Defaults to the default VolumeSnapshotClass
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// DataSource makes the TiKV volumes cloned from a snapshot set of a TikvCluster in
+	// the same namespace, it only takes effect when the cluster is created. The volumes
+	// are not snapshotted at the same instant, so the clone may be inconsistent.
+	// +optional
+	DataSource *TiKVDataSource `json:"dataSource,omitempty"`
+
+	// Encryption makes the operator manage the master key of the encryption at rest
+	// +optional
+	Encryption *TiKVEncryption `json:"encryption,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TiKVEncryption is the master key of the encryption at rest of TiKV
+type TiKVEncryption struct {
+	// MasterKeySecretRef is the Secret in the same namespace which stores the master key
+	// in the key "master-key", the key is a 256-bits key encoded as hex string and ends
+	// with a newline. Updating the key rotates the master key by a rolling restart.
+	MasterKeySecretRef corev1.LocalObjectReference `json:"masterKeySecretRef"`
+}
+
+// +k8s:openapi-gen=true
+// TiKVDataSource is the snapshot set which a TiKV cluster is cloned from
+type TiKVDataSource struct {
+	// ClusterName is the name of the TikvCluster which the snapshots are taken from,
+	// the cluster may have been deleted
+	ClusterName string `json:"clusterName"`
+	// SnapshotSet is the name of the snapshot set recorded in .status.tikv.snapshotSets
+	// of the source cluster
+	SnapshotSet string `json:"snapshotSet"`
+}
+
+// +k8s:openapi-gen=true
+// PodDisruptionBudgetSpec describes the PodDisruptionBudget of a component
+type PodDisruptionBudgetSpec struct {
+	// Whether to create the PodDisruptionBudget
+	// Optional: Defaults to true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MaxUnavailable is the max number of pods which can be unavailable during voluntary disruptions.
+	// The budget is raised by one while the operator is upgrading the component, up to the
+	// number of failures the component can tolerate.
+	// Optional: Defaults to the number of failures the component can tolerate, which is 0 for
+	// 1 or 2 PD members and for max-replicas 1 or 2, the node drains are blocked then
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -397,6 +641,49 @@ type PDStatus struct {
 	FailureMembers  map[string]PDFailureMember `json:"failureMembers,omitempty"`
 	UnjoinedMembers map[string]UnjoinedMember  `json:"unjoinedMembers,omitempty"`
 	Image           string                     `json:"image,omitempty"`
+	// Recovery is the status of the PD quorum-loss recovery triggered by
+	// the annotation tikv.org/pd-recovery
+	Recovery *PDRecoveryStatus `json:"recovery,omitempty"`
+}
+
+// PDRecoveryPhase is the phase of the PD quorum-loss recovery
+type PDRecoveryPhase string
+
+const (
+	// PDRecoveryPrepare means the cluster id and alloc id are being resolved
+	PDRecoveryPrepare PDRecoveryPhase = "Prepare"
+	// PDRecoveryRecreateMember means the PD cluster is being recreated with a single member
+	PDRecoveryRecreateMember PDRecoveryPhase = "RecreateMember"
+	// PDRecoveryRestart means the cluster id and alloc id have been recovered and
+	// the single PD member is being restarted
+	PDRecoveryRestart PDRecoveryPhase = "Restart"
+	// PDRecoveryRejoin means the remaining PD members are rejoining the recovered cluster
+	PDRecoveryRejoin PDRecoveryPhase = "Rejoin"
+	// PDRecoveryComplete means the PD cluster has been recovered
+	PDRecoveryComplete PDRecoveryPhase = "Complete"
+	// PDRecoveryFailed means the recovery can't proceed and must be fixed manually
+	PDRecoveryFailed PDRecoveryPhase = "Failed"
+)
+
+// PDRecoveryStatus is the status of the PD quorum-loss recovery
+type PDRecoveryStatus struct {
+	// Trigger is the value of the annotation which triggers the recovery
+	Trigger string          `json:"trigger,omitempty"`
+	Phase   PDRecoveryPhase `json:"phase,omitempty"`
+	// ClusterID is the id of the lost PD cluster which is recovered
+	ClusterID string `json:"clusterID,omitempty"`
+	// AllocID is the id the recovered PD cluster allocates from
+	AllocID   string      `json:"allocID,omitempty"`
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// Steps records the steps of the recovery
+	Steps []PDRecoveryStep `json:"steps,omitempty"`
+}
+
+// PDRecoveryStep is a step of the PD quorum-loss recovery
+type PDRecoveryStep struct {
+	Phase   PDRecoveryPhase `json:"phase"`
+	Message string          `json:"message,omitempty"`
+	Time    metav1.Time     `json:"time"`
 }
 
 // PDMember is PD member
@@ -436,6 +723,101 @@ type TiKVStatus struct {
 	TombstoneStores map[string]TiKVStore        `json:"tombstoneStores,omitempty"`
 	FailureStores   map[string]TiKVFailureStore `json:"failureStores,omitempty"`
 	Image           string                      `json:"image,omitempty"`
+	// Selector is the label selector of the TiKV pods in the string form, it's
+	// used by the scale subresource, e.g. for the HorizontalPodAutoscaler
+	Selector string `json:"selector,omitempty"`
+	// Replicas is the number of the TiKV pods excluding the ones added by failover, it's
+	// the status replicas of the scale subresource which is compared with .spec.tikv.replicas
+	Replicas int32 `json:"replicas,omitempty"`
+	// ExternalStores are the stores which are not created by the operator, including the
+	// tombstone ones. It is only synced if .spec.tikv.manageExternalStores is true.
+	ExternalStores map[string]TiKVStore `json:"externalStores,omitempty"`
+	// UnsafeRecovery is the status of the online unsafe recovery triggered by
+	// the annotation tikv.org/tikv-unsafe-recovery
+	UnsafeRecovery *TiKVUnsafeRecoveryStatus `json:"unsafeRecovery,omitempty"`
+	// SnapshotSets are the sets of volume snapshots triggered by the annotation
+	// tikv.org/volume-snapshot, keyed by the name of the set
+	SnapshotSets map[string]TiKVSnapshotSet `json:"snapshotSets,omitempty"`
+	// Encryption is the master key managed by spec.tikv.encryption
+	Encryption *TiKVEncryptionStatus `json:"encryption,omitempty"`
+}
+
+// TiKVEncryptionStatus is the status of the master key of the encryption at rest
+type TiKVEncryptionStatus struct {
+	// KeyGeneration is increased whenever the master key is rotated
+	KeyGeneration int64 `json:"keyGeneration"`
+	// PreviousKeyGeneration is the generation of the previous master key, it's 0 if the
+	// master key has never been rotated
+	PreviousKeyGeneration int64 `json:"previousKeyGeneration,omitempty"`
+	// KeyHash is the hash of the master key to detect the changes of the Secret
+	KeyHash string `json:"keyHash"`
+	// LastRotationTime is the last time the master key was rotated
+	LastRotationTime metav1.Time `json:"lastRotationTime,omitempty"`
+}
+
+// TiKVSnapshotSetPhase is the phase of a set of TiKV volume snapshots
+type TiKVSnapshotSetPhase string
+
+const (
+	// TiKVSnapshotSetCreating means the gc, the region balance and the region merge are
+	// paused and the snapshots are being created
+	TiKVSnapshotSetCreating TiKVSnapshotSetPhase = "Creating"
+	// TiKVSnapshotSetReady means all the snapshots are ready to use
+	TiKVSnapshotSetReady TiKVSnapshotSetPhase = "Ready"
+	// TiKVSnapshotSetFailed means any of the snapshots failed
+	TiKVSnapshotSetFailed TiKVSnapshotSetPhase = "Failed"
+)
+
+// TiKVSnapshotSet is a set of snapshots of all the TiKV volumes taken one by one while
+// the gc and the scheduling are paused
+type TiKVSnapshotSet struct {
+	Phase TiKVSnapshotSetPhase `json:"phase,omitempty"`
+	// ClusterID is the id of the cluster the snapshots belong to
+	ClusterID string `json:"clusterID,omitempty"`
+	// AllocID is the id the PD cluster of a clone allocates from, it is read from
+	// PD once all the snapshots are ready
+	AllocID string `json:"allocID,omitempty"`
+	// GCSafePoint is the gc safe point kept while the snapshots are being created
+	GCSafePoint uint64      `json:"gcSafePoint,omitempty"`
+	StartTime   metav1.Time `json:"startTime,omitempty"`
+	// Snapshots are the snapshots keyed by the store id
+	Snapshots map[string]TiKVVolumeSnapshot `json:"snapshots,omitempty"`
+	// Message is the reason why the snapshot set failed
+	Message string `json:"message,omitempty"`
+}
+
+// TiKVVolumeSnapshot is the VolumeSnapshot of the volume of a TiKV store
+type TiKVVolumeSnapshot struct {
+	Name       string `json:"name"`
+	PVCName    string `json:"pvcName"`
+	ReadyToUse bool   `json:"readyToUse,omitempty"`
+}
+
+// TiKVUnsafeRecoveryPhase is the phase of the online unsafe recovery
+type TiKVUnsafeRecoveryPhase string
+
+const (
+	// TiKVUnsafeRecoveryRunning means PD is removing the failed stores from the regions
+	TiKVUnsafeRecoveryRunning TiKVUnsafeRecoveryPhase = "Running"
+	// TiKVUnsafeRecoveryComplete means the failed stores are removed and all regions are healthy
+	TiKVUnsafeRecoveryComplete TiKVUnsafeRecoveryPhase = "Complete"
+	// TiKVUnsafeRecoveryFailed means the recovery is refused or failed
+	TiKVUnsafeRecoveryFailed TiKVUnsafeRecoveryPhase = "Failed"
+)
+
+// TiKVUnsafeRecoveryStatus is the status of the online unsafe recovery
+type TiKVUnsafeRecoveryStatus struct {
+	// Trigger is the value of the annotation which triggers the recovery
+	Trigger string                  `json:"trigger,omitempty"`
+	Phase   TiKVUnsafeRecoveryPhase `json:"phase,omitempty"`
+	// FailedStores are the ids of the stores removed by the recovery
+	FailedStores []string    `json:"failedStores,omitempty"`
+	StartTime    metav1.Time `json:"startTime,omitempty"`
+	// Progress is the latest stage of the recovery reported by PD
+	Progress string `json:"progress,omitempty"`
+	// Message is the reason why the recovery is refused or failed
+	Message            string      `json:"message,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 // TiKVStores is either Up/Down/Offline/Tombstone
@@ -449,6 +831,8 @@ type TiKVStore struct {
 	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime"`
 	// Last time the health transitioned from one to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// MasterKeyGeneration is the generation of the master key the store runs with
+	MasterKeyGeneration int64 `json:"masterKeyGeneration,omitempty"`
 }
 
 // TiKVFailureStore is the tikv failure store information
@@ -456,4 +840,14 @@ type TiKVFailureStore struct {
 	PodName   string      `json:"podName,omitempty"`
 	StoreID   string      `json:"storeID,omitempty"`
 	CreatedAt metav1.Time `json:"createdAt,omitempty"`
+	// Replace is true if the failure store is replaced by a new store of the same pod
+	// instead of a new replica, see .spec.tikv.failover.replaceFailedStore. It is cleared
+	// if the store can't be deleted safely, a new replica is added then.
+	Replace bool `json:"replace,omitempty"`
+	// PVCUID is the UID of the PVC of the failure store to be replaced
+	PVCUID types.UID `json:"pvcUID,omitempty"`
+	// StoreDeleted is true once the failure store to be replaced is deleted from the PD cluster
+	StoreDeleted bool `json:"storeDeleted,omitempty"`
+	// PVCDeleted is true once the PVC and the pod of the tombstone store are deleted
+	PVCDeleted bool `json:"pvcDeleted,omitempty"`
 }
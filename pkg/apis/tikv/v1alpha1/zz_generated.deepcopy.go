@@ -20,7 +20,9 @@ package v1alpha1
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -147,6 +149,11 @@ func (in *DashboardConfig) DeepCopy() *DashboardConfig {
 func (in *DiscoverySpec) DeepCopyInto(out *DiscoverySpec) {
 	*out = *in
 	in.ResourceRequirements.DeepCopyInto(&out.ResourceRequirements)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -160,6 +167,47 @@ func (in *DiscoverySpec) DeepCopy() *DiscoverySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverSpec) DeepCopyInto(out *FailoverSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxCount != nil {
+		in, out := &in.MaxCount, &out.MaxCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReplaceFailedStore != nil {
+		in, out := &in.ReplaceFailedStore, &out.ReplaceFailedStore
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeLossTimeout != nil {
+		in, out := &in.NodeLossTimeout, &out.NodeLossTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverSpec.
+func (in *FailoverSpec) DeepCopy() *FailoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileLogConfig) DeepCopyInto(out *FileLogConfig) {
 	*out = *in
@@ -201,6 +249,48 @@ func (in *FileLogConfig) DeepCopy() *FileLogConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceStatus) DeepCopyInto(out *MaintenanceStatus) {
+	*out = *in
+	if in.NextWindow != nil {
+		in, out := &in.NextWindow, &out.NextWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingOperations != nil {
+		in, out := &in.PendingOperations, &out.PendingOperations
+		*out = make([]DisruptiveOperation, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceStatus.
+func (in *MaintenanceStatus) DeepCopy() *MaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MasterKeyFileConfig) DeepCopyInto(out *MasterKeyFileConfig) {
 	*out = *in
@@ -616,6 +706,47 @@ func (in *PDNamespaceConfig) DeepCopy() *PDNamespaceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PDRecoveryStatus) DeepCopyInto(out *PDRecoveryStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PDRecoveryStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDRecoveryStatus.
+func (in *PDRecoveryStatus) DeepCopy() *PDRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PDRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PDRecoveryStep) DeepCopyInto(out *PDRecoveryStep) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDRecoveryStep.
+func (in *PDRecoveryStep) DeepCopy() *PDRecoveryStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PDRecoveryStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PDReplicationConfig) DeepCopyInto(out *PDReplicationConfig) {
 	*out = *in
@@ -941,6 +1072,11 @@ func (in *PDSpec) DeepCopyInto(out *PDSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(FailoverSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
@@ -956,6 +1092,21 @@ func (in *PDSpec) DeepCopyInto(out *PDSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ExternalEndpoints != nil {
+		in, out := &in.ExternalEndpoints, &out.ExternalEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalTLSClientSecretName != nil {
+		in, out := &in.ExternalTLSClientSecretName, &out.ExternalTLSClientSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -999,6 +1150,11 @@ func (in *PDStatus) DeepCopyInto(out *PDStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(PDRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1060,6 +1216,63 @@ func (in PDStoreLabels) DeepCopy() PDStoreLabels {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
+func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalePolicy) DeepCopyInto(out *ScalePolicy) {
+	*out = *in
+	if in.ScaleOutParallelism != nil {
+		in, out := &in.ScaleOutParallelism, &out.ScaleOutParallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleInParallelism != nil {
+		in, out := &in.ScaleInParallelism, &out.ScaleInParallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleInHighWaterMarkPercent != nil {
+		in, out := &in.ScaleInHighWaterMarkPercent, &out.ScaleInHighWaterMarkPercent
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalePolicy.
+func (in *ScalePolicy) DeepCopy() *ScalePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 	*out = *in
@@ -1543,6 +1756,22 @@ func (in *TiKVCoprocessorReadPoolConfig) DeepCopy() *TiKVCoprocessorReadPoolConf
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVDataSource) DeepCopyInto(out *TiKVDataSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVDataSource.
+func (in *TiKVDataSource) DeepCopy() *TiKVDataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVDataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVDbConfig) DeepCopyInto(out *TiKVDbConfig) {
 	*out = *in
@@ -1704,6 +1933,23 @@ func (in *TiKVDbConfig) DeepCopy() *TiKVDbConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVEncryption) DeepCopyInto(out *TiKVEncryption) {
+	*out = *in
+	out.MasterKeySecretRef = in.MasterKeySecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVEncryption.
+func (in *TiKVEncryption) DeepCopy() *TiKVEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVEncryptionConfig) DeepCopyInto(out *TiKVEncryptionConfig) {
 	*out = *in
@@ -1740,6 +1986,23 @@ func (in *TiKVEncryptionConfig) DeepCopy() *TiKVEncryptionConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVEncryptionStatus) DeepCopyInto(out *TiKVEncryptionStatus) {
+	*out = *in
+	in.LastRotationTime.DeepCopyInto(&out.LastRotationTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVEncryptionStatus.
+func (in *TiKVEncryptionStatus) DeepCopy() *TiKVEncryptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVEncryptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVFailureStore) DeepCopyInto(out *TiKVFailureStore) {
 	*out = *in
@@ -2638,6 +2901,30 @@ func (in *TiKVServerConfig) DeepCopy() *TiKVServerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVSnapshotSet) DeepCopyInto(out *TiKVSnapshotSet) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make(map[string]TiKVVolumeSnapshot, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVSnapshotSet.
+func (in *TiKVSnapshotSet) DeepCopy() *TiKVSnapshotSet {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVSnapshotSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 	*out = *in
@@ -2653,6 +2940,16 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(FailoverSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScalePolicy != nil {
+		in, out := &in.ScalePolicy, &out.ScalePolicy
+		*out = new(ScalePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
@@ -2663,6 +2960,31 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 		*out = new(TiKVConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnsafeRecoveryDownThreshold != nil {
+		in, out := &in.UnsafeRecoveryDownThreshold, &out.UnsafeRecoveryDownThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.DataSource != nil {
+		in, out := &in.DataSource, &out.DataSource
+		*out = new(TiKVDataSource)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(TiKVEncryption)
+		**out = **in
+	}
 	return
 }
 
@@ -2705,6 +3027,30 @@ func (in *TiKVStatus) DeepCopyInto(out *TiKVStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ExternalStores != nil {
+		in, out := &in.ExternalStores, &out.ExternalStores
+		*out = make(map[string]TiKVStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.UnsafeRecovery != nil {
+		in, out := &in.UnsafeRecovery, &out.UnsafeRecovery
+		*out = new(TiKVUnsafeRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotSets != nil {
+		in, out := &in.SnapshotSets, &out.SnapshotSets
+		*out = make(map[string]TiKVSnapshotSet, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(TiKVEncryptionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2986,6 +3332,45 @@ func (in *TiKVUnifiedReadPoolConfig) DeepCopy() *TiKVUnifiedReadPoolConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVUnsafeRecoveryStatus) DeepCopyInto(out *TiKVUnsafeRecoveryStatus) {
+	*out = *in
+	if in.FailedStores != nil {
+		in, out := &in.FailedStores, &out.FailedStores
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVUnsafeRecoveryStatus.
+func (in *TiKVUnsafeRecoveryStatus) DeepCopy() *TiKVUnsafeRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVUnsafeRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVVolumeSnapshot) DeepCopyInto(out *TiKVVolumeSnapshot) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVVolumeSnapshot.
+func (in *TiKVVolumeSnapshot) DeepCopy() *TiKVVolumeSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVVolumeSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TikvCluster) DeepCopyInto(out *TikvCluster) {
 	*out = *in
@@ -3107,6 +3492,11 @@ func (in *TikvClusterSpec) DeepCopyInto(out *TikvClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -3132,6 +3522,11 @@ func (in *TikvClusterStatus) DeepCopyInto(out *TikvClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -14,8 +14,7 @@
 package defaulting
 
 import (
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"k8s.io/utils/pointer"
 )
 
@@ -24,35 +23,23 @@ const (
 	defaultPDImage   = "pingcap/pd"
 )
 
-func SetTikvClusterDefault(tc *v1alpha1.TikvCluster) {
-	setTikvClusterSpecDefault(tc)
+func SetTikvClusterDefault(tc *v1beta1.TikvCluster) {
 	setPdSpecDefault(tc)
 	setTikvSpecDefault(tc)
 }
 
-// setTikvClusterSpecDefault is only managed the property under Spec
-func setTikvClusterSpecDefault(tc *v1alpha1.TikvCluster) {
-	if string(tc.Spec.ImagePullPolicy) == "" {
-		tc.Spec.ImagePullPolicy = corev1.PullIfNotPresent
-	}
-}
-
-func setTikvSpecDefault(tc *v1alpha1.TikvCluster) {
-	if len(tc.Spec.Version) > 0 || tc.Spec.TiKV.Version != nil {
-		if tc.Spec.TiKV.BaseImage == "" {
-			tc.Spec.TiKV.BaseImage = defaultTiKVImage
-		}
+func setTikvSpecDefault(tc *v1beta1.TikvCluster) {
+	if tc.Spec.TiKV.BaseImage == "" {
+		tc.Spec.TiKV.BaseImage = defaultTiKVImage
 	}
 	if tc.Spec.TiKV.MaxFailoverCount == nil {
 		tc.Spec.TiKV.MaxFailoverCount = pointer.Int32Ptr(3)
 	}
 }
 
-func setPdSpecDefault(tc *v1alpha1.TikvCluster) {
-	if len(tc.Spec.Version) > 0 || tc.Spec.PD.Version != nil {
-		if tc.Spec.PD.BaseImage == "" {
-			tc.Spec.PD.BaseImage = defaultPDImage
-		}
+func setPdSpecDefault(tc *v1beta1.TikvCluster) {
+	if tc.Spec.PD.BaseImage == "" {
+		tc.Spec.PD.BaseImage = defaultPDImage
 	}
 	if tc.Spec.PD.MaxFailoverCount == nil {
 		tc.Spec.PD.MaxFailoverCount = pointer.Int32Ptr(3)
@@ -0,0 +1,462 @@
+// Copyright 2019 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/tikv/tikv-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	defaultHelperImage = "busybox:1.26.2"
+	defaultTimeZone    = "UTC"
+
+	defaultTiKVUnsafeRecoveryDownThreshold = time.Hour
+	defaultMaxFailoverCount                = int32(3)
+	defaultScaleParallelism                = int32(1)
+	defaultScaleInHighWaterMarkPercent     = int32(80)
+)
+
+func (tc *TikvCluster) PDImage() string {
+	return componentImage(tc.Spec.PD.BaseImage, tc.Spec.PD.Version)
+}
+
+func (tc *TikvCluster) PDVersion() string {
+	return tc.Spec.PD.Version
+}
+
+func (tc *TikvCluster) TiKVImage() string {
+	return componentImage(tc.Spec.TiKV.BaseImage, tc.Spec.TiKV.Version)
+}
+
+func (tc *TikvCluster) TiKVVersion() string {
+	return tc.Spec.TiKV.Version
+}
+
+// componentImage returns the image of the version, which is either a tag or a digest
+func componentImage(baseImage, version string) string {
+	if strings.Contains(version, ":") {
+		return fmt.Sprintf("%s@%s", baseImage, version)
+	}
+	return fmt.Sprintf("%s:%s", baseImage, version)
+}
+
+func (tc *TikvCluster) GetInstanceName() string {
+	return tc.Name
+}
+
+func (tc *TikvCluster) IsTLSClusterEnabled() bool {
+	return false
+}
+
+func (tc *TikvCluster) Timezone() string {
+	tz := tc.Spec.Timezone
+	if tz == "" {
+		return defaultTimeZone
+	}
+	return tz
+}
+
+func (tc *TikvCluster) PDAllPodsStarted() bool {
+	return tc.PDStsDesiredReplicas() == tc.PDStsActualReplicas()
+}
+
+func (tc *TikvCluster) PDAllMembersReady() bool {
+	if int(tc.PDStsDesiredReplicas()) != len(tc.Status.PD.Members) {
+		return false
+	}
+
+	for _, member := range tc.Status.PD.Members {
+		if !member.Health {
+			return false
+		}
+	}
+	return true
+}
+
+func (tc *TikvCluster) PDAutoFailovering() bool {
+	if len(tc.Status.PD.FailureMembers) == 0 {
+		return false
+	}
+
+	for _, failureMember := range tc.Status.PD.FailureMembers {
+		if !failureMember.MemberDeleted {
+			return true
+		}
+	}
+	return false
+}
+
+func (tc *TikvCluster) PDStsDesiredReplicas() int32 {
+	return tc.Spec.PD.Replicas + int32(len(tc.Status.PD.FailureMembers))
+}
+
+// PDInitialMembers returns the number of PD members expected to bootstrap
+// the cluster, it is the total of the desired replicas of the PD group managed
+// by the operator and the given replicas of the other PD groups joining the
+// cluster, and excludes the replicas added by failover.
+func (tc *TikvCluster) PDInitialMembers(groupReplicas ...int32) int32 {
+	// the PD cluster is bootstrapped by a single member during the recovery
+	if tc.PDRecreatingMember() {
+		return 1
+	}
+	total := tc.Spec.PD.Replicas
+	for _, replicas := range groupReplicas {
+		total += replicas
+	}
+	return total
+}
+
+// PDRecovering returns true if the PD quorum-loss recovery is in progress
+func (tc *TikvCluster) PDRecovering() bool {
+	r := tc.Status.PD.Recovery
+	return r != nil && r.Phase != PDRecoveryComplete && r.Phase != PDRecoveryFailed
+}
+
+// PDRecreatingMember returns true if the PD cluster is being recreated with
+// a single member during the recovery
+func (tc *TikvCluster) PDRecreatingMember() bool {
+	r := tc.Status.PD.Recovery
+	return r != nil && (r.Phase == PDRecoveryPrepare || r.Phase == PDRecoveryRecreateMember)
+}
+
+func (tc *TikvCluster) PDStsActualReplicas() int32 {
+	stsStatus := tc.Status.PD.StatefulSet
+	if stsStatus == nil {
+		return 0
+	}
+	return stsStatus.Replicas
+}
+
+func (tc *TikvCluster) PDStsDesiredOrdinals(excludeFailover bool) sets.Int32 {
+	replicas := tc.Spec.PD.Replicas
+	if !excludeFailover {
+		replicas = tc.PDStsDesiredReplicas()
+	}
+	return helper.GetPodOrdinalsFromReplicasAndDeleteSlots(replicas, tc.getDeleteSlots(label.PDLabelVal))
+}
+
+func (tc *TikvCluster) TiKVAllPodsStarted() bool {
+	return tc.TiKVStsDesiredReplicas() == tc.TiKVStsActualReplicas()
+}
+
+func (tc *TikvCluster) TiKVAllStoresReady() bool {
+	if int(tc.TiKVStsDesiredReplicas()) != len(tc.Status.TiKV.Stores) {
+		return false
+	}
+
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.State != TiKVStateUp {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (tc *TikvCluster) TiKVStsDesiredReplicas() int32 {
+	replicas := tc.Spec.TiKV.Replicas
+	for _, store := range tc.Status.TiKV.FailureStores {
+		// the failure store to be replaced keeps its pod
+		if !store.Replace {
+			replicas++
+		}
+	}
+	return replicas
+}
+
+// TiKVActualReplicasWithoutFailover returns the replicas of the TiKV StatefulSet excluding
+// the ones added by failover
+func (tc *TikvCluster) TiKVActualReplicasWithoutFailover() int32 {
+	replicas := tc.TiKVStsActualReplicas() - (tc.TiKVStsDesiredReplicas() - tc.Spec.TiKV.Replicas)
+	if replicas < 0 {
+		return 0
+	}
+	return replicas
+}
+
+func (tc *TikvCluster) TiKVStsActualReplicas() int32 {
+	stsStatus := tc.Status.TiKV.StatefulSet
+	if stsStatus == nil {
+		return 0
+	}
+	return stsStatus.Replicas
+}
+
+func (tc *TikvCluster) TiKVStsDesiredOrdinals(excludeFailover bool) sets.Int32 {
+	replicas := tc.Spec.TiKV.Replicas
+	if !excludeFailover {
+		replicas = tc.TiKVStsDesiredReplicas()
+	}
+	return helper.GetPodOrdinalsFromReplicasAndDeleteSlots(replicas, tc.getDeleteSlots(label.TiKVLabelVal))
+}
+
+func (tc *TikvCluster) getDeleteSlots(component string) (deleteSlots sets.Int32) {
+	deleteSlots = sets.NewInt32()
+	annotations := tc.GetAnnotations()
+	if annotations == nil {
+		return deleteSlots
+	}
+	var key string
+	if component == label.PDLabelVal {
+		key = label.AnnPDDeleteSlots
+	} else if component == label.TiKVLabelVal {
+		key = label.AnnTiKVDeleteSlots
+	} else {
+		return
+	}
+	value, ok := annotations[key]
+	if !ok {
+		return
+	}
+	var slice []int32
+	err := json.Unmarshal([]byte(value), &slice)
+	if err != nil {
+		return
+	}
+	deleteSlots.Insert(slice...)
+	return
+}
+
+func (tc *TikvCluster) Scheme() string {
+	if tc.IsTLSClusterEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+func (tc *TikvCluster) PDUpgrading() bool {
+	return tc.Status.PD.Phase == UpgradePhase
+}
+
+func (tc *TikvCluster) TiKVUpgrading() bool {
+	return tc.Status.TiKV.Phase == UpgradePhase
+}
+
+// PDFailoverEnabled returns whether the failover of PD is enabled, defaultEnabled is
+// the --auto-failover flag of the controller manager
+func (tc *TikvCluster) PDFailoverEnabled(defaultEnabled bool) bool {
+	return failoverEnabled(tc.Spec.PD.Failover, defaultEnabled)
+}
+
+// PDFailoverPeriod returns the duration a PD member must have been unhealthy before it is
+// failed over, defaultPeriod is the --pd-failover-period flag of the controller manager
+func (tc *TikvCluster) PDFailoverPeriod(defaultPeriod time.Duration) time.Duration {
+	return failoverPeriod(tc.Spec.PD.Failover, defaultPeriod)
+}
+
+// PDMaxFailoverCount returns the max replicas could be added in the failover of PD
+func (tc *TikvCluster) PDMaxFailoverCount() int32 {
+	return maxFailoverCount(tc.Spec.PD.Failover, tc.Spec.PD.MaxFailoverCount)
+}
+
+// TiKVFailoverEnabled returns whether the failover of TiKV is enabled, defaultEnabled is
+// the --auto-failover flag of the controller manager
+func (tc *TikvCluster) TiKVFailoverEnabled(defaultEnabled bool) bool {
+	return failoverEnabled(tc.Spec.TiKV.Failover, defaultEnabled)
+}
+
+// TiKVFailoverPeriod returns the duration a TiKV store must have been Down before it is
+// failed over, defaultPeriod is the --tikv-failover-period flag of the controller manager
+func (tc *TikvCluster) TiKVFailoverPeriod(defaultPeriod time.Duration) time.Duration {
+	return failoverPeriod(tc.Spec.TiKV.Failover, defaultPeriod)
+}
+
+// TiKVMaxFailoverCount returns the max replicas could be added in the failover of TiKV
+func (tc *TikvCluster) TiKVMaxFailoverCount() int32 {
+	return maxFailoverCount(tc.Spec.TiKV.Failover, tc.Spec.TiKV.MaxFailoverCount)
+}
+
+// TiKVReplaceFailedStore returns whether the failed TiKV stores are replaced by new stores
+// of the same pods instead of new replicas
+func (tc *TikvCluster) TiKVReplaceFailedStore() bool {
+	spec := tc.Spec.TiKV.Failover
+	return spec != nil && spec.ReplaceFailedStore != nil && *spec.ReplaceFailedStore
+}
+
+// PDNodeLossTimeout returns the duration a PD pod must have been unschedulable because the
+// node of its local PV is gone before its PVC is deleted, 0 means never
+func (tc *TikvCluster) PDNodeLossTimeout() time.Duration {
+	return nodeLossTimeout(tc.Spec.PD.Failover)
+}
+
+// TiKVNodeLossTimeout returns the duration a TiKV pod must have been unschedulable because
+// the node of its local PV is gone before its PVC is deleted, 0 means never
+func (tc *TikvCluster) TiKVNodeLossTimeout() time.Duration {
+	return nodeLossTimeout(tc.Spec.TiKV.Failover)
+}
+
+func nodeLossTimeout(spec *FailoverSpec) time.Duration {
+	if spec == nil || spec.NodeLossTimeout == nil {
+		return 0
+	}
+	return spec.NodeLossTimeout.Duration
+}
+
+func failoverEnabled(spec *FailoverSpec, defaultEnabled bool) bool {
+	if spec == nil || spec.Enabled == nil {
+		return defaultEnabled
+	}
+	return *spec.Enabled
+}
+
+func failoverPeriod(spec *FailoverSpec, defaultPeriod time.Duration) time.Duration {
+	if spec == nil || spec.Period == nil {
+		return defaultPeriod
+	}
+	return spec.Period.Duration
+}
+
+// maxFailoverCount prefers the failover policy to the deprecated maxFailoverCount
+func maxFailoverCount(spec *FailoverSpec, deprecated *int32) int32 {
+	if spec != nil && spec.MaxCount != nil {
+		return *spec.MaxCount
+	}
+	if deprecated != nil {
+		return *deprecated
+	}
+	return defaultMaxFailoverCount
+}
+
+// TiKVScaleOutParallelism returns the max number of TiKV members added at a time
+func (tc *TikvCluster) TiKVScaleOutParallelism() int32 {
+	if tc.Spec.TiKV.ScalePolicy == nil || tc.Spec.TiKV.ScalePolicy.ScaleOutParallelism == nil {
+		return defaultScaleParallelism
+	}
+	return *tc.Spec.TiKV.ScalePolicy.ScaleOutParallelism
+}
+
+// TiKVScaleInParallelism returns the max number of TiKV members removed at a time
+func (tc *TikvCluster) TiKVScaleInParallelism() int32 {
+	if tc.Spec.TiKV.ScalePolicy == nil || tc.Spec.TiKV.ScalePolicy.ScaleInParallelism == nil {
+		return defaultScaleParallelism
+	}
+	return *tc.Spec.TiKV.ScalePolicy.ScaleInParallelism
+}
+
+// TiKVScaleInHighWaterMarkPercent returns the max used ratio in percent of the capacity of
+// the remaining Up stores after scaling in TiKV
+func (tc *TikvCluster) TiKVScaleInHighWaterMarkPercent() int32 {
+	if tc.Spec.TiKV.ScalePolicy == nil || tc.Spec.TiKV.ScalePolicy.ScaleInHighWaterMarkPercent == nil {
+		return defaultScaleInHighWaterMarkPercent
+	}
+	return *tc.Spec.TiKV.ScalePolicy.ScaleInHighWaterMarkPercent
+}
+
+// TiKVUnsafeRecoveryDownThreshold returns the minimum duration the failure stores must
+// have been Down before they are removed by the online unsafe recovery
+func (tc *TikvCluster) TiKVUnsafeRecoveryDownThreshold() time.Duration {
+	if tc.Spec.TiKV.UnsafeRecoveryDownThreshold == nil {
+		return defaultTiKVUnsafeRecoveryDownThreshold
+	}
+	return tc.Spec.TiKV.UnsafeRecoveryDownThreshold.Duration
+}
+
+// TiKVUnsafeRecovering returns true if the online unsafe recovery is in progress
+func (tc *TikvCluster) TiKVUnsafeRecovering() bool {
+	r := tc.Status.TiKV.UnsafeRecovery
+	return r != nil && r.Phase == TiKVUnsafeRecoveryRunning
+}
+
+// TiKVSnapshotSetCreating returns the name of the snapshot set being created, or empty
+func (tc *TikvCluster) TiKVSnapshotSetCreating() string {
+	for name, set := range tc.Status.TiKV.SnapshotSets {
+		if set.Phase == TiKVSnapshotSetCreating {
+			return name
+		}
+	}
+	return ""
+}
+
+// IsTiKVCloned returns true if the TiKV volumes are cloned from a snapshot set
+func (tc *TikvCluster) IsTiKVCloned() bool {
+	return tc.Spec.TiKV.DataSource != nil
+}
+
+// TiKVMasterKeyRotated returns true if all the stores run with the current master key
+func (tc *TikvCluster) TiKVMasterKeyRotated() bool {
+	e := tc.Status.TiKV.Encryption
+	if e == nil {
+		return true
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.MasterKeyGeneration != e.KeyGeneration {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPDExternal returns true if the TikvCluster joins an external PD cluster
+// instead of managing the PD members itself
+func (tc *TikvCluster) IsPDExternal() bool {
+	return len(tc.Spec.PD.ExternalEndpoints) > 0
+}
+
+// IsPDExternalTLSEnabled returns true if the external PD cluster is accessed over https
+func (tc *TikvCluster) IsPDExternalTLSEnabled() bool {
+	return tc.IsPDExternal() && tc.Spec.PD.ExternalTLSClientSecretName != nil && *tc.Spec.PD.ExternalTLSClientSecretName != ""
+}
+
+func (tc *TikvCluster) PDIsAvailable() bool {
+	// the availability of the external PD cluster is unknown, TiKV is synced
+	// anyway and fails on the requests to PD if it is not available
+	if tc.IsPDExternal() {
+		return true
+	}
+	lowerLimit := tc.Spec.PD.Replicas/2 + 1
+	if int32(len(tc.Status.PD.Members)) < lowerLimit {
+		return false
+	}
+
+	var availableNum int32
+	for _, pdMember := range tc.Status.PD.Members {
+		if pdMember.Health {
+			availableNum++
+		}
+	}
+
+	if availableNum < lowerLimit {
+		return false
+	}
+
+	if tc.Status.PD.StatefulSet == nil || tc.Status.PD.StatefulSet.ReadyReplicas < lowerLimit {
+		return false
+	}
+
+	return true
+}
+
+func (tc *TikvCluster) HelperImage() string {
+	return defaultHelperImage
+}
+
+func (tc *TikvCluster) HelperImagePullPolicy() corev1.PullPolicy {
+	return defaultImagePullPolicy
+}
+
+func (tc *TikvCluster) TiKVContainerPrivilege() *bool {
+	if tc.Spec.TiKV.Privileged == nil {
+		pri := false
+		return &pri
+	}
+	return tc.Spec.TiKV.Privileged
+}
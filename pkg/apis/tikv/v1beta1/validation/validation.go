@@ -0,0 +1,442 @@
+// Copyright 2019 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateTikvCluster validates a TikvCluster, it performs basic validation for all TikvClusters despite it is legacy
+// or not
+func ValidateTikvCluster(tc *v1beta1.TikvCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// validate metadata
+	fldPath := field.NewPath("metadata")
+	// validate metadata/annotations
+	allErrs = append(allErrs, validateAnnotations(tc.ObjectMeta.Annotations, fldPath.Child("annotations"))...)
+	// validate spec
+	allErrs = append(allErrs, validateTiKVClusterSpec(&tc.Spec, field.NewPath("spec"))...)
+	return allErrs
+}
+
+func validateAnnotations(anns map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(anns, fldPath)...)
+	return allErrs
+}
+
+func validateTiKVClusterSpec(spec *v1beta1.TikvClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validatePDSpec(&spec.PD, fldPath.Child("pd"))...)
+	allErrs = append(allErrs, validateTiKVSpec(&spec.TiKV, fldPath.Child("tikv"))...)
+	allErrs = append(allErrs, validateTiKVDataSource(spec, fldPath.Child("tikv", "dataSource"))...)
+	allErrs = append(allErrs, validateTiKVEncryption(&spec.TiKV, fldPath.Child("tikv"))...)
+	allErrs = append(allErrs, validateMaintenanceWindows(spec.MaintenanceWindows, fldPath.Child("maintenanceWindows"))...)
+	allErrs = append(allErrs, validateTimezone(spec.Timezone, fldPath.Child("timezone"))...)
+	return allErrs
+}
+
+// validateTimezone validates that the time zone can be loaded, the maintenance windows
+// are scheduled in it
+func validateTimezone(tz string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if tz == "" {
+		return allErrs
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, tz, err.Error()))
+	}
+	return allErrs
+}
+
+// validateMaintenanceWindows validates the cron schedules and the durations of the windows,
+// the windows must start at fixed times so @every is not allowed
+func validateMaintenanceWindows(windows []v1beta1.MaintenanceWindow, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, window := range windows {
+		idxPath := fldPath.Index(i)
+		if strings.HasPrefix(window.Schedule, "@every") {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("schedule"), window.Schedule, "@every is not supported"))
+		} else if _, err := cron.ParseStandard(window.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("schedule"), window.Schedule, err.Error()))
+		}
+		allErrs = append(allErrs, validatePositiveDuration(&window.Duration, idxPath.Child("duration"))...)
+	}
+	return allErrs
+}
+
+// validateTiKVEncryption validates the master key Secret, the master key in the config
+// would be overridden by it
+func validateTiKVEncryption(spec *v1beta1.TiKVSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.Encryption == nil {
+		return allErrs
+	}
+	if spec.Encryption.MasterKeySecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("encryption", "masterKeySecretRef", "name"), "the master key secret must be specified"))
+	}
+	if spec.Config != nil && spec.Config.Security != nil && spec.Config.Security.Encryption != nil &&
+		(spec.Config.Security.Encryption.MasterKey != nil || spec.Config.Security.Encryption.PreviousMasterKey != nil) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("config", "security", "encryption"), "the master keys can't be configured with spec.tikv.encryption"))
+	}
+	return allErrs
+}
+
+// validateTiKVDataSource validates the snapshot set to clone from, the PD cluster
+// must be managed to recover the ids of the source cluster
+func validateTiKVDataSource(spec *v1beta1.TikvClusterSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	ds := spec.TiKV.DataSource
+	if ds == nil {
+		return allErrs
+	}
+	if ds.ClusterName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clusterName"), "the cluster to clone from must be specified"))
+	}
+	if ds.SnapshotSet == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("snapshotSet"), "the snapshot set to clone from must be specified"))
+	}
+	if len(spec.PD.ExternalEndpoints) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "can't clone with an external PD cluster"))
+	}
+	return allErrs
+}
+
+func validatePDSpec(spec *v1beta1.PDSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(spec.ExternalEndpoints) > 0 {
+		// the PD members are not managed by the TikvCluster
+		return validatePDExternalEndpoints(spec, fldPath)
+	}
+	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateRequestsStorage(spec.ResourceRequirements.Requests, fldPath)...)
+	allErrs = append(allErrs, validatePodDisruptionBudgetSpec(spec.PodDisruptionBudget, fldPath.Child("podDisruptionBudget"))...)
+	allErrs = append(allErrs, validateFailoverSpec(spec.Failover, fldPath.Child("failover"))...)
+	if spec.Failover != nil && spec.Failover.ReplaceFailedStore != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("failover", "replaceFailedStore"), "only supported by TiKV, the failed PD members are always replaced"))
+	}
+	return allErrs
+}
+
+// validatePDExternalEndpoints validates the client urls of the external PD cluster
+func validatePDExternalEndpoints(spec *v1beta1.PDSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	endpointsPath := fldPath.Child("externalEndpoints")
+	var scheme string
+	for i, endpoint := range spec.ExternalEndpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(endpointsPath.Index(i), endpoint, err.Error()))
+			continue
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			allErrs = append(allErrs, field.Invalid(endpointsPath.Index(i), endpoint, "scheme must be http or https"))
+			continue
+		}
+		if u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(endpointsPath.Index(i), endpoint, "host must not be empty"))
+			continue
+		}
+		if scheme != "" && u.Scheme != scheme {
+			allErrs = append(allErrs, field.Invalid(endpointsPath.Index(i), endpoint, "all endpoints must use the same scheme"))
+			continue
+		}
+		scheme = u.Scheme
+	}
+	hasSecret := spec.ExternalTLSClientSecretName != nil && *spec.ExternalTLSClientSecretName != ""
+	if scheme == "https" && !hasSecret {
+		allErrs = append(allErrs, field.Required(fldPath.Child("externalTLSClientSecretName"), "secret must be set to access the external PD over https"))
+	}
+	if scheme == "http" && hasSecret {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("externalTLSClientSecretName"), *spec.ExternalTLSClientSecretName, "secret must not be set to access the external PD over http"))
+	}
+	return allErrs
+}
+
+func validateTiKVSpec(spec *v1beta1.TiKVSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateComponentSpec(&spec.ComponentSpec, fldPath)...)
+	allErrs = append(allErrs, validateRequestsStorage(spec.ResourceRequirements.Requests, fldPath)...)
+	allErrs = append(allErrs, validatePodDisruptionBudgetSpec(spec.PodDisruptionBudget, fldPath.Child("podDisruptionBudget"))...)
+	allErrs = append(allErrs, validatePositiveDuration(spec.UnsafeRecoveryDownThreshold, fldPath.Child("unsafeRecoveryDownThreshold"))...)
+	allErrs = append(allErrs, validateFailoverSpec(spec.Failover, fldPath.Child("failover"))...)
+	allErrs = append(allErrs, validateScalePolicy(spec.ScalePolicy, fldPath.Child("scalePolicy"))...)
+	return allErrs
+}
+
+// validateScalePolicy validates the parallelism and the high-water mark if they're set
+func validateScalePolicy(policy *v1beta1.ScalePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy == nil {
+		return allErrs
+	}
+	if p := policy.ScaleOutParallelism; p != nil && *p < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleOutParallelism"), *p, "must be greater than 0"))
+	}
+	if p := policy.ScaleInParallelism; p != nil && *p < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleInParallelism"), *p, "must be greater than 0"))
+	}
+	if p := policy.ScaleInHighWaterMarkPercent; p != nil && (*p < 1 || *p > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scaleInHighWaterMarkPercent"), *p, "must be between 1 and 100"))
+	}
+	return allErrs
+}
+
+// validateFailoverSpec validates the period and the max count of the failover policy
+func validateFailoverSpec(spec *v1beta1.FailoverSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, validatePositiveDuration(spec.Period, fldPath.Child("period"))...)
+	if spec.MaxCount != nil && *spec.MaxCount < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxCount"), *spec.MaxCount, "must be greater than or equal to 0"))
+	}
+	if spec.NodeLossTimeout != nil && spec.NodeLossTimeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeLossTimeout"), spec.NodeLossTimeout.Duration.String(), "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+// validatePositiveDuration validates the duration is greater than 0 if it's set
+func validatePositiveDuration(d *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if d != nil && d.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, d.Duration.String(), "must be greater than 0"))
+	}
+	return allErrs
+}
+
+// validatePodDisruptionBudgetSpec validates the maxUnavailable of the PodDisruptionBudget
+func validatePodDisruptionBudgetSpec(spec *v1beta1.PodDisruptionBudgetSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec == nil || spec.MaxUnavailable == nil {
+		return allErrs
+	}
+	maxUnavailable := spec.MaxUnavailable
+	if _, err := intstr.GetValueFromIntOrPercent(maxUnavailable, 100, false); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), maxUnavailable.String(), err.Error()))
+	} else if maxUnavailable.Type == intstr.Int && maxUnavailable.IntVal < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), maxUnavailable.String(), "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+func validateComponentSpec(spec *v1beta1.ComponentSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// TODO validate other fields
+	allErrs = append(allErrs, validateEnv(spec.Env, fldPath.Child("env"))...)
+	return allErrs
+}
+
+// validateRequestsStorage validates resources requests storage
+func validateRequestsStorage(requests corev1.ResourceList, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if _, ok := requests[corev1.ResourceStorage]; !ok {
+		allErrs = append(allErrs, field.Required(fldPath.Child("requests.storage").Key((string(corev1.ResourceStorage))), "storage request must not be empty"))
+	}
+	return allErrs
+}
+
+// validateEnv validates env vars
+func validateEnv(vars []corev1.EnvVar, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, ev := range vars {
+		idxPath := fldPath.Index(i)
+		if len(ev.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else {
+			for _, msg := range validation.IsEnvVarName(ev.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), ev.Name, msg))
+			}
+		}
+		allErrs = append(allErrs, validateEnvVarValueFrom(ev, idxPath.Child("valueFrom"))...)
+	}
+	return allErrs
+}
+
+func validateEnvVarValueFrom(ev corev1.EnvVar, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ev.ValueFrom == nil {
+		return allErrs
+	}
+
+	numSources := 0
+
+	if ev.ValueFrom.FieldRef != nil {
+		numSources++
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("fieldRef"), "", "fieldRef is not supported"))
+	}
+	if ev.ValueFrom.ResourceFieldRef != nil {
+		numSources++
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("resourceFieldRef"), "", "resourceFieldRef is not supported"))
+	}
+	if ev.ValueFrom.ConfigMapKeyRef != nil {
+		numSources++
+		allErrs = append(allErrs, validateConfigMapKeySelector(ev.ValueFrom.ConfigMapKeyRef, fldPath.Child("configMapKeyRef"))...)
+	}
+	if ev.ValueFrom.SecretKeyRef != nil {
+		numSources++
+		allErrs = append(allErrs, validateSecretKeySelector(ev.ValueFrom.SecretKeyRef, fldPath.Child("secretKeyRef"))...)
+	}
+
+	if numSources == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "must specify one of: `configMapKeyRef` or `secretKeyRef`"))
+	} else if len(ev.Value) != 0 {
+		if numSources != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, "", "may not be specified when `value` is not empty"))
+		}
+	} else if numSources > 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "may not have more than one field specified at a time"))
+	}
+
+	return allErrs
+}
+
+func validateConfigMapKeySelector(s *corev1.ConfigMapKeySelector, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, msg := range apivalidation.NameIsDNSSubdomain(s.Name, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), s.Name, msg))
+	}
+	if len(s.Key) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("key"), ""))
+	} else {
+		for _, msg := range validation.IsConfigMapKey(s.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("key"), s.Key, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateSecretKeySelector(s *corev1.SecretKeySelector, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, msg := range apivalidation.NameIsDNSSubdomain(s.Name, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), s.Name, msg))
+	}
+	if len(s.Key) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("key"), ""))
+	} else {
+		for _, msg := range validation.IsConfigMapKey(s.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("key"), s.Key, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateCreateTikvCLuster validates a newly created TikvCluster
+func ValidateCreateTikvCluster(tc *v1beta1.TikvCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// basic validation
+	allErrs = append(allErrs, ValidateTikvCluster(tc)...)
+	allErrs = append(allErrs, validateNewTikvClusterSpec(&tc.Spec, field.NewPath("spec"))...)
+	return allErrs
+}
+
+// ValidateUpdateTikvCluster validates a new TikvCluster against an existing TikvCluster to be updated
+func ValidateUpdateTikvCluster(old, tc *v1beta1.TikvCluster) field.ErrorList {
+
+	allErrs := field.ErrorList{}
+	// basic validation
+	allErrs = append(allErrs, ValidateTikvCluster(tc)...)
+	allErrs = append(allErrs, validateUpdatePDConfig(old.Spec.PD.Config, tc.Spec.PD.Config, field.NewPath("spec.pd.config"))...)
+	allErrs = append(allErrs, disallowUsingLegacyAPIInNewCluster(old, tc)...)
+
+	return allErrs
+}
+
+// For now we limit some validations only in Create phase to keep backward compatibility
+func validateNewTikvClusterSpec(spec *v1beta1.TikvClusterSpec, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.PD.Version == "" && len(spec.PD.ExternalEndpoints) == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("pd.version"), spec.PD.Version, "version of PD must not be empty"))
+	}
+	if spec.TiKV.Version == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("tikv.version"), spec.TiKV.Version, "version of TiKV must not be empty"))
+	}
+	if spec.PD.BaseImage == "" && len(spec.PD.ExternalEndpoints) == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("pd.baseImage"), spec.PD.BaseImage, "baseImage of PD must not be empty"))
+	}
+	if spec.TiKV.BaseImage == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("tikv.baseImage"), spec.TiKV.BaseImage, "baseImage of TiKV must not be empty"))
+	}
+	return allErrs
+}
+
+// disallowUsingLegacyAPIInNewCluster checks if user use the legacy API in newly create cluster during update
+// TODO(aylei): this could be removed after we enable validateTikvCluster() in update, which is more strict
+func disallowUsingLegacyAPIInNewCluster(old, tc *v1beta1.TikvCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	path := field.NewPath("spec")
+	if old.Spec.PD.Version != "" && tc.Spec.PD.Version == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("pd.version"), tc.Spec.PD.Version, "version of PD must not be empty"))
+	}
+	if old.Spec.TiKV.Version != "" && tc.Spec.TiKV.Version == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("tikv.version"), tc.Spec.TiKV.Version, "version of TiKV must not be empty"))
+	}
+	if old.Spec.PD.BaseImage != "" && tc.Spec.PD.BaseImage == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("pd.baseImage"), tc.Spec.PD.BaseImage, "baseImage of PD must not be empty"))
+	}
+	if old.Spec.TiKV.BaseImage != "" && tc.Spec.TiKV.BaseImage == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("tikv.baseImage"), tc.Spec.TiKV.BaseImage, "baseImage of TiKV must not be empty"))
+	}
+	if old.Spec.TiKV.Config != nil && tc.Spec.TiKV.Config == nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("tikv.config"), tc.Spec.TiKV.Config, "TiKV.config must not be nil"))
+	}
+	if old.Spec.PD.Config != nil && tc.Spec.PD.Config == nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("pd.config"), tc.Spec.PD.Config, "PD.config must not be nil"))
+	}
+	return allErrs
+}
+
+func validateUpdatePDConfig(old, conf *v1beta1.PDConfig, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// for newly created cluster, both old and new are non-nil, guaranteed by validation
+	if old == nil || conf == nil {
+		return allErrs
+	}
+
+	if conf.Security != nil && len(conf.Security.CertAllowedCN) > 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("security.cert-allowed-cn"), conf.Security.CertAllowedCN,
+			"Only one CN is currently supported"))
+	}
+
+	if !reflect.DeepEqual(old.Schedule, conf.Schedule) {
+		allErrs = append(allErrs, field.Invalid(path.Child("schedule"), conf.Schedule,
+			"PD Schedule Config is immutable through CRD, please modify with pd-ctl instead."))
+	}
+	if !reflect.DeepEqual(old.Replication, conf.Replication) {
+		allErrs = append(allErrs, field.Invalid(path.Child("replication"), conf.Replication,
+			"PD Replication Config is immutable through CRD, please modify with pd-ctl instead."))
+	}
+	return allErrs
+}
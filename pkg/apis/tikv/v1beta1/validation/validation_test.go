@@ -0,0 +1,487 @@
+// Copyright 2019 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+)
+
+func TestValidateRequestsStorage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name                 string
+		haveRequest          bool
+		resourceRequirements corev1.ResourceRequirements
+		expectedErrors       int
+	}{
+		{
+			name:        "has request storage",
+			haveRequest: true,
+			resourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("10G"),
+				},
+			},
+			expectedErrors: 0,
+		},
+		{
+			name:        "Empty request storage",
+			haveRequest: false,
+			resourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{},
+			},
+			expectedErrors: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTikvCluster()
+			if tt.haveRequest {
+				tc.Spec.PD.ResourceRequirements = tt.resourceRequirements
+				tc.Spec.TiKV.ResourceRequirements = tt.resourceRequirements
+			}
+			err := ValidateTikvCluster(tc)
+			r := len(err)
+			g.Expect(r).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidatePodDisruptionBudgetSpec(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		spec           *v1beta1.PodDisruptionBudgetSpec
+		expectedErrors int
+	}{
+		{
+			name:           "not set",
+			spec:           nil,
+			expectedErrors: 0,
+		},
+		{
+			name:           "integer maxUnavailable",
+			spec:           &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: intstrPtr(intstr.FromInt(1))},
+			expectedErrors: 0,
+		},
+		{
+			name:           "percent maxUnavailable",
+			spec:           &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: intstrPtr(intstr.FromString("30%"))},
+			expectedErrors: 0,
+		},
+		{
+			name:           "negative maxUnavailable",
+			spec:           &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: intstrPtr(intstr.FromInt(-1))},
+			expectedErrors: 1,
+		},
+		{
+			name:           "invalid maxUnavailable",
+			spec:           &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: intstrPtr(intstr.FromString("one"))},
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePodDisruptionBudgetSpec(tt.spec, field.NewPath("spec", "pd", "podDisruptionBudget"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidatePositiveDuration(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		duration       *metav1.Duration
+		expectedErrors int
+	}{
+		{
+			name:           "not set",
+			duration:       nil,
+			expectedErrors: 0,
+		},
+		{
+			name:           "positive",
+			duration:       &metav1.Duration{Duration: time.Hour},
+			expectedErrors: 0,
+		},
+		{
+			name:           "zero",
+			duration:       &metav1.Duration{},
+			expectedErrors: 1,
+		},
+		{
+			name:           "negative",
+			duration:       &metav1.Duration{Duration: -time.Minute},
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePositiveDuration(tt.duration, field.NewPath("spec", "tikv", "unsafeRecoveryDownThreshold"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidateFailoverSpec(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		spec           *v1beta1.FailoverSpec
+		expectedErrors int
+	}{
+		{
+			name:           "not set",
+			spec:           nil,
+			expectedErrors: 0,
+		},
+		{
+			name: "valid",
+			spec: &v1beta1.FailoverSpec{
+				Enabled:  pointer.BoolPtr(true),
+				Period:   &metav1.Duration{Duration: 10 * time.Minute},
+				MaxCount: pointer.Int32Ptr(0),
+			},
+			expectedErrors: 0,
+		},
+		{
+			name:           "zero node loss timeout",
+			spec:           &v1beta1.FailoverSpec{NodeLossTimeout: &metav1.Duration{}},
+			expectedErrors: 0,
+		},
+		{
+			name:           "zero period",
+			spec:           &v1beta1.FailoverSpec{Period: &metav1.Duration{}},
+			expectedErrors: 1,
+		},
+		{
+			name: "negative period and max count",
+			spec: &v1beta1.FailoverSpec{
+				Period:   &metav1.Duration{Duration: -time.Minute},
+				MaxCount: pointer.Int32Ptr(-1),
+			},
+			expectedErrors: 2,
+		},
+		{
+			name:           "negative node loss timeout",
+			spec:           &v1beta1.FailoverSpec{NodeLossTimeout: &metav1.Duration{Duration: -time.Minute}},
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFailoverSpec(tt.spec, field.NewPath("spec", "tikv", "failover"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidateReplaceFailedStore(t *testing.T) {
+	g := NewGomegaWithT(t)
+	failover := &v1beta1.FailoverSpec{ReplaceFailedStore: pointer.BoolPtr(true)}
+	requests := corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}
+
+	pd := &v1beta1.PDSpec{Failover: failover}
+	pd.Requests = requests
+	errs := validatePDSpec(pd, field.NewPath("spec", "pd"))
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.pd.failover.replaceFailedStore"))
+
+	tikv := &v1beta1.TiKVSpec{Failover: failover}
+	tikv.Requests = requests
+	g.Expect(validateTiKVSpec(tikv, field.NewPath("spec", "tikv"))).To(BeEmpty())
+}
+
+func TestValidateScalePolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		policy         *v1beta1.ScalePolicy
+		expectedErrors int
+	}{
+		{
+			name:           "not set",
+			policy:         nil,
+			expectedErrors: 0,
+		},
+		{
+			name: "valid",
+			policy: &v1beta1.ScalePolicy{
+				ScaleOutParallelism: pointer.Int32Ptr(5),
+				ScaleInParallelism:  pointer.Int32Ptr(1),
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "zero parallelism",
+			policy: &v1beta1.ScalePolicy{
+				ScaleOutParallelism: pointer.Int32Ptr(0),
+				ScaleInParallelism:  pointer.Int32Ptr(-1),
+			},
+			expectedErrors: 2,
+		},
+		{
+			name:           "valid high-water mark",
+			policy:         &v1beta1.ScalePolicy{ScaleInHighWaterMarkPercent: pointer.Int32Ptr(100)},
+			expectedErrors: 0,
+		},
+		{
+			name:           "high-water mark over 100",
+			policy:         &v1beta1.ScalePolicy{ScaleInHighWaterMarkPercent: pointer.Int32Ptr(101)},
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScalePolicy(tt.policy, field.NewPath("spec", "tikv", "scalePolicy"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidateMaintenanceWindows(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		windows        []v1beta1.MaintenanceWindow
+		expectedErrors int
+	}{
+		{
+			name:           "not set",
+			windows:        nil,
+			expectedErrors: 0,
+		},
+		{
+			name: "valid",
+			windows: []v1beta1.MaintenanceWindow{
+				{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+				{Schedule: "@daily", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			expectedErrors: 0,
+		},
+		{
+			name: "invalid schedule",
+			windows: []v1beta1.MaintenanceWindow{
+				{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				{Schedule: "@every 1h", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			expectedErrors: 2,
+		},
+		{
+			name: "zero duration",
+			windows: []v1beta1.MaintenanceWindow{
+				{Schedule: "0 2 * * *"},
+			},
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMaintenanceWindows(tt.windows, field.NewPath("spec", "maintenanceWindows"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		tz             string
+		expectedErrors int
+	}{
+		{
+			name:           "not set",
+			tz:             "",
+			expectedErrors: 0,
+		},
+		{
+			name:           "UTC",
+			tz:             "UTC",
+			expectedErrors: 0,
+		},
+		{
+			name:           "unknown",
+			tz:             "Mars/Olympus_Mons",
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTimezone(tt.tz, field.NewPath("spec", "timezone"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidatePDExternalEndpoints(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		endpoints      []string
+		tlsSecretName  *string
+		expectedErrors int
+	}{
+		{
+			name:           "http endpoints",
+			endpoints:      []string{"http://10.0.1.1:2379", "http://10.0.1.2:2379"},
+			expectedErrors: 0,
+		},
+		{
+			name:           "https endpoints with secret",
+			endpoints:      []string{"https://pd.example.org:2379"},
+			tlsSecretName:  pointer.StringPtr("external-pd-client"),
+			expectedErrors: 0,
+		},
+		{
+			name:           "https endpoints without secret",
+			endpoints:      []string{"https://pd.example.org:2379"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "http endpoints with secret",
+			endpoints:      []string{"http://10.0.1.1:2379"},
+			tlsSecretName:  pointer.StringPtr("external-pd-client"),
+			expectedErrors: 1,
+		},
+		{
+			name:           "endpoint without scheme",
+			endpoints:      []string{"10.0.1.1:2379"},
+			expectedErrors: 1,
+		},
+		{
+			name:           "mixed schemes",
+			endpoints:      []string{"http://10.0.1.1:2379", "https://10.0.1.2:2379"},
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &v1beta1.PDSpec{
+				ExternalEndpoints:           tt.endpoints,
+				ExternalTLSClientSecretName: tt.tlsSecretName,
+			}
+			err := validatePDSpec(spec, field.NewPath("spec", "pd"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidateTiKVDataSource(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		dataSource     *v1beta1.TiKVDataSource
+		pdEndpoints    []string
+		expectedErrors int
+	}{
+		{
+			name:           "no data source",
+			expectedErrors: 0,
+		},
+		{
+			name:           "valid data source",
+			dataSource:     &v1beta1.TiKVDataSource{ClusterName: "src", SnapshotSet: "s1"},
+			expectedErrors: 0,
+		},
+		{
+			name:           "empty data source",
+			dataSource:     &v1beta1.TiKVDataSource{},
+			expectedErrors: 2,
+		},
+		{
+			name:           "external pd",
+			dataSource:     &v1beta1.TiKVDataSource{ClusterName: "src", SnapshotSet: "s1"},
+			pdEndpoints:    []string{"http://10.0.1.1:2379"},
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &v1beta1.TikvClusterSpec{}
+			spec.TiKV.DataSource = tt.dataSource
+			spec.PD.ExternalEndpoints = tt.pdEndpoints
+			err := validateTiKVDataSource(spec, field.NewPath("spec", "tikv", "dataSource"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func TestValidateTiKVEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tests := []struct {
+		name           string
+		encryption     *v1beta1.TiKVEncryption
+		config         *v1beta1.TiKVConfig
+		expectedErrors int
+	}{
+		{
+			name:           "no encryption",
+			expectedErrors: 0,
+		},
+		{
+			name:           "valid encryption",
+			encryption:     &v1beta1.TiKVEncryption{MasterKeySecretRef: corev1.LocalObjectReference{Name: "key"}},
+			expectedErrors: 0,
+		},
+		{
+			name:           "no secret name",
+			encryption:     &v1beta1.TiKVEncryption{},
+			expectedErrors: 1,
+		},
+		{
+			name:       "master key configured",
+			encryption: &v1beta1.TiKVEncryption{MasterKeySecretRef: corev1.LocalObjectReference{Name: "key"}},
+			config: &v1beta1.TiKVConfig{
+				Security: &v1beta1.TiKVSecurityConfig{
+					Encryption: &v1beta1.TiKVSecurityConfigEncryption{
+						MasterKey: &v1beta1.TiKVSecurityConfigEncryptionMasterKey{},
+					},
+				},
+			},
+			expectedErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &v1beta1.TiKVSpec{}
+			spec.Encryption = tt.encryption
+			spec.Config = tt.config
+			err := validateTiKVEncryption(spec, field.NewPath("spec", "tikv"))
+			g.Expect(len(err)).Should(Equal(tt.expectedErrors))
+		})
+	}
+}
+
+func intstrPtr(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
+
+func newTikvCluster() *v1beta1.TikvCluster {
+	tc := &v1beta1.TikvCluster{}
+	tc.Name = "test-validate-requests-storage"
+	tc.Namespace = "default"
+	return tc
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	tikvv1alpha1 "github.com/tikv/tikv-operator/pkg/client/clientset/versioned/typed/tikv/v1alpha1"
+	tikvv1beta1 "github.com/tikv/tikv-operator/pkg/client/clientset/versioned/typed/tikv/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -27,6 +28,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	TikvV1alpha1() tikvv1alpha1.TikvV1alpha1Interface
+	TikvV1beta1() tikvv1beta1.TikvV1beta1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -34,6 +36,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	tikvV1alpha1 *tikvv1alpha1.TikvV1alpha1Client
+	tikvV1beta1  *tikvv1beta1.TikvV1beta1Client
 }
 
 // TikvV1alpha1 retrieves the TikvV1alpha1Client
@@ -41,6 +44,11 @@ func (c *Clientset) TikvV1alpha1() tikvv1alpha1.TikvV1alpha1Interface {
 	return c.tikvV1alpha1
 }
 
+// TikvV1beta1 retrieves the TikvV1beta1Client
+func (c *Clientset) TikvV1beta1() tikvv1beta1.TikvV1beta1Interface {
+	return c.tikvV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -66,6 +74,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.tikvV1beta1, err = tikvv1beta1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -79,6 +91,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.tikvV1alpha1 = tikvv1alpha1.NewForConfigOrDie(c)
+	cs.tikvV1beta1 = tikvv1beta1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -88,6 +101,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.tikvV1alpha1 = tikvv1alpha1.New(c)
+	cs.tikvV1beta1 = tikvv1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -19,6 +19,8 @@ import (
 	clientset "github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
 	tikvv1alpha1 "github.com/tikv/tikv-operator/pkg/client/clientset/versioned/typed/tikv/v1alpha1"
 	faketikvv1alpha1 "github.com/tikv/tikv-operator/pkg/client/clientset/versioned/typed/tikv/v1alpha1/fake"
+	tikvv1beta1 "github.com/tikv/tikv-operator/pkg/client/clientset/versioned/typed/tikv/v1beta1"
+	faketikvv1beta1 "github.com/tikv/tikv-operator/pkg/client/clientset/versioned/typed/tikv/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -77,3 +79,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) TikvV1alpha1() tikvv1alpha1.TikvV1alpha1Interface {
 	return &faketikvv1alpha1.FakeTikvV1alpha1{Fake: &c.Fake}
 }
+
+// TikvV1beta1 retrieves the TikvV1beta1Client
+func (c *Clientset) TikvV1beta1() tikvv1beta1.TikvV1beta1Interface {
+	return &faketikvv1beta1.FakeTikvV1beta1{Fake: &c.Fake}
+}
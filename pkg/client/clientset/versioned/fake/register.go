@@ -17,6 +17,7 @@ package fake
 
 import (
 	tikvv1alpha1 "github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	tikvv1beta1 "github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -29,6 +30,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 var parameterCodec = runtime.NewParameterCodec(scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	tikvv1alpha1.AddToScheme,
+	tikvv1beta1.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
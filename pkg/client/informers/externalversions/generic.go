@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	v1beta1 "github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -53,6 +54,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1alpha1.SchemeGroupVersion.WithResource("tikvclusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Tikv().V1alpha1().TikvClusters().Informer()}, nil
 
+		// Group=tikv.org, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("tikvclusters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Tikv().V1beta1().TikvClusters().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
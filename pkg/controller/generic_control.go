@@ -23,6 +23,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -55,6 +56,8 @@ type TypedControlInterface interface {
 	CreateOrUpdatePVC(controller runtime.Object, pvc *corev1.PersistentVolumeClaim, setOwnerFlag bool) (*corev1.PersistentVolumeClaim, error)
 	// CreateOrUpdateIngress create the desired ingress or update the current one to desired state if already existed
 	CreateOrUpdateIngress(controller runtime.Object, ingress *extensionsv1beta1.Ingress) (*extensionsv1beta1.Ingress, error)
+	// CreateOrUpdatePodDisruptionBudget create the desired pdb or update the current one to desired state if already existed
+	CreateOrUpdatePodDisruptionBudget(controller runtime.Object, pdb *policyv1beta1.PodDisruptionBudget) (*policyv1beta1.PodDisruptionBudget, error)
 	// UpdateStatus update the /status subresource of the object
 	UpdateStatus(newStatus runtime.Object) error
 	// Delete delete the given object from the cluster
@@ -333,6 +336,21 @@ func (w *typedWrapper) CreateOrUpdateIngress(controller runtime.Object, ingress
 	return result.(*extensionsv1beta1.Ingress), nil
 }
 
+func (w *typedWrapper) CreateOrUpdatePodDisruptionBudget(controller runtime.Object, pdb *policyv1beta1.PodDisruptionBudget) (*policyv1beta1.PodDisruptionBudget, error) {
+	result, err := w.GenericControlInterface.CreateOrUpdate(controller, pdb, func(existing, desired runtime.Object) error {
+		existingPDB := existing.(*policyv1beta1.PodDisruptionBudget)
+		desiredPDB := desired.(*policyv1beta1.PodDisruptionBudget)
+
+		existingPDB.Labels = desiredPDB.Labels
+		existingPDB.Spec = desiredPDB.Spec
+		return nil
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	return result.(*policyv1beta1.PodDisruptionBudget), nil
+}
+
 func (w *typedWrapper) Create(controller, obj runtime.Object) error {
 	return w.GenericControlInterface.Create(controller, obj, true)
 }
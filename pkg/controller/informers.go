@@ -0,0 +1,164 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"time"
+
+	"github.com/tikv/tikv-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NewKubeInformerFactory returns a kube informer factory that only caches the objects
+// managed by tikv-operator in the namespace, the namespace is empty for all namespaces.
+// The cluster-scoped objects are cached by the factory of NewClusterKubeInformerFactory,
+// which is shared by the namespaces.
+func NewKubeInformerFactory(kubeCli kubernetes.Interface, resync time.Duration, namespace string) kubeinformers.SharedInformerFactory {
+	managedBy := label.Label{label.ManagedByLabelKey: label.TiKVOperator}
+	return kubeinformers.NewSharedInformerFactoryWithOptions(kubeCli, resync,
+		kubeinformers.WithNamespace(namespace),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = managedBy.String()
+		}))
+}
+
+// NewClusterKubeInformerFactory returns a kube informer factory of the cluster-scoped
+// objects, i.e. the PVs and the nodes, for the namespaces watched by tikv-operator, all
+// namespaces are watched if namespaces is empty. The PVs are filtered by the namespace
+// label set by the meta manager, the nodes are not labeled by tikv-operator so they are
+// not filtered.
+func NewClusterKubeInformerFactory(kubeCli kubernetes.Interface, resync time.Duration, namespaces []string) kubeinformers.SharedInformerFactory {
+	factory := kubeinformers.NewSharedInformerFactory(kubeCli, resync)
+
+	pvSelector := labels.SelectorFromSet(labels.Set{label.ManagedByLabelKey: label.TiKVOperator})
+	nsReq, err := labels.NewRequirement(label.NamespaceLabelKey, selection.Exists, nil)
+	if len(namespaces) > 0 {
+		nsReq, err = labels.NewRequirement(label.NamespaceLabelKey, selection.In, namespaces)
+	}
+	if err == nil {
+		pvSelector = pvSelector.Add(*nsReq)
+	}
+	factory.InformerFor(&corev1.PersistentVolume{}, func(cli kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+		return coreinformers.NewFilteredPersistentVolumeInformer(cli, resync,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+			func(options *metav1.ListOptions) {
+				options.LabelSelector = pvSelector.String()
+			})
+	})
+	factory.InformerFor(&corev1.Node{}, func(cli kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+		return coreinformers.NewNodeInformer(cli, resync,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	})
+	return factory
+}
+
+// fallbackPVLister gets the PVs missing in the cache from the api server,
+// a PV is only cached after it is labeled by the meta manager.
+type fallbackPVLister struct {
+	corelisters.PersistentVolumeLister
+	kubeCli kubernetes.Interface
+}
+
+// NewFallbackPVLister returns a PersistentVolumeLister that falls back to the api server
+// for the PVs that are not cached by the label-filtered informer yet
+func NewFallbackPVLister(kubeCli kubernetes.Interface, lister corelisters.PersistentVolumeLister) corelisters.PersistentVolumeLister {
+	return &fallbackPVLister{PersistentVolumeLister: lister, kubeCli: kubeCli}
+}
+
+func (l *fallbackPVLister) Get(name string) (*corev1.PersistentVolume, error) {
+	pv, err := l.PersistentVolumeLister.Get(name)
+	if errors.IsNotFound(err) {
+		return l.kubeCli.CoreV1().PersistentVolumes().Get(name, metav1.GetOptions{})
+	}
+	return pv, err
+}
+
+// fallbackPVCLister gets the PVCs missing in the cache from the api server,
+// the PVCs only get the labels of the StatefulSet selector, the ones created otherwise are not cached
+type fallbackPVCLister struct {
+	corelisters.PersistentVolumeClaimLister
+	kubeCli kubernetes.Interface
+}
+
+// NewFallbackPVCLister returns a PersistentVolumeClaimLister that falls back to the api server
+// for the PVCs that are not cached by the label-filtered informer
+func NewFallbackPVCLister(kubeCli kubernetes.Interface, lister corelisters.PersistentVolumeClaimLister) corelisters.PersistentVolumeClaimLister {
+	return &fallbackPVCLister{PersistentVolumeClaimLister: lister, kubeCli: kubeCli}
+}
+
+func (l *fallbackPVCLister) PersistentVolumeClaims(namespace string) corelisters.PersistentVolumeClaimNamespaceLister {
+	return &fallbackPVCNamespaceLister{
+		PersistentVolumeClaimNamespaceLister: l.PersistentVolumeClaimLister.PersistentVolumeClaims(namespace),
+		kubeCli:                              l.kubeCli,
+		namespace:                            namespace,
+	}
+}
+
+type fallbackPVCNamespaceLister struct {
+	corelisters.PersistentVolumeClaimNamespaceLister
+	kubeCli   kubernetes.Interface
+	namespace string
+}
+
+func (l *fallbackPVCNamespaceLister) Get(name string) (*corev1.PersistentVolumeClaim, error) {
+	pvc, err := l.PersistentVolumeClaimNamespaceLister.Get(name)
+	if errors.IsNotFound(err) {
+		return l.kubeCli.CoreV1().PersistentVolumeClaims(l.namespace).Get(name, metav1.GetOptions{})
+	}
+	return pvc, err
+}
+
+// fallbackEndpointsLister gets the Endpoints missing in the cache from the api server,
+// the labels of an Endpoints are copied from its Service by the endpoints controller
+type fallbackEndpointsLister struct {
+	corelisters.EndpointsLister
+	kubeCli kubernetes.Interface
+}
+
+// NewFallbackEndpointsLister returns an EndpointsLister that falls back to the api server
+// for the Endpoints that are not cached by the label-filtered informer
+func NewFallbackEndpointsLister(kubeCli kubernetes.Interface, lister corelisters.EndpointsLister) corelisters.EndpointsLister {
+	return &fallbackEndpointsLister{EndpointsLister: lister, kubeCli: kubeCli}
+}
+
+func (l *fallbackEndpointsLister) Endpoints(namespace string) corelisters.EndpointsNamespaceLister {
+	return &fallbackEndpointsNamespaceLister{
+		EndpointsNamespaceLister: l.EndpointsLister.Endpoints(namespace),
+		kubeCli:                  l.kubeCli,
+		namespace:                namespace,
+	}
+}
+
+type fallbackEndpointsNamespaceLister struct {
+	corelisters.EndpointsNamespaceLister
+	kubeCli   kubernetes.Interface
+	namespace string
+}
+
+func (l *fallbackEndpointsNamespaceLister) Get(name string) (*corev1.Endpoints, error) {
+	eps, err := l.EndpointsNamespaceLister.Get(name)
+	if errors.IsNotFound(err) {
+		return l.kubeCli.CoreV1().Endpoints(l.namespace).Get(name, metav1.GetOptions{})
+	}
+	return eps, err
+}
@@ -0,0 +1,235 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNewKubeInformerFactory(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	managed := map[string]string{label.ManagedByLabelKey: label.TiKVOperator}
+	pvLabels := func(ns string) map[string]string {
+		return map[string]string{label.ManagedByLabelKey: label.TiKVOperator, label.NamespaceLabelKey: ns}
+	}
+	kubeCli := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "a", Labels: managed}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "a"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "b", Labels: managed}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-a", Labels: pvLabels("a")}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-b", Labels: pvLabels("b")}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-unbound", Labels: managed}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-other"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	)
+
+	type testcase struct {
+		name       string
+		namespaces []string
+		pods       []string
+		pvs        []string
+	}
+	tests := []testcase{
+		{
+			name:       "all namespaces",
+			namespaces: nil,
+			pods:       []string{"a/managed", "b/managed"},
+			pvs:        []string{"pv-a", "pv-b"},
+		},
+		{
+			name:       "namespace a",
+			namespaces: []string{"a"},
+			pods:       []string{"a/managed"},
+			pvs:        []string{"pv-a"},
+		},
+		{
+			name:       "namespaces a and b",
+			namespaces: []string{"a", "b"},
+			pods:       []string{"a/managed", "b/managed"},
+			pvs:        []string{"pv-a", "pv-b"},
+		},
+	}
+	for _, test := range tests {
+		t.Log(test.name)
+		stopCh := make(chan struct{})
+		podNames := []string{}
+		namespaces := test.namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{metav1.NamespaceAll}
+		}
+		for _, ns := range namespaces {
+			factory := NewKubeInformerFactory(kubeCli, 0, ns)
+			podLister := factory.Core().V1().Pods().Lister()
+			factory.Start(stopCh)
+			for _, synced := range factory.WaitForCacheSync(stopCh) {
+				g.Expect(synced).To(BeTrue())
+			}
+			pods, err := podLister.List(labels.Everything())
+			g.Expect(err).NotTo(HaveOccurred())
+			for _, pod := range pods {
+				podNames = append(podNames, pod.Namespace+"/"+pod.Name)
+			}
+		}
+		g.Expect(podNames).To(ConsistOf(test.pods))
+
+		clusterFactory := NewClusterKubeInformerFactory(kubeCli, 0, test.namespaces)
+		pvLister := clusterFactory.Core().V1().PersistentVolumes().Lister()
+		nodeLister := clusterFactory.Core().V1().Nodes().Lister()
+		clusterFactory.Start(stopCh)
+		for _, synced := range clusterFactory.WaitForCacheSync(stopCh) {
+			g.Expect(synced).To(BeTrue())
+		}
+
+		pvs, err := pvLister.List(labels.Everything())
+		g.Expect(err).NotTo(HaveOccurred())
+		pvNames := []string{}
+		for _, pv := range pvs {
+			pvNames = append(pvNames, pv.Name)
+		}
+		g.Expect(pvNames).To(ConsistOf(test.pvs))
+
+		nodes, err := nodeLister.List(labels.Everything())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(nodes).To(HaveLen(1))
+		close(stopCh)
+	}
+}
+
+func TestFallbackListers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	kubeCli := fake.NewSimpleClientset(
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: "a"}},
+		&corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: "a"}},
+	)
+	factory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	pvInformer := factory.Core().V1().PersistentVolumes()
+	pvcInformer := factory.Core().V1().PersistentVolumeClaims()
+	epsInformer := factory.Core().V1().Endpoints()
+	pvInformer.Informer().GetIndexer().Add(&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "cached"}})
+	pvcInformer.Informer().GetIndexer().Add(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "cached", Namespace: "a"}})
+	epsInformer.Informer().GetIndexer().Add(&corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "cached", Namespace: "a"}})
+
+	pvLister := NewFallbackPVLister(kubeCli, pvInformer.Lister())
+	pvcLister := NewFallbackPVCLister(kubeCli, pvcInformer.Lister())
+	epsLister := NewFallbackEndpointsLister(kubeCli, epsInformer.Lister())
+
+	for _, name := range []string{"cached", "unlabeled"} {
+		pv, err := pvLister.Get(name)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(pv.Name).To(Equal(name))
+		pvc, err := pvcLister.PersistentVolumeClaims("a").Get(name)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(pvc.Name).To(Equal(name))
+		eps, err := epsLister.Endpoints("a").Get(name)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(eps.Name).To(Equal(name))
+	}
+
+	_, err := pvLister.Get("missing")
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	_, err = pvcLister.PersistentVolumeClaims("b").Get("unlabeled")
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	_, err = epsLister.Endpoints("b").Get("unlabeled")
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// List only returns the cached objects
+	pvs, err := pvLister.List(labels.Everything())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pvs).To(HaveLen(1))
+}
+
+// benchmarkKubeCli returns a clientset with 10 managed pods and PVs in the namespace,
+// among the pods and PVs of other applications
+func benchmarkKubeCli(others int) kubernetes.Interface {
+	objects := []runtime.Object{}
+	for i := 0; i < 10; i++ {
+		l := map[string]string{label.ManagedByLabelKey: label.TiKVOperator, label.NamespaceLabelKey: "tikv"}
+		objects = append(objects,
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("tikv-%d", i), Namespace: "tikv", Labels: l}},
+			&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pv-tikv-%d", i), Labels: l}},
+		)
+	}
+	for i := 0; i < others; i++ {
+		objects = append(objects,
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("app-%d", i), Namespace: "tikv"}},
+			&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pv-app-%d", i)}},
+		)
+	}
+	return fake.NewSimpleClientset(objects...)
+}
+
+// BenchmarkKubeInformerFactory compares the objects cached by the informers of the namespace
+// with and without the label filter, run with -benchmem to compare the memory allocated
+func BenchmarkKubeInformerFactory(b *testing.B) {
+	kubeCli := benchmarkKubeCli(1000)
+	// the informers of the pods and the PVs, and the factories to start
+	type informerSet struct {
+		pods      cache.SharedIndexInformer
+		pvs       cache.SharedIndexInformer
+		factories []kubeinformers.SharedInformerFactory
+	}
+	factories := map[string]func() informerSet{
+		"Unfiltered": func() informerSet {
+			factory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeCli, 0, kubeinformers.WithNamespace("tikv"))
+			return informerSet{
+				pods:      factory.Core().V1().Pods().Informer(),
+				pvs:       factory.Core().V1().PersistentVolumes().Informer(),
+				factories: []kubeinformers.SharedInformerFactory{factory},
+			}
+		},
+		"Filtered": func() informerSet {
+			factory := NewKubeInformerFactory(kubeCli, 0, "tikv")
+			clusterFactory := NewClusterKubeInformerFactory(kubeCli, 0, []string{"tikv"})
+			return informerSet{
+				pods:      factory.Core().V1().Pods().Informer(),
+				pvs:       clusterFactory.Core().V1().PersistentVolumes().Informer(),
+				factories: []kubeinformers.SharedInformerFactory{factory, clusterFactory},
+			}
+		},
+	}
+	for _, name := range []string{"Unfiltered", "Filtered"} {
+		newInformers := factories[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			cached := 0
+			for i := 0; i < b.N; i++ {
+				informers := newInformers()
+				stopCh := make(chan struct{})
+				for _, factory := range informers.factories {
+					factory.Start(stopCh)
+					factory.WaitForCacheSync(stopCh)
+				}
+				cached = len(informers.pods.GetStore().ListKeys()) + len(informers.pvs.GetStore().ListKeys())
+				close(stopCh)
+			}
+			b.ReportMetric(float64(cached), "objects")
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+)
+
+// NodeControlInterface manages the Nodes which host TikvCluster pods
+type NodeControlInterface interface {
+	// UpdateNodeAnnotations updates the annotations of the given node
+	UpdateNodeAnnotations(*corev1.Node) (*corev1.Node, error)
+}
+
+type realNodeControl struct {
+	kubeCli    kubernetes.Interface
+	nodeLister corelisters.NodeLister
+}
+
+// NewRealNodeControl creates a new NodeControlInterface
+func NewRealNodeControl(kubeCli kubernetes.Interface, nodeLister corelisters.NodeLister) NodeControlInterface {
+	return &realNodeControl{
+		kubeCli:    kubeCli,
+		nodeLister: nodeLister,
+	}
+}
+
+func (rnc *realNodeControl) UpdateNodeAnnotations(node *corev1.Node) (*corev1.Node, error) {
+	nodeName := node.GetName()
+	annotations := node.GetAnnotations()
+
+	var updateNode *corev1.Node
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var updateErr error
+		updateNode, updateErr = rnc.kubeCli.CoreV1().Nodes().Update(node)
+		if updateErr == nil {
+			klog.V(4).Infof("update node %s with annotations %v successfully", nodeName, annotations)
+			return nil
+		}
+		klog.Errorf("failed to update node %s with annotations %v, err: %v", nodeName, annotations, updateErr)
+
+		if updated, err := rnc.nodeLister.Get(nodeName); err == nil {
+			// make a copy so we don't mutate the shared cache
+			node = updated.DeepCopy()
+			node.Annotations = annotations
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated node %s from lister: %v", nodeName, err))
+		}
+		return updateErr
+	})
+	return updateNode, err
+}
+
+var _ NodeControlInterface = &realNodeControl{}
+
+// FakeNodeControl is a fake NodeControlInterface
+type FakeNodeControl struct {
+	NodeIndexer       cache.Indexer
+	updateNodeTracker RequestTracker
+}
+
+// NewFakeNodeControl returns a FakeNodeControl
+func NewFakeNodeControl(nodeInformer coreinformers.NodeInformer) *FakeNodeControl {
+	return &FakeNodeControl{
+		nodeInformer.Informer().GetIndexer(),
+		RequestTracker{},
+	}
+}
+
+// SetUpdateNodeError sets the error attributes of updateNodeTracker
+func (fnc *FakeNodeControl) SetUpdateNodeError(err error, after int) {
+	fnc.updateNodeTracker.SetError(err).SetAfter(after)
+}
+
+// UpdateNodeAnnotations updates the node in the indexer
+func (fnc *FakeNodeControl) UpdateNodeAnnotations(node *corev1.Node) (*corev1.Node, error) {
+	defer fnc.updateNodeTracker.Inc()
+	if fnc.updateNodeTracker.ErrorReady() {
+		defer fnc.updateNodeTracker.Reset()
+		return nil, fnc.updateNodeTracker.GetError()
+	}
+
+	return node, fnc.NodeIndexer.Update(node)
+}
+
+var _ NodeControlInterface = &FakeNodeControl{}
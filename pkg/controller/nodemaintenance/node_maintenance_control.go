@@ -0,0 +1,298 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodemaintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+)
+
+// ControlInterface implements the control logic for evicting the TiKV region
+// leaders from the nodes which are under maintenance.
+type ControlInterface interface {
+	// Sync begins or ends the leader eviction of the TiKV stores on the node
+	Sync(*corev1.Node) error
+}
+
+// NewDefaultNodeMaintenanceControl returns a new instance of the default implementation of ControlInterface.
+func NewDefaultNodeMaintenanceControl(
+	pdControl pdapi.PDControlInterface,
+	nodeControl controller.NodeControlInterface,
+	tcLister listers.TikvClusterLister,
+	podLister corelisters.PodLister,
+	maintenanceTaintKey string,
+) ControlInterface {
+	return &defaultNodeMaintenanceControl{
+		pdControl:           pdControl,
+		nodeControl:         nodeControl,
+		tcLister:            tcLister,
+		podLister:           podLister,
+		maintenanceTaintKey: maintenanceTaintKey,
+	}
+}
+
+type defaultNodeMaintenanceControl struct {
+	pdControl           pdapi.PDControlInterface
+	nodeControl         controller.NodeControlInterface
+	tcLister            listers.TikvClusterLister
+	podLister           corelisters.PodLister
+	maintenanceTaintKey string
+}
+
+// evictingStore is a TiKV store whose leaders are evicted because of node maintenance
+type evictingStore struct {
+	namespace string
+	tcName    string
+	storeID   string
+}
+
+func (s evictingStore) String() string {
+	return fmt.Sprintf("%s/%s/%s", s.namespace, s.tcName, s.storeID)
+}
+
+func (c *defaultNodeMaintenanceControl) Sync(node *corev1.Node) error {
+	if c.underMaintenance(node) {
+		return c.beginMaintenance(node)
+	}
+	return c.endMaintenance(node)
+}
+
+// underMaintenance returns true if the node is cordoned or has the maintenance taint
+func (c *defaultNodeMaintenanceControl) underMaintenance(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == c.maintenanceTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// beginMaintenance evicts the leaders of all TiKV stores on the node, and marks the node
+// ready for drain once no leader is left on these stores.
+func (c *defaultNodeMaintenanceControl) beginMaintenance(node *corev1.Node) error {
+	nodeName := node.GetName()
+	stores := getEvictingStores(node)
+	recorded := sets.NewString()
+	for _, store := range stores {
+		recorded.Insert(store.String())
+	}
+
+	podStores, err := c.getStoresOnNode(nodeName)
+	if err != nil {
+		return err
+	}
+	for _, store := range podStores {
+		if recorded.Has(store.String()) {
+			continue
+		}
+		tc, err := c.tcLister.TikvClusters(store.namespace).Get(store.tcName)
+		if err != nil {
+			return err
+		}
+		if err := c.beginEvictLeader(tc, store.storeID); err != nil {
+			return err
+		}
+		klog.Infof("node maintenance: begin evict leader of store %s on node %s", store, nodeName)
+		stores = append(stores, store)
+		recorded.Insert(store.String())
+	}
+	if len(stores) == 0 {
+		// no TiKV store is hosted on this node
+		return nil
+	}
+
+	pending := 0
+	for _, store := range stores {
+		evicted, err := c.leadersEvicted(store)
+		if err != nil {
+			return err
+		}
+		if !evicted {
+			pending++
+		}
+	}
+
+	updated := node.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[label.AnnNodeEvictLeaderStores] = strings.Join(recorded.List(), ",")
+	if pending == 0 {
+		updated.Annotations[label.AnnNodeReadyForDrain] = label.AnnNodeReadyForDrainVal
+	}
+	if !annotationsEqual(node, updated) {
+		if _, err := c.nodeControl.UpdateNodeAnnotations(updated); err != nil {
+			return err
+		}
+	}
+
+	if pending > 0 {
+		return controller.RequeueErrorf("node %s: waiting for the leaders of %d TiKV store(s) to be evicted", nodeName, pending)
+	}
+	return nil
+}
+
+// endMaintenance stops the leader eviction of the stores recorded on the node
+// and removes the maintenance annotations from the node.
+func (c *defaultNodeMaintenanceControl) endMaintenance(node *corev1.Node) error {
+	_, evicting := node.Annotations[label.AnnNodeEvictLeaderStores]
+	_, ready := node.Annotations[label.AnnNodeReadyForDrain]
+	if !evicting && !ready {
+		return nil
+	}
+
+	for _, store := range getEvictingStores(node) {
+		tc, err := c.tcLister.TikvClusters(store.namespace).Get(store.tcName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		storeID, err := strconv.ParseUint(store.storeID, 10, 64)
+		if err != nil {
+			klog.Errorf("node maintenance: invalid store id %s recorded on node %s, skipping", store, node.GetName())
+			continue
+		}
+		if err := controller.GetPDClient(c.pdControl, tc).EndEvictLeader(storeID); err != nil {
+			klog.Errorf("node maintenance: failed to end evict leader of store %s on node %s, %v", store, node.GetName(), err)
+			return err
+		}
+		klog.Infof("node maintenance: end evict leader of store %s on node %s", store, node.GetName())
+	}
+
+	updated := node.DeepCopy()
+	delete(updated.Annotations, label.AnnNodeEvictLeaderStores)
+	delete(updated.Annotations, label.AnnNodeReadyForDrain)
+	_, err := c.nodeControl.UpdateNodeAnnotations(updated)
+	return err
+}
+
+// getStoresOnNode returns the TiKV stores whose pods are scheduled to the node
+func (c *defaultNodeMaintenanceControl) getStoresOnNode(nodeName string) ([]evictingStore, error) {
+	selector, err := label.New().TiKV().Selector()
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.podLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	stores := []evictingStore{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		ns := pod.GetNamespace()
+		tcName := pod.Labels[label.InstanceLabelKey]
+		tc, err := c.tcLister.TikvClusters(ns).Get(tcName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		storeID := pod.Labels[label.StoreIDLabelKey]
+		if storeID == "" {
+			for _, store := range tc.Status.TiKV.Stores {
+				if store.PodName == pod.GetName() {
+					storeID = store.ID
+					break
+				}
+			}
+		}
+		if storeID == "" {
+			klog.V(4).Infof("node maintenance: tikv pod %s/%s has no store yet, skipping", ns, pod.GetName())
+			continue
+		}
+		stores = append(stores, evictingStore{namespace: ns, tcName: tcName, storeID: storeID})
+	}
+	return stores, nil
+}
+
+func (c *defaultNodeMaintenanceControl) beginEvictLeader(tc *v1beta1.TikvCluster, id string) error {
+	storeID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return err
+	}
+	err = controller.GetPDClient(c.pdControl, tc).BeginEvictLeader(storeID)
+	if err != nil {
+		klog.Errorf("node maintenance: failed to begin evict leader: %d, TikvCluster: %s/%s, %v",
+			storeID, tc.GetNamespace(), tc.GetName(), err)
+	}
+	return err
+}
+
+// leadersEvicted returns true if the store has no region leader left, the
+// store is treated as evicted if it does not exist any more.
+func (c *defaultNodeMaintenanceControl) leadersEvicted(store evictingStore) (bool, error) {
+	tc, err := c.tcLister.TikvClusters(store.namespace).Get(store.tcName)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	status, ok := tc.Status.TiKV.Stores[store.storeID]
+	if !ok {
+		return true, nil
+	}
+	return status.LeaderCount == 0, nil
+}
+
+// getEvictingStores parses the stores recorded in the node annotation
+func getEvictingStores(node *corev1.Node) []evictingStore {
+	val := node.Annotations[label.AnnNodeEvictLeaderStores]
+	if val == "" {
+		return nil
+	}
+	stores := []evictingStore{}
+	for _, s := range strings.Split(val, ",") {
+		parts := strings.Split(s, "/")
+		if len(parts) != 3 {
+			klog.Errorf("node maintenance: invalid store %q recorded on node %s, skipping", s, node.GetName())
+			continue
+		}
+		stores = append(stores, evictingStore{namespace: parts[0], tcName: parts[1], storeID: parts[2]})
+	}
+	return stores
+}
+
+func annotationsEqual(a, b *corev1.Node) bool {
+	if len(a.Annotations) != len(b.Annotations) {
+		return false
+	}
+	for k, v := range a.Annotations {
+		if bv, ok := b.Annotations[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
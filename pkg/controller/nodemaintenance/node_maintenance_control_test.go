@@ -0,0 +1,279 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodemaintenance
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNodeMaintenanceControlSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name              string
+		updateNode        func(*corev1.Node)
+		updateTC          func(*v1beta1.TikvCluster)
+		beginEvictErr     bool
+		endEvictErr       bool
+		errExpectFn       func(*GomegaWithT, error)
+		expectFn          func(*GomegaWithT, *corev1.Node)
+		expectBeginEvicts int
+		expectEndEvicts   int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTikvClusterForNodeMaintenance()
+		if test.updateTC != nil {
+			test.updateTC(tc)
+		}
+		node := newNode()
+		if test.updateNode != nil {
+			test.updateNode(node)
+		}
+
+		control, pdClient, tcIndexer, podIndexer, nodeIndexer := newFakeNodeMaintenanceControl(tc)
+		tcIndexer.Add(tc)
+		podIndexer.Add(newTiKVPod(tc, "node-1"))
+		nodeIndexer.Add(node)
+
+		beginEvicts, endEvicts := 0, 0
+		pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			beginEvicts++
+			if test.beginEvictErr {
+				return nil, fmt.Errorf("begin evict leader failed")
+			}
+			return nil, nil
+		})
+		pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			endEvicts++
+			if test.endEvictErr {
+				return nil, fmt.Errorf("end evict leader failed")
+			}
+			return nil, nil
+		})
+
+		err := control.Sync(node)
+		test.errExpectFn(g, err)
+		g.Expect(beginEvicts).To(Equal(test.expectBeginEvicts))
+		g.Expect(endEvicts).To(Equal(test.expectEndEvicts))
+
+		obj, exist, err := nodeIndexer.GetByKey(node.GetName())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(exist).To(BeTrue())
+		test.expectFn(g, obj.(*corev1.Node))
+	}
+
+	tests := []testcase{
+		{
+			name:        "node is schedulable",
+			errExpectFn: expectNoError,
+			expectFn: func(g *GomegaWithT, node *corev1.Node) {
+				g.Expect(node.Annotations).NotTo(HaveKey(label.AnnNodeEvictLeaderStores))
+				g.Expect(node.Annotations).NotTo(HaveKey(label.AnnNodeReadyForDrain))
+			},
+		},
+		{
+			name: "node is cordoned, leaders not evicted",
+			updateNode: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, node *corev1.Node) {
+				g.Expect(node.Annotations[label.AnnNodeEvictLeaderStores]).To(Equal("default/test/1"))
+				g.Expect(node.Annotations).NotTo(HaveKey(label.AnnNodeReadyForDrain))
+			},
+			expectBeginEvicts: 1,
+		},
+		{
+			name: "node is tainted for maintenance, leaders not evicted",
+			updateNode: func(node *corev1.Node) {
+				node.Spec.Taints = []corev1.Taint{{Key: label.NodeMaintenanceTaintKey, Effect: corev1.TaintEffectNoSchedule}}
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, node *corev1.Node) {
+				g.Expect(node.Annotations[label.AnnNodeEvictLeaderStores]).To(Equal("default/test/1"))
+			},
+			expectBeginEvicts: 1,
+		},
+		{
+			name: "node is cordoned, begin evict leader failed",
+			updateNode: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+			},
+			beginEvictErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeFalse())
+			},
+			expectFn: func(g *GomegaWithT, node *corev1.Node) {
+				g.Expect(node.Annotations).NotTo(HaveKey(label.AnnNodeEvictLeaderStores))
+			},
+			expectBeginEvicts: 1,
+		},
+		{
+			name: "node is cordoned, leaders evicted",
+			updateNode: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+				node.Annotations = map[string]string{label.AnnNodeEvictLeaderStores: "default/test/1"}
+			},
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				store := tc.Status.TiKV.Stores["1"]
+				store.LeaderCount = 0
+				tc.Status.TiKV.Stores["1"] = store
+			},
+			errExpectFn: expectNoError,
+			expectFn: func(g *GomegaWithT, node *corev1.Node) {
+				g.Expect(node.Annotations[label.AnnNodeReadyForDrain]).To(Equal(label.AnnNodeReadyForDrainVal))
+			},
+		},
+		{
+			name: "node is cordoned, recorded store is gone",
+			updateNode: func(node *corev1.Node) {
+				node.Spec.Unschedulable = true
+				node.Annotations = map[string]string{label.AnnNodeEvictLeaderStores: "default/test/1,default/test/2"}
+			},
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				store := tc.Status.TiKV.Stores["1"]
+				store.LeaderCount = 0
+				tc.Status.TiKV.Stores["1"] = store
+			},
+			errExpectFn: expectNoError,
+			expectFn: func(g *GomegaWithT, node *corev1.Node) {
+				g.Expect(node.Annotations[label.AnnNodeEvictLeaderStores]).To(Equal("default/test/1,default/test/2"))
+				g.Expect(node.Annotations[label.AnnNodeReadyForDrain]).To(Equal(label.AnnNodeReadyForDrainVal))
+			},
+		},
+		{
+			name: "node is uncordoned",
+			updateNode: func(node *corev1.Node) {
+				node.Annotations = map[string]string{
+					label.AnnNodeEvictLeaderStores: "default/test/1",
+					label.AnnNodeReadyForDrain:     label.AnnNodeReadyForDrainVal,
+				}
+			},
+			errExpectFn: expectNoError,
+			expectFn: func(g *GomegaWithT, node *corev1.Node) {
+				g.Expect(node.Annotations).NotTo(HaveKey(label.AnnNodeEvictLeaderStores))
+				g.Expect(node.Annotations).NotTo(HaveKey(label.AnnNodeReadyForDrain))
+			},
+			expectEndEvicts: 1,
+		},
+		{
+			name: "node is uncordoned, end evict leader failed",
+			updateNode: func(node *corev1.Node) {
+				node.Annotations = map[string]string{label.AnnNodeEvictLeaderStores: "default/test/1"}
+			},
+			endEvictErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, node *corev1.Node) {
+				g.Expect(node.Annotations[label.AnnNodeEvictLeaderStores]).To(Equal("default/test/1"))
+			},
+			expectEndEvicts: 1,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func expectNoError(g *GomegaWithT, err error) {
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func newFakeNodeMaintenanceControl(tc *v1beta1.TikvCluster) (ControlInterface, *pdapi.FakePDClient, cache.Indexer, cache.Indexer, cache.Indexer) {
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cli, 0)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	tcInformer := informerFactory.Tikv().V1beta1().TikvClusters()
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	nodeInformer := kubeInformerFactory.Core().V1().Nodes()
+
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	pdClient := controller.NewFakePDClient(pdControl, tc)
+	nodeControl := controller.NewFakeNodeControl(nodeInformer)
+
+	control := NewDefaultNodeMaintenanceControl(pdControl, nodeControl, tcInformer.Lister(), podInformer.Lister(), label.NodeMaintenanceTaintKey)
+	return control, pdClient, tcInformer.Informer().GetIndexer(), podInformer.Informer().GetIndexer(), nodeInformer.Informer().GetIndexer()
+}
+
+func newTikvClusterForNodeMaintenance() *v1beta1.TikvCluster {
+	return &v1beta1.TikvCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TikvCluster",
+			APIVersion: "tikv.org/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Status: v1beta1.TikvClusterStatus{
+			TiKV: v1beta1.TiKVStatus{
+				Stores: map[string]v1beta1.TiKVStore{
+					"1": {
+						ID:          "1",
+						PodName:     "test-tikv-0",
+						LeaderCount: 10,
+						State:       v1beta1.TiKVStateUp,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTiKVPod(tc *v1beta1.TikvCluster, nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tikv-0",
+			Namespace: tc.GetNamespace(),
+			Labels:    label.New().Instance(tc.GetName()).TiKV().Labels(),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+}
+
+func newNode() *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+		},
+	}
+}
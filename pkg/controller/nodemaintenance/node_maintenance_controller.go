@@ -0,0 +1,157 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodemaintenance
+
+import (
+	"fmt"
+	"time"
+
+	perrors "github.com/pingcap/errors"
+	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+)
+
+// Controller evicts the TiKV region leaders from the nodes which are cordoned
+// or tainted for maintenance, and marks these nodes ready for drain.
+type Controller struct {
+	// control returns an interface capable of syncing a node.
+	// Abstracted out for testing.
+	control ControlInterface
+	// nodeLister is able to list/get nodes from a shared informer's store
+	nodeLister corelisters.NodeLister
+	// nodes that need to be synced.
+	queue workqueue.RateLimitingInterface
+}
+
+// NewController creates a node maintenance controller.
+func NewController(
+	kubeCli kubernetes.Interface,
+	informerFactory informers.SharedInformerFactory,
+	kubeInformerFactory kubeinformers.SharedInformerFactory,
+	clusterKubeInformerFactory kubeinformers.SharedInformerFactory,
+	maintenanceTaintKey string,
+) *Controller {
+	tcInformer := informerFactory.Tikv().V1beta1().TikvClusters()
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	nodeInformer := clusterKubeInformerFactory.Core().V1().Nodes()
+
+	pdControl := pdapi.NewDefaultPDControl(kubeCli)
+	nodeControl := controller.NewRealNodeControl(kubeCli, nodeInformer.Lister())
+
+	nmc := &Controller{
+		control: NewDefaultNodeMaintenanceControl(
+			pdControl,
+			nodeControl,
+			tcInformer.Lister(),
+			podInformer.Lister(),
+			maintenanceTaintKey,
+		),
+		nodeLister: nodeInformer.Lister(),
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(),
+			"nodemaintenance",
+		),
+	}
+
+	nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: nmc.enqueueNode,
+		UpdateFunc: func(old, cur interface{}) {
+			nmc.enqueueNode(cur)
+		},
+	})
+
+	return nmc
+}
+
+// Run runs the node maintenance controller.
+func (nmc *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer nmc.queue.ShutDown()
+
+	klog.Info("Starting node maintenance controller")
+	defer klog.Info("Shutting down node maintenance controller")
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(nmc.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// worker runs a worker goroutine that invokes processNextWorkItem until the the controller's queue is closed
+func (nmc *Controller) worker() {
+	for nmc.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem dequeues items, processes them, and marks them done. It enforces that the syncHandler is never
+// invoked concurrently with the same key.
+func (nmc *Controller) processNextWorkItem() bool {
+	key, quit := nmc.queue.Get()
+	if quit {
+		return false
+	}
+	defer nmc.queue.Done(key)
+	if err := nmc.sync(key.(string)); err != nil {
+		if perrors.Find(err, controller.IsRequeueError) != nil {
+			klog.Infof("Node: %v, still need sync: %v, requeuing", key.(string), err)
+		} else {
+			utilruntime.HandleError(fmt.Errorf("Node: %v, sync failed %v, requeuing", key.(string), err))
+		}
+		nmc.queue.AddRateLimited(key)
+	} else {
+		nmc.queue.Forget(key)
+	}
+	return true
+}
+
+// sync syncs the given node.
+func (nmc *Controller) sync(key string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing Node %q (%v)", key, time.Since(startTime))
+	}()
+
+	node, err := nmc.nodeLister.Get(key)
+	if errors.IsNotFound(err) {
+		klog.Infof("Node has been deleted %v", key)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return nmc.control.Sync(node.DeepCopy())
+}
+
+// enqueueNode enqueues the given node in the work queue.
+func (nmc *Controller) enqueueNode(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("Cound't get node from object %+v", obj))
+		return
+	}
+	nmc.queue.Add(node.GetName())
+}
@@ -14,18 +14,29 @@
 package controller
 
 import (
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
 )
 
 // GetPDClient gets the pd client from the TikvCluster
-func GetPDClient(pdControl pdapi.PDControlInterface, tc *v1alpha1.TikvCluster) pdapi.PDClient {
+func GetPDClient(pdControl pdapi.PDControlInterface, tc *v1beta1.TikvCluster) pdapi.PDClient {
+	if tc.IsPDExternal() {
+		var secretName string
+		if tc.IsPDExternalTLSEnabled() {
+			secretName = *tc.Spec.PD.ExternalTLSClientSecretName
+		}
+		return pdControl.GetExternalPDClient(pdapi.Namespace(tc.GetNamespace()), tc.Spec.PD.ExternalEndpoints, secretName)
+	}
 	return pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsTLSClusterEnabled())
 }
 
 // NewFakePDClient creates a fake pdclient that is set as the pd client
-func NewFakePDClient(pdControl *pdapi.FakePDControl, tc *v1alpha1.TikvCluster) *pdapi.FakePDClient {
+func NewFakePDClient(pdControl *pdapi.FakePDControl, tc *v1beta1.TikvCluster) *pdapi.FakePDClient {
 	pdClient := pdapi.NewFakePDClient()
+	if tc.IsPDExternal() {
+		pdControl.SetExternalPDClient(tc.Spec.PD.ExternalEndpoints, pdClient)
+		return pdClient
+	}
 	pdControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
 	return pdClient
 }
@@ -18,7 +18,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
 	corev1 "k8s.io/api/core/v1"
@@ -36,9 +36,9 @@ import (
 // PodControlInterface manages Pods used in TikvCluster
 type PodControlInterface interface {
 	// TODO change this to UpdatePod
-	UpdateMetaInfo(*v1alpha1.TikvCluster, *corev1.Pod) (*corev1.Pod, error)
-	DeletePod(*v1alpha1.TikvCluster, *corev1.Pod) error
-	UpdatePod(*v1alpha1.TikvCluster, *corev1.Pod) (*corev1.Pod, error)
+	UpdateMetaInfo(*v1beta1.TikvCluster, *corev1.Pod) (*corev1.Pod, error)
+	DeletePod(*v1beta1.TikvCluster, *corev1.Pod) error
+	UpdatePod(*v1beta1.TikvCluster, *corev1.Pod) (*corev1.Pod, error)
 }
 
 type realPodControl struct {
@@ -63,7 +63,7 @@ func NewRealPodControl(
 	}
 }
 
-func (rpc *realPodControl) UpdatePod(tc *v1alpha1.TikvCluster, pod *corev1.Pod) (*corev1.Pod, error) {
+func (rpc *realPodControl) UpdatePod(tc *v1beta1.TikvCluster, pod *corev1.Pod) (*corev1.Pod, error) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	podName := pod.GetName()
@@ -96,7 +96,7 @@ func (rpc *realPodControl) UpdatePod(tc *v1alpha1.TikvCluster, pod *corev1.Pod)
 	return updatePod, err
 }
 
-func (rpc *realPodControl) UpdateMetaInfo(tc *v1alpha1.TikvCluster, pod *corev1.Pod) (*corev1.Pod, error) {
+func (rpc *realPodControl) UpdateMetaInfo(tc *v1beta1.TikvCluster, pod *corev1.Pod) (*corev1.Pod, error) {
 	ns := pod.GetNamespace()
 	podName := pod.GetName()
 	labels := pod.GetLabels()
@@ -108,7 +108,7 @@ func (rpc *realPodControl) UpdateMetaInfo(tc *v1alpha1.TikvCluster, pod *corev1.
 	memberID := labels[label.MemberIDLabelKey]
 	storeID := labels[label.StoreIDLabelKey]
 
-	pdClient := rpc.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tcName, tc.IsTLSClusterEnabled())
+	pdClient := GetPDClient(rpc.pdControl, tc)
 	if labels[label.ClusterIDLabelKey] == "" {
 		cluster, err := pdClient.GetCluster()
 		if err != nil {
@@ -183,7 +183,7 @@ func (rpc *realPodControl) UpdateMetaInfo(tc *v1alpha1.TikvCluster, pod *corev1.
 	return updatePod, err
 }
 
-func (rpc *realPodControl) DeletePod(tc *v1alpha1.TikvCluster, pod *corev1.Pod) error {
+func (rpc *realPodControl) DeletePod(tc *v1beta1.TikvCluster, pod *corev1.Pod) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	podName := pod.GetName()
@@ -199,7 +199,7 @@ func (rpc *realPodControl) DeletePod(tc *v1alpha1.TikvCluster, pod *corev1.Pod)
 	return err
 }
 
-func (rpc *realPodControl) recordPodEvent(verb string, tc *v1alpha1.TikvCluster, podName string, err error) {
+func (rpc *realPodControl) recordPodEvent(verb string, tc *v1beta1.TikvCluster, podName string, err error) {
 	tcName := tc.GetName()
 	if err == nil {
 		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
@@ -275,7 +275,7 @@ func (fpc *FakePodControl) SetGetStoreError(err error, after int) {
 }
 
 // UpdateMetaInfo update the meta info of Pod
-func (fpc *FakePodControl) UpdateMetaInfo(_ *v1alpha1.TikvCluster, pod *corev1.Pod) (*corev1.Pod, error) {
+func (fpc *FakePodControl) UpdateMetaInfo(_ *v1beta1.TikvCluster, pod *corev1.Pod) (*corev1.Pod, error) {
 	defer fpc.updatePodTracker.Inc()
 	if fpc.updatePodTracker.ErrorReady() {
 		defer fpc.updatePodTracker.Reset()
@@ -308,7 +308,7 @@ func (fpc *FakePodControl) UpdateMetaInfo(_ *v1alpha1.TikvCluster, pod *corev1.P
 	return pod, fpc.PodIndexer.Update(pod)
 }
 
-func (fpc *FakePodControl) DeletePod(_ *v1alpha1.TikvCluster, pod *corev1.Pod) error {
+func (fpc *FakePodControl) DeletePod(_ *v1beta1.TikvCluster, pod *corev1.Pod) error {
 	defer fpc.deletePodTracker.Inc()
 	if fpc.deletePodTracker.ErrorReady() {
 		defer fpc.deletePodTracker.Reset()
@@ -318,7 +318,7 @@ func (fpc *FakePodControl) DeletePod(_ *v1alpha1.TikvCluster, pod *corev1.Pod) e
 	return fpc.PodIndexer.Delete(pod)
 }
 
-func (fpc *FakePodControl) UpdatePod(_ *v1alpha1.TikvCluster, pod *corev1.Pod) (*corev1.Pod, error) {
+func (fpc *FakePodControl) UpdatePod(_ *v1beta1.TikvCluster, pod *corev1.Pod) (*corev1.Pod, error) {
 	defer fpc.updatePodTracker.Inc()
 	if fpc.updatePodTracker.ErrorReady() {
 		defer fpc.updatePodTracker.Reset()
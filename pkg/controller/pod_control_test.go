@@ -21,7 +21,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
 	corev1 "k8s.io/api/core/v1"
@@ -359,11 +359,11 @@ func newFakeClientRecorderAndPDControl() (*fake.Clientset, *pdapi.FakePDControl,
 	return fakeClient, pdControl, podInformer.Lister(), podInformer.Informer().GetIndexer(), recorder
 }
 
-func newPod(tc *v1alpha1.TikvCluster) *corev1.Pod {
+func newPod(tc *v1beta1.TikvCluster) *corev1.Pod {
 	return &corev1.Pod{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Pod",
-			APIVersion: "v1alpha1",
+			APIVersion: "v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      TestPodName,
@@ -374,7 +374,7 @@ func newPod(tc *v1alpha1.TikvCluster) *corev1.Pod {
 				label.ManagedByLabelKey: TestManagedByName,
 			},
 		},
-		Spec: newPodSpec(v1alpha1.PDMemberType.String(), "pvc-1"),
+		Spec: newPodSpec(v1beta1.PDMemberType.String(), "pvc-1"),
 	}
 }
 
@@ -126,7 +126,7 @@ func newPV() *corev1.PersistentVolume {
 	return &corev1.PersistentVolume{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PersistentVolume",
-			APIVersion: "v1alpha1",
+			APIVersion: "v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "pv-1",
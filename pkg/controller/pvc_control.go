@@ -17,7 +17,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/label"
 	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -34,9 +34,9 @@ import (
 
 // PVCControlInterface manages PVCs used in TikvCluster
 type PVCControlInterface interface {
-	UpdateMetaInfo(*v1alpha1.TikvCluster, *corev1.PersistentVolumeClaim, *corev1.Pod) (*corev1.PersistentVolumeClaim, error)
-	UpdatePVC(*v1alpha1.TikvCluster, *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error)
-	DeletePVC(*v1alpha1.TikvCluster, *corev1.PersistentVolumeClaim) error
+	UpdateMetaInfo(*v1beta1.TikvCluster, *corev1.PersistentVolumeClaim, *corev1.Pod) (*corev1.PersistentVolumeClaim, error)
+	UpdatePVC(*v1beta1.TikvCluster, *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error)
+	DeletePVC(*v1beta1.TikvCluster, *corev1.PersistentVolumeClaim) error
 	GetPVC(name, namespace string) (*corev1.PersistentVolumeClaim, error)
 }
 
@@ -62,7 +62,7 @@ func (rpc *realPVCControl) GetPVC(name, namespace string) (*corev1.PersistentVol
 	return rpc.pvcLister.PersistentVolumeClaims(namespace).Get(name)
 }
 
-func (rpc *realPVCControl) DeletePVC(tc *v1alpha1.TikvCluster, pvc *corev1.PersistentVolumeClaim) error {
+func (rpc *realPVCControl) DeletePVC(tc *v1beta1.TikvCluster, pvc *corev1.PersistentVolumeClaim) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	pvcName := pvc.GetName()
@@ -75,7 +75,7 @@ func (rpc *realPVCControl) DeletePVC(tc *v1alpha1.TikvCluster, pvc *corev1.Persi
 	return err
 }
 
-func (rpc *realPVCControl) UpdatePVC(tc *v1alpha1.TikvCluster, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
+func (rpc *realPVCControl) UpdatePVC(tc *v1beta1.TikvCluster, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	pvcName := pvc.GetName()
@@ -106,7 +106,7 @@ func (rpc *realPVCControl) UpdatePVC(tc *v1alpha1.TikvCluster, pvc *corev1.Persi
 	return updatePVC, err
 }
 
-func (rpc *realPVCControl) UpdateMetaInfo(tc *v1alpha1.TikvCluster, pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod) (*corev1.PersistentVolumeClaim, error) {
+func (rpc *realPVCControl) UpdateMetaInfo(tc *v1beta1.TikvCluster, pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod) (*corev1.PersistentVolumeClaim, error) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	pvcName := pvc.GetName()
@@ -165,7 +165,7 @@ func (rpc *realPVCControl) UpdateMetaInfo(tc *v1alpha1.TikvCluster, pvc *corev1.
 	return updatePVC, err
 }
 
-func (rpc *realPVCControl) recordPVCEvent(verb string, tc *v1alpha1.TikvCluster, pvcName string, err error) {
+func (rpc *realPVCControl) recordPVCEvent(verb string, tc *v1beta1.TikvCluster, pvcName string, err error) {
 	tcName := tc.GetName()
 	if err == nil {
 		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
@@ -209,7 +209,7 @@ func (fpc *FakePVCControl) SetDeletePVCError(err error, after int) {
 }
 
 // DeletePVC deletes the pvc
-func (fpc *FakePVCControl) DeletePVC(_ *v1alpha1.TikvCluster, pvc *corev1.PersistentVolumeClaim) error {
+func (fpc *FakePVCControl) DeletePVC(_ *v1beta1.TikvCluster, pvc *corev1.PersistentVolumeClaim) error {
 	defer fpc.deletePVCTracker.Inc()
 	if fpc.deletePVCTracker.ErrorReady() {
 		defer fpc.deletePVCTracker.Reset()
@@ -220,7 +220,7 @@ func (fpc *FakePVCControl) DeletePVC(_ *v1alpha1.TikvCluster, pvc *corev1.Persis
 }
 
 // UpdatePVC updates the annotation, labels and spec of pvc
-func (fpc *FakePVCControl) UpdatePVC(_ *v1alpha1.TikvCluster, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
+func (fpc *FakePVCControl) UpdatePVC(_ *v1beta1.TikvCluster, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
 	defer fpc.updatePVCTracker.Inc()
 	if fpc.updatePVCTracker.ErrorReady() {
 		defer fpc.updatePVCTracker.Reset()
@@ -231,7 +231,7 @@ func (fpc *FakePVCControl) UpdatePVC(_ *v1alpha1.TikvCluster, pvc *corev1.Persis
 }
 
 // UpdateMetaInfo updates the meta info of pvc
-func (fpc *FakePVCControl) UpdateMetaInfo(_ *v1alpha1.TikvCluster, pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod) (*corev1.PersistentVolumeClaim, error) {
+func (fpc *FakePVCControl) UpdateMetaInfo(_ *v1beta1.TikvCluster, pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod) (*corev1.PersistentVolumeClaim, error) {
 	defer fpc.updatePVCTracker.Inc()
 	if fpc.updatePVCTracker.ErrorReady() {
 		defer fpc.updatePVCTracker.Reset()
@@ -18,7 +18,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/label"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -147,11 +147,11 @@ func newFakeClientAndRecorder() (*fake.Clientset, corelisters.PersistentVolumeCl
 	return kubeCli, pvcInformer.Lister(), pvcInformer.Informer().GetIndexer(), recorder
 }
 
-func newPVC(tc *v1alpha1.TikvCluster) *corev1.PersistentVolumeClaim {
+func newPVC(tc *v1beta1.TikvCluster) *corev1.PersistentVolumeClaim {
 	return &corev1.PersistentVolumeClaim{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PersistentVolumeClaim",
-			APIVersion: "v1alpha1",
+			APIVersion: "v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "pvc-1",
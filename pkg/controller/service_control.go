@@ -17,9 +17,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
-	tcinformers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions/tikv/v1alpha1"
-	v1listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	tcinformers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions/tikv/v1beta1"
+	v1listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -37,9 +37,9 @@ var ExternalTrafficPolicy string
 
 // ServiceControlInterface manages Services used in TikvCluster
 type ServiceControlInterface interface {
-	CreateService(*v1alpha1.TikvCluster, *corev1.Service) error
-	UpdateService(*v1alpha1.TikvCluster, *corev1.Service) (*corev1.Service, error)
-	DeleteService(*v1alpha1.TikvCluster, *corev1.Service) error
+	CreateService(*v1beta1.TikvCluster, *corev1.Service) error
+	UpdateService(*v1beta1.TikvCluster, *corev1.Service) (*corev1.Service, error)
+	DeleteService(*v1beta1.TikvCluster, *corev1.Service) error
 }
 
 type realServiceControl struct {
@@ -57,13 +57,13 @@ func NewRealServiceControl(kubeCli kubernetes.Interface, svcLister corelisters.S
 	}
 }
 
-func (sc *realServiceControl) CreateService(tc *v1alpha1.TikvCluster, svc *corev1.Service) error {
+func (sc *realServiceControl) CreateService(tc *v1beta1.TikvCluster, svc *corev1.Service) error {
 	_, err := sc.kubeCli.CoreV1().Services(tc.Namespace).Create(svc)
 	sc.recordServiceEvent("create", tc, svc, err)
 	return err
 }
 
-func (sc *realServiceControl) UpdateService(tc *v1alpha1.TikvCluster, svc *corev1.Service) (*corev1.Service, error) {
+func (sc *realServiceControl) UpdateService(tc *v1beta1.TikvCluster, svc *corev1.Service) (*corev1.Service, error) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	svcName := svc.GetName()
@@ -90,13 +90,13 @@ func (sc *realServiceControl) UpdateService(tc *v1alpha1.TikvCluster, svc *corev
 	return updateSvc, err
 }
 
-func (sc *realServiceControl) DeleteService(tc *v1alpha1.TikvCluster, svc *corev1.Service) error {
+func (sc *realServiceControl) DeleteService(tc *v1beta1.TikvCluster, svc *corev1.Service) error {
 	err := sc.kubeCli.CoreV1().Services(tc.Namespace).Delete(svc.Name, nil)
 	sc.recordServiceEvent("delete", tc, svc, err)
 	return err
 }
 
-func (sc *realServiceControl) recordServiceEvent(verb string, tc *v1alpha1.TikvCluster, svc *corev1.Service, err error) {
+func (sc *realServiceControl) recordServiceEvent(verb string, tc *v1beta1.TikvCluster, svc *corev1.Service, err error) {
 	tcName := tc.GetName()
 	svcName := svc.GetName()
 	if err == nil {
@@ -156,7 +156,7 @@ func (ssc *FakeServiceControl) SetDeleteServiceError(err error, after int) {
 }
 
 // CreateService adds the service to SvcIndexer
-func (ssc *FakeServiceControl) CreateService(_ *v1alpha1.TikvCluster, svc *corev1.Service) error {
+func (ssc *FakeServiceControl) CreateService(_ *v1beta1.TikvCluster, svc *corev1.Service) error {
 	defer ssc.createServiceTracker.Inc()
 	if ssc.createServiceTracker.ErrorReady() {
 		defer ssc.createServiceTracker.Reset()
@@ -181,7 +181,7 @@ func (ssc *FakeServiceControl) CreateService(_ *v1alpha1.TikvCluster, svc *corev
 }
 
 // UpdateService updates the service of SvcIndexer
-func (ssc *FakeServiceControl) UpdateService(_ *v1alpha1.TikvCluster, svc *corev1.Service) (*corev1.Service, error) {
+func (ssc *FakeServiceControl) UpdateService(_ *v1beta1.TikvCluster, svc *corev1.Service) (*corev1.Service, error) {
 	defer ssc.updateServiceTracker.Inc()
 	if ssc.updateServiceTracker.ErrorReady() {
 		defer ssc.updateServiceTracker.Reset()
@@ -204,7 +204,7 @@ func (ssc *FakeServiceControl) UpdateService(_ *v1alpha1.TikvCluster, svc *corev
 }
 
 // DeleteService deletes the service of SvcIndexer
-func (ssc *FakeServiceControl) DeleteService(_ *v1alpha1.TikvCluster, _ *corev1.Service) error {
+func (ssc *FakeServiceControl) DeleteService(_ *v1beta1.TikvCluster, _ *corev1.Service) error {
 	return nil
 }
 
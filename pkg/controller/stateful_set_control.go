@@ -17,9 +17,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
-	tcinformers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions/tikv/v1alpha1"
-	v1listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	tcinformers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions/tikv/v1beta1"
+	v1listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1beta1"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -36,11 +36,11 @@ import (
 // StatefulSetControlInterface defines the interface that uses to create, update, and delete StatefulSets,
 type StatefulSetControlInterface interface {
 	// CreateStatefulSet creates a StatefulSet in a TikvCluster.
-	CreateStatefulSet(*v1alpha1.TikvCluster, *apps.StatefulSet) error
+	CreateStatefulSet(*v1beta1.TikvCluster, *apps.StatefulSet) error
 	// UpdateStatefulSet updates a StatefulSet in a TikvCluster.
-	UpdateStatefulSet(*v1alpha1.TikvCluster, *apps.StatefulSet) (*apps.StatefulSet, error)
+	UpdateStatefulSet(*v1beta1.TikvCluster, *apps.StatefulSet) (*apps.StatefulSet, error)
 	// DeleteStatefulSet deletes a StatefulSet in a TikvCluster.
-	DeleteStatefulSet(*v1alpha1.TikvCluster, *apps.StatefulSet) error
+	DeleteStatefulSet(*v1beta1.TikvCluster, *apps.StatefulSet) error
 }
 
 type realStatefulSetControl struct {
@@ -55,7 +55,7 @@ func NewRealStatefuSetControl(kubeCli kubernetes.Interface, setLister appslister
 }
 
 // CreateStatefulSet create a StatefulSet in a TikvCluster.
-func (sc *realStatefulSetControl) CreateStatefulSet(tc *v1alpha1.TikvCluster, set *apps.StatefulSet) error {
+func (sc *realStatefulSetControl) CreateStatefulSet(tc *v1beta1.TikvCluster, set *apps.StatefulSet) error {
 	_, err := sc.kubeCli.AppsV1().StatefulSets(tc.Namespace).Create(set)
 	// sink already exists errors
 	if apierrors.IsAlreadyExists(err) {
@@ -66,7 +66,7 @@ func (sc *realStatefulSetControl) CreateStatefulSet(tc *v1alpha1.TikvCluster, se
 }
 
 // UpdateStatefulSet update a StatefulSet in a TikvCluster.
-func (sc *realStatefulSetControl) UpdateStatefulSet(tc *v1alpha1.TikvCluster, set *apps.StatefulSet) (*apps.StatefulSet, error) {
+func (sc *realStatefulSetControl) UpdateStatefulSet(tc *v1beta1.TikvCluster, set *apps.StatefulSet) (*apps.StatefulSet, error) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	setName := set.GetName()
@@ -97,13 +97,13 @@ func (sc *realStatefulSetControl) UpdateStatefulSet(tc *v1alpha1.TikvCluster, se
 }
 
 // DeleteStatefulSet delete a StatefulSet in a TikvCluster.
-func (sc *realStatefulSetControl) DeleteStatefulSet(tc *v1alpha1.TikvCluster, set *apps.StatefulSet) error {
+func (sc *realStatefulSetControl) DeleteStatefulSet(tc *v1beta1.TikvCluster, set *apps.StatefulSet) error {
 	err := sc.kubeCli.AppsV1().StatefulSets(tc.Namespace).Delete(set.Name, nil)
 	sc.recordStatefulSetEvent("delete", tc, set, err)
 	return err
 }
 
-func (sc *realStatefulSetControl) recordStatefulSetEvent(verb string, tc *v1alpha1.TikvCluster, set *apps.StatefulSet, err error) {
+func (sc *realStatefulSetControl) recordStatefulSetEvent(verb string, tc *v1beta1.TikvCluster, set *apps.StatefulSet, err error) {
 	tcName := tc.Name
 	setName := set.Name
 	if err == nil {
@@ -167,7 +167,7 @@ func (ssc *FakeStatefulSetControl) SetStatusChange(fn func(*apps.StatefulSet)) {
 }
 
 // CreateStatefulSet adds the statefulset to SetIndexer
-func (ssc *FakeStatefulSetControl) CreateStatefulSet(_ *v1alpha1.TikvCluster, set *apps.StatefulSet) error {
+func (ssc *FakeStatefulSetControl) CreateStatefulSet(_ *v1beta1.TikvCluster, set *apps.StatefulSet) error {
 	defer func() {
 		ssc.createStatefulSetTracker.Inc()
 		ssc.statusChange = nil
@@ -186,7 +186,7 @@ func (ssc *FakeStatefulSetControl) CreateStatefulSet(_ *v1alpha1.TikvCluster, se
 }
 
 // UpdateStatefulSet updates the statefulset of SetIndexer
-func (ssc *FakeStatefulSetControl) UpdateStatefulSet(_ *v1alpha1.TikvCluster, set *apps.StatefulSet) (*apps.StatefulSet, error) {
+func (ssc *FakeStatefulSetControl) UpdateStatefulSet(_ *v1beta1.TikvCluster, set *apps.StatefulSet) (*apps.StatefulSet, error) {
 	defer func() {
 		ssc.updateStatefulSetTracker.Inc()
 		ssc.statusChange = nil
@@ -204,7 +204,7 @@ func (ssc *FakeStatefulSetControl) UpdateStatefulSet(_ *v1alpha1.TikvCluster, se
 }
 
 // DeleteStatefulSet deletes the statefulset of SetIndexer
-func (ssc *FakeStatefulSetControl) DeleteStatefulSet(_ *v1alpha1.TikvCluster, _ *apps.StatefulSet) error {
+func (ssc *FakeStatefulSetControl) DeleteStatefulSet(_ *v1beta1.TikvCluster, _ *apps.StatefulSet) error {
 	return nil
 }
 
@@ -14,7 +14,7 @@
 package tikvcluster
 
 import (
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	utiltikvcluster "github.com/tikv/tikv-operator/pkg/util/tikvcluster"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -23,7 +23,7 @@ import (
 // TikvClusterConditionUpdater interface that translates cluster state into
 // into tikv cluster status conditions.
 type TikvClusterConditionUpdater interface {
-	Update(*v1alpha1.TikvCluster) error
+	Update(*v1beta1.TikvCluster) error
 }
 
 type tikvClusterConditionUpdater struct {
@@ -31,13 +31,13 @@ type tikvClusterConditionUpdater struct {
 
 var _ TikvClusterConditionUpdater = &tikvClusterConditionUpdater{}
 
-func (u *tikvClusterConditionUpdater) Update(tc *v1alpha1.TikvCluster) error {
+func (u *tikvClusterConditionUpdater) Update(tc *v1beta1.TikvCluster) error {
 	u.updateReadyCondition(tc)
 	// in the future, we may return error when we need to Kubernetes API, etc.
 	return nil
 }
 
-func allStatefulSetsAreUpToDate(tc *v1alpha1.TikvCluster) bool {
+func allStatefulSetsAreUpToDate(tc *v1beta1.TikvCluster) bool {
 	isUpToDate := func(status *appsv1.StatefulSetStatus, requireExist bool) bool {
 		if status == nil {
 			return !requireExist
@@ -48,7 +48,7 @@ func allStatefulSetsAreUpToDate(tc *v1alpha1.TikvCluster) bool {
 		isUpToDate(tc.Status.TiKV.StatefulSet, true)
 }
 
-func (u *tikvClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TikvCluster) {
+func (u *tikvClusterConditionUpdater) updateReadyCondition(tc *v1beta1.TikvCluster) {
 	status := v1.ConditionFalse
 	reason := ""
 	message := ""
@@ -68,6 +68,6 @@ func (u *tikvClusterConditionUpdater) updateReadyCondition(tc *v1alpha1.TikvClus
 		reason = utiltikvcluster.Ready
 		message = "TiKV cluster is fully up and running"
 	}
-	cond := utiltikvcluster.NewTikvClusterCondition(v1alpha1.TikvClusterReady, status, reason, message)
+	cond := utiltikvcluster.NewTikvClusterCondition(v1beta1.TikvClusterReady, status, reason, message)
 	utiltikvcluster.SetTikvClusterCondition(&tc.Status, *cond)
 }
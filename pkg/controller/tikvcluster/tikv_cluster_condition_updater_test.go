@@ -17,7 +17,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	utiltikvcluster "github.com/tikv/tikv-operator/pkg/util/tikvcluster"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -26,22 +26,22 @@ import (
 func TestTikvClusterConditionUpdater_Ready(t *testing.T) {
 	tests := []struct {
 		name        string
-		tc          *v1alpha1.TikvCluster
+		tc          *v1beta1.TikvCluster
 		wantStatus  v1.ConditionStatus
 		wantReason  string
 		wantMessage string
 	}{
 		{
 			name: "statfulset(s) not up to date",
-			tc: &v1alpha1.TikvCluster{
-				Status: v1alpha1.TikvClusterStatus{
-					PD: v1alpha1.PDStatus{
+			tc: &v1beta1.TikvCluster{
+				Status: v1beta1.TikvClusterStatus{
+					PD: v1beta1.PDStatus{
 						StatefulSet: &appsv1.StatefulSetStatus{
 							CurrentRevision: "1",
 							UpdateRevision:  "2",
 						},
 					},
-					TiKV: v1alpha1.TiKVStatus{
+					TiKV: v1beta1.TiKVStatus{
 						StatefulSet: &appsv1.StatefulSetStatus{
 							CurrentRevision: "1",
 							UpdateRevision:  "2",
@@ -55,15 +55,15 @@ func TestTikvClusterConditionUpdater_Ready(t *testing.T) {
 		},
 		{
 			name: "pd(s) not healthy",
-			tc: &v1alpha1.TikvCluster{
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
+			tc: &v1beta1.TikvCluster{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
 						Replicas: 1,
 					},
 				},
-				Status: v1alpha1.TikvClusterStatus{
-					PD: v1alpha1.PDStatus{
-						Members: map[string]v1alpha1.PDMember{
+				Status: v1beta1.TikvClusterStatus{
+					PD: v1beta1.PDStatus{
+						Members: map[string]v1beta1.PDMember{
 							"pd-1": {
 								Health: false,
 							},
@@ -73,7 +73,7 @@ func TestTikvClusterConditionUpdater_Ready(t *testing.T) {
 							UpdateRevision:  "2",
 						},
 					},
-					TiKV: v1alpha1.TiKVStatus{
+					TiKV: v1beta1.TiKVStatus{
 						StatefulSet: &appsv1.StatefulSetStatus{
 							CurrentRevision: "2",
 							UpdateRevision:  "2",
@@ -87,18 +87,18 @@ func TestTikvClusterConditionUpdater_Ready(t *testing.T) {
 		},
 		{
 			name: "tikv(s) not healthy",
-			tc: &v1alpha1.TikvCluster{
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
+			tc: &v1beta1.TikvCluster{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
 						Replicas: 1,
 					},
-					TiKV: v1alpha1.TiKVSpec{
+					TiKV: v1beta1.TiKVSpec{
 						Replicas: 1,
 					},
 				},
-				Status: v1alpha1.TikvClusterStatus{
-					PD: v1alpha1.PDStatus{
-						Members: map[string]v1alpha1.PDMember{
+				Status: v1beta1.TikvClusterStatus{
+					PD: v1beta1.PDStatus{
+						Members: map[string]v1beta1.PDMember{
 							"pd-0": {
 								Health: true,
 							},
@@ -108,8 +108,8 @@ func TestTikvClusterConditionUpdater_Ready(t *testing.T) {
 							UpdateRevision:  "2",
 						},
 					},
-					TiKV: v1alpha1.TiKVStatus{
-						Stores: map[string]v1alpha1.TiKVStore{
+					TiKV: v1beta1.TiKVStatus{
+						Stores: map[string]v1beta1.TiKVStore{
 							"tikv-0": {
 								State: "Down",
 							},
@@ -127,18 +127,18 @@ func TestTikvClusterConditionUpdater_Ready(t *testing.T) {
 		},
 		{
 			name: "all ready",
-			tc: &v1alpha1.TikvCluster{
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
+			tc: &v1beta1.TikvCluster{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
 						Replicas: 1,
 					},
-					TiKV: v1alpha1.TiKVSpec{
+					TiKV: v1beta1.TiKVSpec{
 						Replicas: 1,
 					},
 				},
-				Status: v1alpha1.TikvClusterStatus{
-					PD: v1alpha1.PDStatus{
-						Members: map[string]v1alpha1.PDMember{
+				Status: v1beta1.TikvClusterStatus{
+					PD: v1beta1.PDStatus{
+						Members: map[string]v1beta1.PDMember{
 							"pd-0": {
 								Health: true,
 							},
@@ -148,8 +148,8 @@ func TestTikvClusterConditionUpdater_Ready(t *testing.T) {
 							UpdateRevision:  "2",
 						},
 					},
-					TiKV: v1alpha1.TiKVStatus{
-						Stores: map[string]v1alpha1.TiKVStore{
+					TiKV: v1beta1.TiKVStatus{
+						Stores: map[string]v1beta1.TiKVStore{
 							"tikv-0": {
 								State: "Up",
 							},
@@ -171,7 +171,7 @@ func TestTikvClusterConditionUpdater_Ready(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			conditionUpdater := &tikvClusterConditionUpdater{}
 			conditionUpdater.Update(tt.tc)
-			cond := utiltikvcluster.GetTikvClusterCondition(tt.tc.Status, v1alpha1.TikvClusterReady)
+			cond := utiltikvcluster.GetTikvClusterCondition(tt.tc.Status, v1beta1.TikvClusterReady)
 			if diff := cmp.Diff(tt.wantStatus, cond.Status); diff != "" {
 				t.Errorf("unexpected status (-want, +got): %s", diff)
 			}
@@ -14,9 +14,11 @@
 package tikvcluster
 
 import (
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1/defaulting"
-	v1alpha1validation "github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1/validation"
+	"time"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1/defaulting"
+	v1beta1validation "github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1/validation"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/manager"
 	"github.com/tikv/tikv-operator/pkg/manager/member"
@@ -32,7 +34,7 @@ import (
 // Currently, there is only one implementation.
 type ControlInterface interface {
 	// UpdateTikvCluster implements the control logic for StatefulSet creation, update, and deletion
-	UpdateTikvCluster(*v1alpha1.TikvCluster) error
+	UpdateTikvCluster(*v1beta1.TikvCluster) error
 }
 
 // NewDefaultTikvClusterControl returns a new instance of the default implementation TikvClusterControlInterface that
@@ -43,6 +45,7 @@ func NewDefaultTikvClusterControl(
 	tikvMemberManager manager.Manager,
 	metaManager manager.Manager,
 	orphanPodsCleaner member.OrphanPodsCleaner,
+	nodeLossPodsCleaner member.NodeLossPodsCleaner,
 	discoveryManager member.PDDiscoveryManager,
 	conditionUpdater TikvClusterConditionUpdater,
 	recorder record.EventRecorder) ControlInterface {
@@ -52,6 +55,7 @@ func NewDefaultTikvClusterControl(
 		tikvMemberManager,
 		metaManager,
 		orphanPodsCleaner,
+		nodeLossPodsCleaner,
 		discoveryManager,
 		conditionUpdater,
 		recorder,
@@ -59,18 +63,19 @@ func NewDefaultTikvClusterControl(
 }
 
 type defaultTikvClusterControl struct {
-	tcControl         controller.TikvClusterControlInterface
-	pdMemberManager   manager.Manager
-	tikvMemberManager manager.Manager
-	metaManager       manager.Manager
-	orphanPodsCleaner member.OrphanPodsCleaner
-	discoveryManager  member.PDDiscoveryManager
-	conditionUpdater  TikvClusterConditionUpdater
-	recorder          record.EventRecorder
+	tcControl           controller.TikvClusterControlInterface
+	pdMemberManager     manager.Manager
+	tikvMemberManager   manager.Manager
+	metaManager         manager.Manager
+	orphanPodsCleaner   member.OrphanPodsCleaner
+	nodeLossPodsCleaner member.NodeLossPodsCleaner
+	discoveryManager    member.PDDiscoveryManager
+	conditionUpdater    TikvClusterConditionUpdater
+	recorder            record.EventRecorder
 }
 
 // UpdateStatefulSet executes the core logic loop for a tikvcluster.
-func (tcc *defaultTikvClusterControl) UpdateTikvCluster(tc *v1alpha1.TikvCluster) error {
+func (tcc *defaultTikvClusterControl) UpdateTikvCluster(tc *v1beta1.TikvCluster) error {
 	tcc.defaulting(tc)
 	if !tcc.validate(tc) {
 		return nil // fatal error, no need to retry on invalid object
@@ -97,8 +102,8 @@ func (tcc *defaultTikvClusterControl) UpdateTikvCluster(tc *v1alpha1.TikvCluster
 	return errorutils.NewAggregate(errs)
 }
 
-func (tcc *defaultTikvClusterControl) validate(tc *v1alpha1.TikvCluster) bool {
-	errs := v1alpha1validation.ValidateTikvCluster(tc)
+func (tcc *defaultTikvClusterControl) validate(tc *v1beta1.TikvCluster) bool {
+	errs := v1beta1validation.ValidateTikvCluster(tc)
 	if len(errs) > 0 {
 		aggregatedErr := errs.ToAggregate()
 		klog.Errorf("tikv cluster %s/%s is not valid and must be fixed first, aggregated error: %v", tc.GetNamespace(), tc.GetName(), aggregatedErr)
@@ -108,34 +113,33 @@ func (tcc *defaultTikvClusterControl) validate(tc *v1alpha1.TikvCluster) bool {
 	return true
 }
 
-func (tcc *defaultTikvClusterControl) defaulting(tc *v1alpha1.TikvCluster) {
+func (tcc *defaultTikvClusterControl) defaulting(tc *v1beta1.TikvCluster) {
 	defaulting.SetTikvClusterDefault(tc)
 }
 
-func (tcc *defaultTikvClusterControl) updateTikvCluster(tc *v1alpha1.TikvCluster) error {
+func (tcc *defaultTikvClusterControl) updateTikvCluster(tc *v1beta1.TikvCluster) error {
+	// the disruptive operations wait for the maintenance windows, the others go on even if
+	// the windows are invalid
+	if err := member.SyncMaintenanceStatus(tc, time.Now()); err != nil {
+		klog.Errorf("%v", err)
+		tcc.recorder.Event(tc, v1.EventTypeWarning, "FailedSyncMaintenanceStatus", err.Error())
+	}
+
 	// cleaning all orphan pods managed by operator
 	if _, err := tcc.orphanPodsCleaner.Clean(tc); err != nil {
 		return err
 	}
 
-	// reconcile PD discovery service
-	if err := tcc.discoveryManager.Reconcile(tc); err != nil {
+	// rescheduling the pods stuck pending because the nodes of their local PVs are gone
+	if _, err := tcc.nodeLossPodsCleaner.Clean(tc); err != nil {
 		return err
 	}
 
-	// works that should do to making the pd cluster current state match the desired state:
-	//   - create or update the pd service
-	//   - create or update the pd headless service
-	//   - create the pd statefulset
-	//   - sync pd cluster status from pd to TikvCluster object
-	//   - set two annotations to the first pd member:
-	// 	   - label.Bootstrapping
-	// 	   - label.Replicas
-	//   - upgrade the pd cluster
-	//   - scale out/in the pd cluster
-	//   - failover the pd cluster
-	if err := tcc.pdMemberManager.Sync(tc); err != nil {
-		return err
+	// the PD members are not managed if the TikvCluster joins an external PD cluster
+	if !tc.IsPDExternal() {
+		if err := tcc.syncPD(tc); err != nil {
+			return err
+		}
 	}
 
 	// works that should do to making the tikv cluster current state match the desired state:
@@ -162,6 +166,26 @@ func (tcc *defaultTikvClusterControl) updateTikvCluster(tc *v1alpha1.TikvCluster
 	return nil
 }
 
+func (tcc *defaultTikvClusterControl) syncPD(tc *v1beta1.TikvCluster) error {
+	// reconcile PD discovery service
+	if err := tcc.discoveryManager.Reconcile(tc); err != nil {
+		return err
+	}
+
+	// works that should do to making the pd cluster current state match the desired state:
+	//   - create or update the pd service
+	//   - create or update the pd headless service
+	//   - create the pd statefulset
+	//   - sync pd cluster status from pd to TikvCluster object
+	//   - set two annotations to the first pd member:
+	// 	   - label.Bootstrapping
+	// 	   - label.Replicas
+	//   - upgrade the pd cluster
+	//   - scale out/in the pd cluster
+	//   - failover the pd cluster
+	return tcc.pdMemberManager.Sync(tc)
+}
+
 var _ ControlInterface = &defaultTikvClusterControl{}
 
 type FakeTikvClusterControlInterface struct {
@@ -176,7 +200,7 @@ func (ftcc *FakeTikvClusterControlInterface) SetUpdateTCError(err error) {
 	ftcc.err = err
 }
 
-func (ftcc *FakeTikvClusterControlInterface) UpdateTikvCluster(_ *v1alpha1.TikvCluster) error {
+func (ftcc *FakeTikvClusterControlInterface) UpdateTikvCluster(_ *v1beta1.TikvCluster) error {
 	if ftcc.err != nil {
 		return ftcc.err
 	}
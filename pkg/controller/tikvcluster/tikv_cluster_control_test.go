@@ -19,7 +19,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
 	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
 	"github.com/tikv/tikv-operator/pkg/controller"
@@ -39,8 +39,9 @@ func TestTikvClusterControlUpdateTikvCluster(t *testing.T) {
 
 	type testcase struct {
 		name                     string
-		update                   func(cluster *v1alpha1.TikvCluster)
+		update                   func(cluster *v1beta1.TikvCluster)
 		orphanPodCleanerErr      bool
+		nodeLossPodCleanerErr    bool
 		syncPDMemberManagerErr   bool
 		syncTiKVMemberManagerErr bool
 		syncMetaManagerErr       bool
@@ -54,11 +55,14 @@ func TestTikvClusterControlUpdateTikvCluster(t *testing.T) {
 		if test.update != nil {
 			test.update(tc)
 		}
-		control, orphanPodCleaner, pdMemberManager, tikvMemberManager, metaManager, tcUpdater := newFakeTikvClusterControl()
+		control, orphanPodCleaner, nodeLossPodCleaner, pdMemberManager, tikvMemberManager, metaManager, tcUpdater := newFakeTikvClusterControl()
 
 		if test.orphanPodCleanerErr {
 			orphanPodCleaner.SetnOrphanPodCleanerError(fmt.Errorf("clean orphan pod error"))
 		}
+		if test.nodeLossPodCleanerErr {
+			nodeLossPodCleaner.SetNodeLossPodsCleanerError(fmt.Errorf("clean node loss pod error"))
+		}
 		if test.syncPDMemberManagerErr {
 			pdMemberManager.SetSyncError(fmt.Errorf("pd member manager sync error"))
 		}
@@ -91,6 +95,20 @@ func TestTikvClusterControlUpdateTikvCluster(t *testing.T) {
 				g.Expect(strings.Contains(err.Error(), "clean orphan pod error")).To(Equal(true))
 			},
 		},
+		{
+			name:                     "clean node loss pod error",
+			update:                   nil,
+			orphanPodCleanerErr:      false,
+			nodeLossPodCleanerErr:    true,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			updateTCStatusErr:        false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "clean node loss pod error")).To(Equal(true))
+			},
+		},
 		{
 			name:                     "pd member manager sync error",
 			update:                   nil,
@@ -104,6 +122,20 @@ func TestTikvClusterControlUpdateTikvCluster(t *testing.T) {
 				g.Expect(strings.Contains(err.Error(), "pd member manager sync error")).To(Equal(true))
 			},
 		},
+		{
+			name: "pd member manager is skipped for external pd",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.PD.ExternalEndpoints = []string{"http://10.0.1.1:2379"}
+			},
+			orphanPodCleanerErr:      false,
+			syncPDMemberManagerErr:   true,
+			syncTiKVMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			updateTCStatusErr:        false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
 		{
 			name:                     "tikv member manager sync error",
 			update:                   nil,
@@ -144,17 +176,17 @@ func TestTikvClusterControlUpdateTikvCluster(t *testing.T) {
 		},
 		{
 			name: "tikvcluster status update failed",
-			update: func(cluster *v1alpha1.TikvCluster) {
-				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+			update: func(cluster *v1beta1.TikvCluster) {
+				cluster.Status.PD.Members = map[string]v1beta1.PDMember{
 					"pd-0": {Name: "pd-0", Health: true},
 					"pd-1": {Name: "pd-1", Health: true},
 					"pd-2": {Name: "pd-2", Health: true},
 				}
 				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
-				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
-					"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
-					"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
-					"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+				cluster.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+					"tikv-0": {PodName: "tikv-0", State: v1beta1.TiKVStateUp},
+					"tikv-1": {PodName: "tikv-1", State: v1beta1.TiKVStateUp},
+					"tikv-2": {PodName: "tikv-2", State: v1beta1.TiKVStateUp},
 				}
 				cluster.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
 			},
@@ -170,17 +202,17 @@ func TestTikvClusterControlUpdateTikvCluster(t *testing.T) {
 		},
 		{
 			name: "normal",
-			update: func(cluster *v1alpha1.TikvCluster) {
-				cluster.Status.PD.Members = map[string]v1alpha1.PDMember{
+			update: func(cluster *v1beta1.TikvCluster) {
+				cluster.Status.PD.Members = map[string]v1beta1.PDMember{
 					"pd-0": {Name: "pd-0", Health: true},
 					"pd-1": {Name: "pd-1", Health: true},
 					"pd-2": {Name: "pd-2", Health: true},
 				}
 				cluster.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
-				cluster.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
-					"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
-					"tikv-1": {PodName: "tikv-1", State: v1alpha1.TiKVStateUp},
-					"tikv-2": {PodName: "tikv-2", State: v1alpha1.TiKVStateUp},
+				cluster.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+					"tikv-0": {PodName: "tikv-0", State: v1beta1.TiKVStateUp},
+					"tikv-1": {PodName: "tikv-1", State: v1beta1.TiKVStateUp},
+					"tikv-2": {PodName: "tikv-2", State: v1beta1.TiKVStateUp},
 				}
 				cluster.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
 			},
@@ -202,26 +234,27 @@ func TestTikvClusterControlUpdateTikvCluster(t *testing.T) {
 
 func TestTikvClusterStatusEquality(t *testing.T) {
 	g := NewGomegaWithT(t)
-	tcStatus := v1alpha1.TikvClusterStatus{}
+	tcStatus := v1beta1.TikvClusterStatus{}
 
 	tcStatusCopy := tcStatus.DeepCopy()
-	tcStatusCopy.PD = v1alpha1.PDStatus{}
+	tcStatusCopy.PD = v1beta1.PDStatus{}
 	g.Expect(apiequality.Semantic.DeepEqual(&tcStatus, tcStatusCopy)).To(Equal(true))
 
 	tcStatusCopy = tcStatus.DeepCopy()
-	tcStatusCopy.PD.Phase = v1alpha1.NormalPhase
+	tcStatusCopy.PD.Phase = v1beta1.NormalPhase
 	g.Expect(apiequality.Semantic.DeepEqual(&tcStatus, tcStatusCopy)).To(Equal(false))
 }
 
 func newFakeTikvClusterControl() (
 	ControlInterface,
 	*mm.FakeOrphanPodsCleaner,
+	*mm.FakeNodeLossPodsCleaner,
 	*mm.FakePDMemberManager,
 	*mm.FakeTiKVMemberManager,
 	*meta.FakeMetaManager,
 	*controller.FakeTikvClusterControl) {
 	cli := fake.NewSimpleClientset()
-	tcInformer := informers.NewSharedInformerFactory(cli, 0).Tikv().V1alpha1().TikvClusters()
+	tcInformer := informers.NewSharedInformerFactory(cli, 0).Tikv().V1beta1().TikvClusters()
 	recorder := record.NewFakeRecorder(10)
 
 	tcUpdater := controller.NewFakeTikvClusterControl(tcInformer)
@@ -229,6 +262,7 @@ func newFakeTikvClusterControl() (
 	tikvMemberManager := mm.NewFakeTiKVMemberManager()
 	metaManager := meta.NewFakeMetaManager()
 	orphanPodCleaner := mm.NewFakeOrphanPodsCleaner()
+	nodeLossPodCleaner := mm.NewFakeNodeLossPodsCleaner()
 	discoveryManager := mm.NewFakeDiscoveryManger()
 	control := NewDefaultTikvClusterControl(
 		tcUpdater,
@@ -236,41 +270,47 @@ func newFakeTikvClusterControl() (
 		tikvMemberManager,
 		metaManager,
 		orphanPodCleaner,
+		nodeLossPodCleaner,
 		discoveryManager,
 		&tikvClusterConditionUpdater{},
 		recorder,
 	)
 
-	return control, orphanPodCleaner, pdMemberManager, tikvMemberManager, metaManager, tcUpdater
+	return control, orphanPodCleaner, nodeLossPodCleaner, pdMemberManager, tikvMemberManager, metaManager, tcUpdater
 }
 
-func newTikvClusterForTikvClusterControl() *v1alpha1.TikvCluster {
-	return &v1alpha1.TikvCluster{
+func newTikvClusterForTikvClusterControl() *v1beta1.TikvCluster {
+	return &v1beta1.TikvCluster{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "TikvCluster",
-			APIVersion: "pingcap.com/v1alpha1",
+			APIVersion: "pingcap.com/v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test-pd",
 			Namespace: corev1.NamespaceDefault,
 			UID:       types.UID("test"),
 		},
-		Spec: v1alpha1.TikvClusterSpec{
-			Version: "v3.0.8",
-			PD: v1alpha1.PDSpec{
+		Spec: v1beta1.TikvClusterSpec{
+			PD: v1beta1.PDSpec{
+				ComponentSpec: v1beta1.ComponentSpec{
+					Version: "v3.0.8",
+				},
 				Replicas:  3,
 				BaseImage: "pingcap/pd",
-				Config:    &v1alpha1.PDConfig{},
+				Config:    &v1beta1.PDConfig{},
 				ResourceRequirements: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceStorage: resource.MustParse("10G"),
 					},
 				},
 			},
-			TiKV: v1alpha1.TiKVSpec{
+			TiKV: v1beta1.TiKVSpec{
+				ComponentSpec: v1beta1.ComponentSpec{
+					Version: "v3.0.8",
+				},
 				Replicas:  3,
 				BaseImage: "pingcap/tikv",
-				Config:    &v1alpha1.TiKVConfig{},
+				Config:    &v1beta1.TiKVConfig{},
 				ResourceRequirements: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceStorage: resource.MustParse("10G"),
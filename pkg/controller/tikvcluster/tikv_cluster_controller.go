@@ -18,10 +18,10 @@ import (
 	"time"
 
 	perrors "github.com/pingcap/errors"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
 	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
-	listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1alpha1"
+	listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	mm "github.com/tikv/tikv-operator/pkg/manager/member"
 	"github.com/tikv/tikv-operator/pkg/manager/meta"
@@ -71,6 +71,7 @@ func NewController(
 	genericCli client.Client,
 	informerFactory informers.SharedInformerFactory,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
+	clusterKubeInformerFactory kubeinformers.SharedInformerFactory,
 	autoFailover bool,
 	pdFailoverPeriod time.Duration,
 	tikvFailoverPeriod time.Duration,
@@ -79,31 +80,40 @@ func NewController(
 	eventBroadcaster.StartLogging(klog.V(2).Infof)
 	eventBroadcaster.StartRecordingToSink(&eventv1.EventSinkImpl{
 		Interface: eventv1.New(kubeCli.CoreV1().RESTClient()).Events("")})
-	recorder := eventBroadcaster.NewRecorder(v1alpha1.Scheme, corev1.EventSource{Component: "tikv-controller-manager"})
+	recorder := eventBroadcaster.NewRecorder(v1beta1.Scheme, corev1.EventSource{Component: "tikv-controller-manager"})
 
-	tcInformer := informerFactory.Tikv().V1alpha1().TikvClusters()
+	tcInformer := informerFactory.Tikv().V1beta1().TikvClusters()
 	setInformer := kubeInformerFactory.Apps().V1().StatefulSets()
 	svcInformer := kubeInformerFactory.Core().V1().Services()
 	epsInformer := kubeInformerFactory.Core().V1().Endpoints()
 	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
-	pvInformer := kubeInformerFactory.Core().V1().PersistentVolumes()
+	pvInformer := clusterKubeInformerFactory.Core().V1().PersistentVolumes()
 	podInformer := kubeInformerFactory.Core().V1().Pods()
-	nodeInformer := kubeInformerFactory.Core().V1().Nodes()
+	nodeInformer := clusterKubeInformerFactory.Core().V1().Nodes()
+	// the kube informers may only cache the labeled objects, see controller.NewKubeInformerFactory
+	// and controller.NewClusterKubeInformerFactory
+	pvcLister := controller.NewFallbackPVCLister(kubeCli, pvcInformer.Lister())
+	pvLister := controller.NewFallbackPVLister(kubeCli, pvInformer.Lister())
+	epsLister := controller.NewFallbackEndpointsLister(kubeCli, epsInformer.Lister())
 
 	tcControl := controller.NewRealTikvClusterControl(cli, tcInformer.Lister(), recorder)
 	pdControl := pdapi.NewDefaultPDControl(kubeCli)
 	setControl := controller.NewRealStatefuSetControl(kubeCli, setInformer.Lister(), recorder)
 	svcControl := controller.NewRealServiceControl(kubeCli, svcInformer.Lister(), recorder)
-	pvControl := controller.NewRealPVControl(kubeCli, pvcInformer.Lister(), pvInformer.Lister(), recorder)
-	pvcControl := controller.NewRealPVCControl(kubeCli, recorder, pvcInformer.Lister())
+	pvControl := controller.NewRealPVControl(kubeCli, pvcLister, pvLister, recorder)
+	pvcControl := controller.NewRealPVCControl(kubeCli, recorder, pvcLister)
 	podControl := controller.NewRealPodControl(kubeCli, pdControl, podInformer.Lister(), recorder)
 	typedControl := controller.NewTypedControl(controller.NewRealGenericControl(genericCli, recorder))
-	pdScaler := mm.NewPDScaler(pdControl, pvcInformer.Lister(), pvcControl)
-	tikvScaler := mm.NewTiKVScaler(pdControl, pvcInformer.Lister(), pvcControl, podInformer.Lister())
-	pdFailover := mm.NewPDFailover(cli, pdControl, pdFailoverPeriod, podInformer.Lister(), podControl, pvcInformer.Lister(), pvcControl, pvInformer.Lister(), recorder)
-	tikvFailover := mm.NewTiKVFailover(tikvFailoverPeriod, recorder)
+	snapshotControl := controller.NewRealVolumeSnapshotControl(genericCli, recorder)
+	pdScaler := mm.NewPDScaler(pdControl, pvcLister, pvcControl)
+	tikvScaler := mm.NewTiKVScaler(pdControl, pvcLister, pvcControl, podInformer.Lister(), recorder)
+	pdFailover := mm.NewPDFailover(cli, pdControl, pdFailoverPeriod, podInformer.Lister(), podControl, pvcLister, pvcControl, pvLister, recorder)
+	tikvFailover := mm.NewTiKVFailover(pdControl, tikvFailoverPeriod, podInformer.Lister(), podControl, pvcLister, pvcControl, recorder)
+	pdRecovery := mm.NewPDRecovery(pdControl, setControl, podInformer.Lister(), podControl, pvcLister, pvcControl, snapshotControl)
 	pdUpgrader := mm.NewPDUpgrader(pdControl, podControl, podInformer.Lister())
 	tikvUpgrader := mm.NewTiKVUpgrader(pdControl, podControl, podInformer.Lister())
+	tikvUnsafeRecovery := mm.NewTiKVUnsafeRecovery(pdControl, recorder)
+	tikvSnapshotter := mm.NewTiKVSnapshotter(pdControl, pvcLister, snapshotControl, recorder)
 
 	tcc := &Controller{
 		kubeClient: kubeCli,
@@ -119,31 +129,38 @@ func NewController(
 				setInformer.Lister(),
 				svcInformer.Lister(),
 				podInformer.Lister(),
-				epsInformer.Lister(),
-				pvcInformer.Lister(),
+				epsLister,
+				pvcLister,
 				pdScaler,
 				pdUpgrader,
 				autoFailover,
 				pdFailover,
+				pdRecovery,
 			),
 			mm.NewTiKVMemberManager(
 				pdControl,
 				setControl,
 				svcControl,
+				podControl,
 				typedControl,
+				pvcControl,
 				setInformer.Lister(),
 				svcInformer.Lister(),
 				podInformer.Lister(),
+				pvcLister,
 				nodeInformer.Lister(),
 				autoFailover,
 				tikvFailover,
 				tikvScaler,
 				tikvUpgrader,
+				tikvUnsafeRecovery,
+				tikvSnapshotter,
+				snapshotControl,
 			),
 			meta.NewMetaManager(
-				pvcInformer.Lister(),
+				pvcLister,
 				pvcControl,
-				pvInformer.Lister(),
+				pvLister,
 				pvControl,
 				podInformer.Lister(),
 				podControl,
@@ -151,9 +168,20 @@ func NewController(
 			mm.NewOrphanPodsCleaner(
 				podInformer.Lister(),
 				podControl,
-				pvcInformer.Lister(),
+				pvcLister,
 				kubeCli,
 			),
+			mm.NewNodeLossPodsCleaner(
+				pdControl,
+				podInformer.Lister(),
+				podControl,
+				pvcLister,
+				pvcControl,
+				pvLister,
+				nodeInformer.Lister(),
+				autoFailover,
+				recorder,
+			),
 			mm.NewPDDiscoveryManager(typedControl),
 			&tikvClusterConditionUpdater{},
 			recorder,
@@ -252,7 +280,7 @@ func (tcc *Controller) sync(key string) error {
 	return tcc.syncTikvCluster(tc.DeepCopy())
 }
 
-func (tcc *Controller) syncTikvCluster(tc *v1alpha1.TikvCluster) error {
+func (tcc *Controller) syncTikvCluster(tc *v1beta1.TikvCluster) error {
 	return tcc.control.UpdateTikvCluster(tc)
 }
 
@@ -343,7 +371,7 @@ func (tcc *Controller) deleteStatefulSet(obj interface{}) {
 // resolveTikvClusterFromSet returns the TikvCluster by a StatefulSet,
 // or nil if the StatefulSet could not be resolved to a matching TikvCluster
 // of the correct Kind.
-func (tcc *Controller) resolveTikvClusterFromSet(namespace string, set *apps.StatefulSet) *v1alpha1.TikvCluster {
+func (tcc *Controller) resolveTikvClusterFromSet(namespace string, set *apps.StatefulSet) *v1beta1.TikvCluster {
 	controllerRef := metav1.GetControllerOf(set)
 	if controllerRef == nil {
 		return nil
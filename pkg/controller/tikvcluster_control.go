@@ -17,10 +17,10 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
-	tcinformers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions/tikv/v1alpha1"
-	listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1alpha1"
+	tcinformers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions/tikv/v1beta1"
+	listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,7 +33,7 @@ import (
 
 // TikvClusterControlInterface manages TikvClusters
 type TikvClusterControlInterface interface {
-	UpdateTikvCluster(*v1alpha1.TikvCluster, *v1alpha1.TikvClusterStatus, *v1alpha1.TikvClusterStatus) (*v1alpha1.TikvCluster, error)
+	UpdateTikvCluster(*v1beta1.TikvCluster, *v1beta1.TikvClusterStatus, *v1beta1.TikvClusterStatus) (*v1beta1.TikvCluster, error)
 }
 
 type realTikvClusterControl struct {
@@ -53,22 +53,23 @@ func NewRealTikvClusterControl(cli versioned.Interface,
 	}
 }
 
-func (rtc *realTikvClusterControl) UpdateTikvCluster(tc *v1alpha1.TikvCluster, newStatus *v1alpha1.TikvClusterStatus, oldStatus *v1alpha1.TikvClusterStatus) (*v1alpha1.TikvCluster, error) {
+func (rtc *realTikvClusterControl) UpdateTikvCluster(tc *v1beta1.TikvCluster, newStatus *v1beta1.TikvClusterStatus, oldStatus *v1beta1.TikvClusterStatus) (*v1beta1.TikvCluster, error) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
 	status := tc.Status.DeepCopy()
-	var updateTC *v1alpha1.TikvCluster
+	var updateTC *v1beta1.TikvCluster
 
 	// don't wait due to limited number of clients, but backoff after the default number of steps
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		var updateErr error
-		updateTC, updateErr = rtc.cli.TikvV1alpha1().TikvClusters(ns).Update(tc)
+		// the status subresource is enabled, the spec is not changed by the controller
+		updateTC, updateErr = rtc.cli.TikvV1beta1().TikvClusters(ns).UpdateStatus(tc)
 		if updateErr == nil {
-			klog.Infof("TikvCluster: [%s/%s] updated successfully", ns, tcName)
+			klog.Infof("TikvCluster: [%s/%s] status updated successfully", ns, tcName)
 			return nil
 		}
-		klog.Errorf("failed to update TikvCluster: [%s/%s], error: %v", ns, tcName, updateErr)
+		klog.Errorf("failed to update the status of TikvCluster: [%s/%s], error: %v", ns, tcName, updateErr)
 
 		if updated, err := rtc.tcLister.TikvClusters(ns).Get(tcName); err == nil {
 			// make a copy so we don't mutate the shared cache
@@ -83,7 +84,7 @@ func (rtc *realTikvClusterControl) UpdateTikvCluster(tc *v1alpha1.TikvCluster, n
 	return updateTC, err
 }
 
-func (rtc *realTikvClusterControl) recordTikvClusterEvent(verb string, tc *v1alpha1.TikvCluster, err error) {
+func (rtc *realTikvClusterControl) recordTikvClusterEvent(verb string, tc *v1beta1.TikvCluster, err error) {
 	tcName := tc.GetName()
 	if err == nil {
 		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
@@ -98,7 +99,7 @@ func (rtc *realTikvClusterControl) recordTikvClusterEvent(verb string, tc *v1alp
 	}
 }
 
-func deepEqualExceptHeartbeatTime(newStatus *v1alpha1.TikvClusterStatus, oldStatus *v1alpha1.TikvClusterStatus) bool {
+func deepEqualExceptHeartbeatTime(newStatus *v1beta1.TikvClusterStatus, oldStatus *v1beta1.TikvClusterStatus) bool {
 	sweepHeartbeatTime(newStatus.TiKV.Stores)
 	sweepHeartbeatTime(newStatus.TiKV.TombstoneStores)
 	sweepHeartbeatTime(oldStatus.TiKV.Stores)
@@ -107,7 +108,7 @@ func deepEqualExceptHeartbeatTime(newStatus *v1alpha1.TikvClusterStatus, oldStat
 	return apiequality.Semantic.DeepEqual(newStatus, oldStatus)
 }
 
-func sweepHeartbeatTime(stores map[string]v1alpha1.TiKVStore) {
+func sweepHeartbeatTime(stores map[string]v1beta1.TiKVStore) {
 	for id, store := range stores {
 		store.LastHeartbeatTime = metav1.Time{}
 		stores[id] = store
@@ -136,7 +137,7 @@ func (ssc *FakeTikvClusterControl) SetUpdateTikvClusterError(err error, after in
 }
 
 // UpdateTikvCluster updates the TikvCluster
-func (ssc *FakeTikvClusterControl) UpdateTikvCluster(tc *v1alpha1.TikvCluster, _ *v1alpha1.TikvClusterStatus, _ *v1alpha1.TikvClusterStatus) (*v1alpha1.TikvCluster, error) {
+func (ssc *FakeTikvClusterControl) UpdateTikvCluster(tc *v1beta1.TikvCluster, _ *v1beta1.TikvClusterStatus, _ *v1beta1.TikvClusterStatus) (*v1beta1.TikvCluster, error) {
 	defer ssc.updateTikvClusterTracker.Inc()
 	if ssc.updateTikvClusterTracker.ErrorReady() {
 		defer ssc.updateTikvClusterTracker.Reset()
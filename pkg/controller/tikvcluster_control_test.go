@@ -20,9 +20,9 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
-	listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1alpha1"
+	listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,13 +38,16 @@ func TestTikvClusterControlUpdateTikvCluster(t *testing.T) {
 	tc.Spec.PD.Replicas = int32(5)
 	fakeClient := &fake.Clientset{}
 	control := NewRealTikvClusterControl(fakeClient, nil, recorder)
+	var subresource string
 	fakeClient.AddReactor("update", "tikvclusters", func(action core.Action) (bool, runtime.Object, error) {
 		update := action.(core.UpdateAction)
+		subresource = update.GetSubresource()
 		return true, update.GetObject(), nil
 	})
-	updateTC, err := control.UpdateTikvCluster(tc, &v1alpha1.TikvClusterStatus{}, &v1alpha1.TikvClusterStatus{})
+	updateTC, err := control.UpdateTikvCluster(tc, &v1beta1.TikvClusterStatus{}, &v1beta1.TikvClusterStatus{})
 	g.Expect(err).To(Succeed())
 	g.Expect(updateTC.Spec.PD.Replicas).To(Equal(int32(5)))
+	g.Expect(subresource).To(Equal("status"))
 }
 
 func TestTikvClusterControlUpdateTikvClusterConflictSuccess(t *testing.T) {
@@ -64,17 +67,17 @@ func TestTikvClusterControlUpdateTikvClusterConflictSuccess(t *testing.T) {
 		}
 		return true, update.GetObject(), nil
 	})
-	_, err := control.UpdateTikvCluster(tc, &v1alpha1.TikvClusterStatus{}, &v1alpha1.TikvClusterStatus{})
+	_, err := control.UpdateTikvCluster(tc, &v1beta1.TikvClusterStatus{}, &v1beta1.TikvClusterStatus{})
 	g.Expect(err).To(Succeed())
 }
 
 func TestDeepEqualExceptHeartbeatTime(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	new := &v1alpha1.TikvClusterStatus{
-		TiKV: v1alpha1.TiKVStatus{
+	new := &v1beta1.TikvClusterStatus{
+		TiKV: v1beta1.TiKVStatus{
 			Synced: true,
-			Stores: map[string]v1alpha1.TiKVStore{
+			Stores: map[string]v1beta1.TiKVStore{
 				"1": {
 					LastHeartbeatTime: metav1.Now(),
 					ID:                "1",
@@ -83,10 +86,10 @@ func TestDeepEqualExceptHeartbeatTime(t *testing.T) {
 		},
 	}
 	time.Sleep(1 * time.Second)
-	old := &v1alpha1.TikvClusterStatus{
-		TiKV: v1alpha1.TiKVStatus{
+	old := &v1beta1.TikvClusterStatus{
+		TiKV: v1beta1.TiKVStatus{
 			Synced: true,
-			Stores: map[string]v1alpha1.TiKVStore{
+			Stores: map[string]v1beta1.TiKVStore{
 				"1": {
 					LastHeartbeatTime: metav1.Now(),
 					ID:                "1",
@@ -19,7 +19,7 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/scheme"
 	"github.com/tikv/tikv-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
@@ -38,7 +38,7 @@ import (
 
 var (
 	// controllerKind contains the schema.GroupVersionKind for tikvcluster controller type.
-	ControllerKind = v1alpha1.SchemeGroupVersion.WithKind("TikvCluster")
+	ControllerKind = v1beta1.SchemeGroupVersion.WithKind("TikvCluster")
 
 	// ClusterScoped controls whether operator should manage kubernetes cluster wide TiDB clusters
 	ClusterScoped bool
@@ -99,7 +99,7 @@ func IsIgnoreError(err error) bool {
 }
 
 // GetOwnerRef returns TikvCluster's OwnerReference
-func GetOwnerRef(tc *v1alpha1.TikvCluster) metav1.OwnerReference {
+func GetOwnerRef(tc *v1beta1.TikvCluster) metav1.OwnerReference {
 	controller := true
 	blockOwnerDeletion := true
 	return metav1.OwnerReference{
@@ -153,6 +153,11 @@ func TiKVMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tikv", clusterName)
 }
 
+// TiKVEncryptionSecretName returns the name of the secret of the master keys mounted to TiKV
+func TiKVEncryptionSecretName(clusterName string) string {
+	return fmt.Sprintf("%s-tikv-encryption", clusterName)
+}
+
 // TiKVPeerMemberName returns tikv peer service name
 func TiKVPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tikv-peer", clusterName)
@@ -209,6 +214,11 @@ func DiscoveryMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-discovery", clusterName)
 }
 
+// DiscoveryStateMemberName returns the name of the configmap which persists the bootstrap state of tikv discovery
+func DiscoveryStateMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-discovery-state", clusterName)
+}
+
 // AnnProm adds annotations for prometheus scraping metrics
 func AnnProm(port int32) map[string]string {
 	return map[string]string{
@@ -256,13 +266,13 @@ func ContainerResource(req corev1.ResourceRequirements) corev1.ResourceRequireme
 // MemberConfigMapName returns the default ConfigMap name of the specified member type
 // Deprecated
 // TODO: remove after helm get totally abandoned
-func MemberConfigMapName(tc *v1alpha1.TikvCluster, member v1alpha1.MemberType) string {
+func MemberConfigMapName(tc *v1beta1.TikvCluster, member v1beta1.MemberType) string {
 	nameKey := fmt.Sprintf("%s-%s", tc.Name, member)
 	return nameKey + getConfigMapSuffix(tc, member.String(), nameKey)
 }
 
 // getConfigMapSuffix return the ConfigMap name suffix
-func getConfigMapSuffix(tc *v1alpha1.TikvCluster, component string, name string) string {
+func getConfigMapSuffix(tc *v1beta1.TikvCluster, component string, name string) string {
 	if tc.Annotations == nil {
 		return ""
 	}
@@ -18,7 +18,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -173,12 +173,12 @@ func TestMemberConfigMapName(t *testing.T) {
 		name        string
 		annotations map[string]string
 		tcName      string
-		member      v1alpha1.MemberType
+		member      v1beta1.MemberType
 		expectFn    func(*GomegaWithT, string)
 	}
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
-		tc := &v1alpha1.TikvCluster{}
+		tc := &v1beta1.TikvCluster{}
 		tc.Name = test.tcName
 		tc.Annotations = test.annotations
 		test.expectFn(g, MemberConfigMapName(tc, test.member))
@@ -188,7 +188,7 @@ func TestMemberConfigMapName(t *testing.T) {
 			name:        "backward compatible when no annotations set",
 			annotations: map[string]string{},
 			tcName:      "cluster-name",
-			member:      v1alpha1.TiKVMemberType,
+			member:      v1beta1.TiKVMemberType,
 			expectFn: func(g *GomegaWithT, s string) {
 				g.Expect(s).To(Equal("cluster-name-tikv"))
 			},
@@ -199,7 +199,7 @@ func TestMemberConfigMapName(t *testing.T) {
 				"tikv.org/tikv.cluster-name-tikv.sha": "uuuuuuuu",
 			},
 			tcName: "cluster-name",
-			member: v1alpha1.TiKVMemberType,
+			member: v1beta1.TiKVMemberType,
 			expectFn: func(g *GomegaWithT, s string) {
 				g.Expect(s).To(Equal("cluster-name-tikv-uuuuuuuu"))
 			},
@@ -208,7 +208,7 @@ func TestMemberConfigMapName(t *testing.T) {
 			name:        "nil annotations",
 			annotations: nil,
 			tcName:      "cluster-name",
-			member:      v1alpha1.TiKVMemberType,
+			member:      v1beta1.TiKVMemberType,
 			expectFn: func(g *GomegaWithT, s string) {
 				g.Expect(s).To(Equal("cluster-name-tikv"))
 			},
@@ -219,7 +219,7 @@ func TestMemberConfigMapName(t *testing.T) {
 				"tikv.org/tikv.cluster-name-tikv.sha": "",
 			},
 			tcName: "cluster-name",
-			member: v1alpha1.TiKVMemberType,
+			member: v1beta1.TiKVMemberType,
 			expectFn: func(g *GomegaWithT, s string) {
 				g.Expect(s).To(Equal("cluster-name-tikv"))
 			},
@@ -230,7 +230,7 @@ func TestMemberConfigMapName(t *testing.T) {
 				"tikv.org/pd.cluster-name-tikv.sha": "",
 			},
 			tcName: "cluster-name",
-			member: v1alpha1.TiKVMemberType,
+			member: v1beta1.TiKVMemberType,
 			expectFn: func(g *GomegaWithT, s string) {
 				g.Expect(s).To(Equal("cluster-name-tikv"))
 			},
@@ -313,8 +313,8 @@ func collectEvents(source <-chan string) []string {
 	return events
 }
 
-func newTikvCluster() *v1alpha1.TikvCluster {
-	tc := &v1alpha1.TikvCluster{
+func newTikvCluster() *v1beta1.TikvCluster {
+	tc := &v1beta1.TikvCluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "demo",
 			Namespace: metav1.NamespaceDefault,
@@ -323,7 +323,7 @@ func newTikvCluster() *v1alpha1.TikvCluster {
 	return tc
 }
 
-func newService(tc *v1alpha1.TikvCluster, _ string) *corev1.Service {
+func newService(tc *v1beta1.TikvCluster, _ string) *corev1.Service {
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      GetName(tc.Name, "pd"),
@@ -333,7 +333,7 @@ func newService(tc *v1alpha1.TikvCluster, _ string) *corev1.Service {
 	return svc
 }
 
-func newStatefulSet(tc *v1alpha1.TikvCluster, _ string) *apps.StatefulSet {
+func newStatefulSet(tc *v1beta1.TikvCluster, _ string) *apps.StatefulSet {
 	set := &apps.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      GetName(tc.Name, "pd"),
@@ -0,0 +1,247 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// VolumeSnapshotGroupVersionKind is the kind of the CSI VolumeSnapshots, the external
+	// snapshotter is not vendored so they are managed as unstructured objects
+	VolumeSnapshotGroupVersionKind = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1beta1", Kind: "VolumeSnapshot"}
+	// VolumeSnapshotListGroupVersionKind is the kind of the list of VolumeSnapshots
+	VolumeSnapshotListGroupVersionKind = VolumeSnapshotGroupVersionKind.GroupVersion().WithKind("VolumeSnapshotList")
+)
+
+// VolumeSnapshotControlInterface manages the CSI VolumeSnapshots of TiKV volumes
+type VolumeSnapshotControlInterface interface {
+	// CreateVolumeSnapshot creates the VolumeSnapshot, it is not owned by the TikvCluster
+	// so that the cluster can be cloned after it is deleted
+	CreateVolumeSnapshot(tc *v1beta1.TikvCluster, snapshot *unstructured.Unstructured) error
+	// UpdateVolumeSnapshot updates the VolumeSnapshot
+	UpdateVolumeSnapshot(tc *v1beta1.TikvCluster, snapshot *unstructured.Unstructured) error
+	// GetVolumeSnapshot gets the VolumeSnapshot by name
+	GetVolumeSnapshot(ns, name string) (*unstructured.Unstructured, error)
+	// ListVolumeSnapshots lists the VolumeSnapshots matching the selector
+	ListVolumeSnapshots(ns string, selector labels.Selector) ([]*unstructured.Unstructured, error)
+}
+
+type realVolumeSnapshotControl struct {
+	cli      client.Client
+	recorder record.EventRecorder
+}
+
+// NewRealVolumeSnapshotControl creates a new VolumeSnapshotControlInterface
+func NewRealVolumeSnapshotControl(cli client.Client, recorder record.EventRecorder) VolumeSnapshotControlInterface {
+	return &realVolumeSnapshotControl{cli, recorder}
+}
+
+func (c *realVolumeSnapshotControl) CreateVolumeSnapshot(tc *v1beta1.TikvCluster, snapshot *unstructured.Unstructured) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	name := snapshot.GetName()
+	err := c.cli.Create(context.TODO(), snapshot)
+	if err != nil {
+		klog.Errorf("failed to create VolumeSnapshot: [%s/%s], TikvCluster: %s, %v", ns, name, tcName, err)
+	} else {
+		klog.V(4).Infof("create VolumeSnapshot: [%s/%s] successfully, TikvCluster: %s", ns, name, tcName)
+	}
+	c.recordVolumeSnapshotEvent("create", tc, name, err)
+	return err
+}
+
+func (c *realVolumeSnapshotControl) UpdateVolumeSnapshot(tc *v1beta1.TikvCluster, snapshot *unstructured.Unstructured) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	name := snapshot.GetName()
+	err := c.cli.Update(context.TODO(), snapshot)
+	if err != nil {
+		klog.Errorf("failed to update VolumeSnapshot: [%s/%s], TikvCluster: %s, %v", ns, name, tcName, err)
+	} else {
+		klog.V(4).Infof("update VolumeSnapshot: [%s/%s] successfully, TikvCluster: %s", ns, name, tcName)
+	}
+	c.recordVolumeSnapshotEvent("update", tc, name, err)
+	return err
+}
+
+func (c *realVolumeSnapshotControl) GetVolumeSnapshot(ns, name string) (*unstructured.Unstructured, error) {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGroupVersionKind)
+	if err := c.cli.Get(context.TODO(), client.ObjectKey{Namespace: ns, Name: name}, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func (c *realVolumeSnapshotControl) ListVolumeSnapshots(ns string, selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(VolumeSnapshotListGroupVersionKind)
+	if err := c.cli.List(context.TODO(), list, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	snapshots := []*unstructured.Unstructured{}
+	for i := range list.Items {
+		snapshots = append(snapshots, &list.Items[i])
+	}
+	return snapshots, nil
+}
+
+func (c *realVolumeSnapshotControl) recordVolumeSnapshotEvent(verb string, tc *v1beta1.TikvCluster, name string, err error) {
+	tcName := tc.GetName()
+	if err == nil {
+		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
+		msg := fmt.Sprintf("%s VolumeSnapshot %s in TikvCluster %s successful",
+			strings.ToLower(verb), name, tcName)
+		c.recorder.Event(tc, corev1.EventTypeNormal, reason, msg)
+	} else {
+		reason := fmt.Sprintf("Failed%s", strings.Title(verb))
+		msg := fmt.Sprintf("%s VolumeSnapshot %s in TikvCluster %s failed error: %s",
+			strings.ToLower(verb), name, tcName, err)
+		c.recorder.Event(tc, corev1.EventTypeWarning, reason, msg)
+	}
+}
+
+var _ VolumeSnapshotControlInterface = &realVolumeSnapshotControl{}
+
+// NewVolumeSnapshot returns the VolumeSnapshot of the pvc, the default VolumeSnapshotClass
+// is used if className is nil
+func NewVolumeSnapshot(ns, name, pvcName string, className *string, labels, annotations map[string]string) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGroupVersionKind)
+	snapshot.SetNamespace(ns)
+	snapshot.SetName(name)
+	snapshot.SetLabels(labels)
+	snapshot.SetAnnotations(annotations)
+	// the values are strings which can't fail to be set
+	_ = unstructured.SetNestedField(snapshot.Object, pvcName, "spec", "source", "persistentVolumeClaimName")
+	if className != nil {
+		_ = unstructured.SetNestedField(snapshot.Object, *className, "spec", "volumeSnapshotClassName")
+	}
+	return snapshot
+}
+
+// VolumeSnapshotReadyToUse returns true if the VolumeSnapshot can be used to provision volumes
+func VolumeSnapshotReadyToUse(snapshot *unstructured.Unstructured) bool {
+	ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+	return ready
+}
+
+// VolumeSnapshotError returns the error message of the VolumeSnapshot, or empty if it has no error
+func VolumeSnapshotError(snapshot *unstructured.Unstructured) string {
+	msg, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message")
+	if !found {
+		return ""
+	}
+	if msg == "" {
+		return "unknown error"
+	}
+	return msg
+}
+
+// FakeVolumeSnapshotControl is a fake VolumeSnapshotControlInterface, it also plays
+// the CSI snapshot controller by SetReadyToUse and SetError
+type FakeVolumeSnapshotControl struct {
+	Snapshots                   map[string]*unstructured.Unstructured
+	createVolumeSnapshotTracker RequestTracker
+}
+
+// NewFakeVolumeSnapshotControl returns a FakeVolumeSnapshotControl
+func NewFakeVolumeSnapshotControl() *FakeVolumeSnapshotControl {
+	return &FakeVolumeSnapshotControl{
+		map[string]*unstructured.Unstructured{},
+		RequestTracker{},
+	}
+}
+
+// SetCreateVolumeSnapshotError sets the error attributes of createVolumeSnapshotTracker
+func (c *FakeVolumeSnapshotControl) SetCreateVolumeSnapshotError(err error, after int) {
+	c.createVolumeSnapshotTracker.SetError(err).SetAfter(after)
+}
+
+// SetReadyToUse marks the VolumeSnapshot ready to use
+func (c *FakeVolumeSnapshotControl) SetReadyToUse(ns, name string) {
+	if snapshot, ok := c.Snapshots[ns+"/"+name]; ok {
+		_ = unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse")
+	}
+}
+
+// SetError marks the VolumeSnapshot failed
+func (c *FakeVolumeSnapshotControl) SetError(ns, name, msg string) {
+	if snapshot, ok := c.Snapshots[ns+"/"+name]; ok {
+		_ = unstructured.SetNestedField(snapshot.Object, msg, "status", "error", "message")
+	}
+}
+
+// CreateVolumeSnapshot adds the VolumeSnapshot to Snapshots
+func (c *FakeVolumeSnapshotControl) CreateVolumeSnapshot(_ *v1beta1.TikvCluster, snapshot *unstructured.Unstructured) error {
+	defer c.createVolumeSnapshotTracker.Inc()
+	if c.createVolumeSnapshotTracker.ErrorReady() {
+		defer c.createVolumeSnapshotTracker.Reset()
+		return c.createVolumeSnapshotTracker.GetError()
+	}
+
+	key := snapshot.GetNamespace() + "/" + snapshot.GetName()
+	if _, ok := c.Snapshots[key]; ok {
+		return errors.NewAlreadyExists(VolumeSnapshotGroupVersionKind.GroupVersion().WithResource("volumesnapshots").GroupResource(), snapshot.GetName())
+	}
+	c.Snapshots[key] = snapshot.DeepCopy()
+	return nil
+}
+
+// UpdateVolumeSnapshot replaces the VolumeSnapshot in Snapshots
+func (c *FakeVolumeSnapshotControl) UpdateVolumeSnapshot(_ *v1beta1.TikvCluster, snapshot *unstructured.Unstructured) error {
+	key := snapshot.GetNamespace() + "/" + snapshot.GetName()
+	if _, ok := c.Snapshots[key]; !ok {
+		return errors.NewNotFound(VolumeSnapshotGroupVersionKind.GroupVersion().WithResource("volumesnapshots").GroupResource(), snapshot.GetName())
+	}
+	c.Snapshots[key] = snapshot.DeepCopy()
+	return nil
+}
+
+// GetVolumeSnapshot gets the VolumeSnapshot from Snapshots
+func (c *FakeVolumeSnapshotControl) GetVolumeSnapshot(ns, name string) (*unstructured.Unstructured, error) {
+	snapshot, ok := c.Snapshots[ns+"/"+name]
+	if !ok {
+		return nil, errors.NewNotFound(VolumeSnapshotGroupVersionKind.GroupVersion().WithResource("volumesnapshots").GroupResource(), name)
+	}
+	return snapshot.DeepCopy(), nil
+}
+
+// ListVolumeSnapshots lists the VolumeSnapshots of Snapshots sorted by name
+func (c *FakeVolumeSnapshotControl) ListVolumeSnapshots(ns string, selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	snapshots := []*unstructured.Unstructured{}
+	for _, snapshot := range c.Snapshots {
+		if snapshot.GetNamespace() == ns && selector.Matches(labels.Set(snapshot.GetLabels())) {
+			snapshots = append(snapshots, snapshot.DeepCopy())
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].GetName() < snapshots[j].GetName() })
+	return snapshots, nil
+}
+
+var _ VolumeSnapshotControlInterface = &FakeVolumeSnapshotControl{}
@@ -0,0 +1,85 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestVolumeSnapshotControl(t *testing.T) {
+	g := NewGomegaWithT(t)
+	recorder := record.NewFakeRecorder(10)
+	tc := newTikvCluster()
+	// the fake client looks up the kinds in the scheme even for unstructured objects
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(VolumeSnapshotGroupVersionKind, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(VolumeSnapshotListGroupVersionKind, &unstructured.UnstructuredList{})
+	control := NewRealVolumeSnapshotControl(fake.NewFakeClientWithScheme(s), recorder)
+
+	for _, set := range []string{"s1", "s2"} {
+		snapshot := NewVolumeSnapshot(tc.Namespace, "tikv-tikv-0-"+set, "tikv-tikv-0", pointer.StringPtr("csi"),
+			map[string]string{"set": set}, nil)
+		g.Expect(control.CreateVolumeSnapshot(tc, snapshot)).To(Succeed())
+	}
+	events := collectEvents(recorder.Events)
+	g.Expect(events).To(HaveLen(2))
+	g.Expect(events[0]).To(ContainSubstring(corev1.EventTypeNormal))
+
+	snapshot, err := control.GetVolumeSnapshot(tc.Namespace, "tikv-tikv-0-s1")
+	g.Expect(err).NotTo(HaveOccurred())
+	pvcName, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	g.Expect(pvcName).To(Equal("tikv-tikv-0"))
+	className, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+	g.Expect(className).To(Equal("csi"))
+
+	snapshot.SetAnnotations(map[string]string{"a": "b"})
+	g.Expect(control.UpdateVolumeSnapshot(tc, snapshot)).To(Succeed())
+	snapshot, err = control.GetVolumeSnapshot(tc.Namespace, "tikv-tikv-0-s1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshot.GetAnnotations()).To(HaveKeyWithValue("a", "b"))
+
+	snapshots, err := control.ListVolumeSnapshots(tc.Namespace, labels.SelectorFromSet(labels.Set{"set": "s2"}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(snapshots).To(HaveLen(1))
+	g.Expect(snapshots[0].GetName()).To(Equal("tikv-tikv-0-s2"))
+}
+
+func TestVolumeSnapshotStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+	control := NewFakeVolumeSnapshotControl()
+	tc := newTikvCluster()
+	g.Expect(control.CreateVolumeSnapshot(tc, NewVolumeSnapshot(tc.Namespace, "snap", "pvc", nil, nil, nil))).To(Succeed())
+
+	snapshot, err := control.GetVolumeSnapshot(tc.Namespace, "snap")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(VolumeSnapshotReadyToUse(snapshot)).To(BeFalse())
+	g.Expect(VolumeSnapshotError(snapshot)).To(BeEmpty())
+
+	control.SetReadyToUse(tc.Namespace, "snap")
+	snapshot, _ = control.GetVolumeSnapshot(tc.Namespace, "snap")
+	g.Expect(VolumeSnapshotReadyToUse(snapshot)).To(BeTrue())
+
+	control.SetError(tc.Namespace, "snap", "disk quota exceeded")
+	snapshot, _ = control.GetVolumeSnapshot(tc.Namespace, "snap")
+	g.Expect(VolumeSnapshotError(snapshot)).To(Equal("disk quota exceeded"))
+}
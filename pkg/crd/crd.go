@@ -0,0 +1,219 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crd generates the CustomResourceDefinitions of the tikv-operator
+// from the Go types and installs them
+package crd
+
+import (
+	"reflect"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	tikvwebhook "github.com/tikv/tikv-operator/pkg/webhook"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsinstall "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var scheme = runtime.NewScheme()
+
+var tikvClusterCRDName = "tikvclusters." + v1beta1.SchemeGroupVersion.Group
+
+func init() {
+	apiextensionsinstall.Install(scheme)
+}
+
+// ConversionWebhook is the service of the webhook which converts the custom resources
+// between the API versions
+type ConversionWebhook struct {
+	Namespace string
+	Name      string
+	// CABundle is the PEM encoded CA bundle which signs the certificate of the webhook server
+	CABundle []byte
+}
+
+// DefaultConversionWebhook is the conversion webhook set in the CRD manifests
+var DefaultConversionWebhook = ConversionWebhook{
+	Namespace: "tikv-operator",
+	Name:      "tikv-operator-webhook",
+}
+
+// TikvClusterCRD returns the apiextensions.k8s.io/v1 CustomResourceDefinition of TikvCluster.
+// Both v1alpha1 and v1beta1 are served, the TikvClusters are stored in v1beta1 and converted
+// by the conversion webhook.
+func TikvClusterCRD(webhook ConversionWebhook) *apiextensionsv1.CustomResourceDefinition {
+	v1alpha1Schema := SchemaOf(reflect.TypeOf(v1alpha1.TikvCluster{}))
+	v1beta1Schema := SchemaOf(reflect.TypeOf(v1beta1.TikvCluster{}))
+	path := tikvwebhook.ConversionPath
+	port := int32(443)
+	return &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: tikvClusterCRDName,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: v1beta1.SchemeGroupVersion.Group,
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "tikvclusters",
+				Singular: "tikvcluster",
+				Kind:     "TikvCluster",
+				ListKind: "TikvClusterList",
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    v1alpha1.SchemeGroupVersion.Version,
+					Served:  true,
+					Storage: false,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1alpha1Schema,
+					},
+					Subresources:             tikvClusterSubresources(),
+					AdditionalPrinterColumns: printerColumns(".spec.version", "The version of the cluster"),
+				},
+				{
+					Name:    v1beta1.SchemeGroupVersion.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1beta1Schema,
+					},
+					Subresources:             tikvClusterSubresources(),
+					AdditionalPrinterColumns: printerColumns(".spec.tikv.version", "The version of TiKV cluster"),
+				},
+			},
+			Conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service: &apiextensionsv1.ServiceReference{
+							Namespace: webhook.Namespace,
+							Name:      webhook.Name,
+							Path:      &path,
+							Port:      &port,
+						},
+						CABundle: webhook.CABundle,
+					},
+					// the conversion webhook of controller-runtime only supports v1beta1 ConversionReview
+					ConversionReviewVersions: []string{apiextensionsv1beta1.SchemeGroupVersion.Version},
+				},
+			},
+		},
+	}
+}
+
+// TikvClusterCRDV1beta1 returns the apiextensions.k8s.io/v1beta1 CustomResourceDefinition
+// of TikvCluster for Kubernetes 1.15, the earlier versions do not support the conversion
+// webhook and the pruning by default
+func TikvClusterCRDV1beta1(webhook ConversionWebhook) (*apiextensionsv1beta1.CustomResourceDefinition, error) {
+	internal := &apiextensions.CustomResourceDefinition{}
+	if err := scheme.Convert(TikvClusterCRD(webhook), internal, nil); err != nil {
+		return nil, err
+	}
+	crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+	if err := scheme.Convert(internal, crd, nil); err != nil {
+		return nil, err
+	}
+	crd.APIVersion = apiextensionsv1beta1.SchemeGroupVersion.String()
+	crd.Kind = "CustomResourceDefinition"
+	// v1 CRDs always prune the unknown fields
+	crd.Spec.PreserveUnknownFields = boolPtr(false)
+	return crd, nil
+}
+
+// tikvClusterSubresources returns the subresources of TikvCluster, the scale subresource
+// scales TiKV, e.g. by kubectl scale or the HorizontalPodAutoscaler. Its status replicas
+// exclude the failover replicas, which are not counted in .spec.tikv.replicas.
+func tikvClusterSubresources() *apiextensionsv1.CustomResourceSubresources {
+	selectorPath := ".status.tikv.selector"
+	return &apiextensionsv1.CustomResourceSubresources{
+		Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+		Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+			SpecReplicasPath:   ".spec.tikv.replicas",
+			StatusReplicasPath: ".status.tikv.replicas",
+			LabelSelectorPath:  &selectorPath,
+		},
+	}
+}
+
+// printerColumns returns the additional printer columns of TikvCluster, the version column
+// is read from versionPath
+func printerColumns(versionPath, versionDescription string) []apiextensionsv1.CustomResourceColumnDefinition {
+	return []apiextensionsv1.CustomResourceColumnDefinition{
+		{
+			Name:     "Ready",
+			Type:     "string",
+			JSONPath: `.status.conditions[?(@.type=="Ready")].status`,
+		},
+		{
+			Name:        "Version",
+			Type:        "string",
+			Description: versionDescription,
+			JSONPath:    versionPath,
+		},
+		{
+			Name:        "PD",
+			Type:        "string",
+			Description: "The image for PD cluster",
+			JSONPath:    ".status.pd.image",
+		},
+		{
+			Name:        "Desire",
+			Type:        "integer",
+			Description: "The desired replicas number of PD cluster",
+			JSONPath:    ".spec.pd.replicas",
+		},
+		{
+			Name:        "Current",
+			Type:        "integer",
+			Description: "The current replicas number of PD cluster",
+			JSONPath:    ".status.pd.statefulSet.readyReplicas",
+		},
+		{
+			Name:        "TiKV",
+			Type:        "string",
+			Description: "The image for TiKV cluster",
+			JSONPath:    ".status.tikv.image",
+		},
+		{
+			Name:        "Desire",
+			Type:        "integer",
+			Description: "The desired replicas number of TiKV cluster",
+			JSONPath:    ".spec.tikv.replicas",
+		},
+		{
+			Name:        "Current",
+			Type:        "integer",
+			Description: "The current replicas number of TiKV cluster",
+			JSONPath:    ".status.tikv.statefulSet.readyReplicas",
+		},
+		{
+			Name:     "Age",
+			Type:     "date",
+			JSONPath: ".metadata.creationTimestamp",
+		},
+		{
+			Name:     "Status",
+			Type:     "string",
+			Priority: 1,
+			JSONPath: `.status.conditions[?(@.type=="Ready")].message`,
+		},
+	}
+}
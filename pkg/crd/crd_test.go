@@ -0,0 +1,367 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/validation"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/client-go/testing"
+)
+
+func TestSchemaOf(t *testing.T) {
+	type inlined struct {
+		Inlined string `json:"inlined"`
+	}
+	type node struct {
+		Children []node `json:"children"`
+	}
+	type testStruct struct {
+		metav1.TypeMeta `json:",inline"`
+		inlined         `json:",inline"`
+		Name            string `json:"name"`
+		Replicas        *int32 `json:"replicas,omitempty"`
+		Skipped         string `json:"-"`
+		Untagged        bool
+		Storage         resource.Quantity            `json:"storage"`
+		Port            intstr.IntOrString           `json:"port"`
+		Time            metav1.Time                  `json:"time"`
+		Labels          map[string]string            `json:"labels"`
+		Resources       map[string]resource.Quantity `json:"resources"`
+		Tree            node                         `json:"tree"`
+		unexported      string
+	}
+
+	schema := SchemaOf(reflect.TypeOf(testStruct{}))
+	g := NewGomegaWithT(t)
+	g.Expect(schema.Type).To(Equal("object"))
+	keys := []string{}
+	for key := range schema.Properties {
+		keys = append(keys, key)
+	}
+	g.Expect(keys).To(ConsistOf("apiVersion", "kind", "inlined", "name", "replicas", "Untagged", "storage", "port", "time", "labels", "resources", "tree"))
+	g.Expect(schema.Properties["replicas"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int32"}))
+	g.Expect(schema.Properties["storage"].XIntOrString).To(BeTrue())
+	g.Expect(schema.Properties["port"].XIntOrString).To(BeTrue())
+	g.Expect(schema.Properties["time"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "string", Format: "date-time"}))
+	g.Expect(schema.Properties["labels"].AdditionalProperties.Schema.Type).To(Equal("string"))
+	g.Expect(schema.Properties["resources"].AdditionalProperties.Schema.XIntOrString).To(BeTrue())
+	children := schema.Properties["tree"].Properties["children"].Items.Schema
+	g.Expect(children.XPreserveUnknownFields).To(Equal(boolPtr(true)))
+}
+
+// TestFieldValidations checks that fieldValidations matches the +kubebuilder:validation
+// markers of the API types
+func TestFieldValidations(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	expected := map[string]fieldValidation{}
+	for _, dir := range []string{"../apis/tikv/v1alpha1", "../apis/tikv/v1beta1"} {
+		pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+			return !strings.HasSuffix(info.Name(), "_test.go")
+		}, parser.ParseComments)
+		g.Expect(err).NotTo(HaveOccurred())
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				for typeName, fields := range markedFields(file) {
+					for fieldName, validation := range fields {
+						expected[filepath.Base(dir)+"."+typeName+"."+fieldName] = validation
+					}
+				}
+			}
+		}
+	}
+	g.Expect(expected).NotTo(BeEmpty())
+
+	actual := map[string]fieldValidation{}
+	for typ, fields := range fieldValidations {
+		for fieldName, validation := range fields {
+			actual[filepath.Base(typ.PkgPath())+"."+typ.Name()+"."+fieldName] = validation
+		}
+	}
+	g.Expect(actual).To(Equal(expected))
+}
+
+// markedFields returns the validations of the struct fields with the
+// +kubebuilder:validation markers in file, keyed by the type name and the JSON name
+func markedFields(file *ast.File) map[string]map[string]fieldValidation {
+	result := map[string]map[string]fieldValidation{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return false
+		}
+		for _, field := range st.Fields.List {
+			if field.Doc == nil || field.Tag == nil {
+				continue
+			}
+			var validation fieldValidation
+			marked := false
+			for _, comment := range field.Doc.List {
+				marker := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+				if !strings.HasPrefix(marker, "+kubebuilder:validation:") {
+					continue
+				}
+				kv := strings.SplitN(strings.TrimPrefix(marker, "+kubebuilder:validation:"), "=", 2)
+				switch kv[0] {
+				case "Minimum":
+					min, _ := strconv.ParseFloat(kv[1], 64)
+					validation.Minimum = &min
+				case "Maximum":
+					max, _ := strconv.ParseFloat(kv[1], 64)
+					validation.Maximum = &max
+				case "Enum":
+					validation.Enum = strings.Split(kv[1], ",")
+				default:
+					continue
+				}
+				marked = true
+			}
+			if !marked {
+				continue
+			}
+			tag, _ := strconv.Unquote(field.Tag.Value)
+			name := strings.Split(reflect.StructTag(tag).Get("json"), ",")[0]
+			if result[spec.Name.Name] == nil {
+				result[spec.Name.Name] = map[string]fieldValidation{}
+			}
+			result[spec.Name.Name][name] = validation
+		}
+		return false
+	})
+	return result
+}
+
+func TestTikvClusterCRD(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	crd := TikvClusterCRD(ConversionWebhook{Namespace: "ns", Name: "webhook", CABundle: []byte("ca")})
+	internal := &apiextensions.CustomResourceDefinition{}
+	g.Expect(scheme.Convert(crd, internal, nil)).To(Succeed())
+	internal.Status.StoredVersions = []string{"v1alpha1", "v1beta1"}
+	g.Expect(validation.ValidateCustomResourceDefinition(internal, apiextensionsv1.SchemeGroupVersion)).To(BeEmpty())
+
+	g.Expect(crd.Spec.Versions).To(HaveLen(2))
+	v1alpha1, v1beta1 := crd.Spec.Versions[0], crd.Spec.Versions[1]
+	g.Expect(v1alpha1.Name).To(Equal("v1alpha1"))
+	g.Expect(v1alpha1.Storage).To(BeFalse())
+	g.Expect(v1beta1.Name).To(Equal("v1beta1"))
+	g.Expect(v1beta1.Storage).To(BeTrue())
+	schema := v1beta1.Schema.OpenAPIV3Schema
+	g.Expect(schema.Properties["metadata"]).To(Equal(apiextensionsv1.JSONSchemaProps{Type: "object"}))
+	g.Expect(schema.Properties["spec"].Properties["tikv"].Properties["replicas"].Type).To(Equal("integer"))
+	g.Expect(schema.Properties["spec"].Properties["tikv"].Properties["version"].Type).To(Equal("string"))
+	g.Expect(schema.Properties["spec"].Properties).NotTo(HaveKey("version"))
+	g.Expect(v1alpha1.Schema.OpenAPIV3Schema.Properties["spec"].Properties["version"].Type).To(Equal("string"))
+	g.Expect(v1alpha1.Subresources.Status).NotTo(BeNil())
+	g.Expect(v1beta1.Subresources.Status).NotTo(BeNil())
+	g.Expect(v1beta1.Subresources.Scale.SpecReplicasPath).To(Equal(".spec.tikv.replicas"))
+	g.Expect(*v1beta1.Subresources.Scale.LabelSelectorPath).To(Equal(".status.tikv.selector"))
+	g.Expect(schema.Properties["status"].Properties["tikv"].Properties["selector"].Type).To(Equal("string"))
+	g.Expect(*schema.Properties["spec"].Properties["tikv"].Properties["replicas"].Minimum).To(Equal(float64(1)))
+	scalePolicy := schema.Properties["spec"].Properties["tikv"].Properties["scalePolicy"]
+	g.Expect(*scalePolicy.Properties["scaleInHighWaterMarkPercent"].Maximum).To(Equal(float64(100)))
+	g.Expect(schema.Properties["spec"].Properties["tikv"].Properties["configUpdateStrategy"].Enum).To(HaveLen(2))
+	g.Expect(v1alpha1.AdditionalPrinterColumns[1].JSONPath).To(Equal(".spec.version"))
+	g.Expect(v1beta1.AdditionalPrinterColumns[1].JSONPath).To(Equal(".spec.tikv.version"))
+	g.Expect(crd.Spec.Conversion.Strategy).To(Equal(apiextensionsv1.WebhookConverter))
+	clientConfig := crd.Spec.Conversion.Webhook.ClientConfig
+	g.Expect(clientConfig.Service.Namespace).To(Equal("ns"))
+	g.Expect(clientConfig.Service.Name).To(Equal("webhook"))
+	g.Expect(*clientConfig.Service.Path).To(Equal("/convert"))
+	g.Expect(clientConfig.CABundle).To(Equal([]byte("ca")))
+
+	crdV1beta1, err := TikvClusterCRDV1beta1(DefaultConversionWebhook)
+	g.Expect(err).NotTo(HaveOccurred())
+	internal = &apiextensions.CustomResourceDefinition{}
+	g.Expect(scheme.Convert(crdV1beta1, internal, nil)).To(Succeed())
+	internal.Status.StoredVersions = []string{"v1alpha1", "v1beta1"}
+	g.Expect(validation.ValidateCustomResourceDefinition(internal, apiextensionsv1beta1.SchemeGroupVersion)).To(BeEmpty())
+	g.Expect(crdV1beta1.Spec.Versions).To(HaveLen(2))
+	// the identical subresources of the versions are set at the top level
+	g.Expect(crdV1beta1.Spec.Subresources.Status).NotTo(BeNil())
+	g.Expect(crdV1beta1.Spec.Subresources.Scale.StatusReplicasPath).To(Equal(".status.tikv.replicas"))
+	for _, version := range crdV1beta1.Spec.Versions {
+		g.Expect(version.AdditionalPrinterColumns).To(HaveLen(len(v1beta1.AdditionalPrinterColumns)))
+	}
+	g.Expect(crdV1beta1.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["version"].Type).To(Equal("string"))
+	g.Expect(crdV1beta1.Spec.Conversion.Strategy).To(Equal(apiextensionsv1beta1.WebhookConverter))
+	g.Expect(crdV1beta1.Spec.Conversion.WebhookClientConfig.Service.Name).To(Equal("tikv-operator-webhook"))
+}
+
+func establishOnWrite(action core.Action) (bool, runtime.Object, error) {
+	switch crd := action.(core.CreateAction).GetObject().(type) {
+	case *apiextensionsv1.CustomResourceDefinition:
+		crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}}
+	case *apiextensionsv1beta1.CustomResourceDefinition:
+		crd.Status.Conditions = []apiextensionsv1beta1.CustomResourceDefinitionCondition{{Type: apiextensionsv1beta1.Established, Status: apiextensionsv1beta1.ConditionTrue}}
+	}
+	return false, nil, nil
+}
+
+func TestInstall(t *testing.T) {
+	tests := []struct {
+		name         string
+		groupVersion string
+		existing     []runtime.Object
+	}{
+		{
+			name:         "create v1",
+			groupVersion: "apiextensions.k8s.io/v1",
+		},
+		{
+			name:         "update v1",
+			groupVersion: "apiextensions.k8s.io/v1",
+			existing: []runtime.Object{&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "tikvclusters.tikv.org", ResourceVersion: "1"},
+			}},
+		},
+		{
+			name:         "create v1beta1",
+			groupVersion: "apiextensions.k8s.io/v1beta1",
+		},
+		{
+			name:         "update v1beta1",
+			groupVersion: "apiextensions.k8s.io/v1beta1",
+			existing: []runtime.Object{&apiextensionsv1beta1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "tikvclusters.tikv.org", ResourceVersion: "1"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			cli := fake.NewSimpleClientset(test.existing...)
+			cli.Resources = []*metav1.APIResourceList{{GroupVersion: test.groupVersion}}
+			cli.PrependReactor("create", "customresourcedefinitions", establishOnWrite)
+			cli.PrependReactor("update", "customresourcedefinitions", establishOnWrite)
+
+			g.Expect(Install(cli, ConversionWebhook{Namespace: "ns", Name: "webhook", CABundle: []byte("ca")})).To(Succeed())
+			if test.groupVersion == "apiextensions.k8s.io/v1" {
+				crd, err := cli.ApiextensionsV1().CustomResourceDefinitions().Get("tikvclusters.tikv.org", metav1.GetOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(crd.Spec.Versions).To(HaveLen(2))
+				g.Expect(crd.Spec.Conversion.Webhook.ClientConfig.Service.Namespace).To(Equal("ns"))
+				g.Expect(crd.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal([]byte("ca")))
+			} else {
+				crd, err := cli.ApiextensionsV1beta1().CustomResourceDefinitions().Get("tikvclusters.tikv.org", metav1.GetOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(crd.Spec.Versions).To(HaveLen(2))
+				g.Expect(crd.Spec.Conversion.WebhookClientConfig.Service.Namespace).To(Equal("ns"))
+				g.Expect(crd.Spec.Conversion.WebhookClientConfig.CABundle).To(Equal([]byte("ca")))
+			}
+		})
+	}
+}
+
+func TestConversionWebhookRequired(t *testing.T) {
+	webhook := ConversionWebhook{Namespace: "ns", Name: "webhook"}
+	crdV1beta1, err := TikvClusterCRDV1beta1(webhook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crdV1beta1.Status.StoredVersions = []string{"v1alpha1", "v1beta1"}
+	// the TikvClusters may be stored in v1alpha1
+	upgradedV1 := TikvClusterCRD(webhook)
+	upgradedV1.Status.StoredVersions = []string{"v1alpha1", "v1beta1"}
+	installedV1 := TikvClusterCRD(webhook)
+	installedV1.Status.StoredVersions = []string{"v1beta1"}
+	noneV1 := TikvClusterCRD(webhook)
+	noneV1.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+	noneV1.Status.StoredVersions = []string{"v1alpha1", "v1beta1"}
+
+	tests := []struct {
+		name         string
+		groupVersion string
+		existing     []runtime.Object
+		webhook      ConversionWebhook
+		expected     bool
+	}{
+		{
+			name:         "crd is not installed",
+			groupVersion: "apiextensions.k8s.io/v1",
+			webhook:      webhook,
+			expected:     false,
+		},
+		{
+			name:         "v1 crd converts by the webhook",
+			groupVersion: "apiextensions.k8s.io/v1",
+			existing:     []runtime.Object{upgradedV1},
+			webhook:      webhook,
+			expected:     true,
+		},
+		{
+			name:         "v1 crd converts by the webhook but nothing is stored in v1alpha1",
+			groupVersion: "apiextensions.k8s.io/v1",
+			existing:     []runtime.Object{installedV1},
+			webhook:      webhook,
+			expected:     false,
+		},
+		{
+			name:         "v1 crd converts by another webhook",
+			groupVersion: "apiextensions.k8s.io/v1",
+			existing:     []runtime.Object{upgradedV1},
+			webhook:      ConversionWebhook{Namespace: "ns", Name: "other"},
+			expected:     false,
+		},
+		{
+			name:         "v1 crd does not convert",
+			groupVersion: "apiextensions.k8s.io/v1",
+			existing:     []runtime.Object{noneV1},
+			webhook:      webhook,
+			expected:     false,
+		},
+		{
+			name:         "v1beta1 crd converts by the webhook",
+			groupVersion: "apiextensions.k8s.io/v1beta1",
+			existing:     []runtime.Object{crdV1beta1},
+			webhook:      webhook,
+			expected:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			cli := fake.NewSimpleClientset(test.existing...)
+			cli.Resources = []*metav1.APIResourceList{{GroupVersion: test.groupVersion}}
+
+			required, err := ConversionWebhookRequired(cli, test.webhook)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(required).To(Equal(test.expected))
+		})
+	}
+}
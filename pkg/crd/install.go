@@ -0,0 +1,161 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	utildiscovery "github.com/tikv/tikv-operator/pkg/util/discovery"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+// EstablishTimeout is the timeout to wait for the installed CRDs to be established
+var EstablishTimeout = time.Minute
+
+// Install creates the CRDs of the tikv-operator, or updates them if they exist, then
+// waits for them to be established. The apiextensions.k8s.io/v1 CRDs are used if the
+// cluster serves them. The custom resources are converted between the API versions by
+// the given webhook.
+func Install(cli apiextensionsclientset.Interface, webhook ConversionWebhook) error {
+	v1Supported, err := utildiscovery.IsAPIGroupVersionSupported(cli.Discovery(), apiextensionsv1.SchemeGroupVersion.String())
+	if err != nil {
+		return err
+	}
+	if v1Supported {
+		return installV1(cli, TikvClusterCRD(webhook))
+	}
+	crd, err := TikvClusterCRDV1beta1(webhook)
+	if err != nil {
+		return err
+	}
+	return installV1beta1(cli, crd)
+}
+
+// ConversionWebhookRequired returns whether the installed TikvCluster CRD converts the
+// TikvClusters by the given webhook service and some of them may be stored in v1alpha1.
+// These TikvClusters can not be read if the webhook is not served then. The webhook is
+// not called if all the TikvClusters are stored and requested in v1beta1.
+func ConversionWebhookRequired(cli apiextensionsclientset.Interface, webhook ConversionWebhook) (bool, error) {
+	v1Supported, err := utildiscovery.IsAPIGroupVersionSupported(cli.Discovery(), apiextensionsv1.SchemeGroupVersion.String())
+	if err != nil {
+		return false, err
+	}
+	var service *apiextensionsv1.ServiceReference
+	var storedVersions []string
+	if v1Supported {
+		crd, err := cli.ApiextensionsV1().CustomResourceDefinitions().Get(tikvClusterCRDName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		conversion := crd.Spec.Conversion
+		if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+			return false, nil
+		}
+		service = conversion.Webhook.ClientConfig.Service
+		storedVersions = crd.Status.StoredVersions
+	} else {
+		crd, err := cli.ApiextensionsV1beta1().CustomResourceDefinitions().Get(tikvClusterCRDName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		conversion := crd.Spec.Conversion
+		if conversion == nil || conversion.Strategy != apiextensionsv1beta1.WebhookConverter || conversion.WebhookClientConfig == nil || conversion.WebhookClientConfig.Service == nil {
+			return false, nil
+		}
+		service = &apiextensionsv1.ServiceReference{
+			Namespace: conversion.WebhookClientConfig.Service.Namespace,
+			Name:      conversion.WebhookClientConfig.Service.Name,
+		}
+		storedVersions = crd.Status.StoredVersions
+	}
+	if service == nil || service.Namespace != webhook.Namespace || service.Name != webhook.Name {
+		return false, nil
+	}
+	for _, version := range storedVersions {
+		if version == v1alpha1.SchemeGroupVersion.Version {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func installV1(cli apiextensionsclientset.Interface, crd *apiextensionsv1.CustomResourceDefinition) error {
+	crdCli := cli.ApiextensionsV1().CustomResourceDefinitions()
+	existing, err := crdCli.Get(crd.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = crdCli.Create(crd)
+	} else if err == nil {
+		crd.ResourceVersion = existing.ResourceVersion
+		_, err = crdCli.Update(crd)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to install CRD %s: %v", crd.Name, err)
+	}
+	klog.Infof("CRD %s installed", crd.Name)
+
+	return wait.PollImmediate(time.Second, EstablishTimeout, func() (bool, error) {
+		crd, err := crdCli.Get(crd.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, cond := range crd.Status.Conditions {
+			if cond.Type == apiextensionsv1.Established && cond.Status == apiextensionsv1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
+
+func installV1beta1(cli apiextensionsclientset.Interface, crd *apiextensionsv1beta1.CustomResourceDefinition) error {
+	crdCli := cli.ApiextensionsV1beta1().CustomResourceDefinitions()
+	existing, err := crdCli.Get(crd.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = crdCli.Create(crd)
+	} else if err == nil {
+		crd.ResourceVersion = existing.ResourceVersion
+		_, err = crdCli.Update(crd)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to install CRD %s: %v", crd.Name, err)
+	}
+	klog.Infof("CRD %s installed", crd.Name)
+
+	return wait.PollImmediate(time.Second, EstablishTimeout, func() (bool, error) {
+		crd, err := crdCli.Get(crd.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, cond := range crd.Status.Conditions {
+			if cond.Type == apiextensionsv1beta1.Established && cond.Status == apiextensionsv1beta1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	quantityType    = reflect.TypeOf(resource.Quantity{})
+	intOrStringType = reflect.TypeOf(intstr.IntOrString{})
+	timeType        = reflect.TypeOf(metav1.Time{})
+	microTimeType   = reflect.TypeOf(metav1.MicroTime{})
+	durationType    = reflect.TypeOf(metav1.Duration{})
+	objectMetaType  = reflect.TypeOf(metav1.ObjectMeta{})
+	rawMessageType  = reflect.TypeOf(json.RawMessage{})
+	bytesType       = reflect.TypeOf([]byte{})
+)
+
+// SchemaOf returns the structural OpenAPI v3 schema of the JSON encoding of t.
+// The schema follows the json tags of the struct fields, the types with custom
+// JSON encodings of apimachinery are mapped to their wire formats. The validations
+// of the fields in fieldValidations are added to their schemas.
+func SchemaOf(t reflect.Type) apiextensionsv1.JSONSchemaProps {
+	return schemaOf(t, map[reflect.Type]bool{})
+}
+
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) apiextensionsv1.JSONSchemaProps {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case quantityType, intOrStringType:
+		return apiextensionsv1.JSONSchemaProps{XIntOrString: true}
+	case timeType, microTimeType:
+		return apiextensionsv1.JSONSchemaProps{Type: "string", Format: "date-time"}
+	case durationType:
+		return apiextensionsv1.JSONSchemaProps{Type: "string"}
+	case objectMetaType:
+		// the schema of the metadata is provided by the apiserver
+		return apiextensionsv1.JSONSchemaProps{Type: "object"}
+	case rawMessageType:
+		return apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: boolPtr(true)}
+	case bytesType:
+		return apiextensionsv1.JSONSchemaProps{Type: "string", Format: "byte"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return apiextensionsv1.JSONSchemaProps{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return apiextensionsv1.JSONSchemaProps{Type: "number"}
+	case reflect.String:
+		return apiextensionsv1.JSONSchemaProps{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		items := schemaOf(t.Elem(), visiting)
+		return apiextensionsv1.JSONSchemaProps{
+			Type:  "array",
+			Items: &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &items},
+		}
+	case reflect.Map:
+		values := schemaOf(t.Elem(), visiting)
+		return apiextensionsv1.JSONSchemaProps{
+			Type:                 "object",
+			AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: &values},
+		}
+	case reflect.Interface:
+		return apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: boolPtr(true)}
+	case reflect.Struct:
+		if visiting[t] {
+			// recursive types are not expressible in a structural schema
+			return apiextensionsv1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: boolPtr(true)}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		props := apiextensionsv1.JSONSchemaProps{
+			Type:       "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{},
+		}
+		addStructFields(props.Properties, t, visiting)
+		return props
+	}
+	panic(fmt.Sprintf("unsupported type %s", t))
+}
+
+func addStructFields(properties map[string]apiextensionsv1.JSONSchemaProps, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		name, inline := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			addStructFields(properties, ft, visiting)
+			continue
+		}
+		schema := schemaOf(field.Type, visiting)
+		if validation, ok := fieldValidations[t][name]; ok {
+			validation.apply(&schema)
+		}
+		properties[name] = schema
+	}
+}
+
+// jsonName returns the name of the field in the JSON encoding, and whether the
+// fields of the field are inlined into the parent
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && ft.Kind() == reflect.Struct {
+			return "", true
+		}
+		return field.Name, false
+	}
+	return name, false
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crd
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// fieldValidation is the validation of a struct field declared by the
+// +kubebuilder:validation markers in the Go types
+type fieldValidation struct {
+	Minimum *float64
+	Maximum *float64
+	Enum    []string
+}
+
+// fieldValidations are the validations of the struct fields keyed by the struct
+// type and the JSON name of the field. They must be kept in sync with the
+// +kubebuilder:validation markers of the API types, which is checked by the tests.
+var fieldValidations = map[reflect.Type]map[string]fieldValidation{
+	reflect.TypeOf(v1beta1.DiscoverySpec{}): {
+		"replicas": {Minimum: float64Ptr(1)},
+	},
+	reflect.TypeOf(v1beta1.PDSpec{}): {
+		"replicas":         {Minimum: float64Ptr(1)},
+		"maxFailoverCount": {Minimum: float64Ptr(0)},
+	},
+	reflect.TypeOf(v1beta1.FailoverSpec{}): {
+		"maxCount": {Minimum: float64Ptr(0)},
+	},
+	reflect.TypeOf(v1beta1.ScalePolicy{}): {
+		"scaleOutParallelism":         {Minimum: float64Ptr(1)},
+		"scaleInParallelism":          {Minimum: float64Ptr(1)},
+		"scaleInHighWaterMarkPercent": {Minimum: float64Ptr(1), Maximum: float64Ptr(100)},
+	},
+	reflect.TypeOf(v1beta1.TiKVSpec{}): {
+		"replicas":         {Minimum: float64Ptr(1)},
+		"maxFailoverCount": {Minimum: float64Ptr(0)},
+	},
+	reflect.TypeOf(v1beta1.ComponentSpec{}): {
+		"configUpdateStrategy": {Enum: []string{"InPlace", "RollingUpdate"}},
+	},
+	reflect.TypeOf(v1alpha1.DiscoverySpec{}): {
+		"replicas": {Minimum: float64Ptr(1)},
+	},
+	reflect.TypeOf(v1alpha1.PDSpec{}): {
+		"replicas":         {Minimum: float64Ptr(1)},
+		"maxFailoverCount": {Minimum: float64Ptr(0)},
+	},
+	reflect.TypeOf(v1alpha1.FailoverSpec{}): {
+		"maxCount": {Minimum: float64Ptr(0)},
+	},
+	reflect.TypeOf(v1alpha1.ScalePolicy{}): {
+		"scaleOutParallelism":         {Minimum: float64Ptr(1)},
+		"scaleInParallelism":          {Minimum: float64Ptr(1)},
+		"scaleInHighWaterMarkPercent": {Minimum: float64Ptr(1), Maximum: float64Ptr(100)},
+	},
+	reflect.TypeOf(v1alpha1.TiKVSpec{}): {
+		"replicas":         {Minimum: float64Ptr(1)},
+		"maxFailoverCount": {Minimum: float64Ptr(0)},
+	},
+	reflect.TypeOf(v1alpha1.TikvClusterSpec{}): {
+		"configUpdateStrategy": {Enum: []string{"InPlace", "RollingUpdate"}},
+	},
+}
+
+// apply sets the validation on the schema of the field
+func (v fieldValidation) apply(schema *apiextensionsv1.JSONSchemaProps) {
+	schema.Minimum = v.Minimum
+	schema.Maximum = v.Maximum
+	for _, value := range v.Enum {
+		raw, _ := json.Marshal(value)
+		schema.Enum = append(schema.Enum, apiextensionsv1.JSON{Raw: raw})
+	}
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
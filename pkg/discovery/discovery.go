@@ -19,10 +19,13 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
+	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 )
@@ -34,23 +37,29 @@ type PDDiscovery interface {
 
 type pdDiscovery struct {
 	cli       versioned.Interface
+	kubeCli   kubernetes.Interface
 	lock      sync.Mutex
 	clusters  map[string]*clusterInfo
-	tcGetFn   func(ns, tcName string) (*v1alpha1.TikvCluster, error)
+	store     clusterStore
+	tcGetFn   func(ns, tcName string) (*v1beta1.TikvCluster, error)
 	pdControl pdapi.PDControlInterface
 }
 
 type clusterInfo struct {
 	resourceVersion string
 	peers           map[string]struct{}
+	// version is the version of the persisted state
+	version string
 }
 
 // NewPDDiscovery returns a PDDiscovery
 func NewPDDiscovery(cli versioned.Interface, kubeCli kubernetes.Interface) PDDiscovery {
 	td := &pdDiscovery{
 		cli:       cli,
+		kubeCli:   kubeCli,
 		pdControl: pdapi.NewDefaultPDControl(kubeCli),
 		clusters:  map[string]*clusterInfo{},
+		store:     &configMapStore{kubeCli: kubeCli},
 	}
 	td.tcGetFn = td.realTCGetFn
 	return td
@@ -64,39 +73,88 @@ func (td *pdDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		return "", fmt.Errorf("advertisePeerUrl is empty")
 	}
 	klog.Infof("advertisePeerUrl is: %s", advertisePeerUrl)
-	strArr := strings.Split(advertisePeerUrl, ".")
-	if len(strArr) != 4 {
-		return "", fmt.Errorf("advertisePeerUrl format is wrong: %s", advertisePeerUrl)
+	peer, err := ParseAdvertisePeerURL(advertisePeerUrl)
+	if err != nil {
+		return "", err
 	}
 
-	podName, peerServiceName, ns := strArr[0], strArr[1], strArr[2]
-	tcName := strings.TrimSuffix(peerServiceName, "-pd-peer")
+	podName, peerServiceName, ns := peer.PodName, peer.PeerServiceName, peer.Namespace
 	podNamespace := os.Getenv("MY_POD_NAMESPACE")
 	if ns != podNamespace {
 		return "", fmt.Errorf("the peer's namespace: %s is not equal to discovery namespace: %s", ns, podNamespace)
 	}
+	groups, err := td.listPDGroups(ns)
+	if err != nil {
+		return "", err
+	}
+	var tcName string
+	for _, set := range groups {
+		if set.Spec.ServiceName == peerServiceName {
+			tcName = set.Labels[label.InstanceLabelKey]
+			break
+		}
+	}
+	if tcName == "" {
+		if !strings.HasSuffix(peerServiceName, "-pd-peer") {
+			return "", fmt.Errorf("the peer service: %s is not a PD group of a TikvCluster", peerServiceName)
+		}
+		tcName = strings.TrimSuffix(peerServiceName, "-pd-peer")
+	}
 	tc, err := td.tcGetFn(ns, tcName)
 	if err != nil {
 		return "", err
 	}
+	if peer.ClusterDomain != "" && tc.Spec.ClusterDomain != "" && peer.ClusterDomain != tc.Spec.ClusterDomain {
+		return "", fmt.Errorf("the peer's cluster domain: %s is not equal to the cluster domain of TikvCluster %s/%s: %s",
+			peer.ClusterDomain, ns, tcName, tc.Spec.ClusterDomain)
+	}
 	keyName := fmt.Sprintf("%s/%s", ns, tcName)
-	// TODO: the replicas should be the total replicas of pd sets.
-	replicas := tc.Spec.PD.Replicas
+	var groupReplicas []int32
+	for _, set := range groups {
+		if set.Labels[label.InstanceLabelKey] != tcName {
+			continue
+		}
+		if set.Spec.Replicas == nil {
+			groupReplicas = append(groupReplicas, 1)
+		} else {
+			groupReplicas = append(groupReplicas, *set.Spec.Replicas)
+		}
+	}
+	replicas := tc.PDInitialMembers(groupReplicas...)
 
+	if td.store != nil {
+		// the state may be changed by other discovery replicas, always start from the persisted one
+		persisted, err := td.store.load(tc)
+		if err != nil {
+			return "", err
+		}
+		td.clusters[keyName] = persisted
+	}
 	currentCluster := td.clusters[keyName]
 	if currentCluster == nil || currentCluster.resourceVersion != tc.ResourceVersion {
 		td.clusters[keyName] = &clusterInfo{
 			resourceVersion: tc.ResourceVersion,
 			peers:           map[string]struct{}{},
 		}
+		if currentCluster != nil {
+			td.clusters[keyName].version = currentCluster.version
+		}
 	}
 	currentCluster = td.clusters[keyName]
 	currentCluster.peers[podName] = struct{}{}
 
 	if len(currentCluster.peers) == int(replicas) {
 		delete(currentCluster.peers, podName)
+		// only one discovery replica can persist the state successfully, so that
+		// only one PD member bootstraps the cluster
+		if err := td.saveCluster(tc, currentCluster); err != nil {
+			return "", err
+		}
 		return fmt.Sprintf("--initial-cluster=%s=%s://%s", podName, tc.Scheme(), advertisePeerUrl), nil
 	}
+	if err := td.saveCluster(tc, currentCluster); err != nil {
+		return "", err
+	}
 
 	pdClient := td.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsTLSClusterEnabled())
 	membersInfo, err := pdClient.GetMembers()
@@ -110,9 +168,84 @@ func (td *pdDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		membersArr = append(membersArr, memberURL)
 	}
 	delete(currentCluster.peers, podName)
+	if err := td.saveCluster(tc, currentCluster); err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("--join=%s", strings.Join(membersArr, ",")), nil
 }
 
-func (td *pdDiscovery) realTCGetFn(ns, tcName string) (*v1alpha1.TikvCluster, error) {
-	return td.cli.TikvV1alpha1().TikvClusters(ns).Get(tcName, metav1.GetOptions{})
+// PeerURL is the parsed advertise peer url of a PD member, which is in the form
+// of <pod>.<peer-service>.<namespace>.svc[.<cluster-domain>][:<port>]
+type PeerURL struct {
+	PodName         string
+	PeerServiceName string
+	Namespace       string
+	// ClusterDomain is empty if the url is not fully-qualified
+	ClusterDomain string
+}
+
+// ParseAdvertisePeerURL parses the advertise peer url of a PD member, the scheme,
+// port and the trailing dot of a fully-qualified name are ignored.
+func ParseAdvertisePeerURL(advertisePeerUrl string) (*PeerURL, error) {
+	host := advertisePeerUrl
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+len("://"):]
+	}
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.TrimSuffix(host, ".")
+
+	strArr := strings.SplitN(host, ".", 5)
+	if len(strArr) < 4 || strArr[3] != "svc" {
+		return nil, fmt.Errorf("advertisePeerUrl format is wrong: %s", advertisePeerUrl)
+	}
+	for _, s := range strArr {
+		if s == "" {
+			return nil, fmt.Errorf("advertisePeerUrl format is wrong: %s", advertisePeerUrl)
+		}
+	}
+	peer := &PeerURL{
+		PodName:         strArr[0],
+		PeerServiceName: strArr[1],
+		Namespace:       strArr[2],
+	}
+	if len(strArr) == 5 {
+		peer.ClusterDomain = strArr[4]
+	}
+	return peer, nil
+}
+
+func (td *pdDiscovery) saveCluster(tc *v1beta1.TikvCluster, info *clusterInfo) error {
+	if td.store == nil {
+		return nil
+	}
+	if err := td.store.save(tc, info); err != nil {
+		klog.Errorf("failed to persist the discovery state of TikvCluster %s/%s: %v", tc.GetNamespace(), tc.GetName(), err)
+		return err
+	}
+	return nil
+}
+
+// listPDGroups returns the StatefulSets of the PD groups which join the PD cluster
+// of a TikvCluster besides the PD group managed by the operator, they are labeled
+// with the instance of the TikvCluster and the PD component.
+func (td *pdDiscovery) listPDGroups(ns string) ([]apps.StatefulSet, error) {
+	selector := labels.SelectorFromSet(labels.Set{label.ComponentLabelKey: label.PDLabelVal})
+	setList, err := td.kubeCli.AppsV1().StatefulSets(ns).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	var groups []apps.StatefulSet
+	for _, set := range setList.Items {
+		if set.Labels[label.ManagedByLabelKey] == label.TiKVOperator || set.Labels[label.InstanceLabelKey] == "" {
+			continue
+		}
+		groups = append(groups, set)
+	}
+	return groups, nil
+}
+
+func (td *pdDiscovery) realTCGetFn(ns, tcName string) (*v1beta1.TikvCluster, error) {
+	return td.cli.TikvV1beta1().TikvClusters(ns).Get(tcName, metav1.GetOptions{})
 }
@@ -16,15 +16,20 @@ package discovery
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/pdpb"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
 )
 
 func TestDiscoveryDiscovery(t *testing.T) {
@@ -35,7 +40,8 @@ func TestDiscoveryDiscovery(t *testing.T) {
 		ns           string
 		url          string
 		clusters     map[string]*clusterInfo
-		tcFn         func() (*v1alpha1.TikvCluster, error)
+		tcFn         func() (*v1beta1.TikvCluster, error)
+		sets         []apps.StatefulSet
 		getMembersFn func() (*pdapi.MembersInfo, error)
 		expectFn     func(*GomegaWithT, *pdDiscovery, string, error)
 	}
@@ -43,6 +49,9 @@ func TestDiscoveryDiscovery(t *testing.T) {
 		t.Log(test.name)
 
 		kubeCli := kubefake.NewSimpleClientset()
+		for i := range test.sets {
+			kubeCli.AppsV1().StatefulSets(test.sets[i].Namespace).Create(&test.sets[i])
+		}
 		fakePDControl := pdapi.NewFakePDControl(kubeCli)
 		pdClient := pdapi.NewFakePDClient()
 		tc, err := test.tcFn()
@@ -54,8 +63,9 @@ func TestDiscoveryDiscovery(t *testing.T) {
 		})
 
 		td := &pdDiscovery{
+			kubeCli:   kubeCli,
 			pdControl: fakePDControl,
-			tcGetFn: func(ns, tcName string) (*v1alpha1.TikvCluster, error) {
+			tcGetFn: func(ns, tcName string) (*v1beta1.TikvCluster, error) {
 				return tc, err
 			},
 			clusters: test.clusters,
@@ -106,7 +116,7 @@ func TestDiscoveryDiscovery(t *testing.T) {
 			ns:       "default",
 			url:      "demo-pd-0.demo-pd-peer.default.svc:2380",
 			clusters: map[string]*clusterInfo{},
-			tcFn: func() (*v1alpha1.TikvCluster, error) {
+			tcFn: func() (*v1beta1.TikvCluster, error) {
 				return nil, fmt.Errorf("failed to get tidbcluster")
 			},
 			expectFn: func(g *GomegaWithT, td *pdDiscovery, s string, err error) {
@@ -222,6 +232,105 @@ func TestDiscoveryDiscovery(t *testing.T) {
 				g.Expect(s).To(Equal("--initial-cluster=demo-pd-2=http://demo-pd-2.demo-pd-peer.default.svc:2380"))
 			},
 		},
+		{
+			name: "1 cluster, third ordinal with cluster domain, return the initial-cluster args",
+			ns:   "default",
+			url:  "demo-pd-2.demo-pd-peer.default.svc.cluster.local:2380",
+			tcFn: func() (*v1beta1.TikvCluster, error) {
+				tc, _ := newTC()
+				tc.Spec.ClusterDomain = "cluster.local"
+				return tc, nil
+			},
+			clusters: map[string]*clusterInfo{
+				"default/demo": {
+					resourceVersion: "1",
+					peers: map[string]struct{}{
+						"demo-pd-0": {},
+						"demo-pd-1": {},
+					},
+				},
+			},
+			expectFn: func(g *GomegaWithT, td *pdDiscovery, s string, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(td.clusters["default/demo"].peers)).To(Equal(2))
+				g.Expect(s).To(Equal("--initial-cluster=demo-pd-2=http://demo-pd-2.demo-pd-peer.default.svc.cluster.local:2380"))
+			},
+		},
+		{
+			name: "cluster domain is wrong",
+			ns:   "default",
+			url:  "demo-pd-0.demo-pd-peer.default.svc.cluster.local:2380",
+			tcFn: func() (*v1beta1.TikvCluster, error) {
+				tc, _ := newTC()
+				tc.Spec.ClusterDomain = "example.org"
+				return tc, nil
+			},
+			clusters: map[string]*clusterInfo{},
+			expectFn: func(g *GomegaWithT, td *pdDiscovery, s string, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "is not equal to the cluster domain")).To(BeTrue())
+				g.Expect(len(td.clusters)).To(BeZero())
+			},
+		},
+		{
+			name:     "peer service is not a PD group",
+			ns:       "default",
+			url:      "demo-pd-0.demo-pd.default.svc:2380",
+			clusters: map[string]*clusterInfo{},
+			tcFn:     newTC,
+			expectFn: func(g *GomegaWithT, td *pdDiscovery, s string, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "is not a PD group")).To(BeTrue())
+				g.Expect(len(td.clusters)).To(BeZero())
+			},
+		},
+		{
+			name: "2 PD groups, third ordinal of the operator group, there are no pd members",
+			ns:   "default",
+			url:  "demo-pd-2.demo-pd-peer.default.svc:2380",
+			tcFn: newTC,
+			sets: []apps.StatefulSet{newPDSet("demo-pd", "demo-pd-peer", 3, true), newPDSet("demo-pd-east", "demo-pd-east-peer", 2, false)},
+			clusters: map[string]*clusterInfo{
+				"default/demo": {
+					resourceVersion: "1",
+					peers: map[string]struct{}{
+						"demo-pd-0": {},
+						"demo-pd-1": {},
+					},
+				},
+			},
+			getMembersFn: func() (*pdapi.MembersInfo, error) {
+				return nil, fmt.Errorf("there are no pd members")
+			},
+			expectFn: func(g *GomegaWithT, td *pdDiscovery, s string, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "there are no pd members")).To(BeTrue())
+				g.Expect(len(td.clusters["default/demo"].peers)).To(Equal(3))
+			},
+		},
+		{
+			name: "2 PD groups, last member of the other group, return the initial-cluster args",
+			ns:   "default",
+			url:  "demo-pd-east-1.demo-pd-east-peer.default.svc:2380",
+			tcFn: newTC,
+			sets: []apps.StatefulSet{newPDSet("demo-pd", "demo-pd-peer", 3, true), newPDSet("demo-pd-east", "demo-pd-east-peer", 2, false)},
+			clusters: map[string]*clusterInfo{
+				"default/demo": {
+					resourceVersion: "1",
+					peers: map[string]struct{}{
+						"demo-pd-0":      {},
+						"demo-pd-1":      {},
+						"demo-pd-2":      {},
+						"demo-pd-east-0": {},
+					},
+				},
+			},
+			expectFn: func(g *GomegaWithT, td *pdDiscovery, s string, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(td.clusters["default/demo"].peers)).To(Equal(4))
+				g.Expect(s).To(Equal("--initial-cluster=demo-pd-east-1=http://demo-pd-east-1.demo-pd-east-peer.default.svc:2380"))
+			},
+		},
 		{
 			name: "1 cluster, the first ordinal second request, get members failed",
 			ns:   "default",
@@ -315,7 +424,7 @@ func TestDiscoveryDiscovery(t *testing.T) {
 			name: "1 cluster, the fourth ordinal request, get members success",
 			ns:   "default",
 			url:  "demo-pd-3.demo-pd-peer.default.svc:2380",
-			tcFn: func() (*v1alpha1.TikvCluster, error) {
+			tcFn: func() (*v1beta1.TikvCluster, error) {
 				tc, _ := newTC()
 				tc.Spec.PD.Replicas = 5
 				return tc, nil
@@ -352,7 +461,7 @@ func TestDiscoveryDiscovery(t *testing.T) {
 			name: "2 clusters, the five ordinal request, get members success",
 			ns:   "default",
 			url:  "demo-pd-3.demo-pd-peer.default.svc:2380",
-			tcFn: func() (*v1alpha1.TikvCluster, error) {
+			tcFn: func() (*v1beta1.TikvCluster, error) {
 				tc, _ := newTC()
 				tc.Spec.PD.Replicas = 5
 				return tc, nil
@@ -402,16 +511,141 @@ func TestDiscoveryDiscovery(t *testing.T) {
 	}
 }
 
-func newTC() (*v1alpha1.TikvCluster, error) {
-	return &v1alpha1.TikvCluster{
-		TypeMeta: metav1.TypeMeta{Kind: "TikvCluster", APIVersion: "v1alpha1"},
+func TestDiscoveryPersistState(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	kubeCli := kubefake.NewSimpleClientset()
+	// the fake clientset does not maintain resourceVersion
+	version := 0
+	setResourceVersion := func(action core.Action) (bool, runtime.Object, error) {
+		version++
+		obj := action.(core.CreateAction).GetObject().(metav1.Object)
+		obj.SetResourceVersion(strconv.Itoa(version))
+		return false, nil, nil
+	}
+	kubeCli.PrependReactor("create", "configmaps", setResourceVersion)
+	kubeCli.PrependReactor("update", "configmaps", setResourceVersion)
+	fakePDControl := pdapi.NewFakePDControl(kubeCli)
+	pdClient := pdapi.NewFakePDClient()
+	tc, _ := newTC()
+	fakePDControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, fmt.Errorf("there are no pd members")
+	})
+	os.Setenv("MY_POD_NAMESPACE", "default")
+
+	// two discovery replicas share the state persisted in the configmap
+	newDiscovery := func() *pdDiscovery {
+		return &pdDiscovery{
+			kubeCli:   kubeCli,
+			pdControl: fakePDControl,
+			tcGetFn: func(ns, tcName string) (*v1beta1.TikvCluster, error) {
+				return tc, nil
+			},
+			clusters: map[string]*clusterInfo{},
+			store:    &configMapStore{kubeCli: kubeCli},
+		}
+	}
+	td1, td2 := newDiscovery(), newDiscovery()
+
+	_, err := td1.Discover("demo-pd-0.demo-pd-peer.default.svc:2380")
+	g.Expect(err).To(HaveOccurred())
+	_, err = td2.Discover("demo-pd-1.demo-pd-peer.default.svc:2380")
+	g.Expect(err).To(HaveOccurred())
+
+	cm, err := kubeCli.CoreV1().ConfigMaps("default").Get("demo-discovery-state", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data[statePeersKey]).To(Equal("demo-pd-0,demo-pd-1"))
+
+	// a restarted replica continues from the persisted state
+	s, err := newDiscovery().Discover("demo-pd-2.demo-pd-peer.default.svc:2380")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal("--initial-cluster=demo-pd-2=http://demo-pd-2.demo-pd-peer.default.svc:2380"))
+
+	cm, err = kubeCli.CoreV1().ConfigMaps("default").Get("demo-discovery-state", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data[statePeersKey]).To(Equal("demo-pd-0,demo-pd-1"))
+	g.Expect(cm.Data[stateResourceVersionKey]).To(Equal("1"))
+}
+
+func TestParseAdvertisePeerURL(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		url       string
+		expectErr bool
+		expect    *PeerURL
+	}{
+		{
+			url:    "demo-pd-0.demo-pd-peer.default.svc:2380",
+			expect: &PeerURL{PodName: "demo-pd-0", PeerServiceName: "demo-pd-peer", Namespace: "default"},
+		},
+		{
+			url:    "demo-pd-0.demo-pd-peer.default.svc",
+			expect: &PeerURL{PodName: "demo-pd-0", PeerServiceName: "demo-pd-peer", Namespace: "default"},
+		},
+		{
+			url:    "demo-pd-0.demo-pd-peer.default.svc.cluster.local:2380",
+			expect: &PeerURL{PodName: "demo-pd-0", PeerServiceName: "demo-pd-peer", Namespace: "default", ClusterDomain: "cluster.local"},
+		},
+		{
+			url:    "http://demo-pd-0.demo-pd-peer.default.svc.k8s.example.org.:2380",
+			expect: &PeerURL{PodName: "demo-pd-0", PeerServiceName: "demo-pd-peer", Namespace: "default", ClusterDomain: "k8s.example.org"},
+		},
+		{
+			url:       "demo-pd-0.demo-pd-peer.default:2380",
+			expectErr: true,
+		},
+		{
+			url:       "demo-pd-0.demo-pd-peer.default.cluster.local:2380",
+			expectErr: true,
+		},
+		{
+			url:       "demo-pd-0..default.svc:2380",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.url)
+		peer, err := ParseAdvertisePeerURL(test.url)
+		if test.expectErr {
+			g.Expect(err).To(HaveOccurred())
+			continue
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(peer).To(Equal(test.expect))
+	}
+}
+
+func newPDSet(name, serviceName string, replicas int32, managed bool) apps.StatefulSet {
+	l := label.Label{label.InstanceLabelKey: "demo"}.PD()
+	if managed {
+		l = label.New().Instance("demo").PD()
+	}
+	return apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			Labels:    l,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: serviceName,
+		},
+	}
+}
+
+func newTC() (*v1beta1.TikvCluster, error) {
+	return &v1beta1.TikvCluster{
+		TypeMeta: metav1.TypeMeta{Kind: "TikvCluster", APIVersion: "v1beta1"},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            "demo",
 			Namespace:       metav1.NamespaceDefault,
 			ResourceVersion: "1",
 		},
-		Spec: v1alpha1.TikvClusterSpec{
-			PD: v1alpha1.PDSpec{Replicas: 3},
+		Spec: v1beta1.TikvClusterSpec{
+			PD: v1beta1.PDSpec{Replicas: 3},
 		},
 	}, nil
 }
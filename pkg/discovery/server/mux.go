@@ -17,31 +17,46 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 
 	restful "github.com/emicklei/go-restful"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
 	"github.com/tikv/tikv-operator/pkg/discovery"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog"
 )
 
 type server struct {
 	discovery discovery.PDDiscovery
+	podLister corelisters.PodLister
 }
 
 // StartServer starts a TiDB Discovery server
-func StartServer(cli versioned.Interface, kubeCli kubernetes.Interface, port int) {
-	svr := &server{discovery.NewPDDiscovery(cli, kubeCli)}
+func StartServer(cli versioned.Interface, kubeCli kubernetes.Interface, podLister corelisters.PodLister, port int) {
+	svr := &server{
+		discovery: discovery.NewPDDiscovery(cli, kubeCli),
+		podLister: podLister,
+	}
 
 	ws := new(restful.WebService)
 	ws.Route(ws.GET("/new/{advertise-peer-url}").To(svr.newHandler))
+	ws.Route(ws.GET("/healthz").To(svr.healthzHandler))
 	restful.Add(ws)
 
+	// plain HTTP only, HTTPS with the cluster certificate comes with cluster TLS support
 	klog.Infof("starting PD Discovery server, listening on 0.0.0.0:%d", port)
 	klog.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", port), nil))
 }
 
+func (svr *server) healthzHandler(req *restful.Request, resp *restful.Response) {
+	if _, err := io.WriteString(resp, "ok"); err != nil {
+		klog.Errorf("failed to write healthz response, %v", err)
+	}
+}
+
 func (svr *server) newHandler(req *restful.Request, resp *restful.Response) {
 	encodedAdvertisePeerURL := req.PathParameter("advertise-peer-url")
 	data, err := base64.StdEncoding.DecodeString(encodedAdvertisePeerURL)
@@ -54,6 +69,14 @@ func (svr *server) newHandler(req *restful.Request, resp *restful.Response) {
 	}
 	advertisePeerURL := string(data)
 
+	if code, err := svr.verifyPeer(req.Request, advertisePeerURL); err != nil {
+		klog.Errorf("failed to verify the request from %s for %s, %v", req.Request.RemoteAddr, advertisePeerURL, err)
+		if err := resp.WriteError(code, err); err != nil {
+			klog.Errorf("failed to write, error: %v", err)
+		}
+		return
+	}
+
 	result, err := svr.discovery.Discover(advertisePeerURL)
 	if err != nil {
 		klog.Errorf("failed to discover: %s, %v", advertisePeerURL, err)
@@ -68,3 +91,35 @@ func (svr *server) newHandler(req *restful.Request, resp *restful.Response) {
 		klog.Errorf("failed to write, string: %s, %v", result, err)
 	}
 }
+
+// verifyPeer verifies that the request comes from the PD pod named in the advertise
+// peer url by comparing the source IP with the pod IP, it returns the http status
+// code to respond with if the verification fails.
+func (svr *server) verifyPeer(r *http.Request, advertisePeerURL string) (int, error) {
+	peer, err := discovery.ParseAdvertisePeerURL(advertisePeerURL)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	podName, ns := peer.PodName, peer.Namespace
+
+	sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return http.StatusBadRequest, fmt.Errorf("failed to parse the remote address %s, %v", r.RemoteAddr, err)
+	}
+
+	pod, err := svr.podLister.Pods(ns).Get(podName)
+	if errors.IsNotFound(err) {
+		// the informer may lag behind, the PD pod retries the request
+		return http.StatusServiceUnavailable, fmt.Errorf("pod %s/%s is not found", ns, podName)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if pod.Status.PodIP == "" {
+		return http.StatusServiceUnavailable, fmt.Errorf("the IP of pod %s/%s is not assigned yet", ns, podName)
+	}
+	if !net.ParseIP(sourceIP).Equal(net.ParseIP(pod.Status.PodIP)) {
+		return http.StatusForbidden, fmt.Errorf("source IP %s is not the IP %s of pod %s/%s", sourceIP, pod.Status.PodIP, ns, podName)
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestVerifyPeer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name             string
+		advertisePeerURL string
+		remoteAddr       string
+		podIP            string
+		expectCode       int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		kubeCli := kubefake.NewSimpleClientset()
+		podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+		podInformer.Informer().GetIndexer().Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "demo-pd-0",
+				Namespace: "demo",
+			},
+			Status: corev1.PodStatus{
+				PodIP: test.podIP,
+			},
+		})
+		svr := &server{podLister: podInformer.Lister()}
+
+		code, err := svr.verifyPeer(&http.Request{RemoteAddr: test.remoteAddr}, test.advertisePeerURL)
+		g.Expect(code).To(Equal(test.expectCode))
+		if test.expectCode == http.StatusOK {
+			g.Expect(err).NotTo(HaveOccurred())
+		} else {
+			g.Expect(err).To(HaveOccurred())
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:             "source IP matches the pod IP",
+			advertisePeerURL: "demo-pd-0.demo-pd-peer.demo.svc:2380",
+			remoteAddr:       "10.0.0.1:34567",
+			podIP:            "10.0.0.1",
+			expectCode:       http.StatusOK,
+		},
+		{
+			name:             "source IP does not match the pod IP",
+			advertisePeerURL: "demo-pd-0.demo-pd-peer.demo.svc:2380",
+			remoteAddr:       "10.0.0.2:34567",
+			podIP:            "10.0.0.1",
+			expectCode:       http.StatusForbidden,
+		},
+		{
+			name:             "pod IP is not assigned",
+			advertisePeerURL: "demo-pd-0.demo-pd-peer.demo.svc:2380",
+			remoteAddr:       "10.0.0.1:34567",
+			expectCode:       http.StatusServiceUnavailable,
+		},
+		{
+			name:             "pod is not found",
+			advertisePeerURL: "demo-pd-1.demo-pd-peer.demo.svc:2380",
+			remoteAddr:       "10.0.0.1:34567",
+			podIP:            "10.0.0.1",
+			expectCode:       http.StatusServiceUnavailable,
+		},
+		{
+			name:             "advertise peer url is malformed",
+			advertisePeerURL: "demo-pd-0:2380",
+			remoteAddr:       "10.0.0.1:34567",
+			podIP:            "10.0.0.1",
+			expectCode:       http.StatusBadRequest,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	stateResourceVersionKey = "tikvcluster-resource-version"
+	statePeersKey           = "peers"
+)
+
+// clusterStore persists the bootstrap state of PD clusters, so that the state
+// survives restarts of the discovery service and is shared by all its replicas.
+type clusterStore interface {
+	// load returns the persisted state of the TikvCluster, an empty state is
+	// returned if nothing is persisted yet
+	load(tc *v1beta1.TikvCluster) (*clusterInfo, error)
+	// save persists the state of the TikvCluster, it fails with a conflict error
+	// if the state has been changed by others since it is loaded
+	save(tc *v1beta1.TikvCluster, info *clusterInfo) error
+}
+
+// configMapStore persists the bootstrap state in a ConfigMap per TikvCluster,
+// the resourceVersion of the ConfigMap is used for optimistic concurrency control.
+type configMapStore struct {
+	kubeCli kubernetes.Interface
+}
+
+func (s *configMapStore) load(tc *v1beta1.TikvCluster) (*clusterInfo, error) {
+	name := controller.DiscoveryStateMemberName(tc.GetName())
+	cm, err := s.kubeCli.CoreV1().ConfigMaps(tc.GetNamespace()).Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return &clusterInfo{peers: map[string]struct{}{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &clusterInfo{
+		resourceVersion: cm.Data[stateResourceVersionKey],
+		peers:           map[string]struct{}{},
+		version:         cm.ResourceVersion,
+	}
+	for _, peer := range strings.Split(cm.Data[statePeersKey], ",") {
+		if peer != "" {
+			info.peers[peer] = struct{}{}
+		}
+	}
+	return info, nil
+}
+
+func (s *configMapStore) save(tc *v1beta1.TikvCluster, info *clusterInfo) error {
+	peers := make([]string, 0, len(info.peers))
+	for peer := range info.peers {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.DiscoveryStateMemberName(tc.GetName()),
+			Namespace:       tc.GetNamespace(),
+			Labels:          label.New().Instance(tc.GetInstanceName()).Discovery().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+			ResourceVersion: info.version,
+		},
+		Data: map[string]string{
+			stateResourceVersionKey: info.resourceVersion,
+			statePeersKey:           strings.Join(peers, ","),
+		},
+	}
+
+	var saved *corev1.ConfigMap
+	var err error
+	if info.version == "" {
+		saved, err = s.kubeCli.CoreV1().ConfigMaps(tc.GetNamespace()).Create(cm)
+	} else {
+		saved, err = s.kubeCli.CoreV1().ConfigMaps(tc.GetNamespace()).Update(cm)
+	}
+	if err != nil {
+		return err
+	}
+	info.version = saved.ResourceVersion
+	return nil
+}
@@ -45,6 +45,9 @@ const (
 	// StoreIDLabelKey is TiKV store id label key
 	StoreIDLabelKey string = "tikv.org/store-id"
 
+	// SnapshotSetLabelKey is the VolumeSnapshot label key of the snapshot set it belongs to
+	SnapshotSetLabelKey string = "tikv.org/snapshot-set"
+
 	// MemberIDLabelKey is member id label key
 	MemberIDLabelKey string = "tikv.org/member-id"
 
@@ -57,6 +60,24 @@ const (
 	// AnnEvictLeaderBeginTime is pod annotation key to indicate the begin time for evicting region leader
 	AnnEvictLeaderBeginTime = "tikv.org/evictLeaderBeginTime"
 
+	// AnnEvictLeaderByUpgrader is pod annotation key to indicate that the leader eviction of
+	// the pod is begun by the upgrader rather than the pod eviction webhook
+	AnnEvictLeaderByUpgrader = "tikv.org/evict-leader-by-upgrader"
+
+	// AnnNodeEvictLeaderStores is node annotation key to record the TiKV stores whose leaders are
+	// being evicted because the node is under maintenance, the value is a comma separated list of
+	// <namespace>/<tikvcluster>/<store-id>
+	AnnNodeEvictLeaderStores = "tikv.org/evict-leader-stores"
+
+	// AnnNodeReadyForDrain is node annotation key to indicate that all TiKV leaders on the node have been evicted
+	AnnNodeReadyForDrain = "tikv.org/ready-for-drain"
+
+	// AnnNodeReadyForDrainVal is node annotation value to indicate that the node is ready for drain
+	AnnNodeReadyForDrainVal = "true"
+
+	// NodeMaintenanceTaintKey is the default node taint key to indicate the node is under maintenance
+	NodeMaintenanceTaintKey = "tikv.org/maintenance"
+
 	// AnnPodDeferDeleting is pod annotation key to indicate the pod which need to be restarted
 	AnnPodDeferDeleting = "tikv.org/pod-defer-deleting"
 
@@ -66,6 +87,10 @@ const (
 	// AnnPVCDeferDeleting is pvc defer deletion annotation key used in PVC for defer deleting PVC
 	AnnPVCDeferDeleting = "tikv.org/pvc-defer-deleting"
 
+	// AnnPVCEvictLeaderStore is pvc annotation key to record the TiKV store whose leader eviction was begun
+	// by the pod eviction webhook, the leader eviction is ended once the evicted pod is recreated
+	AnnPVCEvictLeaderStore = "tikv.org/evict-leader-store"
+
 	// AnnPVCPodScheduling is pod scheduling annotation key, it represents whether the pod is scheduling
 	AnnPVCPodScheduling = "tikv.org/pod-scheduling"
 
@@ -75,6 +100,36 @@ const (
 	// TiKVDeleteSlots is annotation key of tikv delete slots.
 	AnnTiKVDeleteSlots = "tikv.tikv.org/delete-slots"
 
+	// AnnTiKVDeleteExternalStores is annotation key of the ids of the external stores to be deleted,
+	// the value is a json array, e.g. [1,2]
+	AnnTiKVDeleteExternalStores = "tikv.tikv.org/delete-external-stores"
+
+	// AnnPDRecovery is tc annotation key to trigger the recovery of the PD cluster which lost the quorum,
+	// a new recovery is started whenever the value is changed
+	AnnPDRecovery = "tikv.org/pd-recovery"
+
+	// AnnPDRecoveryAllocID is tc annotation key to specify the alloc id used in the PD recovery,
+	// it must be larger than any id allocated by the lost PD cluster, e.g. the current id
+	// allocation of the cluster in the PD monitoring plus a margin. It is required to recover
+	// a lost PD cluster, a clone reads it from the VolumeSnapshots instead.
+	AnnPDRecoveryAllocID = "tikv.org/pd-recovery-alloc-id"
+
+	// AnnTiKVUnsafeRecovery is tc annotation key to trigger the online unsafe recovery which removes
+	// the failure stores from the regions that lost the majority, a new recovery is started whenever
+	// the value is changed
+	AnnTiKVUnsafeRecovery = "tikv.org/tikv-unsafe-recovery"
+
+	// AnnTiKVVolumeSnapshot is tc annotation key to trigger the VolumeSnapshots of all the TiKV volumes,
+	// the value is the name of the snapshot set and a new set is created whenever the value is changed
+	AnnTiKVVolumeSnapshot = "tikv.org/volume-snapshot"
+
+	// AnnTiKVMasterKeyGeneration is TiKV pod annotation key of the generation of the master key
+	AnnTiKVMasterKeyGeneration = "tikv.org/master-key-generation"
+
+	// AnnV1alpha1Spec is tc annotation key of the v1alpha1 spec which can't be represented in
+	// v1beta1, it is restored when the tc is converted back to v1alpha1
+	AnnV1alpha1Spec = "tikv.org/v1alpha1-spec"
+
 	// AnnSysctlInit is pod annotation key to indicate whether configuring sysctls with init container
 	AnnSysctlInit = "tikv.org/sysctl-init"
 
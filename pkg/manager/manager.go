@@ -13,10 +13,10 @@
 
 package manager
 
-import "github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+import "github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 
 // Manager implements the logic for syncing tikvcluster.
 type Manager interface {
 	// Sync	implements the logic for syncing tikvcluster.
-	Sync(*v1alpha1.TikvCluster) error
+	Sync(*v1beta1.TikvCluster) error
 }
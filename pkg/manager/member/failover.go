@@ -13,7 +13,7 @@
 
 package member
 
-import "github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+import "github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 
 const (
 	unHealthEventReason     = "Unhealthy"
@@ -22,6 +22,6 @@ const (
 
 // Failover implements the logic for pd/tikv/tidb's failover and recovery.
 type Failover interface {
-	Failover(*v1alpha1.TikvCluster) error
-	Recover(*v1alpha1.TikvCluster)
+	Failover(*v1beta1.TikvCluster) error
+	Recover(*v1beta1.TikvCluster)
 }
@@ -0,0 +1,98 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// SyncMaintenanceStatus records whether now is in a maintenance window of the cluster and
+// the start time of the next window. The pending operations are cleared, they are recorded
+// again by the member managers in this round of sync. If the windows can not be evaluated,
+// the cluster is regarded as outside any window so that the disruptive operations wait
+// while the others go on.
+func SyncMaintenanceStatus(tc *v1beta1.TikvCluster, now time.Time) error {
+	if len(tc.Spec.MaintenanceWindows) == 0 {
+		tc.Status.Maintenance = nil
+		return nil
+	}
+	tc.Status.Maintenance = &v1beta1.MaintenanceStatus{}
+	loc, err := time.LoadLocation(tc.Timezone())
+	if err != nil {
+		return fmt.Errorf("TikvCluster: [%s/%s], failed to load the time zone %s: %v", tc.GetNamespace(), tc.GetName(), tc.Timezone(), err)
+	}
+	now = now.In(loc)
+
+	status := &v1beta1.MaintenanceStatus{}
+	for _, window := range tc.Spec.MaintenanceWindows {
+		schedule, err := cron.ParseStandard(window.Schedule)
+		if err != nil {
+			return fmt.Errorf("TikvCluster: [%s/%s], invalid maintenance window schedule %q: %v", tc.GetNamespace(), tc.GetName(), window.Schedule, err)
+		}
+		// the window is open if it started in the last duration
+		if start := schedule.Next(now.Add(-window.Duration.Duration)); !start.IsZero() && !start.After(now) {
+			status.InWindow = true
+		}
+		next := schedule.Next(now)
+		if next.IsZero() {
+			continue
+		}
+		if status.NextWindow == nil || next.Before(status.NextWindow.Time) {
+			status.NextWindow = &metav1.Time{Time: next}
+		}
+	}
+	tc.Status.Maintenance = status
+	return nil
+}
+
+// allowDisruption returns whether the disruptive operation is allowed by the maintenance windows,
+// the operation is recorded as pending if it has to wait for the next window
+func allowDisruption(tc *v1beta1.TikvCluster, op v1beta1.DisruptiveOperation) bool {
+	m := tc.Status.Maintenance
+	if m == nil || m.InWindow {
+		return true
+	}
+	for _, pending := range m.PendingOperations {
+		if pending == op {
+			return false
+		}
+	}
+	m.PendingOperations = append(m.PendingOperations, op)
+	klog.Infof("TikvCluster: [%s/%s], %s is waiting for the next maintenance window", tc.GetNamespace(), tc.GetName(), op)
+	return false
+}
+
+// holdUpgrade keeps the pod template and the partition of the statefulset, so that
+// no pod is restarted until the next maintenance window
+func holdUpgrade(oldSet, newSet *apps.StatefulSet) error {
+	if !templateEqual(newSet, oldSet) {
+		_, podSpec, err := GetLastAppliedConfig(oldSet)
+		if err != nil {
+			return err
+		}
+		newSet.Spec.Template.ObjectMeta = *oldSet.Spec.Template.ObjectMeta.DeepCopy()
+		newSet.Spec.Template.Spec = *podSpec
+	}
+	if rolling := oldSet.Spec.UpdateStrategy.RollingUpdate; rolling != nil && rolling.Partition != nil {
+		setUpgradePartition(newSet, *rolling.Partition)
+	}
+	return nil
+}
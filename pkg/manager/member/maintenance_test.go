@@ -0,0 +1,137 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncMaintenanceStatus(t *testing.T) {
+	// Saturday 2020-06-06 03:00 UTC
+	now := time.Date(2020, 6, 6, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		timezone   string
+		windows    []v1beta1.MaintenanceWindow
+		inWindow   bool
+		nextWindow time.Time
+	}{
+		{
+			name: "in the window",
+			windows: []v1beta1.MaintenanceWindow{
+				{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			},
+			inWindow:   true,
+			nextWindow: time.Date(2020, 6, 13, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "after the window",
+			windows: []v1beta1.MaintenanceWindow{
+				{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			inWindow:   false,
+			nextWindow: time.Date(2020, 6, 13, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "the earliest of the windows",
+			windows: []v1beta1.MaintenanceWindow{
+				{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: time.Hour}},
+				{Schedule: "30 22 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			inWindow:   false,
+			nextWindow: time.Date(2020, 6, 6, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "in the time zone of the cluster",
+			timezone: "Asia/Shanghai",
+			windows: []v1beta1.MaintenanceWindow{
+				// 10:00 in Shanghai is 02:00 UTC
+				{Schedule: "0 10 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			},
+			inWindow:   true,
+			nextWindow: time.Date(2020, 6, 7, 2, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			tc := &v1beta1.TikvCluster{}
+			tc.Spec.Timezone = tt.timezone
+			tc.Spec.MaintenanceWindows = tt.windows
+			tc.Status.Maintenance = &v1beta1.MaintenanceStatus{
+				PendingOperations: []v1beta1.DisruptiveOperation{v1beta1.TiKVUpgradeOperation},
+			}
+			g.Expect(SyncMaintenanceStatus(tc, now)).To(Succeed())
+			g.Expect(tc.Status.Maintenance.InWindow).To(Equal(tt.inWindow))
+			g.Expect(tc.Status.Maintenance.NextWindow.Time.Equal(tt.nextWindow)).To(BeTrue(), "next window %v", tc.Status.Maintenance.NextWindow)
+			g.Expect(tc.Status.Maintenance.PendingOperations).To(BeEmpty())
+		})
+	}
+
+	g := NewGomegaWithT(t)
+	tc := &v1beta1.TikvCluster{}
+	tc.Status.Maintenance = &v1beta1.MaintenanceStatus{}
+	g.Expect(SyncMaintenanceStatus(tc, now)).To(Succeed())
+	g.Expect(tc.Status.Maintenance).To(BeNil())
+
+	// the cluster is regarded as outside any window if the windows can not be evaluated
+	tc.Spec.Timezone = "Mars/Olympus_Mons"
+	tc.Spec.MaintenanceWindows = []v1beta1.MaintenanceWindow{
+		{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+	}
+	g.Expect(SyncMaintenanceStatus(tc, now)).NotTo(Succeed())
+	g.Expect(tc.Status.Maintenance).NotTo(BeNil())
+	g.Expect(tc.Status.Maintenance.InWindow).To(BeFalse())
+	g.Expect(allowDisruption(tc, v1beta1.TiKVUpgradeOperation)).To(BeFalse())
+}
+
+func TestAllowDisruption(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1beta1.TikvCluster{}
+	g.Expect(allowDisruption(tc, v1beta1.TiKVUpgradeOperation)).To(BeTrue())
+
+	tc.Status.Maintenance = &v1beta1.MaintenanceStatus{InWindow: true}
+	g.Expect(allowDisruption(tc, v1beta1.TiKVUpgradeOperation)).To(BeTrue())
+	g.Expect(tc.Status.Maintenance.PendingOperations).To(BeEmpty())
+
+	tc.Status.Maintenance = &v1beta1.MaintenanceStatus{}
+	g.Expect(allowDisruption(tc, v1beta1.TiKVUpgradeOperation)).To(BeFalse())
+	g.Expect(allowDisruption(tc, v1beta1.TiKVScaleInOperation)).To(BeFalse())
+	g.Expect(allowDisruption(tc, v1beta1.TiKVUpgradeOperation)).To(BeFalse())
+	g.Expect(tc.Status.Maintenance.PendingOperations).To(Equal([]v1beta1.DisruptiveOperation{
+		v1beta1.TiKVUpgradeOperation,
+		v1beta1.TiKVScaleInOperation,
+	}))
+}
+
+func TestHoldUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	oldSet := newStatefulSetForTiKVUpgrader()
+	oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = controller.Int32Ptr(2)
+	g.Expect(SetStatefulSetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+	newSet := newStatefulSetForTiKVUpgrader()
+	newSet.Spec.Template.Spec.Containers[0].Image = "tikv-test-image:v2"
+
+	g.Expect(holdUpgrade(oldSet, newSet)).To(Succeed())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-test-image"))
+	g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+}
@@ -0,0 +1,373 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+)
+
+const (
+	skipReasonNodeLossPodsCleanerIsNotTarget       = "node loss pods cleaner: member type is not pd, tikv"
+	skipReasonNodeLossPodsCleanerFailoverDisabled  = "node loss pods cleaner: failover is disabled"
+	skipReasonNodeLossPodsCleanerPodIsSchedulable  = "node loss pods cleaner: pod is scheduled or not unschedulable"
+	skipReasonNodeLossPodsCleanerNodeIsFound       = "node loss pods cleaner: the nodes of the PVs are found"
+	skipReasonNodeLossPodsCleanerNoNodes           = "node loss pods cleaner: no nodes are listed"
+	skipReasonNodeLossPodsCleanerTimeoutNotReached = "node loss pods cleaner: pod is unschedulable for less than the node loss timeout"
+	skipReasonNodeLossPodsCleanerStatusNotSynced   = "node loss pods cleaner: member status is not synced"
+	skipReasonNodeLossPodsCleanerHandledByFailover = "node loss pods cleaner: member is handled by failover"
+	skipReasonNodeLossPodsCleanerPDNotInQuorum     = "node loss pods cleaner: pd cluster is not in quorum"
+	skipReasonNodeLossPodsCleanerStoreNotFound     = "node loss pods cleaner: store is not found"
+	skipReasonNodeLossPodsCleanerStoreIsNotDown    = "node loss pods cleaner: store is not Down or Tombstone"
+	skipReasonNodeLossPodsCleanerStoreIsDeleting   = "node loss pods cleaner: store is being deleted"
+	skipReasonNodeLossPodsCleanerPreflightFailed   = "node loss pods cleaner: the remaining stores can not hold the data and the region replicas"
+	skipReasonNodeLossPodsCleanerPVCIsTerminating  = "node loss pods cleaner: pvc is terminating, pod deleted"
+	skipReasonNodeLossPodsCleanerPVCAndPodDeleted  = "node loss pods cleaner: pvc and pod are deleted"
+)
+
+// NodeLossPodsCleaner implements the logic for rescheduling the pods stuck
+// Pending because the nodes of their local PVs are gone
+//
+// A local PV can only be used on its node, once the node is removed from the
+// cluster, the pod recreated by the statefulset controller can't be
+// scheduled anymore as long as its PVC is bound to the PV. The failover
+// doesn't help either, it only adds new replicas. Once the pod has been
+// unschedulable for the node loss timeout, its PD member or TiKV store is
+// deleted from the PD cluster, then its PVC and the pod are deleted, so that
+// the statefulset controller recreates the pod with a new PVC. A TiKV store
+// must be Down, it is deleted from the PD cluster and the PVC is only deleted
+// once the store becomes Tombstone, otherwise the new store can't join the PD
+// cluster with the same address. The store is only deleted if the remaining
+// stores pass the pre-flight checks of the scale-in. The cleanup is part of
+// the failover, it is skipped if the failover of the member type is disabled.
+type NodeLossPodsCleaner interface {
+	Clean(*v1beta1.TikvCluster) (map[string]string, error)
+}
+
+type nodeLossPodsCleaner struct {
+	pdControl    pdapi.PDControlInterface
+	podLister    corelisters.PodLister
+	podControl   controller.PodControlInterface
+	pvcLister    corelisters.PersistentVolumeClaimLister
+	pvcControl   controller.PVCControlInterface
+	pvLister     corelisters.PersistentVolumeLister
+	nodeLister   corelisters.NodeLister
+	autoFailover bool
+	recorder     record.EventRecorder
+}
+
+// NewNodeLossPodsCleaner returns a NodeLossPodsCleaner
+func NewNodeLossPodsCleaner(pdControl pdapi.PDControlInterface,
+	podLister corelisters.PodLister,
+	podControl controller.PodControlInterface,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	pvcControl controller.PVCControlInterface,
+	pvLister corelisters.PersistentVolumeLister,
+	nodeLister corelisters.NodeLister,
+	autoFailover bool,
+	recorder record.EventRecorder) NodeLossPodsCleaner {
+	return &nodeLossPodsCleaner{
+		pdControl,
+		podLister,
+		podControl,
+		pvcLister,
+		pvcControl,
+		pvLister,
+		nodeLister,
+		autoFailover,
+		recorder}
+}
+
+func (nc *nodeLossPodsCleaner) Clean(tc *v1beta1.TikvCluster) (map[string]string, error) {
+	ns := tc.GetNamespace()
+	// for unit test
+	skipReason := map[string]string{}
+
+	selector, err := label.New().Instance(tc.GetInstanceName()).Selector()
+	if err != nil {
+		return skipReason, err
+	}
+	pods, err := nc.podLister.Pods(ns).List(selector)
+	if err != nil {
+		return skipReason, err
+	}
+
+	for _, pod := range pods {
+		podName := pod.GetName()
+		l := label.Label(pod.Labels)
+		if !(l.IsPD() || l.IsTiKV()) {
+			skipReason[podName] = skipReasonNodeLossPodsCleanerIsNotTarget
+			continue
+		}
+		if (l.IsPD() && !tc.PDFailoverEnabled(nc.autoFailover)) || (l.IsTiKV() && !tc.TiKVFailoverEnabled(nc.autoFailover)) {
+			skipReason[podName] = skipReasonNodeLossPodsCleanerFailoverDisabled
+			continue
+		}
+
+		_, cond := podutil.GetPodCondition(&pod.Status, corev1.PodScheduled)
+		if len(pod.Spec.NodeName) > 0 || cond == nil ||
+			cond.Status != corev1.ConditionFalse || cond.Reason != corev1.PodReasonUnschedulable {
+			skipReason[podName] = skipReasonNodeLossPodsCleanerPodIsSchedulable
+			continue
+		}
+
+		pvcs, reason, err := nc.getNodeLostPVCs(pod)
+		if err != nil {
+			return skipReason, err
+		}
+		if len(pvcs) == 0 {
+			skipReason[podName] = reason
+			continue
+		}
+
+		// the pod recreated before the old PVC is gone blocks the deletion of the PVC
+		terminating := false
+		for _, pvc := range pvcs {
+			if pvc.DeletionTimestamp != nil {
+				terminating = true
+				break
+			}
+		}
+		if terminating {
+			if err := nc.podControl.DeletePod(tc, pod); err != nil {
+				return skipReason, err
+			}
+			skipReason[podName] = skipReasonNodeLossPodsCleanerPVCIsTerminating
+			continue
+		}
+
+		timeout := tc.PDNodeLossTimeout()
+		if l.IsTiKV() {
+			timeout = tc.TiKVNodeLossTimeout()
+		}
+		if timeout <= 0 || time.Now().Before(cond.LastTransitionTime.Add(timeout)) {
+			skipReason[podName] = skipReasonNodeLossPodsCleanerTimeoutNotReached
+			continue
+		}
+
+		if l.IsPD() {
+			reason, err = nc.deletePDMember(tc, podName)
+		} else {
+			reason, err = nc.deleteTiKVStore(tc, podName)
+		}
+		if err != nil {
+			return skipReason, err
+		}
+		if reason != "" {
+			skipReason[podName] = reason
+			continue
+		}
+
+		for _, pvc := range pvcs {
+			if err := nc.pvcControl.DeletePVC(tc, pvc); err != nil {
+				klog.Errorf("node loss pods cleaner: failed to delete pvc: %s/%s, %v", ns, pvc.Name, err)
+				return skipReason, err
+			}
+			klog.Infof("node loss pods cleaner: delete pvc: %s/%s successfully", ns, pvc.Name)
+			nc.recorder.Eventf(tc, corev1.EventTypeWarning, "NodeLost",
+				"%s is unschedulable because the node of its PV %s is gone, its pvc %s is deleted", podName, pvc.Spec.VolumeName, pvc.Name)
+		}
+		if err := nc.podControl.DeletePod(tc, pod); err != nil {
+			return skipReason, err
+		}
+		skipReason[podName] = skipReasonNodeLossPodsCleanerPVCAndPodDeleted
+	}
+
+	return skipReason, nil
+}
+
+// getNodeLostPVCs returns the PVCs of the pod bound to the PVs whose nodes are gone,
+// or the reason why there is none
+func (nc *nodeLossPodsCleaner) getNodeLostPVCs(pod *corev1.Pod) ([]*corev1.PersistentVolumeClaim, string, error) {
+	nodes, err := nc.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, "", err
+	}
+	// the node cache may not be synced, the nodes are never considered gone then
+	if len(nodes) == 0 {
+		return nil, skipReasonNodeLossPodsCleanerNoNodes, nil
+	}
+
+	var pvcs []*corev1.PersistentVolumeClaim
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim == nil || vol.PersistentVolumeClaim.ClaimName == "" {
+			continue
+		}
+		pvc, err := nc.pvcLister.PersistentVolumeClaims(pod.GetNamespace()).Get(vol.PersistentVolumeClaim.ClaimName)
+		if errors.IsNotFound(err) {
+			// the orphan pods cleaner deletes the pod
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv, err := nc.pvLister.Get(pvc.Spec.VolumeName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+			continue
+		}
+		if !matchAnyNode(pv.Spec.NodeAffinity.Required.NodeSelectorTerms, nodes) {
+			pvcs = append(pvcs, pvc)
+		}
+	}
+	return pvcs, skipReasonNodeLossPodsCleanerNodeIsFound, nil
+}
+
+func matchAnyNode(terms []corev1.NodeSelectorTerm, nodes []*corev1.Node) bool {
+	for _, node := range nodes {
+		if v1helper.MatchNodeSelectorTerms(terms, labels.Set(node.Labels), nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// deletePDMember deletes the PD member of the pod from the PD cluster, it returns the
+// reason if the member can't be deleted
+func (nc *nodeLossPodsCleaner) deletePDMember(tc *v1beta1.TikvCluster, podName string) (string, error) {
+	if !tc.Status.PD.Synced {
+		return skipReasonNodeLossPodsCleanerStatusNotSynced, nil
+	}
+	if _, ok := tc.Status.PD.FailureMembers[podName]; ok {
+		return skipReasonNodeLossPodsCleanerHandledByFailover, nil
+	}
+	pdMember, ok := tc.Status.PD.Members[podName]
+	if !ok {
+		// the member has been deleted or never joined the PD cluster
+		return "", nil
+	}
+
+	healthCount := 0
+	for _, member := range tc.Status.PD.Members {
+		if member.Health {
+			healthCount++
+		}
+	}
+	if pdMember.Health || healthCount <= len(tc.Status.PD.Members)/2 {
+		return skipReasonNodeLossPodsCleanerPDNotInQuorum, nil
+	}
+
+	memberID, err := strconv.ParseUint(pdMember.ID, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	if err := controller.GetPDClient(nc.pdControl, tc).DeleteMemberByID(memberID); err != nil {
+		klog.Errorf("node loss pods cleaner: failed to delete member: %d, %v", memberID, err)
+		return "", err
+	}
+	klog.Infof("node loss pods cleaner: delete member: %d successfully", memberID)
+	nc.recorder.Eventf(tc, corev1.EventTypeWarning, "PDMemberDeleted",
+		"%s(%d) deleted from cluster, the node of its PV is gone", podName, memberID)
+	return "", nil
+}
+
+// deleteTiKVStore deletes the Down TiKV store of the pod from the PD cluster if the remaining
+// stores pass the pre-flight checks of the scale-in, it returns the reason if the store is
+// not Tombstone yet
+func (nc *nodeLossPodsCleaner) deleteTiKVStore(tc *v1beta1.TikvCluster, podName string) (string, error) {
+	if !tc.Status.TiKV.Synced {
+		return skipReasonNodeLossPodsCleanerStatusNotSynced, nil
+	}
+	for _, failureStore := range tc.Status.TiKV.FailureStores {
+		if failureStore.PodName == podName && failureStore.Replace {
+			return skipReasonNodeLossPodsCleanerHandledByFailover, nil
+		}
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.PodName != podName {
+			continue
+		}
+		switch store.State {
+		case v1beta1.TiKVStateDown:
+			id, err := strconv.ParseUint(store.ID, 10, 64)
+			if err != nil {
+				return "", err
+			}
+			pdClient := controller.GetPDClient(nc.pdControl, tc)
+			storesInfo, err := pdClient.GetStores()
+			if err != nil {
+				return "", fmt.Errorf("node loss pods cleaner: failed to get the stores of TikvCluster %s/%s, %v", tc.GetNamespace(), tc.GetName(), err)
+			}
+			config, err := pdClient.GetConfig()
+			if err != nil {
+				return "", fmt.Errorf("node loss pods cleaner: failed to get the config of PD of TikvCluster %s/%s, %v", tc.GetNamespace(), tc.GetName(), err)
+			}
+			if err := checkScaleIn(storesInfo.Stores, config.Replication, map[uint64]bool{id: true}, tc.TiKVScaleInHighWaterMarkPercent()); err != nil {
+				msg := fmt.Sprintf("%s(%d) can not be deleted from cluster: %v", podName, id, err)
+				klog.Warningf("node loss pods cleaner: TikvCluster %s/%s, %s", tc.GetNamespace(), tc.GetName(), msg)
+				nc.recorder.Event(tc, corev1.EventTypeWarning, "NodeLossCleanupBlocked", msg)
+				return skipReasonNodeLossPodsCleanerPreflightFailed, nil
+			}
+			if err := pdClient.DeleteStore(id); err != nil {
+				klog.Errorf("node loss pods cleaner: failed to delete store %d, %v", id, err)
+				return "", err
+			}
+			klog.Infof("node loss pods cleaner: delete store %d of %s/%s successfully", id, tc.GetNamespace(), podName)
+			nc.recorder.Eventf(tc, corev1.EventTypeWarning, "TiKVStoreDeleted",
+				"%s(%d) deleted from cluster, the node of its PV is gone", podName, id)
+			return skipReasonNodeLossPodsCleanerStoreIsDeleting, nil
+		case v1beta1.TiKVStateOffline:
+			return skipReasonNodeLossPodsCleanerStoreIsDeleting, nil
+		default:
+			return skipReasonNodeLossPodsCleanerStoreIsNotDown, nil
+		}
+	}
+	for _, store := range tc.Status.TiKV.TombstoneStores {
+		if store.PodName == podName {
+			return "", nil
+		}
+	}
+	return skipReasonNodeLossPodsCleanerStoreNotFound, nil
+}
+
+type FakeNodeLossPodsCleaner struct {
+	err error
+}
+
+// NewFakeNodeLossPodsCleaner returns a fake node loss pods cleaner
+func NewFakeNodeLossPodsCleaner() *FakeNodeLossPodsCleaner {
+	return &FakeNodeLossPodsCleaner{}
+}
+
+func (fnc *FakeNodeLossPodsCleaner) SetNodeLossPodsCleanerError(err error) {
+	fnc.err = err
+}
+
+func (fnc *FakeNodeLossPodsCleaner) Clean(_ *v1beta1.TikvCluster) (map[string]string, error) {
+	return nil, fnc.err
+}
+
+var _ NodeLossPodsCleaner = &FakeNodeLossPodsCleaner{}
@@ -0,0 +1,391 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func TestNodeLossPodsCleanerClean(t *testing.T) {
+	type testcase struct {
+		name             string
+		pd               bool
+		scheduled        bool
+		unschedulableFor time.Duration
+		nodes            []string
+		pvcTerminating   bool
+		autoFailover     *bool
+		upStores         int
+		update           func(*v1beta1.TikvCluster)
+		expectReason     string
+		expectDeleted    bool
+		expectPodDeleted bool
+		expectPDCall     bool
+	}
+
+	tests := []testcase{
+		{
+			name:             "pod is scheduled",
+			scheduled:        true,
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			expectReason:     skipReasonNodeLossPodsCleanerPodIsSchedulable,
+		},
+		{
+			name:             "node of the pv is found",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-1", "node-2"},
+			expectReason:     skipReasonNodeLossPodsCleanerNodeIsFound,
+		},
+		{
+			name:             "no nodes are listed",
+			unschedulableFor: time.Hour,
+			expectReason:     skipReasonNodeLossPodsCleanerNoNodes,
+		},
+		{
+			name:             "timeout is not reached",
+			unschedulableFor: time.Minute,
+			nodes:            []string{"node-2"},
+			expectReason:     skipReasonNodeLossPodsCleanerTimeoutNotReached,
+		},
+		{
+			name:             "cleanup is disabled",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Failover = &v1beta1.FailoverSpec{NodeLossTimeout: &metav1.Duration{}}
+			},
+			expectReason: skipReasonNodeLossPodsCleanerTimeoutNotReached,
+		},
+		{
+			name:             "cleanup is disabled by default",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Failover = nil
+			},
+			expectReason: skipReasonNodeLossPodsCleanerTimeoutNotReached,
+		},
+		{
+			name:             "tikv failover is disabled",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Failover.Enabled = pointer.BoolPtr(false)
+				tc.Status.TiKV.Stores["1"] = v1beta1.TiKVStore{ID: "1", PodName: "test-tikv-0", State: v1beta1.TiKVStateDown}
+			},
+			expectReason: skipReasonNodeLossPodsCleanerFailoverDisabled,
+		},
+		{
+			name:             "auto failover is disabled",
+			pd:               true,
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			autoFailover:     pointer.BoolPtr(false),
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Members["test-pd-0"] = v1beta1.PDMember{Name: "test-pd-0", ID: "1", Health: false}
+			},
+			expectReason: skipReasonNodeLossPodsCleanerFailoverDisabled,
+		},
+		{
+			name:             "tikv store is up",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores["1"] = v1beta1.TiKVStore{ID: "1", PodName: "test-tikv-0", State: v1beta1.TiKVStateUp}
+			},
+			expectReason: skipReasonNodeLossPodsCleanerStoreIsNotDown,
+		},
+		{
+			name:             "tikv store is down",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores["1"] = v1beta1.TiKVStore{ID: "1", PodName: "test-tikv-0", State: v1beta1.TiKVStateDown}
+			},
+			upStores:     3,
+			expectReason: skipReasonNodeLossPodsCleanerStoreIsDeleting,
+			expectPDCall: true,
+		},
+		{
+			name:             "remaining stores are fewer than max-replicas",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores["1"] = v1beta1.TiKVStore{ID: "1", PodName: "test-tikv-0", State: v1beta1.TiKVStateDown}
+			},
+			upStores:     2,
+			expectReason: skipReasonNodeLossPodsCleanerPreflightFailed,
+		},
+		{
+			name:             "tikv store is offline",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores["1"] = v1beta1.TiKVStore{ID: "1", PodName: "test-tikv-0", State: v1beta1.TiKVStateOffline}
+			},
+			expectReason: skipReasonNodeLossPodsCleanerStoreIsDeleting,
+		},
+		{
+			name:             "tikv store is not found",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			expectReason:     skipReasonNodeLossPodsCleanerStoreNotFound,
+		},
+		{
+			name:             "tikv store is replaced by failover",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-0", StoreID: "1", Replace: true},
+				}
+			},
+			expectReason: skipReasonNodeLossPodsCleanerHandledByFailover,
+		},
+		{
+			name:             "tikv store is tombstone",
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.TombstoneStores["1"] = v1beta1.TiKVStore{ID: "1", PodName: "test-tikv-0", State: v1beta1.TiKVStateTombstone}
+			},
+			expectReason:     skipReasonNodeLossPodsCleanerPVCAndPodDeleted,
+			expectDeleted:    true,
+			expectPodDeleted: true,
+		},
+		{
+			name:             "pvc is terminating",
+			unschedulableFor: time.Minute,
+			nodes:            []string{"node-2"},
+			pvcTerminating:   true,
+			expectReason:     skipReasonNodeLossPodsCleanerPVCIsTerminating,
+			expectPodDeleted: true,
+		},
+		{
+			name:             "pd member is unhealthy",
+			pd:               true,
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Members["test-pd-0"] = v1beta1.PDMember{Name: "test-pd-0", ID: "1", Health: false}
+			},
+			expectReason:     skipReasonNodeLossPodsCleanerPVCAndPodDeleted,
+			expectDeleted:    true,
+			expectPodDeleted: true,
+			expectPDCall:     true,
+		},
+		{
+			name:             "pd cluster is not in quorum",
+			pd:               true,
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Members["test-pd-0"] = v1beta1.PDMember{Name: "test-pd-0", ID: "1", Health: false}
+				tc.Status.PD.Members["test-pd-1"] = v1beta1.PDMember{Name: "test-pd-1", ID: "2", Health: false}
+			},
+			expectReason: skipReasonNodeLossPodsCleanerPDNotInQuorum,
+		},
+		{
+			name:             "pd member is handled by failover",
+			pd:               true,
+			unschedulableFor: time.Hour,
+			nodes:            []string{"node-2"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.FailureMembers = map[string]v1beta1.PDFailureMember{
+					"test-pd-0": {PodName: "test-pd-0", MemberID: "1"},
+				}
+			},
+			expectReason: skipReasonNodeLossPodsCleanerHandledByFailover,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			tc := newTikvClusterForPD()
+			tc.Status.PD.Synced = true
+			tc.Status.PD.Members = map[string]v1beta1.PDMember{
+				"test-pd-1": {Name: "test-pd-1", ID: "2", Health: true},
+				"test-pd-2": {Name: "test-pd-2", ID: "3", Health: true},
+			}
+			tc.Status.TiKV.Synced = true
+			tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+			tc.Status.TiKV.TombstoneStores = map[string]v1beta1.TiKVStore{}
+			tc.Spec.PD.Failover = &v1beta1.FailoverSpec{NodeLossTimeout: &metav1.Duration{Duration: 5 * time.Minute}}
+			tc.Spec.TiKV.Failover = &v1beta1.FailoverSpec{NodeLossTimeout: &metav1.Duration{Duration: 5 * time.Minute}}
+			if tt.update != nil {
+				tt.update(tc)
+			}
+
+			nc, podIndexer, pvcIndexer, pvIndexer, nodeIndexer, pdControl := newFakeNodeLossPodsCleaner()
+			if tt.autoFailover != nil {
+				nc.autoFailover = *tt.autoFailover
+			}
+			pdCalled := false
+			pdClient := controller.NewFakePDClient(pdControl, tc)
+			pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+				storesInfo := &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{newStoreInfo(1, v1beta1.TiKVStateDown, 100, 90)}}
+				for i := 0; i < tt.upStores; i++ {
+					storesInfo.Stores = append(storesInfo.Stores, newStoreInfo(uint64(i+2), v1beta1.TiKVStateUp, 100, 90))
+				}
+				return storesInfo, nil
+			})
+			pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+				return &pdapi.PDConfigFromAPI{Replication: &pdapi.PDReplicationConfig{}}, nil
+			})
+			pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+				pdCalled = true
+				return nil, nil
+			})
+			pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+				pdCalled = true
+				return nil, nil
+			})
+
+			memberType := v1beta1.TiKVMemberType
+			setName := controller.TiKVMemberName(tc.GetName())
+			labels := label.New().Instance(tc.GetInstanceName()).TiKV().Labels()
+			if tt.pd {
+				memberType = v1beta1.PDMemberType
+				setName = controller.PDMemberName(tc.GetName())
+				labels = label.New().Instance(tc.GetInstanceName()).PD().Labels()
+			}
+			podName := ordinalPodName(memberType, tc.GetName(), 0)
+			pvcName := ordinalPVCName(memberType, setName, 0)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: metav1.NamespaceDefault,
+					Labels:    labels,
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: memberType.String(),
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+							},
+						},
+					},
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					Conditions: []corev1.PodCondition{
+						{
+							Type:               corev1.PodScheduled,
+							Status:             corev1.ConditionFalse,
+							Reason:             corev1.PodReasonUnschedulable,
+							LastTransitionTime: metav1.NewTime(time.Now().Add(-tt.unschedulableFor)),
+						},
+					},
+				},
+			}
+			if tt.scheduled {
+				pod.Spec.NodeName = "node-2"
+			}
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      pvcName,
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+			}
+			if tt.pvcTerminating {
+				now := metav1.Now()
+				pvc.DeletionTimestamp = &now
+			}
+			pv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+				Spec: corev1.PersistentVolumeSpec{
+					NodeAffinity: &corev1.VolumeNodeAffinity{
+						Required: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{
+											Key:      "kubernetes.io/hostname",
+											Operator: corev1.NodeSelectorOpIn,
+											Values:   []string{"node-1"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			g.Expect(podIndexer.Add(pod)).To(Succeed())
+			g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+			g.Expect(pvIndexer.Add(pv)).To(Succeed())
+			for _, name := range tt.nodes {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: map[string]string{"kubernetes.io/hostname": name},
+					},
+				}
+				g.Expect(nodeIndexer.Add(node)).To(Succeed())
+			}
+
+			skipReason, err := nc.Clean(tc)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(skipReason[podName]).To(Equal(tt.expectReason))
+			g.Expect(pdCalled).To(Equal(tt.expectPDCall))
+			_, pvcExists, _ := pvcIndexer.Get(pvc)
+			g.Expect(pvcExists).To(Equal(!tt.expectDeleted))
+			_, podExists, _ := podIndexer.Get(pod)
+			g.Expect(podExists).To(Equal(!tt.expectPodDeleted))
+		})
+	}
+}
+
+func newFakeNodeLossPodsCleaner() (*nodeLossPodsCleaner, cache.Indexer, cache.Indexer, cache.Indexer, cache.Indexer, *pdapi.FakePDControl) {
+	kubeCli := kubefake.NewSimpleClientset()
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	pvInformer := kubeInformerFactory.Core().V1().PersistentVolumes()
+	nodeInformer := kubeInformerFactory.Core().V1().Nodes()
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+
+	return &nodeLossPodsCleaner{
+			pdControl,
+			podInformer.Lister(),
+			controller.NewFakePodControl(podInformer),
+			pvcInformer.Lister(),
+			controller.NewFakePVCControl(pvcInformer),
+			pvInformer.Lister(),
+			nodeInformer.Lister(),
+			true,
+			record.NewFakeRecorder(10)},
+		podInformer.Informer().GetIndexer(), pvcInformer.Informer().GetIndexer(),
+		pvInformer.Informer().GetIndexer(), nodeInformer.Informer().GetIndexer(), pdControl
+}
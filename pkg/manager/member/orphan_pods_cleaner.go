@@ -14,7 +14,7 @@
 package member
 
 import (
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -47,7 +47,7 @@ const (
 // https://github.com/kubernetes/kubernetes/blob/84fe3db5cf58bf0fc8ff792b885465ceaf70a435/pkg/controller/statefulset/stateful_pod_control.go#L175-L199
 //
 type OrphanPodsCleaner interface {
-	Clean(*v1alpha1.TikvCluster) (map[string]string, error)
+	Clean(*v1beta1.TikvCluster) (map[string]string, error)
 }
 
 type orphanPodsCleaner struct {
@@ -65,7 +65,7 @@ func NewOrphanPodsCleaner(podLister corelisters.PodLister,
 	return &orphanPodsCleaner{podLister, podControl, pvcLister, kubeCli}
 }
 
-func (opc *orphanPodsCleaner) Clean(tc *v1alpha1.TikvCluster) (map[string]string, error) {
+func (opc *orphanPodsCleaner) Clean(tc *v1beta1.TikvCluster) (map[string]string, error) {
 	ns := tc.GetNamespace()
 	// for unit test
 	skipReason := map[string]string{}
@@ -175,7 +175,7 @@ func (fpc *FakeOrphanPodsCleaner) SetnOrphanPodCleanerError(err error) {
 	fpc.err = err
 }
 
-func (fpc *FakeOrphanPodsCleaner) Clean(_ *v1alpha1.TikvCluster) (map[string]string, error) {
+func (fpc *FakeOrphanPodsCleaner) Clean(_ *v1beta1.TikvCluster) (map[string]string, error) {
 	return nil, fpc.err
 }
 
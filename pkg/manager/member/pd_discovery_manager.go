@@ -16,7 +16,7 @@ package member
 import (
 	"encoding/json"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	appsv1 "k8s.io/api/apps/v1"
@@ -27,7 +27,7 @@ import (
 )
 
 type PDDiscoveryManager interface {
-	Reconcile(tc *v1alpha1.TikvCluster) error
+	Reconcile(tc *v1beta1.TikvCluster) error
 }
 
 type realPDDiscoveryManager struct {
@@ -38,7 +38,7 @@ func NewPDDiscoveryManager(typedControl controller.TypedControlInterface) PDDisc
 	return &realPDDiscoveryManager{typedControl}
 }
 
-func (m *realPDDiscoveryManager) Reconcile(tc *v1alpha1.TikvCluster) error {
+func (m *realPDDiscoveryManager) Reconcile(tc *v1beta1.TikvCluster) error {
 
 	meta, _ := getDiscoveryMeta(tc, controller.DiscoveryMemberName)
 
@@ -47,7 +47,7 @@ func (m *realPDDiscoveryManager) Reconcile(tc *v1alpha1.TikvCluster) error {
 		ObjectMeta: meta,
 		Rules: []rbacv1.PolicyRule{
 			{
-				APIGroups:     []string{v1alpha1.GroupName},
+				APIGroups:     []string{v1beta1.GroupName},
 				Resources:     []string{"tikvclusters"},
 				ResourceNames: []string{tc.Name},
 				Verbs:         []string{"get"},
@@ -57,6 +57,24 @@ func (m *realPDDiscoveryManager) Reconcile(tc *v1alpha1.TikvCluster) error {
 				Resources: []string{"secrets"},
 				Verbs:     []string{"get", "list"},
 			},
+			{
+				// the bootstrap state of PD cluster is persisted in a configmap
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"get", "create", "update"},
+			},
+			{
+				// the source IP of a discovery request is verified against the PD pod IP
+				APIGroups: []string{corev1.GroupName},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				// the PD groups joining the PD cluster are counted in the initial members
+				APIGroups: []string{appsv1.GroupName},
+				Resources: []string{"statefulsets"},
+				Verbs:     []string{"list"},
+			},
 		},
 	})
 	if err != nil {
@@ -99,7 +117,7 @@ func (m *realPDDiscoveryManager) Reconcile(tc *v1alpha1.TikvCluster) error {
 	return nil
 }
 
-func getTidbDiscoveryService(tc *v1alpha1.TikvCluster, deploy *appsv1.Deployment) *corev1.Service {
+func getTidbDiscoveryService(tc *v1beta1.TikvCluster, deploy *appsv1.Deployment) *corev1.Service {
 	meta, _ := getDiscoveryMeta(tc, controller.DiscoveryMemberName)
 	return &corev1.Service{
 		ObjectMeta: meta,
@@ -116,12 +134,16 @@ func getTidbDiscoveryService(tc *v1alpha1.TikvCluster, deploy *appsv1.Deployment
 	}
 }
 
-func getTidbDiscoveryDeployment(tc *v1alpha1.TikvCluster) (*appsv1.Deployment, error) {
+func getTidbDiscoveryDeployment(tc *v1beta1.TikvCluster) (*appsv1.Deployment, error) {
 	meta, l := getDiscoveryMeta(tc, controller.DiscoveryMemberName)
+	replicas := controller.Int32Ptr(1)
+	if tc.Spec.Discovery.Replicas != nil {
+		replicas = tc.Spec.Discovery.Replicas
+	}
 	d := &appsv1.Deployment{
 		ObjectMeta: meta,
 		Spec: appsv1.DeploymentSpec{
-			Replicas: controller.Int32Ptr(1),
+			Replicas: replicas,
 			Selector: l.LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
@@ -137,6 +159,14 @@ func getTidbDiscoveryDeployment(tc *v1alpha1.TikvCluster) (*appsv1.Deployment, e
 						},
 						Image:           controller.PDDiscoveryImage,
 						ImagePullPolicy: corev1.PullIfNotPresent,
+						ReadinessProbe: &corev1.Probe{
+							Handler: corev1.Handler{
+								HTTPGet: &corev1.HTTPGetAction{
+									Path: "/healthz",
+									Port: intstr.FromInt(10261),
+								},
+							},
+						},
 						Env: []corev1.EnvVar{
 							{
 								Name: "MY_POD_NAMESPACE",
@@ -167,7 +197,7 @@ func getTidbDiscoveryDeployment(tc *v1alpha1.TikvCluster) (*appsv1.Deployment, e
 	return d, nil
 }
 
-func getDiscoveryMeta(tc *v1alpha1.TikvCluster, nameFunc func(string) string) (metav1.ObjectMeta, label.Label) {
+func getDiscoveryMeta(tc *v1beta1.TikvCluster, nameFunc func(string) string) (metav1.ObjectMeta, label.Label) {
 	instanceName := tc.GetInstanceName()
 	discoveryLabel := label.New().Instance(instanceName).Discovery()
 
@@ -192,7 +222,7 @@ func (fdm *FakeDiscoveryManager) SetReconcileError(err error) {
 	fdm.err = err
 }
 
-func (fdm *FakeDiscoveryManager) Reconcile(_ *v1alpha1.TikvCluster) error {
+func (fdm *FakeDiscoveryManager) Reconcile(_ *v1beta1.TikvCluster) error {
 	if fdm.err != nil {
 		return fdm.err
 	}
@@ -19,7 +19,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,22 +28,23 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
-func newTikvClusterForPDDiscovery() *v1alpha1.TikvCluster {
-	return &v1alpha1.TikvCluster{
+func newTikvClusterForPDDiscovery() *v1beta1.TikvCluster {
+	return &v1beta1.TikvCluster{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "TikvCluster",
-			APIVersion: "tikv.org/v1alpha1",
+			APIVersion: "tikv.org/v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test",
 			Namespace: corev1.NamespaceDefault,
 			UID:       types.UID("test"),
 		},
-		Spec: v1alpha1.TikvClusterSpec{
-			PD: v1alpha1.PDSpec{
-				ComponentSpec: v1alpha1.ComponentSpec{
-					Image: v1alpha1.PDMemberType.String(),
+		Spec: v1beta1.TikvClusterSpec{
+			PD: v1beta1.PDSpec{
+				ComponentSpec: v1beta1.ComponentSpec{
+					Version: "v1",
 				},
+				BaseImage: v1beta1.PDMemberType.String(),
 				ResourceRequirements: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceCPU:    resource.MustParse("1"),
@@ -60,9 +61,9 @@ func TestPDDiscoveryManager_Reconcile(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                string
-		prepare             func(tc *v1alpha1.TikvCluster, ctrl *controller.FakeGenericControl)
+		prepare             func(tc *v1beta1.TikvCluster, ctrl *controller.FakeGenericControl)
 		errOnCreateOrUpdate bool
-		expect              func([]appsv1.Deployment, *v1alpha1.TikvCluster, error)
+		expect              func([]appsv1.Deployment, *v1beta1.TikvCluster, error)
 	}
 	testFn := func(tt *testcase) {
 		t.Log(tt.name)
@@ -84,7 +85,7 @@ func TestPDDiscoveryManager_Reconcile(t *testing.T) {
 	cases := []*testcase{
 		{
 			name: "Basic",
-			expect: func(deploys []appsv1.Deployment, tc *v1alpha1.TikvCluster, err error) {
+			expect: func(deploys []appsv1.Deployment, tc *v1beta1.TikvCluster, err error) {
 				g.Expect(err).To(Succeed())
 				g.Expect(deploys).To(HaveLen(1))
 				g.Expect(deploys[0].Name).To((Equal("test-discovery")))
@@ -93,7 +94,7 @@ func TestPDDiscoveryManager_Reconcile(t *testing.T) {
 		},
 		{
 			name: "Setting discovery resource",
-			prepare: func(tc *v1alpha1.TikvCluster, ctrl *controller.FakeGenericControl) {
+			prepare: func(tc *v1beta1.TikvCluster, ctrl *controller.FakeGenericControl) {
 				tc.Spec.Discovery.ResourceRequirements = corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceCPU:              resource.MustParse("1"),
@@ -107,7 +108,7 @@ func TestPDDiscoveryManager_Reconcile(t *testing.T) {
 					},
 				}
 			},
-			expect: func(deploys []appsv1.Deployment, tc *v1alpha1.TikvCluster, err error) {
+			expect: func(deploys []appsv1.Deployment, tc *v1beta1.TikvCluster, err error) {
 				g.Expect(err).To(Succeed())
 				g.Expect(deploys).To(HaveLen(1))
 				g.Expect(deploys[0].Spec.Template.Spec.Containers[0].Resources).To(Equal(corev1.ResourceRequirements{
@@ -128,7 +129,7 @@ func TestPDDiscoveryManager_Reconcile(t *testing.T) {
 		},
 		{
 			name: "Create or update resource error",
-			expect: func(deploys []appsv1.Deployment, tc *v1alpha1.TikvCluster, err error) {
+			expect: func(deploys []appsv1.Deployment, tc *v1beta1.TikvCluster, err error) {
 				g.Expect(err).NotTo(Succeed())
 				g.Expect(deploys).To(BeEmpty())
 			},
@@ -18,7 +18,7 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
@@ -43,7 +43,8 @@ type pdFailover struct {
 	recorder         record.EventRecorder
 }
 
-// NewPDFailover returns a pd Failover
+// NewPDFailover returns a pd Failover, pdFailoverPeriod is used for the clusters
+// which don't set the failover period of PD
 func NewPDFailover(cli versioned.Interface,
 	pdControl pdapi.PDControlInterface,
 	pdFailoverPeriod time.Duration,
@@ -65,7 +66,7 @@ func NewPDFailover(cli versioned.Interface,
 		recorder}
 }
 
-func (pf *pdFailover) Failover(tc *v1alpha1.TikvCluster) error {
+func (pf *pdFailover) Failover(tc *v1beta1.TikvCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
@@ -73,7 +74,7 @@ func (pf *pdFailover) Failover(tc *v1alpha1.TikvCluster) error {
 		return fmt.Errorf("TikvCluster: %s/%s's pd status sync failed, can't failover", ns, tcName)
 	}
 	if tc.Status.PD.FailureMembers == nil {
-		tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{}
+		tc.Status.PD.FailureMembers = map[string]v1beta1.PDFailureMember{}
 	}
 
 	healthCount := 0
@@ -93,8 +94,8 @@ func (pf *pdFailover) Failover(tc *v1alpha1.TikvCluster) error {
 	}
 
 	failureReplicas := getFailureReplicas(tc)
-	if failureReplicas >= int(*tc.Spec.PD.MaxFailoverCount) {
-		klog.Errorf("PD failover replicas (%d) reaches the limit (%d), skip failover", failureReplicas, *tc.Spec.PD.MaxFailoverCount)
+	if maxFailoverCount := tc.PDMaxFailoverCount(); failureReplicas >= int(maxFailoverCount) {
+		klog.Errorf("PD failover replicas (%d) reaches the limit (%d), skip failover", failureReplicas, maxFailoverCount)
 		return nil
 	}
 
@@ -112,12 +113,12 @@ func (pf *pdFailover) Failover(tc *v1alpha1.TikvCluster) error {
 	return pf.tryToDeleteAFailureMember(tc)
 }
 
-func (pf *pdFailover) Recover(tc *v1alpha1.TikvCluster) {
+func (pf *pdFailover) Recover(tc *v1beta1.TikvCluster) {
 	tc.Status.PD.FailureMembers = nil
 	klog.Infof("pd failover: clearing pd failoverMembers, %s/%s", tc.GetNamespace(), tc.GetName())
 }
 
-func (pf *pdFailover) tryToMarkAPeerAsFailure(tc *v1alpha1.TikvCluster) error {
+func (pf *pdFailover) tryToMarkAPeerAsFailure(tc *v1beta1.TikvCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
@@ -130,9 +131,9 @@ func (pf *pdFailover) tryToMarkAPeerAsFailure(tc *v1alpha1.TikvCluster) error {
 		}
 
 		if tc.Status.PD.FailureMembers == nil {
-			tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{}
+			tc.Status.PD.FailureMembers = map[string]v1beta1.PDFailureMember{}
 		}
-		deadline := pdMember.LastTransitionTime.Add(pf.pdFailoverPeriod)
+		deadline := pdMember.LastTransitionTime.Add(tc.PDFailoverPeriod(pf.pdFailoverPeriod))
 		_, exist := tc.Status.PD.FailureMembers[podName]
 		if pdMember.Health || time.Now().Before(deadline) || exist {
 			continue
@@ -142,7 +143,7 @@ func (pf *pdFailover) tryToMarkAPeerAsFailure(tc *v1alpha1.TikvCluster) error {
 		if err != nil {
 			return err
 		}
-		pvcName := ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(tcName), ordinal)
+		pvcName := ordinalPVCName(v1beta1.PDMemberType, controller.PDMemberName(tcName), ordinal)
 		pvc, err := pf.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
 		if err != nil {
 			return err
@@ -153,7 +154,7 @@ func (pf *pdFailover) tryToMarkAPeerAsFailure(tc *v1alpha1.TikvCluster) error {
 
 		// mark a peer member failed and return an error to skip reconciliation
 		// note that status of tidb cluster will be updated always
-		tc.Status.PD.FailureMembers[podName] = v1alpha1.PDFailureMember{
+		tc.Status.PD.FailureMembers[podName] = v1beta1.PDFailureMember{
 			PodName:       podName,
 			MemberID:      pdMember.ID,
 			PVCUID:        pvc.UID,
@@ -170,10 +171,10 @@ func (pf *pdFailover) tryToMarkAPeerAsFailure(tc *v1alpha1.TikvCluster) error {
 // pvc. If this succeeds, new pod & pvc will be created by Kubernetes.
 // Note that this will fail if the kubelet on the node which failed pod was
 // running on is not responding.
-func (pf *pdFailover) tryToDeleteAFailureMember(tc *v1alpha1.TikvCluster) error {
+func (pf *pdFailover) tryToDeleteAFailureMember(tc *v1beta1.TikvCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	var failureMember *v1alpha1.PDFailureMember
+	var failureMember *v1beta1.PDFailureMember
 	var failurePodName string
 
 	for podName, pdMember := range tc.Status.PD.FailureMembers {
@@ -214,7 +215,7 @@ func (pf *pdFailover) tryToDeleteAFailureMember(tc *v1alpha1.TikvCluster) error
 	if err != nil {
 		return err
 	}
-	pvcName := ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(tcName), ordinal)
+	pvcName := ordinalPVCName(v1beta1.PDMemberType, controller.PDMemberName(tcName), ordinal)
 	pvc, err := pf.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
@@ -239,7 +240,7 @@ func (pf *pdFailover) tryToDeleteAFailureMember(tc *v1alpha1.TikvCluster) error
 	return nil
 }
 
-func setMemberDeleted(tc *v1alpha1.TikvCluster, podName string) {
+func setMemberDeleted(tc *v1beta1.TikvCluster, podName string) {
 	failureMember := tc.Status.PD.FailureMembers[podName]
 	failureMember.MemberDeleted = true
 	tc.Status.PD.FailureMembers[podName] = failureMember
@@ -253,15 +254,15 @@ func NewFakePDFailover() Failover {
 	return &fakePDFailover{}
 }
 
-func (fpf *fakePDFailover) Failover(_ *v1alpha1.TikvCluster) error {
+func (fpf *fakePDFailover) Failover(_ *v1beta1.TikvCluster) error {
 	return nil
 }
 
-func (fpf *fakePDFailover) Recover(_ *v1alpha1.TikvCluster) {
+func (fpf *fakePDFailover) Recover(_ *v1beta1.TikvCluster) {
 	return
 }
 
-func (pf *pdFailover) isPodDesired(tc *v1alpha1.TikvCluster, podName string) bool {
+func (pf *pdFailover) isPodDesired(tc *v1beta1.TikvCluster, podName string) bool {
 	ordinals := tc.PDStsDesiredOrdinals(true)
 	ordinal, err := util.GetOrdinalFromPodName(podName)
 	if err != nil {
@@ -21,7 +21,7 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
@@ -42,7 +42,7 @@ func TestPDFailoverFailover(t *testing.T) {
 	recorder := record.NewFakeRecorder(100)
 	type testcase struct {
 		name                     string
-		update                   func(*v1alpha1.TikvCluster)
+		update                   func(*v1beta1.TikvCluster)
 		maxFailoverCount         int32
 		hasPVC                   bool
 		hasPod                   bool
@@ -53,7 +53,7 @@ func TestPDFailoverFailover(t *testing.T) {
 		delPVCFailed             bool
 		statusSyncFailed         bool
 		errExpectFn              func(*GomegaWithT, error)
-		expectFn                 func(*v1alpha1.TikvCluster, *pdFailover)
+		expectFn                 func(*v1beta1.TikvCluster, *pdFailover)
 	}
 
 	tests := []testcase{
@@ -69,7 +69,7 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPVCFailed:             false,
 			statusSyncFailed:         false,
 			errExpectFn:              errExpectNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 				events := collectEvents(recorder.Events)
@@ -88,7 +88,7 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPVCFailed:             false,
 			statusSyncFailed:         true,
 			errExpectFn:              errExpectNotNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				events := collectEvents(recorder.Events)
 				g.Expect(events).To(HaveLen(0))
@@ -109,7 +109,7 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "pd cluster is not health")).To(Equal(true))
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 				events := collectEvents(recorder.Events)
@@ -130,10 +130,10 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPodFailed:             false,
 			delPVCFailed:             false,
 			errExpectFn:              errExpectNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(1))
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
 				g.Expect(ok).To(Equal(true))
 				g.Expect(pd1.MemberDeleted).To(Equal(true))
@@ -144,9 +144,9 @@ func TestPDFailoverFailover(t *testing.T) {
 		},
 		{
 			name: "has one not ready member, but not exceed deadline",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				oneNotReadyMember(tc)
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1 := tc.Status.PD.Members[pd1Name]
 				pd1.LastTransitionTime = metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
 				tc.Status.PD.Members[pd1Name] = pd1
@@ -160,7 +160,7 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPVCFailed:             false,
 			statusSyncFailed:         false,
 			errExpectFn:              errExpectNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 				events := collectEvents(recorder.Events)
@@ -170,9 +170,9 @@ func TestPDFailoverFailover(t *testing.T) {
 		},
 		{
 			name: "has one not ready member, and exceed deadline, lastTransitionTime is zero",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				oneNotReadyMember(tc)
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1 := tc.Status.PD.Members[pd1Name]
 				pd1.LastTransitionTime = metav1.Time{}
 				tc.Status.PD.Members[pd1Name] = pd1
@@ -186,7 +186,7 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPVCFailed:             false,
 			statusSyncFailed:         false,
 			errExpectFn:              errExpectNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 				events := collectEvents(recorder.Events)
@@ -209,7 +209,7 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "persistentvolumeclaim \"pd-test-pd-1\" not found")).To(Equal(true))
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 				events := collectEvents(recorder.Events)
@@ -232,7 +232,7 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "marking Pod: default/test-pd-1 pd member: test-pd-1 as failure")).To(Equal(true))
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(1))
 				failureMembers := tc.Status.PD.FailureMembers["test-pd-1"]
@@ -258,7 +258,53 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPVCFailed:             false,
 			statusSyncFailed:         false,
 			errExpectFn:              errExpectNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
+				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
+				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
+				events := collectEvents(recorder.Events)
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0]).To(ContainSubstring("test-pd-1(12891273174085095651) is unhealthy"))
+			},
+		},
+		{
+			name: "has one not ready member but the max count of the cluster is 0",
+			update: func(tc *v1beta1.TikvCluster) {
+				oneNotReadyMember(tc)
+				tc.Spec.PD.Failover = &v1beta1.FailoverSpec{MaxCount: pointer.Int32Ptr(0)}
+			},
+			maxFailoverCount:         3,
+			hasPVC:                   true,
+			hasPod:                   true,
+			podWithDeletionTimestamp: false,
+			delMemberFailed:          false,
+			delPodFailed:             false,
+			delPVCFailed:             false,
+			statusSyncFailed:         false,
+			errExpectFn:              errExpectNil,
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
+				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
+				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
+				events := collectEvents(recorder.Events)
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0]).To(ContainSubstring("test-pd-1(12891273174085095651) is unhealthy"))
+			},
+		},
+		{
+			name: "has one not ready member, but not exceed the period of the cluster",
+			update: func(tc *v1beta1.TikvCluster) {
+				oneNotReadyMember(tc)
+				tc.Spec.PD.Failover = &v1beta1.FailoverSpec{Period: &metav1.Duration{Duration: 15 * time.Minute}}
+			},
+			maxFailoverCount:         3,
+			hasPVC:                   true,
+			hasPod:                   true,
+			podWithDeletionTimestamp: false,
+			delMemberFailed:          false,
+			delPodFailed:             false,
+			delPVCFailed:             false,
+			statusSyncFailed:         false,
+			errExpectFn:              errExpectNil,
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 				events := collectEvents(recorder.Events)
@@ -278,9 +324,9 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPVCFailed:             false,
 			statusSyncFailed:         false,
 			errExpectFn:              errExpectNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
 				g.Expect(ok).To(Equal(true))
 				g.Expect(pd1.MemberDeleted).To(Equal(true))
@@ -292,9 +338,9 @@ func TestPDFailoverFailover(t *testing.T) {
 		},
 		{
 			name: "has one not ready member, and exceed deadline, don't have PVC, has Pod, delete pod success, but memberID is wrong",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				oneNotReadyMemberAndAFailureMember(tc)
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1 := tc.Status.PD.FailureMembers[pd1Name]
 				pd1.MemberID = "wrong-id"
 				tc.Status.PD.FailureMembers[pd1Name] = pd1
@@ -311,9 +357,9 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "invalid syntax")).To(Equal(true))
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
 				g.Expect(ok).To(Equal(true))
 				g.Expect(pd1.MemberDeleted).To(Equal(false))
@@ -337,9 +383,9 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "failed to delete member")).To(Equal(true))
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
 				g.Expect(ok).To(Equal(true))
 				g.Expect(pd1.MemberDeleted).To(Equal(false))
@@ -363,9 +409,9 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "delete pod: API server failed")).To(Equal(true))
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
 				g.Expect(ok).To(Equal(true))
 				g.Expect(pd1.MemberDeleted).To(Equal(false))
@@ -390,9 +436,9 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "delete pvc: API server failed")).To(Equal(true))
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster, _ *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, _ *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
 				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
 				g.Expect(ok).To(Equal(true))
 				g.Expect(pd1.MemberDeleted).To(Equal(false))
@@ -414,10 +460,10 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPVCFailed:             false,
 			statusSyncFailed:         false,
 			errExpectFn:              errExpectNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, pf *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, pf *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
-				pvcName := ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(tc.GetName()), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
+				pvcName := ordinalPVCName(v1beta1.PDMemberType, controller.PDMemberName(tc.GetName()), 1)
 				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
 				g.Expect(ok).To(Equal(true))
 				g.Expect(pd1.MemberDeleted).To(Equal(true))
@@ -445,10 +491,10 @@ func TestPDFailoverFailover(t *testing.T) {
 			delPVCFailed:             false,
 			statusSyncFailed:         false,
 			errExpectFn:              errExpectNil,
-			expectFn: func(tc *v1alpha1.TikvCluster, pf *pdFailover) {
+			expectFn: func(tc *v1beta1.TikvCluster, pf *pdFailover) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
-				pd1Name := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
-				pvcName := ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(tc.GetName()), 1)
+				pd1Name := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
+				pvcName := ordinalPVCName(v1beta1.PDMemberType, controller.PDMemberName(tc.GetName()), 1)
 				pd1, ok := tc.Status.PD.FailureMembers[pd1Name]
 				g.Expect(ok).To(Equal(true))
 				g.Expect(pd1.MemberDeleted).To(Equal(true))
@@ -483,14 +529,14 @@ func TestPDFailoverFailover(t *testing.T) {
 			})
 
 			if test.hasPVC {
-				pvc := newPVCForPDFailover(tc, v1alpha1.PDMemberType, 1)
+				pvc := newPVCForPDFailover(tc, v1beta1.PDMemberType, 1)
 				if test.pvcWithDeletionTimestamp {
 					pvc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
 				}
 				pvcIndexer.Add(pvc)
 			}
 			if test.hasPod {
-				pod := newPodForPDFailover(tc, v1alpha1.PDMemberType, 1)
+				pod := newPodForPDFailover(tc, v1beta1.PDMemberType, 1)
 				if test.podWithDeletionTimestamp {
 					pod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
 				}
@@ -517,8 +563,8 @@ func TestPDFailoverRecovery(t *testing.T) {
 
 	type testcase struct {
 		name     string
-		update   func(*v1alpha1.TikvCluster)
-		expectFn func(*v1alpha1.TikvCluster)
+		update   func(*v1beta1.TikvCluster)
+		expectFn func(*v1beta1.TikvCluster)
 	}
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
@@ -532,77 +578,77 @@ func TestPDFailoverRecovery(t *testing.T) {
 	tests := []testcase{
 		{
 			name: "two failure member, user don't modify the replicas",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				twoFailureMembers(tc)
 				tc.Spec.PD.Replicas = 3
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster) {
+			expectFn: func(tc *v1beta1.TikvCluster) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 			},
 		},
 		{
 			name: "two failure member, user modify the replicas to 4",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				twoFailureMembers(tc)
 				tc.Spec.PD.Replicas = 4
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster) {
+			expectFn: func(tc *v1beta1.TikvCluster) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(4))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 			},
 		},
 		{
 			name: "two failure member, user increase the replicas",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				twoFailureMembers(tc)
 				tc.Spec.PD.Replicas = 7
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster) {
+			expectFn: func(tc *v1beta1.TikvCluster) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(7))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 			},
 		},
 		{
 			name: "two failure member, user decrease the replicas",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				twoFailureMembers(tc)
 				tc.Spec.PD.Replicas = 1
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster) {
+			expectFn: func(tc *v1beta1.TikvCluster) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(1))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 			},
 		},
 		{
 			name: "one failure member, user don't modify the replicas",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				oneFailureMember(tc)
 				tc.Spec.PD.Replicas = 3
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster) {
+			expectFn: func(tc *v1beta1.TikvCluster) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(3))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 			},
 		},
 		{
 			name: "two failure member, user increase the replicas",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				oneFailureMember(tc)
 				tc.Spec.PD.Replicas = 5
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster) {
+			expectFn: func(tc *v1beta1.TikvCluster) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(5))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 			},
 		},
 		{
 			name: "two failure member, user decrease the replicas",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				oneFailureMember(tc)
 				tc.Spec.PD.Replicas = 1
 			},
-			expectFn: func(tc *v1alpha1.TikvCluster) {
+			expectFn: func(tc *v1beta1.TikvCluster) {
 				g.Expect(int(tc.Spec.PD.Replicas)).To(Equal(1))
 				g.Expect(len(tc.Status.PD.FailureMembers)).To(Equal(0))
 			},
@@ -640,73 +686,73 @@ func newFakePDFailover() (*pdFailover, cache.Indexer, cache.Indexer, *pdapi.Fake
 		pdControl, podControl, pvcControl
 }
 
-func oneFailureMember(tc *v1alpha1.TikvCluster) {
-	pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
-	pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
-	pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
-	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+func oneFailureMember(tc *v1beta1.TikvCluster) {
+	pd0 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 0)
+	pd1 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
+	pd2 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 2)
+	tc.Status.PD.Members = map[string]v1beta1.PDMember{
 		pd0: {Name: pd0, ID: "0", Health: true},
 		pd2: {Name: pd2, ID: "2", Health: true},
 	}
-	tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{
+	tc.Status.PD.FailureMembers = map[string]v1beta1.PDFailureMember{
 		pd1: {PodName: pd1, PVCUID: "pvc-1-uid", MemberID: "12891273174085095651"},
 	}
 }
 
-func twoFailureMembers(tc *v1alpha1.TikvCluster) {
-	pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
-	pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
-	pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
-	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+func twoFailureMembers(tc *v1beta1.TikvCluster) {
+	pd0 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 0)
+	pd1 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
+	pd2 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 2)
+	tc.Status.PD.Members = map[string]v1beta1.PDMember{
 		pd2: {Name: pd2, ID: "2", Health: true},
 	}
-	tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{
+	tc.Status.PD.FailureMembers = map[string]v1beta1.PDFailureMember{
 		pd0: {PodName: pd0},
 		pd1: {PodName: pd1},
 	}
 }
 
-func oneNotReadyMember(tc *v1alpha1.TikvCluster) {
-	pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
-	pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
-	pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
-	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+func oneNotReadyMember(tc *v1beta1.TikvCluster) {
+	pd0 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 0)
+	pd1 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
+	pd2 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 2)
+	tc.Status.PD.Members = map[string]v1beta1.PDMember{
 		pd0: {Name: pd0, ID: "0", Health: true},
 		pd1: {Name: pd1, ID: "12891273174085095651", Health: false, LastTransitionTime: metav1.Time{Time: time.Now().Add(-10 * time.Minute)}},
 		pd2: {Name: pd2, ID: "2", Health: true},
 	}
 }
 
-func oneNotReadyMemberAndAFailureMember(tc *v1alpha1.TikvCluster) {
-	pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
-	pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
-	pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
-	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+func oneNotReadyMemberAndAFailureMember(tc *v1beta1.TikvCluster) {
+	pd0 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 0)
+	pd1 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
+	pd2 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 2)
+	tc.Status.PD.Members = map[string]v1beta1.PDMember{
 		pd0: {Name: pd0, ID: "0", Health: true},
 		pd1: {Name: pd1, ID: "12891273174085095651", Health: false, LastTransitionTime: metav1.Time{Time: time.Now().Add(-10 * time.Minute)}},
 		pd2: {Name: pd2, ID: "2", Health: true},
 	}
-	tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{
+	tc.Status.PD.FailureMembers = map[string]v1beta1.PDFailureMember{
 		pd1: {PodName: pd1, PVCUID: "pvc-1-uid", MemberID: "12891273174085095651"},
 	}
 }
 
-func allMembersReady(tc *v1alpha1.TikvCluster) {
-	pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
-	pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
-	pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
-	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+func allMembersReady(tc *v1beta1.TikvCluster) {
+	pd0 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 0)
+	pd1 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
+	pd2 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 2)
+	tc.Status.PD.Members = map[string]v1beta1.PDMember{
 		pd0: {Name: pd0, ID: "0", Health: true},
 		pd1: {Name: pd1, ID: "12891273174085095651", Health: true},
 		pd2: {Name: pd2, ID: "2", Health: true},
 	}
 }
 
-func twoMembersNotReady(tc *v1alpha1.TikvCluster) {
-	pd0 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
-	pd1 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 1)
-	pd2 := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 2)
-	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+func twoMembersNotReady(tc *v1beta1.TikvCluster) {
+	pd0 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 0)
+	pd1 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 1)
+	pd2 := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 2)
+	tc.Status.PD.Members = map[string]v1beta1.PDMember{
 		pd0: {Name: pd0, ID: "0", Health: false},
 		pd1: {Name: pd1, ID: "12891273174085095651", Health: false},
 		pd2: {Name: pd2, ID: "2", Health: true},
@@ -721,7 +767,7 @@ func errExpectNotNil(g *GomegaWithT, err error) {
 	g.Expect(err).To(HaveOccurred())
 }
 
-func newPVCForPDFailover(tc *v1alpha1.TikvCluster, memberType v1alpha1.MemberType, ordinal int32) *corev1.PersistentVolumeClaim {
+func newPVCForPDFailover(tc *v1beta1.TikvCluster, memberType v1beta1.MemberType, ordinal int32) *corev1.PersistentVolumeClaim {
 	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ordinalPVCName(memberType, controller.PDMemberName(tc.GetName()), ordinal),
@@ -734,7 +780,7 @@ func newPVCForPDFailover(tc *v1alpha1.TikvCluster, memberType v1alpha1.MemberTyp
 	}
 }
 
-func newPodForPDFailover(tc *v1alpha1.TikvCluster, memberType v1alpha1.MemberType, ordinal int32) *corev1.Pod {
+func newPodForPDFailover(tc *v1beta1.TikvCluster, memberType v1beta1.MemberType, ordinal int32) *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ordinalPodName(memberType, tc.GetName(), ordinal),
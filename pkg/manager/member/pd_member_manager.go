@@ -19,7 +19,7 @@ import (
 	"strings"
 
 	"github.com/Masterminds/semver"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/manager"
@@ -58,6 +58,7 @@ type pdMemberManager struct {
 	pdUpgrader   Upgrader
 	autoFailover bool
 	pdFailover   Failover
+	pdRecovery   PDRecovery
 }
 
 // NewPDMemberManager returns a *pdMemberManager
@@ -74,7 +75,8 @@ func NewPDMemberManager(pdControl pdapi.PDControlInterface,
 	pdScaler Scaler,
 	pdUpgrader Upgrader,
 	autoFailover bool,
-	pdFailover Failover) manager.Manager {
+	pdFailover Failover,
+	pdRecovery PDRecovery) manager.Manager {
 	return &pdMemberManager{
 		pdControl,
 		setControl,
@@ -89,10 +91,11 @@ func NewPDMemberManager(pdControl pdapi.PDControlInterface,
 		pdScaler,
 		pdUpgrader,
 		autoFailover,
-		pdFailover}
+		pdFailover,
+		pdRecovery}
 }
 
-func (pmm *pdMemberManager) Sync(tc *v1alpha1.TikvCluster) error {
+func (pmm *pdMemberManager) Sync(tc *v1beta1.TikvCluster) error {
 	// Sync PD Service
 	if err := pmm.syncPDServiceForTikvCluster(tc); err != nil {
 		return err
@@ -103,11 +106,16 @@ func (pmm *pdMemberManager) Sync(tc *v1alpha1.TikvCluster) error {
 		return err
 	}
 
+	// Sync PD PodDisruptionBudget
+	if err := syncPodDisruptionBudget(pmm.typedControl, tc, getPDPodDisruptionBudget(tc), tc.Spec.PD.PodDisruptionBudget, v1beta1.PDDisruptionBlocked); err != nil {
+		return err
+	}
+
 	// Sync PD StatefulSet
 	return pmm.syncPDStatefulSetForTikvCluster(tc)
 }
 
-func (pmm *pdMemberManager) syncPDServiceForTikvCluster(tc *v1alpha1.TikvCluster) error {
+func (pmm *pdMemberManager) syncPDServiceForTikvCluster(tc *v1beta1.TikvCluster) error {
 	if tc.Spec.Paused {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for pd service", tc.GetNamespace(), tc.GetName())
 		return nil
@@ -151,7 +159,7 @@ func (pmm *pdMemberManager) syncPDServiceForTikvCluster(tc *v1alpha1.TikvCluster
 	return nil
 }
 
-func (pmm *pdMemberManager) syncPDHeadlessServiceForTikvCluster(tc *v1alpha1.TikvCluster) error {
+func (pmm *pdMemberManager) syncPDHeadlessServiceForTikvCluster(tc *v1beta1.TikvCluster) error {
 	if tc.Spec.Paused {
 		klog.V(4).Infof("tidb cluster %s/%s is paused, skip syncing for pd headless service", tc.GetNamespace(), tc.GetName())
 		return nil
@@ -191,7 +199,7 @@ func (pmm *pdMemberManager) syncPDHeadlessServiceForTikvCluster(tc *v1alpha1.Tik
 	return nil
 }
 
-func (pmm *pdMemberManager) syncPDStatefulSetForTikvCluster(tc *v1alpha1.TikvCluster) error {
+func (pmm *pdMemberManager) syncPDStatefulSetForTikvCluster(tc *v1beta1.TikvCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
@@ -212,6 +220,11 @@ func (pmm *pdMemberManager) syncPDStatefulSetForTikvCluster(tc *v1alpha1.TikvClu
 		return nil
 	}
 
+	held, err := pmm.pdRecovery.Sync(tc, oldPDSet)
+	if err != nil {
+		return err
+	}
+
 	cm, err := pmm.syncPDConfigMap(tc, oldPDSet)
 	if err != nil {
 		return err
@@ -221,6 +234,10 @@ func (pmm *pdMemberManager) syncPDStatefulSetForTikvCluster(tc *v1alpha1.TikvClu
 		return err
 	}
 	if setNotExist {
+		// a cloned cluster is bootstrapped by a single member
+		if tc.PDRecreatingMember() {
+			newPDSet.Spec.Replicas = controller.Int32Ptr(1)
+		}
 		err = SetStatefulSetLastAppliedConfigAnnotation(newPDSet)
 		if err != nil {
 			return err
@@ -232,27 +249,37 @@ func (pmm *pdMemberManager) syncPDStatefulSetForTikvCluster(tc *v1alpha1.TikvClu
 		return controller.RequeueErrorf("TikvCluster: [%s/%s], waiting for PD cluster running", ns, tcName)
 	}
 
+	if held {
+		return nil
+	}
+
 	if !tc.Status.PD.Synced {
 		force := NeedForceUpgrade(tc)
 		if force {
-			tc.Status.PD.Phase = v1alpha1.UpgradePhase
+			tc.Status.PD.Phase = v1beta1.UpgradePhase
 			setUpgradePartition(newPDSet, 0)
 			errSTS := updateStatefulSet(pmm.setControl, tc, newPDSet, oldPDSet)
 			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s pd needs force upgrade, %v", ns, tcName, errSTS)
 		}
 	}
 
-	if !templateEqual(newPDSet, oldPDSet) || tc.Status.PD.Phase == v1alpha1.UpgradePhase {
-		if err := pmm.pdUpgrader.Upgrade(tc, oldPDSet, newPDSet); err != nil {
+	if !templateEqual(newPDSet, oldPDSet) || tc.Status.PD.Phase == v1beta1.UpgradePhase {
+		if !allowDisruption(tc, v1beta1.PDUpgradeOperation) {
+			if err := holdUpgrade(oldPDSet, newPDSet); err != nil {
+				return err
+			}
+		} else if err := pmm.pdUpgrader.Upgrade(tc, oldPDSet, newPDSet); err != nil {
 			return err
 		}
 	}
 
-	if err := pmm.pdScaler.Scale(tc, oldPDSet, newPDSet); err != nil {
+	if scaling, _, _, _ := scaleOne(oldPDSet, newPDSet); scaling < 0 && !allowDisruption(tc, v1beta1.PDScaleInOperation) {
+		resetReplicas(newPDSet, oldPDSet)
+	} else if err := pmm.pdScaler.Scale(tc, oldPDSet, newPDSet); err != nil {
 		return err
 	}
 
-	if pmm.autoFailover {
+	if tc.PDFailoverEnabled(pmm.autoFailover) && !tc.PDRecovering() {
 		if pmm.shouldRecover(tc) {
 			pmm.pdFailover.Recover(tc)
 		} else if tc.PDAllPodsStarted() && !tc.PDAllMembersReady() || tc.PDAutoFailovering() {
@@ -266,7 +293,7 @@ func (pmm *pdMemberManager) syncPDStatefulSetForTikvCluster(tc *v1alpha1.TikvClu
 }
 
 // shouldRecover checks whether we should perform recovery operation.
-func (pmm *pdMemberManager) shouldRecover(tc *v1alpha1.TikvCluster) bool {
+func (pmm *pdMemberManager) shouldRecover(tc *v1beta1.TikvCluster) bool {
 	if tc.Status.PD.FailureMembers == nil {
 		return false
 	}
@@ -292,7 +319,7 @@ func (pmm *pdMemberManager) shouldRecover(tc *v1alpha1.TikvCluster) bool {
 	return true
 }
 
-func (pmm *pdMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, set *apps.StatefulSet) error {
+func (pmm *pdMemberManager) syncTikvClusterStatus(tc *v1beta1.TikvCluster, set *apps.StatefulSet) error {
 	if set == nil {
 		// skip if not created yet
 		return nil
@@ -308,9 +335,9 @@ func (pmm *pdMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, set
 		return err
 	}
 	if upgrading {
-		tc.Status.PD.Phase = v1alpha1.UpgradePhase
+		tc.Status.PD.Phase = v1beta1.UpgradePhase
 	} else {
-		tc.Status.PD.Phase = v1alpha1.NormalPhase
+		tc.Status.PD.Phase = v1beta1.NormalPhase
 	}
 
 	pdClient := controller.GetPDClient(pmm.pdControl, tc)
@@ -341,7 +368,7 @@ func (pmm *pdMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, set
 		tc.Status.PD.Synced = false
 		return err
 	}
-	pdStatus := map[string]v1alpha1.PDMember{}
+	pdStatus := map[string]v1beta1.PDMember{}
 	for _, memberHealth := range healthInfo.Healths {
 		id := memberHealth.MemberID
 		memberID := fmt.Sprintf("%d", id)
@@ -356,7 +383,7 @@ func (pmm *pdMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, set
 			continue
 		}
 
-		status := v1alpha1.PDMember{
+		status := v1beta1.PDMember{
 			Name:      name,
 			ID:        memberID,
 			ClientURL: clientURL,
@@ -391,7 +418,7 @@ func (pmm *pdMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, set
 }
 
 // syncPDConfigMap syncs the configmap of PD
-func (pmm *pdMemberManager) syncPDConfigMap(tc *v1alpha1.TikvCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
+func (pmm *pdMemberManager) syncPDConfigMap(tc *v1beta1.TikvCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
 
 	// For backward compatibility, only sync tidb configmap when .pd.config is non-nil
 	if tc.Spec.PD.Config == nil {
@@ -401,19 +428,12 @@ func (pmm *pdMemberManager) syncPDConfigMap(tc *v1alpha1.TikvCluster, set *apps.
 	if err != nil {
 		return nil, err
 	}
-	if set != nil && tc.BasePDSpec().ConfigUpdateStrategy() == v1alpha1.ConfigUpdateStrategyInPlace {
-		inUseName := FindConfigMapVolume(&set.Spec.Template.Spec, func(name string) bool {
-			return strings.HasPrefix(name, controller.PDMemberName(tc.Name))
-		})
-		if inUseName != "" {
-			newCm.Name = inUseName
-		}
-	}
+	keepInUseConfigMapName(newCm, set, tc.BasePDSpec().ConfigUpdateStrategy(), controller.PDMemberName(tc.Name))
 
 	return pmm.typedControl.CreateOrUpdateConfigMap(tc, newCm)
 }
 
-func (pmm *pdMemberManager) getNewPDServiceForTikvCluster(tc *v1alpha1.TikvCluster) *corev1.Service {
+func (pmm *pdMemberManager) getNewPDServiceForTikvCluster(tc *v1beta1.TikvCluster) *corev1.Service {
 	ns := tc.Namespace
 	tcName := tc.Name
 	svcName := controller.PDMemberName(tcName)
@@ -460,7 +480,7 @@ func (pmm *pdMemberManager) getNewPDServiceForTikvCluster(tc *v1alpha1.TikvClust
 	return pdService
 }
 
-func getNewPDHeadlessServiceForTikvCluster(tc *v1alpha1.TikvCluster) *corev1.Service {
+func getNewPDHeadlessServiceForTikvCluster(tc *v1beta1.TikvCluster) *corev1.Service {
 	ns := tc.Namespace
 	tcName := tc.Name
 	svcName := controller.PDPeerMemberName(tcName)
@@ -490,7 +510,7 @@ func getNewPDHeadlessServiceForTikvCluster(tc *v1alpha1.TikvCluster) *corev1.Ser
 	}
 }
 
-func (pmm *pdMemberManager) pdStatefulSetIsUpgrading(set *apps.StatefulSet, tc *v1alpha1.TikvCluster) (bool, error) {
+func (pmm *pdMemberManager) pdStatefulSetIsUpgrading(set *apps.StatefulSet, tc *v1beta1.TikvCluster) (bool, error) {
 	if statefulSetIsUpgrading(set) {
 		return true, nil
 	}
@@ -517,7 +537,7 @@ func (pmm *pdMemberManager) pdStatefulSetIsUpgrading(set *apps.StatefulSet, tc *
 	return false, nil
 }
 
-func getFailureReplicas(tc *v1alpha1.TikvCluster) int {
+func getFailureReplicas(tc *v1beta1.TikvCluster) int {
 	failureReplicas := 0
 	for _, failureMember := range tc.Status.PD.FailureMembers {
 		if failureMember.MemberDeleted {
@@ -527,12 +547,12 @@ func getFailureReplicas(tc *v1alpha1.TikvCluster) int {
 	return failureReplicas
 }
 
-func getNewPDSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+func getNewPDSetForTikvCluster(tc *v1beta1.TikvCluster, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
 	ns := tc.Namespace
 	tcName := tc.Name
 	basePDSpec := tc.BasePDSpec()
 	instanceName := tc.GetInstanceName()
-	pdConfigMap := controller.MemberConfigMapName(tc, v1alpha1.PDMemberType)
+	pdConfigMap := controller.MemberConfigMapName(tc, v1beta1.PDMemberType)
 	if cm != nil {
 		pdConfigMap = cm.Name
 	}
@@ -542,7 +562,7 @@ func getNewPDSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap) (
 		annMount,
 		{Name: "config", ReadOnly: true, MountPath: "/etc/pd"},
 		{Name: "startup-script", ReadOnly: true, MountPath: "/usr/local/bin"},
-		{Name: v1alpha1.PDMemberType.String(), MountPath: "/var/lib/pd"},
+		{Name: v1beta1.PDMemberType.String(), MountPath: "/var/lib/pd"},
 	}
 	if tc.IsTLSClusterEnabled() {
 		volMounts = append(volMounts, corev1.VolumeMount{
@@ -595,7 +615,7 @@ func getNewPDSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap) (
 	failureReplicas := getFailureReplicas(tc)
 
 	pdContainer := corev1.Container{
-		Name:            v1alpha1.PDMemberType.String(),
+		Name:            v1beta1.PDMemberType.String(),
 		Image:           tc.PDImage(),
 		ImagePullPolicy: basePDSpec.ImagePullPolicy(),
 		Command:         []string{"/bin/sh", "/usr/local/bin/pd_start_script.sh"},
@@ -678,7 +698,7 @@ func getNewPDSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap) (
 			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
 				{
 					ObjectMeta: metav1.ObjectMeta{
-						Name: v1alpha1.PDMemberType.String(),
+						Name: v1beta1.PDMemberType.String(),
 					},
 					Spec: corev1.PersistentVolumeClaimSpec{
 						AccessModes: []corev1.PersistentVolumeAccessMode{
@@ -702,7 +722,7 @@ func getNewPDSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap) (
 	return pdSet, nil
 }
 
-func getPDConfigMap(tc *v1alpha1.TikvCluster) (*corev1.ConfigMap, error) {
+func getPDConfigMap(tc *v1beta1.TikvCluster) (*corev1.ConfigMap, error) {
 
 	// For backward compatibility, only sync tidb configmap when .tidb.config is non-nil
 	config := tc.Spec.PD.Config
@@ -714,7 +734,10 @@ func getPDConfigMap(tc *v1alpha1.TikvCluster) (*corev1.ConfigMap, error) {
 	if err != nil {
 		return nil, err
 	}
-	startScript, err := RenderPDStartScript(&PDStartScriptModel{Scheme: tc.Scheme()})
+	startScript, err := RenderPDStartScript(&PDStartScriptModel{
+		Scheme:        tc.Scheme(),
+		ClusterDomain: tc.Spec.ClusterDomain,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -733,7 +756,7 @@ func getPDConfigMap(tc *v1alpha1.TikvCluster) (*corev1.ConfigMap, error) {
 			"startup-script": startScript,
 		},
 	}
-	if tc.BasePDSpec().ConfigUpdateStrategy() == v1alpha1.ConfigUpdateStrategyRollingUpdate {
+	if tc.BasePDSpec().ConfigUpdateStrategy() == v1beta1.ConfigUpdateStrategyRollingUpdate {
 		if err := AddConfigMapDigestSuffix(cm); err != nil {
 			return nil, err
 		}
@@ -750,7 +773,7 @@ func clusterVersionGreaterThanOrEqualTo4(version string) (bool, error) {
 	return v.Major() >= 4, nil
 }
 
-func (pmm *pdMemberManager) collectUnjoinedMembers(tc *v1alpha1.TikvCluster, set *apps.StatefulSet, pdStatus map[string]v1alpha1.PDMember) error {
+func (pmm *pdMemberManager) collectUnjoinedMembers(tc *v1beta1.TikvCluster, set *apps.StatefulSet, pdStatus map[string]v1beta1.PDMember) error {
 	podSelector, podSelectErr := metav1.LabelSelectorAsSelector(set.Spec.Selector)
 	if podSelectErr != nil {
 		return podSelectErr
@@ -769,18 +792,18 @@ func (pmm *pdMemberManager) collectUnjoinedMembers(tc *v1alpha1.TikvCluster, set
 		}
 		if !joined {
 			if tc.Status.PD.UnjoinedMembers == nil {
-				tc.Status.PD.UnjoinedMembers = map[string]v1alpha1.UnjoinedMember{}
+				tc.Status.PD.UnjoinedMembers = map[string]v1beta1.UnjoinedMember{}
 			}
 			ordinal, err := util.GetOrdinalFromPodName(pod.Name)
 			if err != nil {
 				return err
 			}
-			pvcName := ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(tc.Name), ordinal)
+			pvcName := ordinalPVCName(v1beta1.PDMemberType, controller.PDMemberName(tc.Name), ordinal)
 			pvc, err := pmm.pvcLister.PersistentVolumeClaims(tc.Namespace).Get(pvcName)
 			if err != nil {
 				return err
 			}
-			tc.Status.PD.UnjoinedMembers[pod.Name] = v1alpha1.UnjoinedMember{
+			tc.Status.PD.UnjoinedMembers[pod.Name] = v1beta1.UnjoinedMember{
 				PodName:   pod.Name,
 				PVCUID:    pvc.UID,
 				CreatedAt: metav1.Now(),
@@ -809,7 +832,7 @@ func (fpmm *FakePDMemberManager) SetSyncError(err error) {
 	fpmm.err = err
 }
 
-func (fpmm *FakePDMemberManager) Sync(tc *v1alpha1.TikvCluster) error {
+func (fpmm *FakePDMemberManager) Sync(tc *v1beta1.TikvCluster) error {
 	if fpmm.err != nil {
 		return fpmm.err
 	}
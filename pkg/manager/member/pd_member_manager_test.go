@@ -22,7 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/metapb"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
 	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
 	"github.com/tikv/tikv-operator/pkg/controller"
@@ -46,7 +46,7 @@ func TestPDMemberManagerSyncCreate(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                       string
-		prepare                    func(cluster *v1alpha1.TikvCluster)
+		prepare                    func(cluster *v1beta1.TikvCluster)
 		errWhenCreateStatefulSet   bool
 		errWhenCreatePDService     bool
 		errWhenCreatePDPeerService bool
@@ -180,7 +180,7 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                       string
-		modify                     func(cluster *v1alpha1.TikvCluster)
+		modify                     func(cluster *v1beta1.TikvCluster)
 		pdHealth                   *pdapi.HealthInfo
 		errWhenUpdateStatefulSet   bool
 		errWhenUpdatePDService     bool
@@ -192,13 +192,13 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 		expectPDServiceFn          func(*GomegaWithT, *corev1.Service, error)
 		expectPDPeerServiceFn      func(*GomegaWithT, *corev1.Service, error)
 		expectStatefulSetFn        func(*GomegaWithT, *apps.StatefulSet, error)
-		expectTikvClusterFn        func(*GomegaWithT, *v1alpha1.TikvCluster)
+		expectTikvClusterFn        func(*GomegaWithT, *v1beta1.TikvCluster)
 	}
 
 	tests := []testcase{
 		{
 			name: "normal",
-			modify: func(tc *v1alpha1.TikvCluster) {
+			modify: func(tc *v1beta1.TikvCluster) {
 				tc.Spec.PD.Replicas = 5
 			},
 			pdHealth: &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
@@ -220,9 +220,9 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 				// g.Expect(int(*set.Spec.Replicas)).To(Equal(4))
 			},
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.ClusterID).To(Equal("1"))
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.NormalPhase))
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.NormalPhase))
 				g.Expect(tc.Status.PD.StatefulSet.ObservedGeneration).To(Equal(int64(1)))
 				g.Expect(len(tc.Status.PD.Members)).To(Equal(3))
 				g.Expect(tc.Status.PD.Members["pd1"].Health).To(Equal(true))
@@ -232,7 +232,7 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 		},
 		{
 			name: "error when update statefulset",
-			modify: func(tc *v1alpha1.TikvCluster) {
+			modify: func(tc *v1beta1.TikvCluster) {
 				tc.Spec.PD.Replicas = 5
 			},
 			pdHealth: &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
@@ -252,7 +252,7 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 		},
 		{
 			name: "error when sync pd status",
-			modify: func(tc *v1alpha1.TikvCluster) {
+			modify: func(tc *v1beta1.TikvCluster) {
 				tc.Spec.PD.Replicas = 5
 			},
 			errWhenUpdateStatefulSet:   false,
@@ -265,14 +265,14 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.PD.Synced).To(BeFalse())
 				g.Expect(tc.Status.PD.Members).To(BeNil())
 			},
 		},
 		{
 			name: "error when sync cluster ID",
-			modify: func(tc *v1alpha1.TikvCluster) {
+			modify: func(tc *v1beta1.TikvCluster) {
 				tc.Spec.PD.Replicas = 5
 			},
 			errWhenUpdateStatefulSet:   false,
@@ -286,7 +286,7 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.PD.Synced).To(BeFalse())
 				g.Expect(tc.Status.PD.Members).To(BeNil())
 			},
@@ -418,7 +418,7 @@ func TestPDMemberManagerPdStatefulSetIsUpgrading(t *testing.T) {
 		if test.hasPod {
 			pod := &corev1.Pod{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:        ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0),
+					Name:        ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 0),
 					Namespace:   metav1.NamespaceDefault,
 					Annotations: map[string]string{},
 					Labels:      label.New().Instance(tc.GetInstanceName()).PD().Labels(),
@@ -492,12 +492,12 @@ func TestPDMemberManagerUpgrade(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                string
-		modify              func(cluster *v1alpha1.TikvCluster)
+		modify              func(cluster *v1beta1.TikvCluster)
 		pdHealth            *pdapi.HealthInfo
 		err                 bool
 		statusChange        func(*apps.StatefulSet)
 		expectStatefulSetFn func(*GomegaWithT, *apps.StatefulSet, error)
-		expectTikvClusterFn func(*GomegaWithT, *v1alpha1.TikvCluster)
+		expectTikvClusterFn func(*GomegaWithT, *v1beta1.TikvCluster)
 	}
 
 	testFn := func(test *testcase, t *testing.T) {
@@ -548,8 +548,8 @@ func TestPDMemberManagerUpgrade(t *testing.T) {
 	tests := []testcase{
 		{
 			name: "upgrade successful",
-			modify: func(cluster *v1alpha1.TikvCluster) {
-				cluster.Spec.PD.Image = "pd-test-image:v2"
+			modify: func(cluster *v1beta1.TikvCluster) {
+				cluster.Spec.PD.Version = "v2"
 			},
 			pdHealth: &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
 				{Name: "pd1", MemberID: uint64(1), ClientUrls: []string{"http://pd1:2379"}, Health: true},
@@ -568,8 +568,8 @@ func TestPDMemberManagerUpgrade(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 				g.Expect(set.Spec.Template.Spec.Containers[0].Image).To(Equal("pd-test-image:v2"))
 			},
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(len(tc.Status.PD.Members)).To(Equal(3))
 				g.Expect(tc.Status.PD.Members["pd1"].Health).To(Equal(true))
 				g.Expect(tc.Status.PD.Members["pd2"].Health).To(Equal(true))
@@ -588,19 +588,19 @@ func TestPDMemberManagerSyncPDSts(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                string
-		modify              func(cluster *v1alpha1.TikvCluster)
+		modify              func(cluster *v1beta1.TikvCluster)
 		pdHealth            *pdapi.HealthInfo
 		err                 bool
 		statusChange        func(*apps.StatefulSet)
 		expectStatefulSetFn func(*GomegaWithT, *apps.StatefulSet, error)
-		expectTikvClusterFn func(*GomegaWithT, *v1alpha1.TikvCluster)
+		expectTikvClusterFn func(*GomegaWithT, *v1beta1.TikvCluster)
 	}
 
 	tests := []testcase{
 		{
 			name: "force upgrade",
-			modify: func(cluster *v1alpha1.TikvCluster) {
-				cluster.Spec.PD.Image = "pd-test-image:v2"
+			modify: func(cluster *v1beta1.TikvCluster) {
+				cluster.Spec.PD.Version = "v2"
 				cluster.Spec.PD.Replicas = 1
 				cluster.ObjectMeta.Annotations = make(map[string]string)
 				cluster.ObjectMeta.Annotations["tikv.org/force-upgrade"] = "true"
@@ -624,14 +624,14 @@ func TestPDMemberManagerSyncPDSts(t *testing.T) {
 				g.Expect(*set.Spec.Replicas).To(Equal(int32(1)))
 				g.Expect(*set.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(0)))
 			},
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 			},
 		},
 		{
 			name: "non force upgrade",
-			modify: func(cluster *v1alpha1.TikvCluster) {
-				cluster.Spec.PD.Image = "pd-test-image:v2"
+			modify: func(cluster *v1beta1.TikvCluster) {
+				cluster.Spec.PD.Version = "v2"
 				cluster.Spec.PD.Replicas = 1
 			},
 			pdHealth: &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
@@ -649,12 +649,12 @@ func TestPDMemberManagerSyncPDSts(t *testing.T) {
 			},
 			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
-				g.Expect(set.Spec.Template.Spec.Containers[0].Image).To(Equal("pd-test-image"))
+				g.Expect(set.Spec.Template.Spec.Containers[0].Image).To(Equal("pd-test-image:v1"))
 				g.Expect(*set.Spec.Replicas).To(Equal(int32(3)))
 				g.Expect(*set.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
 			},
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.NormalPhase))
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.NormalPhase))
 			},
 		},
 	}
@@ -716,7 +716,7 @@ func newFakePDMemberManager() (*pdMemberManager, *controller.FakeStatefulSetCont
 	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
 	epsInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Endpoints()
 	pvcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().PersistentVolumeClaims()
-	tcInformer := informers.NewSharedInformerFactory(cli, 0).Tikv().V1alpha1().TikvClusters()
+	tcInformer := informers.NewSharedInformerFactory(cli, 0).Tikv().V1beta1().TikvClusters()
 	setControl := controller.NewFakeStatefulSetControl(setInformer, tcInformer)
 	svcControl := controller.NewFakeServiceControl(svcInformer, epsInformer, tcInformer)
 	podControl := controller.NewFakePodControl(podInformer)
@@ -742,25 +742,27 @@ func newFakePDMemberManager() (*pdMemberManager, *controller.FakeStatefulSetCont
 		pdUpgrader,
 		autoFailover,
 		pdFailover,
+		NewFakePDRecovery(),
 	}, setControl, svcControl, pdControl, podInformer.Informer().GetIndexer(), pvcInformer.Informer().GetIndexer(), podControl
 }
 
-func newTikvClusterForPD() *v1alpha1.TikvCluster {
-	return &v1alpha1.TikvCluster{
+func newTikvClusterForPD() *v1beta1.TikvCluster {
+	return &v1beta1.TikvCluster{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "TikvCluster",
-			APIVersion: "tikv.org/v1alpha1",
+			APIVersion: "tikv.org/v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test",
 			Namespace: corev1.NamespaceDefault,
 			UID:       types.UID("test"),
 		},
-		Spec: v1alpha1.TikvClusterSpec{
-			PD: v1alpha1.PDSpec{
-				ComponentSpec: v1alpha1.ComponentSpec{
-					Image: "pd-test-image",
+		Spec: v1beta1.TikvClusterSpec{
+			PD: v1beta1.PDSpec{
+				ComponentSpec: v1beta1.ComponentSpec{
+					Version: "v1",
 				},
+				BaseImage: "pd-test-image",
 				ResourceRequirements: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceCPU:     resource.MustParse("1"),
@@ -771,10 +773,11 @@ func newTikvClusterForPD() *v1alpha1.TikvCluster {
 				Replicas:         3,
 				StorageClassName: pointer.StringPtr("my-storage-class"),
 			},
-			TiKV: v1alpha1.TiKVSpec{
-				ComponentSpec: v1alpha1.ComponentSpec{
-					Image: "tikv-test-image",
+			TiKV: v1beta1.TiKVSpec{
+				ComponentSpec: v1beta1.ComponentSpec{
+					Version: "v1",
 				},
+				BaseImage: "tikv-test-image",
 				ResourceRequirements: corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceCPU:     resource.MustParse("1"),
@@ -797,12 +800,12 @@ func expectErrIsNotFound(g *GomegaWithT, err error) {
 func TestGetNewPDHeadlessServiceForTikvCluster(t *testing.T) {
 	tests := []struct {
 		name     string
-		tc       v1alpha1.TikvCluster
+		tc       v1beta1.TikvCluster
 		expected corev1.Service
 	}{
 		{
 			name: "basic",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
@@ -820,7 +823,7 @@ func TestGetNewPDHeadlessServiceForTikvCluster(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -888,7 +891,7 @@ func testPDContainerEnv(t *testing.T, env []corev1.EnvVar) func(sts *apps.Statef
 	return func(sts *apps.StatefulSet) {
 		got := []corev1.EnvVar{}
 		for _, c := range sts.Spec.Template.Spec.Containers {
-			if c.Name == v1alpha1.PDMemberType.String() {
+			if c.Name == v1beta1.PDMemberType.String() {
 				got = c.Env
 			}
 		}
@@ -902,13 +905,13 @@ func TestGetNewPDSetForTikvCluster(t *testing.T) {
 	enable := true
 	tests := []struct {
 		name    string
-		tc      v1alpha1.TikvCluster
+		tc      v1beta1.TikvCluster
 		wantErr bool
 		testSts func(sts *apps.StatefulSet)
 	}{
 		{
 			name: "pd network is not host",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
@@ -918,14 +921,14 @@ func TestGetNewPDSetForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "pd network is host",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							HostNetwork: &enable,
 						},
 					},
@@ -935,14 +938,14 @@ func TestGetNewPDSetForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "pd network is not host when tikv is host",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							HostNetwork: &enable,
 						},
 					},
@@ -952,13 +955,13 @@ func TestGetNewPDSetForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "PD should respect resources config",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
 						ResourceRequirements: corev1.ResourceRequirements{
 							Requests: corev1.ResourceList{
 								corev1.ResourceCPU:              resource.MustParse("1"),
@@ -983,7 +986,7 @@ func TestGetNewPDSetForTikvCluster(t *testing.T) {
 					},
 				}))
 				nameToContainer := MapContainers(&sts.Spec.Template.Spec)
-				pdContainer := nameToContainer[v1alpha1.PDMemberType.String()]
+				pdContainer := nameToContainer[v1beta1.PDMemberType.String()]
 				g.Expect(pdContainer.Resources).To(Equal(corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceCPU:              resource.MustParse("1"),
@@ -1000,14 +1003,14 @@ func TestGetNewPDSetForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "set custom env",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							Env: []corev1.EnvVar{
 								{
 									Name: "DASHBOARD_SESSION_SECRET",
@@ -1081,15 +1084,15 @@ func TestGetNewPDSetForTikvCluster(t *testing.T) {
 
 func TestGetPDConfigMap(t *testing.T) {
 	g := NewGomegaWithT(t)
-	updateStrategy := v1alpha1.ConfigUpdateStrategyInPlace
+	updateStrategy := v1beta1.ConfigUpdateStrategyInPlace
 	testCases := []struct {
 		name     string
-		tc       v1alpha1.TikvCluster
+		tc       v1beta1.TikvCluster
 		expected *corev1.ConfigMap
 	}{
 		{
 			name: "PD config is nil",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
@@ -1099,22 +1102,22 @@ func TestGetPDConfigMap(t *testing.T) {
 		},
 		{
 			name: "basic",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							ConfigUpdateStrategy: &updateStrategy,
 						},
-						Config: &v1alpha1.PDConfig{
-							Schedule: &v1alpha1.PDScheduleConfig{
+						Config: &v1beta1.PDConfig{
+							Schedule: &v1beta1.PDScheduleConfig{
 								MaxStoreDownTime:         pointer.StringPtr("5m"),
 								DisableRemoveDownReplica: pointer.BoolPtr(true),
 							},
-							Replication: &v1alpha1.PDReplicationConfig{
+							Replication: &v1beta1.PDReplicationConfig{
 								MaxReplicas:    func() *uint64 { i := uint64(5); return &i }(),
 								LocationLabels: []string{"node", "rack"},
 							},
@@ -1134,7 +1137,7 @@ func TestGetPDConfigMap(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -1182,17 +1185,17 @@ func TestGetPDConfigMap(t *testing.T) {
 func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 	tests := []struct {
 		name     string
-		tc       v1alpha1.TikvCluster
+		tc       v1beta1.TikvCluster
 		expected corev1.Service
 	}{
 		{
 			name: "basic",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{},
+				Spec: v1beta1.TikvClusterSpec{},
 			},
 			expected: corev1.Service{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1206,7 +1209,7 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -1240,14 +1243,14 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "basic and  specify ClusterIP type,clusterIP",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
-						Service: &v1alpha1.ServiceSpec{ClusterIP: pointer.StringPtr("172.20.10.1")},
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						Service: &v1beta1.ServiceSpec{ClusterIP: pointer.StringPtr("172.20.10.1")},
 					},
 				},
 			},
@@ -1263,7 +1266,7 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -1298,14 +1301,14 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "basic and specify LoadBalancerIP type , LoadBalancerType",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
-						Service: &v1alpha1.ServiceSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						Service: &v1beta1.ServiceSpec{
 							Type:           corev1.ServiceTypeLoadBalancer,
 							LoadBalancerIP: pointer.StringPtr("172.20.10.1"),
 						},
@@ -1324,7 +1327,7 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -1359,14 +1362,14 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "basic and specify pd service overwrite",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
-						Service: &v1alpha1.ServiceSpec{Type: corev1.ServiceTypeClusterIP,
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						Service: &v1beta1.ServiceSpec{Type: corev1.ServiceTypeClusterIP,
 							ClusterIP: pointer.StringPtr("172.20.10.1")},
 					},
 				},
@@ -1383,7 +1386,7 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -1418,14 +1421,14 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "basic and specify pd service portname",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
-						Service: &v1alpha1.ServiceSpec{Type: corev1.ServiceTypeClusterIP,
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						Service: &v1beta1.ServiceSpec{Type: corev1.ServiceTypeClusterIP,
 							ClusterIP: pointer.StringPtr("172.20.10.1"),
 							PortName:  pointer.StringPtr("http-pd"),
 						},
@@ -1444,7 +1447,7 @@ func TestGetNewPdServiceForTikvCluster(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -1494,11 +1497,11 @@ func TestPDMemberManagerSyncPDStsWhenPdNotJoinCluster(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                string
-		modify              func(cluster *v1alpha1.TikvCluster, podIndexer cache.Indexer, pvcIndexer cache.Indexer)
+		modify              func(cluster *v1beta1.TikvCluster, podIndexer cache.Indexer, pvcIndexer cache.Indexer)
 		pdHealth            *pdapi.HealthInfo
-		tcStatusChange      func(cluster *v1alpha1.TikvCluster)
+		tcStatusChange      func(cluster *v1beta1.TikvCluster)
 		err                 bool
-		expectTikvClusterFn func(*GomegaWithT, *v1alpha1.TikvCluster)
+		expectTikvClusterFn func(*GomegaWithT, *v1beta1.TikvCluster)
 	}
 
 	testFn := func(test *testcase, t *testing.T) {
@@ -1541,11 +1544,11 @@ func TestPDMemberManagerSyncPDStsWhenPdNotJoinCluster(t *testing.T) {
 	tests := []testcase{
 		{
 			name: "add pd unjoin cluster member info ",
-			modify: func(cluster *v1alpha1.TikvCluster, podIndexer cache.Indexer, pvcIndexer cache.Indexer) {
+			modify: func(cluster *v1beta1.TikvCluster, podIndexer cache.Indexer, pvcIndexer cache.Indexer) {
 				for ordinal := 0; ordinal < 3; ordinal++ {
 					pod := &corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:        ordinalPodName(v1alpha1.PDMemberType, cluster.GetName(), int32(ordinal)),
+							Name:        ordinalPodName(v1beta1.PDMemberType, cluster.GetName(), int32(ordinal)),
 							Namespace:   metav1.NamespaceDefault,
 							Annotations: map[string]string{},
 							Labels:      label.New().Instance(cluster.GetInstanceName()).PD().Labels(),
@@ -1556,7 +1559,7 @@ func TestPDMemberManagerSyncPDStsWhenPdNotJoinCluster(t *testing.T) {
 				for ordinal := 0; ordinal < 3; ordinal++ {
 					pvc := &corev1.PersistentVolumeClaim{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:        ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(cluster.GetName()), int32(ordinal)),
+							Name:        ordinalPVCName(v1beta1.PDMemberType, controller.PDMemberName(cluster.GetName()), int32(ordinal)),
 							Namespace:   metav1.NamespaceDefault,
 							Annotations: map[string]string{},
 							Labels:      label.New().Instance(cluster.GetInstanceName()).PD().Labels(),
@@ -1571,25 +1574,25 @@ func TestPDMemberManagerSyncPDStsWhenPdNotJoinCluster(t *testing.T) {
 				{Name: "test-pd-1", MemberID: uint64(2), ClientUrls: []string{"http://test-pd-1:2379"}, Health: false},
 			}},
 			err: false,
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.PD.UnjoinedMembers["test-pd-2"]).NotTo(BeNil())
 			},
 		},
 		{
 			name: "clear unjoin cluster member info when the member join the cluster ",
-			tcStatusChange: func(cluster *v1alpha1.TikvCluster) {
-				cluster.Status.PD.UnjoinedMembers = map[string]v1alpha1.UnjoinedMember{
+			tcStatusChange: func(cluster *v1beta1.TikvCluster) {
+				cluster.Status.PD.UnjoinedMembers = map[string]v1beta1.UnjoinedMember{
 					"test-pd-0": {
 						PodName:   "test-pd-0",
 						CreatedAt: metav1.Now(),
 					},
 				}
 			},
-			modify: func(cluster *v1alpha1.TikvCluster, podIndexer cache.Indexer, pvcIndexer cache.Indexer) {
+			modify: func(cluster *v1beta1.TikvCluster, podIndexer cache.Indexer, pvcIndexer cache.Indexer) {
 				for ordinal := 0; ordinal < 3; ordinal++ {
 					pod := &corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:        ordinalPodName(v1alpha1.PDMemberType, cluster.GetName(), int32(ordinal)),
+							Name:        ordinalPodName(v1beta1.PDMemberType, cluster.GetName(), int32(ordinal)),
 							Namespace:   metav1.NamespaceDefault,
 							Annotations: map[string]string{},
 							Labels:      label.New().Instance(cluster.GetInstanceName()).PD().Labels(),
@@ -1600,7 +1603,7 @@ func TestPDMemberManagerSyncPDStsWhenPdNotJoinCluster(t *testing.T) {
 				for ordinal := 0; ordinal < 3; ordinal++ {
 					pvc := &corev1.PersistentVolumeClaim{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:        ordinalPVCName(v1alpha1.PDMemberType, controller.PDMemberName(cluster.GetName()), int32(ordinal)),
+							Name:        ordinalPVCName(v1beta1.PDMemberType, controller.PDMemberName(cluster.GetName()), int32(ordinal)),
 							Namespace:   metav1.NamespaceDefault,
 							Annotations: map[string]string{},
 							Labels:      label.New().Instance(cluster.GetInstanceName()).PD().Labels(),
@@ -1616,7 +1619,7 @@ func TestPDMemberManagerSyncPDStsWhenPdNotJoinCluster(t *testing.T) {
 				{Name: "test-pd-2", MemberID: uint64(2), ClientUrls: []string{"http://test-pd-2:2379"}, Health: false},
 			}},
 			err: false,
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.PD.UnjoinedMembers).To(BeEmpty())
 			},
 		},
@@ -1675,37 +1678,37 @@ func TestPDShouldRecover(t *testing.T) {
 	})
 	tests := []struct {
 		name string
-		tc   *v1alpha1.TikvCluster
+		tc   *v1beta1.TikvCluster
 		pods []*v1.Pod
 		want bool
 	}{
 		{
 			name: "should not recover if no failure members",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "failover",
 					Namespace: v1.NamespaceDefault,
 				},
-				Status: v1alpha1.TikvClusterStatus{},
+				Status: v1beta1.TikvClusterStatus{},
 			},
 			pods: pods,
 			want: false,
 		},
 		{
 			name: "should not recover if a member is not healthy",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "failover",
 					Namespace: v1.NamespaceDefault,
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
 						Replicas: 2,
 					},
 				},
-				Status: v1alpha1.TikvClusterStatus{
-					PD: v1alpha1.PDStatus{
-						Members: map[string]v1alpha1.PDMember{
+				Status: v1beta1.TikvClusterStatus{
+					PD: v1beta1.PDStatus{
+						Members: map[string]v1beta1.PDMember{
 							"failover-pd-0": {
 								Name:   "failover-pd-0",
 								Health: false,
@@ -1715,7 +1718,7 @@ func TestPDShouldRecover(t *testing.T) {
 								Health: true,
 							},
 						},
-						FailureMembers: map[string]v1alpha1.PDFailureMember{
+						FailureMembers: map[string]v1beta1.PDFailureMember{
 							"failover-pd-0": {
 								PodName: "failover-pd-0",
 							},
@@ -1728,19 +1731,19 @@ func TestPDShouldRecover(t *testing.T) {
 		},
 		{
 			name: "should recover if all members are ready and healthy",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "failover",
 					Namespace: v1.NamespaceDefault,
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
 						Replicas: 2,
 					},
 				},
-				Status: v1alpha1.TikvClusterStatus{
-					PD: v1alpha1.PDStatus{
-						Members: map[string]v1alpha1.PDMember{
+				Status: v1beta1.TikvClusterStatus{
+					PD: v1beta1.PDStatus{
+						Members: map[string]v1beta1.PDMember{
 							"failover-pd-0": {
 								Name:   "failover-pd-0",
 								Health: true,
@@ -1750,7 +1753,7 @@ func TestPDShouldRecover(t *testing.T) {
 								Health: true,
 							},
 						},
-						FailureMembers: map[string]v1alpha1.PDFailureMember{
+						FailureMembers: map[string]v1beta1.PDFailureMember{
 							"failover-pd-0": {
 								PodName: "failover-pd-0",
 							},
@@ -1763,19 +1766,19 @@ func TestPDShouldRecover(t *testing.T) {
 		},
 		{
 			name: "should recover if all members are ready and healthy (ignore auto-created failover pods)",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "failover",
 					Namespace: v1.NamespaceDefault,
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
 						Replicas: 2,
 					},
 				},
-				Status: v1alpha1.TikvClusterStatus{
-					PD: v1alpha1.PDStatus{
-						Members: map[string]v1alpha1.PDMember{
+				Status: v1beta1.TikvClusterStatus{
+					PD: v1beta1.PDStatus{
+						Members: map[string]v1beta1.PDMember{
 							"failover-pd-0": {
 								Name:   "failover-pd-0",
 								Health: true,
@@ -1789,7 +1792,7 @@ func TestPDShouldRecover(t *testing.T) {
 								Health: false,
 							},
 						},
-						FailureMembers: map[string]v1alpha1.PDFailureMember{
+						FailureMembers: map[string]v1beta1.PDFailureMember{
 							"failover-pd-0": {
 								PodName: "failover-pd-0",
 							},
@@ -1834,7 +1837,7 @@ func hasTLSVol(sts *apps.StatefulSet) bool {
 
 func hasTLSVolMount(sts *apps.StatefulSet) bool {
 	for _, container := range sts.Spec.Template.Spec.Containers {
-		if container.Name == v1alpha1.PDMemberType.String() {
+		if container.Name == v1beta1.PDMemberType.String() {
 			for _, vm := range container.VolumeMounts {
 				if vm.Name == "tikv-client-tls" {
 					return true
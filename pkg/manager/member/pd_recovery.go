@@ -0,0 +1,367 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+)
+
+// PDRecovery recovers the PD cluster which lost the quorum like pd-recover does.
+type PDRecovery interface {
+	// Sync drives the recovery triggered by the annotation tikv.org/pd-recovery,
+	// it returns true if the PD statefulset is held by the recovery and must not
+	// be synced as usual.
+	Sync(tc *v1beta1.TikvCluster, set *apps.StatefulSet) (bool, error)
+}
+
+type pdRecovery struct {
+	pdControl       pdapi.PDControlInterface
+	setControl      controller.StatefulSetControlInterface
+	podLister       corelisters.PodLister
+	podControl      controller.PodControlInterface
+	pvcLister       corelisters.PersistentVolumeClaimLister
+	pvcControl      controller.PVCControlInterface
+	snapshotControl controller.VolumeSnapshotControlInterface
+}
+
+// NewPDRecovery returns a PDRecovery
+func NewPDRecovery(pdControl pdapi.PDControlInterface,
+	setControl controller.StatefulSetControlInterface,
+	podLister corelisters.PodLister,
+	podControl controller.PodControlInterface,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	pvcControl controller.PVCControlInterface,
+	snapshotControl controller.VolumeSnapshotControlInterface) PDRecovery {
+	return &pdRecovery{
+		pdControl,
+		setControl,
+		podLister,
+		podControl,
+		pvcLister,
+		pvcControl,
+		snapshotControl}
+}
+
+func (pr *pdRecovery) Sync(tc *v1beta1.TikvCluster, set *apps.StatefulSet) (bool, error) {
+	if !tc.PDRecovering() {
+		trigger := tc.GetAnnotations()[label.AnnPDRecovery]
+		// a cloned cluster recovers the pd cluster from the ids of the snapshots
+		// before the TiKV statefulset is created
+		if tc.IsTiKVCloned() && tc.Status.PD.Recovery == nil && tc.Status.TiKV.StatefulSet == nil {
+			trigger = pdRecoveryCloneTrigger(tc)
+		}
+		if trigger == "" {
+			return false, nil
+		}
+		if r := tc.Status.PD.Recovery; r != nil && r.Trigger == trigger {
+			return false, nil
+		}
+		tc.Status.PD.Recovery = &v1beta1.PDRecoveryStatus{
+			Trigger:   trigger,
+			StartTime: metav1.Now(),
+		}
+		setPDRecoveryPhase(tc, v1beta1.PDRecoveryPrepare, "recovery is triggered by %s=%s", label.AnnPDRecovery, trigger)
+	}
+
+	var err error
+	switch tc.Status.PD.Recovery.Phase {
+	case v1beta1.PDRecoveryPrepare:
+		err = pr.prepare(tc)
+	case v1beta1.PDRecoveryRecreateMember:
+		err = pr.recreateMember(tc, set)
+	case v1beta1.PDRecoveryRestart:
+		err = pr.restart(tc)
+	case v1beta1.PDRecoveryRejoin:
+		err = pr.rejoin(tc)
+	}
+	// the statefulset is scaled out as usual once the single member is restarted
+	held := tc.PDRecreatingMember() || tc.Status.PD.Recovery.Phase == v1beta1.PDRecoveryRestart
+	return held, err
+}
+
+func (pr *pdRecovery) prepare(tc *v1beta1.TikvCluster) error {
+	if tc.IsTiKVCloned() && tc.Status.PD.Recovery.Trigger == pdRecoveryCloneTrigger(tc) {
+		return pr.prepareClone(tc)
+	}
+	if tc.Status.PD.Synced {
+		setPDRecoveryPhase(tc, v1beta1.PDRecoveryFailed, "the pd cluster is healthy, no need to recover")
+		return nil
+	}
+
+	selector, err := label.New().Instance(tc.GetInstanceName()).TiKV().Selector()
+	if err != nil {
+		return err
+	}
+	tikvPods, err := pr.podLister.Pods(tc.GetNamespace()).List(selector)
+	if err != nil {
+		return err
+	}
+
+	clusterID, err := getPDRecoveryClusterID(tc, tikvPods)
+	if err != nil {
+		setPDRecoveryPhase(tc, v1beta1.PDRecoveryFailed, "failed to get the cluster id: %v", err)
+		return nil
+	}
+	allocID, err := getPDRecoveryAllocID(tc)
+	if err != nil {
+		setPDRecoveryPhase(tc, v1beta1.PDRecoveryFailed, "failed to get the alloc id: %v", err)
+		return nil
+	}
+
+	r := tc.Status.PD.Recovery
+	r.ClusterID = strconv.FormatUint(clusterID, 10)
+	r.AllocID = strconv.FormatUint(allocID, 10)
+	setPDRecoveryPhase(tc, v1beta1.PDRecoveryRecreateMember,
+		"recreating the pd cluster with a single member, cluster id: %d, alloc id: %d", clusterID, allocID)
+	return nil
+}
+
+// prepareClone reads the ids recorded on the snapshots the TiKV volumes are cloned from
+func (pr *pdRecovery) prepareClone(tc *v1beta1.TikvCluster) error {
+	snapshots, err := getTiKVDataSourceSnapshots(pr.snapshotControl, tc)
+	if err != nil {
+		setPDRecoveryPhase(tc, v1beta1.PDRecoveryFailed, "failed to get the snapshots to clone from: %v", err)
+		return nil
+	}
+	clusterID, allocID, err := getTiKVDataSourceIDs(snapshots)
+	if err != nil {
+		setPDRecoveryPhase(tc, v1beta1.PDRecoveryFailed, "failed to get the ids to clone from: %v", err)
+		return nil
+	}
+
+	r := tc.Status.PD.Recovery
+	r.ClusterID = strconv.FormatUint(clusterID, 10)
+	r.AllocID = strconv.FormatUint(allocID, 10)
+	setPDRecoveryPhase(tc, v1beta1.PDRecoveryRecreateMember,
+		"creating the pd cluster with a single member to clone cluster id: %d, alloc id: %d", clusterID, allocID)
+	return nil
+}
+
+func (pr *pdRecovery) recreateMember(tc *v1beta1.TikvCluster, set *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	r := tc.Status.PD.Recovery
+
+	// the statefulset of a cloned cluster is created with a single member
+	if set == nil {
+		return nil
+	}
+
+	if set.Spec.Replicas == nil || *set.Spec.Replicas != 1 {
+		newSet := set.DeepCopy()
+		replicas := int32(1)
+		newSet.Spec.Replicas = &replicas
+		if _, err := pr.setControl.UpdateStatefulSet(tc, newSet); err != nil {
+			return err
+		}
+	}
+
+	// all the PD members are recreated with new volumes, the data of the lost
+	// PD cluster must not be reused
+	selector, err := label.New().Instance(tc.GetInstanceName()).PD().Selector()
+	if err != nil {
+		return err
+	}
+	pvcs, err := pr.pvcLister.PersistentVolumeClaims(ns).List(selector)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		if !pvc.CreationTimestamp.Before(&r.StartTime) || pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if err := pr.pvcControl.DeletePVC(tc, pvc); err != nil {
+			return err
+		}
+		klog.Infof("pd recovery: delete pvc %s/%s of TikvCluster %s", ns, pvc.GetName(), tcName)
+	}
+
+	// The new pod may reuse the old PVC if it's created before the old PVC is deleted,
+	// so the first pod is deleted over and over until it uses a new PVC.
+	pvcName := ordinalPVCName(v1beta1.PDMemberType, controller.PDMemberName(tcName), 0)
+	pvc, err := pr.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if pvc == nil || pvc.CreationTimestamp.Before(&r.StartTime) {
+		if err := pr.deletePod(tc, PdPodName(tcName, 0)); err != nil {
+			return err
+		}
+		return controller.RequeueErrorf("pd recovery: TikvCluster: [%s/%s], waiting for pvc %s to be recreated", ns, tcName, pvcName)
+	}
+
+	pdClient := controller.GetPDClient(pr.pdControl, tc)
+	healthInfo, err := pdClient.GetHealth()
+	if err != nil {
+		return controller.RequeueErrorf("pd recovery: TikvCluster: [%s/%s], waiting for the single pd member running, %v", ns, tcName, err)
+	}
+	if len(healthInfo.Healths) != 1 || !healthInfo.Healths[0].Health {
+		return controller.RequeueErrorf("pd recovery: TikvCluster: [%s/%s], waiting for the single pd member healthy", ns, tcName)
+	}
+
+	clusterID, err := strconv.ParseUint(r.ClusterID, 10, 64)
+	if err != nil {
+		return err
+	}
+	allocID, err := strconv.ParseUint(r.AllocID, 10, 64)
+	if err != nil {
+		return err
+	}
+	etcdClient, err := pr.pdControl.GetPDEtcdClient(pdapi.Namespace(ns), tcName, tc.IsTLSClusterEnabled())
+	if err != nil {
+		return err
+	}
+	recovered, err := etcdClient.RecoverCluster(clusterID, allocID)
+	if err != nil {
+		return err
+	}
+	if !recovered {
+		setPDRecoveryPhase(tc, v1beta1.PDRecoveryFailed, "the recreated pd cluster has been bootstrapped, can't recover it")
+		return nil
+	}
+
+	// pd loads the recovered cluster id only when it starts
+	if err := pr.deletePod(tc, PdPodName(tcName, 0)); err != nil {
+		return err
+	}
+	setPDRecoveryPhase(tc, v1beta1.PDRecoveryRestart, "the cluster id and alloc id are written, restarting the pd member")
+	return nil
+}
+
+func (pr *pdRecovery) restart(tc *v1beta1.TikvCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	cluster, err := controller.GetPDClient(pr.pdControl, tc).GetCluster()
+	if err != nil {
+		return controller.RequeueErrorf("pd recovery: TikvCluster: [%s/%s], waiting for the pd member restarted, %v", ns, tcName, err)
+	}
+	if strconv.FormatUint(cluster.Id, 10) != tc.Status.PD.Recovery.ClusterID {
+		return controller.RequeueErrorf("pd recovery: TikvCluster: [%s/%s], waiting for the pd member restarted with cluster id %s",
+			ns, tcName, tc.Status.PD.Recovery.ClusterID)
+	}
+	setPDRecoveryPhase(tc, v1beta1.PDRecoveryRejoin, "the pd cluster %d is recovered, rejoining the remaining members", cluster.Id)
+	return nil
+}
+
+func (pr *pdRecovery) rejoin(tc *v1beta1.TikvCluster) error {
+	if !tc.Status.PD.Synced {
+		return nil
+	}
+	healthCount := 0
+	for _, member := range tc.Status.PD.Members {
+		if member.Health {
+			healthCount++
+		}
+	}
+	if healthCount < int(tc.Spec.PD.Replicas) {
+		return nil
+	}
+	setPDRecoveryPhase(tc, v1beta1.PDRecoveryComplete, "all %d pd members are healthy", healthCount)
+	return nil
+}
+
+func (pr *pdRecovery) deletePod(tc *v1beta1.TikvCluster, podName string) error {
+	pod, err := pr.podLister.Pods(tc.GetNamespace()).Get(podName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pod.DeletionTimestamp != nil {
+		return nil
+	}
+	return pr.podControl.DeletePod(tc, pod)
+}
+
+// getPDRecoveryClusterID returns the cluster id labeled on the surviving TiKV pods,
+// or the last known one in the status if there are no such pods.
+func getPDRecoveryClusterID(tc *v1beta1.TikvCluster, tikvPods []*corev1.Pod) (uint64, error) {
+	clusterID := tc.Status.ClusterID
+	fromPod := ""
+	for _, pod := range tikvPods {
+		id := pod.Labels[label.ClusterIDLabelKey]
+		if id == "" {
+			continue
+		}
+		if fromPod != "" && fromPod != id {
+			return 0, fmt.Errorf("the tikv pods belong to different clusters: %s, %s", fromPod, id)
+		}
+		fromPod = id
+	}
+	if fromPod != "" {
+		if clusterID != "" && clusterID != fromPod {
+			return 0, fmt.Errorf("the cluster id of the tikv pods %s is not equal to the one in status %s", fromPod, clusterID)
+		}
+		clusterID = fromPod
+	}
+	if clusterID == "" {
+		return 0, fmt.Errorf("the cluster id is unknown")
+	}
+	return strconv.ParseUint(clusterID, 10, 64)
+}
+
+// getPDRecoveryAllocID returns the alloc id specified by the annotation tikv.org/pd-recovery-alloc-id.
+// The region and peer ids are allocated by the same allocator as the store ids and can't be read
+// from the surviving stores, so the alloc id is never guessed.
+func getPDRecoveryAllocID(tc *v1beta1.TikvCluster) (uint64, error) {
+	v, ok := tc.GetAnnotations()[label.AnnPDRecoveryAllocID]
+	if !ok {
+		return 0, fmt.Errorf("the alloc id is unknown, specify one larger than all the ids allocated by the lost pd cluster by the annotation %s",
+			label.AnnPDRecoveryAllocID)
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
+
+// pdRecoveryCloneTrigger returns the trigger of the recovery of a cloned cluster
+func pdRecoveryCloneTrigger(tc *v1beta1.TikvCluster) string {
+	ds := tc.Spec.TiKV.DataSource
+	return fmt.Sprintf("clone/%s/%s", ds.ClusterName, ds.SnapshotSet)
+}
+
+func setPDRecoveryPhase(tc *v1beta1.TikvCluster, phase v1beta1.PDRecoveryPhase, format string, args ...interface{}) {
+	r := tc.Status.PD.Recovery
+	msg := fmt.Sprintf(format, args...)
+	r.Phase = phase
+	r.Steps = append(r.Steps, v1beta1.PDRecoveryStep{
+		Phase:   phase,
+		Message: msg,
+		Time:    metav1.Now(),
+	})
+	klog.Infof("pd recovery: TikvCluster: [%s/%s] enters phase %s: %s", tc.GetNamespace(), tc.GetName(), phase, msg)
+}
+
+type fakePDRecovery struct{}
+
+// NewFakePDRecovery returns a fake PDRecovery
+func NewFakePDRecovery() PDRecovery {
+	return &fakePDRecovery{}
+}
+
+func (fpr *fakePDRecovery) Sync(_ *v1beta1.TikvCluster, _ *apps.StatefulSet) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,512 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+)
+
+func TestPDRecoverySync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	startTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	recovering := func(phase v1beta1.PDRecoveryPhase) func(*v1beta1.TikvCluster) {
+		return func(tc *v1beta1.TikvCluster) {
+			tc.Annotations = map[string]string{label.AnnPDRecovery: "1"}
+			tc.Status.PD.Recovery = &v1beta1.PDRecoveryStatus{
+				Trigger:   "1",
+				Phase:     phase,
+				ClusterID: "6800000000000000000",
+				AllocID:   "100000010",
+				StartTime: startTime,
+			}
+		}
+	}
+
+	cloning := func(tc *v1beta1.TikvCluster) {
+		tc.Spec.TiKV.DataSource = &v1beta1.TiKVDataSource{ClusterName: "src", SnapshotSet: "s1"}
+	}
+
+	type testcase struct {
+		name            string
+		update          func(*v1beta1.TikvCluster)
+		tikvPods        []*corev1.Pod
+		snapshots       []*unstructured.Unstructured
+		noSet           bool
+		pdPVCCreated    *metav1.Time
+		hasPDPod        bool
+		health          *pdapi.HealthInfo
+		clusterID       uint64
+		bootstrapped    bool
+		expectHeld      bool
+		errExpectFn     func(*GomegaWithT, error)
+		expectFn        func(*GomegaWithT, *v1beta1.TikvCluster)
+		expectResources func(*GomegaWithT, *pdRecovery, *pdapi.FakePDEtcdClient)
+	}
+
+	tests := []testcase{
+		{
+			name:        "not triggered",
+			update:      func(tc *v1beta1.TikvCluster) {},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery).To(BeNil())
+			},
+		},
+		{
+			name: "trigger has been handled",
+			update: func(tc *v1beta1.TikvCluster) {
+				recovering(v1beta1.PDRecoveryComplete)(tc)
+			},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryComplete))
+				g.Expect(tc.Status.PD.Recovery.Steps).To(BeEmpty())
+			},
+		},
+		{
+			name: "pd cluster is healthy",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Annotations = map[string]string{label.AnnPDRecovery: "1"}
+				tc.Status.PD.Synced = true
+			},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.PD.Recovery
+				g.Expect(r.Trigger).To(Equal("1"))
+				g.Expect(r.Phase).To(Equal(v1beta1.PDRecoveryFailed))
+				g.Expect(r.Steps).To(HaveLen(2))
+				g.Expect(r.Steps[0].Phase).To(Equal(v1beta1.PDRecoveryPrepare))
+			},
+		},
+		{
+			name: "cluster id is unknown",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Annotations = map[string]string{label.AnnPDRecovery: "1"}
+			},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.PD.Recovery
+				g.Expect(r.Phase).To(Equal(v1beta1.PDRecoveryFailed))
+				g.Expect(r.Steps[1].Message).To(ContainSubstring("cluster id is unknown"))
+			},
+		},
+		{
+			name: "tikv pods belong to another cluster",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Annotations = map[string]string{label.AnnPDRecovery: "1"}
+				tc.Status.ClusterID = "1"
+			},
+			tikvPods: []*corev1.Pod{
+				newTiKVPodForPDRecovery("test-tikv-0", "2", "1"),
+			},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryFailed))
+			},
+		},
+		{
+			name: "alloc id is not specified",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Annotations = map[string]string{label.AnnPDRecovery: "1"}
+				tc.Status.ClusterID = "1"
+				tc.Status.TiKV.TombstoneStores = map[string]v1beta1.TiKVStore{"12": {ID: "12"}}
+			},
+			tikvPods: []*corev1.Pod{
+				newTiKVPodForPDRecovery("test-tikv-0", "1", "4"),
+			},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.PD.Recovery
+				g.Expect(r.Phase).To(Equal(v1beta1.PDRecoveryFailed))
+				g.Expect(r.Steps[1].Message).To(ContainSubstring(label.AnnPDRecoveryAllocID))
+			},
+		},
+		{
+			name: "cluster id is read from the tikv stores",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Annotations = map[string]string{label.AnnPDRecovery: "1", label.AnnPDRecoveryAllocID: "5000"}
+			},
+			tikvPods: []*corev1.Pod{
+				newTiKVPodForPDRecovery("test-tikv-0", "6800000000000000000", "4"),
+				newTiKVPodForPDRecovery("test-tikv-1", "6800000000000000000", "5"),
+			},
+			pdPVCCreated: &startTime,
+			hasPDPod:     true,
+			expectHeld:   true,
+			errExpectFn:  errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.PD.Recovery
+				g.Expect(r.Phase).To(Equal(v1beta1.PDRecoveryRecreateMember))
+				g.Expect(r.ClusterID).To(Equal("6800000000000000000"))
+				g.Expect(r.AllocID).To(Equal("5000"))
+				g.Expect(tc.PDInitialMembers()).To(Equal(int32(1)))
+			},
+		},
+		{
+			name: "alloc id is specified by annotation",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Annotations = map[string]string{label.AnnPDRecovery: "1", label.AnnPDRecoveryAllocID: "3000"}
+				tc.Status.ClusterID = "6800000000000000000"
+			},
+			expectHeld:  true,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.AllocID).To(Equal("3000"))
+			},
+		},
+		{
+			name: "a new trigger restarts the recovery",
+			update: func(tc *v1beta1.TikvCluster) {
+				recovering(v1beta1.PDRecoveryFailed)(tc)
+				tc.Annotations[label.AnnPDRecovery] = "2"
+				tc.Annotations[label.AnnPDRecoveryAllocID] = "6000"
+				tc.Status.ClusterID = "6800000000000000000"
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{"1": {ID: "1"}}
+			},
+			expectHeld:  true,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.PD.Recovery
+				g.Expect(r.Trigger).To(Equal("2"))
+				g.Expect(r.Phase).To(Equal(v1beta1.PDRecoveryRecreateMember))
+				g.Expect(r.AllocID).To(Equal("6000"))
+			},
+		},
+		{
+			name:         "old pvc is deleted with the pod",
+			update:       recovering(v1beta1.PDRecoveryRecreateMember),
+			pdPVCCreated: &metav1.Time{Time: startTime.Add(-time.Minute)},
+			hasPDPod:     true,
+			expectHeld:   true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryRecreateMember))
+			},
+			expectResources: func(g *GomegaWithT, pr *pdRecovery, _ *pdapi.FakePDEtcdClient) {
+				set, err := pr.setControl.(*controller.FakeStatefulSetControl).SetLister.StatefulSets(corev1.NamespaceDefault).Get("test-pd")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(*set.Spec.Replicas).To(Equal(int32(1)))
+				_, err = pr.pvcLister.PersistentVolumeClaims(corev1.NamespaceDefault).Get("pd-test-pd-0")
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+				_, err = pr.podLister.Pods(corev1.NamespaceDefault).Get("test-pd-0")
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			},
+		},
+		{
+			name:         "waiting for the single member healthy",
+			update:       recovering(v1beta1.PDRecoveryRecreateMember),
+			pdPVCCreated: &metav1.Time{Time: startTime.Add(time.Minute)},
+			hasPDPod:     true,
+			health: &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
+				{Name: "test-pd-0", Health: false},
+			}},
+			expectHeld: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryRecreateMember))
+			},
+		},
+		{
+			name:         "cluster id and alloc id are written",
+			update:       recovering(v1beta1.PDRecoveryRecreateMember),
+			pdPVCCreated: &metav1.Time{Time: startTime.Add(time.Minute)},
+			hasPDPod:     true,
+			health: &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
+				{Name: "test-pd-0", Health: true},
+			}},
+			expectHeld:  true,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryRestart))
+				g.Expect(tc.PDInitialMembers()).To(Equal(int32(3)))
+			},
+			expectResources: func(g *GomegaWithT, pr *pdRecovery, etcdClient *pdapi.FakePDEtcdClient) {
+				g.Expect(etcdClient.Keys).To(HaveKeyWithValue("/pd/cluster_id", "6800000000000000000"))
+				g.Expect(etcdClient.Keys).To(HaveKeyWithValue("/pd/6800000000000000000/alloc_id", "100000010"))
+				_, err := pr.podLister.Pods(corev1.NamespaceDefault).Get("test-pd-0")
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			},
+		},
+		{
+			name:         "recreated pd cluster has been bootstrapped",
+			update:       recovering(v1beta1.PDRecoveryRecreateMember),
+			pdPVCCreated: &metav1.Time{Time: startTime.Add(time.Minute)},
+			hasPDPod:     true,
+			health: &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
+				{Name: "test-pd-0", Health: true},
+			}},
+			bootstrapped: true,
+			expectHeld:   false,
+			errExpectFn:  errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryFailed))
+			},
+		},
+		{
+			name:       "waiting for the pd member restarted",
+			update:     recovering(v1beta1.PDRecoveryRestart),
+			clusterID:  1,
+			expectHeld: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryRestart))
+			},
+		},
+		{
+			name:        "pd member is restarted with the recovered cluster id",
+			update:      recovering(v1beta1.PDRecoveryRestart),
+			clusterID:   6800000000000000000,
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryRejoin))
+				g.Expect(tc.PDRecovering()).To(BeTrue())
+			},
+		},
+		{
+			name: "waiting for the remaining members",
+			update: func(tc *v1beta1.TikvCluster) {
+				recovering(v1beta1.PDRecoveryRejoin)(tc)
+				tc.Status.PD.Synced = true
+				tc.Status.PD.Members = map[string]v1beta1.PDMember{
+					"test-pd-0": {Name: "test-pd-0", Health: true},
+					"test-pd-1": {Name: "test-pd-1", Health: true},
+				}
+			},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery.Phase).To(Equal(v1beta1.PDRecoveryRejoin))
+			},
+		},
+		{
+			name: "all members rejoined",
+			update: func(tc *v1beta1.TikvCluster) {
+				recovering(v1beta1.PDRecoveryRejoin)(tc)
+				tc.Status.PD.Synced = true
+				tc.Status.PD.Members = map[string]v1beta1.PDMember{
+					"test-pd-0": {Name: "test-pd-0", Health: true},
+					"test-pd-1": {Name: "test-pd-1", Health: true},
+					"test-pd-2": {Name: "test-pd-2", Health: true},
+				}
+			},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.PD.Recovery
+				g.Expect(r.Phase).To(Equal(v1beta1.PDRecoveryComplete))
+				g.Expect(r.Steps).To(HaveLen(1))
+				g.Expect(tc.PDRecovering()).To(BeFalse())
+			},
+		},
+		{
+			name:   "clone is started",
+			update: cloning,
+			snapshots: []*unstructured.Unstructured{
+				newTiKVSnapshotForPDRecovery("tikv-src-tikv-0-s1", "6", "1", "5000", true),
+				newTiKVSnapshotForPDRecovery("tikv-src-tikv-1-s1", "6", "4", "6000", true),
+			},
+			noSet:       true,
+			expectHeld:  true,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.PD.Recovery
+				g.Expect(r.Trigger).To(Equal("clone/src/s1"))
+				g.Expect(r.Phase).To(Equal(v1beta1.PDRecoveryRecreateMember))
+				g.Expect(r.ClusterID).To(Equal("6"))
+				g.Expect(r.AllocID).To(Equal("6000"))
+				g.Expect(tc.PDInitialMembers()).To(Equal(int32(1)))
+			},
+		},
+		{
+			name:   "snapshot to clone from is not ready",
+			update: cloning,
+			snapshots: []*unstructured.Unstructured{
+				newTiKVSnapshotForPDRecovery("tikv-src-tikv-0-s1", "6", "1", "100000001", false),
+			},
+			noSet:       true,
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.PD.Recovery
+				g.Expect(r.Phase).To(Equal(v1beta1.PDRecoveryFailed))
+				g.Expect(r.Steps[1].Message).To(ContainSubstring("is not ready to use"))
+			},
+		},
+		{
+			name: "cloned cluster has been created",
+			update: func(tc *v1beta1.TikvCluster) {
+				cloning(tc)
+				tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{}
+			},
+			expectHeld:  false,
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.PD.Recovery).To(BeNil())
+			},
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTikvClusterForPD()
+			tc.Spec.PD.Replicas = 3
+			tt.update(tc)
+
+			pr, pdControl, podIndexer, pvcIndexer, setIndexer := newFakePDRecovery()
+			pdClient := controller.NewFakePDClient(pdControl, tc)
+			pdClient.AddReaction(pdapi.GetHealthActionType, func(action *pdapi.Action) (interface{}, error) {
+				return tt.health, nil
+			})
+			pdClient.AddReaction(pdapi.GetClusterActionType, func(action *pdapi.Action) (interface{}, error) {
+				return &metapb.Cluster{Id: tt.clusterID}, nil
+			})
+			etcdClient := pdapi.NewFakePDEtcdClient()
+			etcdClient.Bootstrapped = tt.bootstrapped
+			pdControl.SetPDEtcdClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), etcdClient)
+
+			set := &apps.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pd", Namespace: corev1.NamespaceDefault},
+				Spec:       apps.StatefulSetSpec{Replicas: pointer.Int32Ptr(3)},
+			}
+			g.Expect(setIndexer.Add(set)).To(Succeed())
+			for _, pod := range tt.tikvPods {
+				g.Expect(podIndexer.Add(pod)).To(Succeed())
+			}
+			if tt.pdPVCCreated != nil {
+				pvc := &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "pd-test-pd-0",
+						Namespace:         corev1.NamespaceDefault,
+						Labels:            label.New().Instance(tc.GetInstanceName()).PD().Labels(),
+						CreationTimestamp: *tt.pdPVCCreated,
+					},
+				}
+				g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+			}
+			if tt.hasPDPod {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-pd-0",
+						Namespace: corev1.NamespaceDefault,
+						Labels:    label.New().Instance(tc.GetInstanceName()).PD().Labels(),
+					},
+				}
+				g.Expect(podIndexer.Add(pod)).To(Succeed())
+			}
+
+			snapshotControl := pr.snapshotControl.(*controller.FakeVolumeSnapshotControl)
+			for _, snapshot := range tt.snapshots {
+				g.Expect(snapshotControl.CreateVolumeSnapshot(tc, snapshot)).To(Succeed())
+			}
+			if tt.noSet {
+				set = nil
+			}
+
+			held, err := pr.Sync(tc, set)
+			tt.errExpectFn(g, err)
+			g.Expect(held).To(Equal(tt.expectHeld))
+			tt.expectFn(g, tc)
+			if tt.expectResources != nil {
+				tt.expectResources(g, pr, etcdClient)
+			}
+		})
+	}
+}
+
+func newTiKVPodForPDRecovery(name, clusterID, storeID string) *corev1.Pod {
+	l := label.New().Instance("test").TiKV().Labels()
+	l[label.ClusterIDLabelKey] = clusterID
+	l[label.StoreIDLabelKey] = storeID
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: corev1.NamespaceDefault,
+			Labels:    l,
+		},
+	}
+}
+
+func newFakePDRecovery() (*pdRecovery, *pdapi.FakePDControl, cache.Indexer, cache.Indexer, cache.Indexer) {
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	setInformer := kubeInformerFactory.Apps().V1().StatefulSets()
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	tcInformer := informers.NewSharedInformerFactory(cli, 0).Tikv().V1beta1().TikvClusters()
+	setControl := controller.NewFakeStatefulSetControl(setInformer, tcInformer)
+	podControl := controller.NewFakePodControl(podInformer)
+	pvcControl := controller.NewFakePVCControl(pvcInformer)
+
+	return &pdRecovery{
+			pdControl,
+			setControl,
+			podInformer.Lister(),
+			podControl,
+			pvcInformer.Lister(),
+			pvcControl,
+			controller.NewFakeVolumeSnapshotControl()},
+		pdControl,
+		podInformer.Informer().GetIndexer(),
+		pvcInformer.Informer().GetIndexer(),
+		setInformer.Informer().GetIndexer()
+}
+
+func newTiKVSnapshotForPDRecovery(name, clusterID, storeID, allocID string, ready bool) *unstructured.Unstructured {
+	l := label.New().Instance("src").TiKV().Labels()
+	l[label.ClusterIDLabelKey] = clusterID
+	l[label.StoreIDLabelKey] = storeID
+	l[label.SnapshotSetLabelKey] = "s1"
+	snapshot := controller.NewVolumeSnapshot(corev1.NamespaceDefault, name, "pvc", nil, l,
+		map[string]string{label.AnnPDRecoveryAllocID: allocID})
+	if ready {
+		_ = unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse")
+	}
+	return snapshot
+}
@@ -18,7 +18,7 @@ import (
 	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
@@ -40,7 +40,7 @@ func NewPDScaler(pdControl pdapi.PDControlInterface,
 	return &pdScaler{generalScaler{pdControl, pvcLister, pvcControl}}
 }
 
-func (psd *pdScaler) Scale(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (psd *pdScaler) Scale(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	scaling, _, _, _ := scaleOne(oldSet, newSet)
 	if scaling > 0 {
 		return psd.ScaleOut(tc, oldSet, newSet)
@@ -50,7 +50,7 @@ func (psd *pdScaler) Scale(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, n
 	return psd.SyncAutoScalerAnn(tc, oldSet)
 }
 
-func (psd *pdScaler) ScaleOut(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (psd *pdScaler) ScaleOut(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
 	resetReplicas(newSet, oldSet)
 	ns := tc.GetNamespace()
@@ -60,7 +60,7 @@ func (psd *pdScaler) ScaleOut(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet
 	}
 
 	klog.Infof("scaling out pd statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
-	_, err := psd.deleteDeferDeletingPVC(tc, oldSet.GetName(), v1alpha1.PDMemberType, ordinal)
+	_, err := psd.deleteDeferDeletingPVC(tc, oldSet.GetName(), v1beta1.PDMemberType, ordinal)
 	if err != nil {
 		return err
 	}
@@ -78,7 +78,7 @@ func (psd *pdScaler) ScaleOut(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet
 	totalCount := *oldSet.Spec.Replicas
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
 	for _, i := range podOrdinals {
-		podName := ordinalPodName(v1alpha1.PDMemberType, tcName, i)
+		podName := ordinalPodName(v1beta1.PDMemberType, tcName, i)
 		if member, ok := tc.Status.PD.Members[podName]; ok && member.Health {
 			healthCount++
 		}
@@ -94,7 +94,7 @@ func (psd *pdScaler) ScaleOut(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet
 
 // We need remove member from cluster before reducing statefulset replicas
 // only remove one member at a time when scale down
-func (psd *pdScaler) ScaleIn(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (psd *pdScaler) ScaleIn(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
@@ -137,7 +137,7 @@ func (psd *pdScaler) ScaleIn(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet,
 	}
 	klog.Infof("pd scale in: delete member %s successfully", memberName)
 
-	pvcName := ordinalPVCName(v1alpha1.PDMemberType, setName, ordinal)
+	pvcName := ordinalPVCName(v1beta1.PDMemberType, setName, ordinal)
 	pvc, err := psd.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
 	if err != nil {
 		return err
@@ -162,7 +162,7 @@ func (psd *pdScaler) ScaleIn(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet,
 	return nil
 }
 
-func (psd *pdScaler) SyncAutoScalerAnn(tc *v1alpha1.TikvCluster, actual *apps.StatefulSet) error {
+func (psd *pdScaler) SyncAutoScalerAnn(tc *v1beta1.TikvCluster, actual *apps.StatefulSet) error {
 	return nil
 }
 
@@ -173,7 +173,7 @@ func NewFakePDScaler() Scaler {
 	return &fakePDScaler{}
 }
 
-func (fsd *fakePDScaler) Scale(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (fsd *fakePDScaler) Scale(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	if *newSet.Spec.Replicas > *oldSet.Spec.Replicas {
 		return fsd.ScaleOut(tc, oldSet, newSet)
 	} else if *newSet.Spec.Replicas < *oldSet.Spec.Replicas {
@@ -182,16 +182,16 @@ func (fsd *fakePDScaler) Scale(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSe
 	return nil
 }
 
-func (fsd *fakePDScaler) ScaleOut(_ *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (fsd *fakePDScaler) ScaleOut(_ *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas+1, nil)
 	return nil
 }
 
-func (fsd *fakePDScaler) ScaleIn(_ *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (fsd *fakePDScaler) ScaleIn(_ *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas-1, nil)
 	return nil
 }
 
-func (fsd *fakePDScaler) SyncAutoScalerAnn(tc *v1alpha1.TikvCluster, actual *apps.StatefulSet) error {
+func (fsd *fakePDScaler) SyncAutoScalerAnn(tc *v1beta1.TikvCluster, actual *apps.StatefulSet) error {
 	return nil
 }
@@ -20,7 +20,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/pdpb"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
@@ -37,7 +37,7 @@ func TestPDScalerScaleOut(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name             string
-		update           func(cluster *v1alpha1.TikvCluster)
+		update           func(cluster *v1beta1.TikvCluster)
 		pdUpgrading      bool
 		hasPVC           bool
 		hasDeferAnn      bool
@@ -54,7 +54,7 @@ func TestPDScalerScaleOut(t *testing.T) {
 		test.update(tc)
 
 		if test.pdUpgrading {
-			tc.Status.PD.Phase = v1alpha1.UpgradePhase
+			tc.Status.PD.Phase = v1beta1.UpgradePhase
 		}
 
 		oldSet := newStatefulSetForPDScale()
@@ -63,8 +63,8 @@ func TestPDScalerScaleOut(t *testing.T) {
 
 		scaler, _, pvcIndexer, pvcControl := newFakePDScaler()
 
-		pvc := newPVCForStatefulSet(oldSet, v1alpha1.PDMemberType, tc.Name)
-		pvc.Name = ordinalPVCName(v1alpha1.PDMemberType, oldSet.GetName(), *oldSet.Spec.Replicas)
+		pvc := newPVCForStatefulSet(oldSet, v1beta1.PDMemberType, tc.Name)
+		pvc.Name = ordinalPVCName(v1beta1.PDMemberType, oldSet.GetName(), *oldSet.Spec.Replicas)
 		if !test.annoIsNil {
 			pvc.Annotations = map[string]string{}
 		}
@@ -159,10 +159,10 @@ func TestPDScalerScaleOut(t *testing.T) {
 		},
 		{
 			name: "failover now",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				normalPDMember(tc)
-				podName := ordinalPodName(v1alpha1.PDMemberType, tc.GetName(), 0)
-				tc.Status.PD.FailureMembers = map[string]v1alpha1.PDFailureMember{
+				podName := ordinalPodName(v1beta1.PDMemberType, tc.GetName(), 0)
+				tc.Status.PD.FailureMembers = map[string]v1beta1.PDFailureMember{
 					podName: {PodName: podName},
 				}
 				pd := tc.Status.PD.Members[podName]
@@ -180,7 +180,7 @@ func TestPDScalerScaleOut(t *testing.T) {
 		},
 		{
 			name: "don't have members",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Members = nil
 			},
 			pdUpgrading:      false,
@@ -194,10 +194,10 @@ func TestPDScalerScaleOut(t *testing.T) {
 		},
 		{
 			name: "pd member not health",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				normalPDMember(tc)
 				tcName := tc.GetName()
-				podName := ordinalPodName(v1alpha1.PDMemberType, tcName, 1)
+				podName := ordinalPodName(v1beta1.PDMemberType, tcName, 1)
 				member1 := tc.Status.PD.Members[podName]
 				member1.Health = false
 				tc.Status.PD.Members[podName] = member1
@@ -249,7 +249,7 @@ func TestPDScalerScaleIn(t *testing.T) {
 		tc := newTikvClusterForPD()
 
 		if test.pdUpgrading {
-			tc.Status.PD.Phase = v1alpha1.UpgradePhase
+			tc.Status.PD.Phase = v1beta1.UpgradePhase
 		}
 
 		oldSet := newStatefulSetForPDScale()
@@ -259,7 +259,7 @@ func TestPDScalerScaleIn(t *testing.T) {
 		scaler, pdControl, pvcIndexer, pvcControl := newFakePDScaler()
 
 		if test.hasPVC {
-			pvc := newScaleInPVCForStatefulSet(oldSet, v1alpha1.PDMemberType, tc.Name)
+			pvc := newScaleInPVCForStatefulSet(oldSet, v1beta1.PDMemberType, tc.Name)
 			pvcIndexer.Add(pvc)
 		}
 
@@ -407,7 +407,7 @@ func newStatefulSetForPDScale() *apps.StatefulSet {
 	return set
 }
 
-func newPVCForStatefulSet(set *apps.StatefulSet, memberType v1alpha1.MemberType, name string) *corev1.PersistentVolumeClaim {
+func newPVCForStatefulSet(set *apps.StatefulSet, memberType v1beta1.MemberType, name string) *corev1.PersistentVolumeClaim {
 	podName := ordinalPodName(memberType, name, *set.Spec.Replicas)
 	l := label.New().Instance(name)
 	l[label.AnnPodNameKey] = podName
@@ -420,7 +420,7 @@ func newPVCForStatefulSet(set *apps.StatefulSet, memberType v1alpha1.MemberType,
 	}
 }
 
-func newScaleInPVCForStatefulSet(set *apps.StatefulSet, memberType v1alpha1.MemberType, name string) *corev1.PersistentVolumeClaim {
+func newScaleInPVCForStatefulSet(set *apps.StatefulSet, memberType v1beta1.MemberType, name string) *corev1.PersistentVolumeClaim {
 	podName := ordinalPodName(memberType, name, *set.Spec.Replicas-1)
 	l := label.New().Instance(name)
 	l[label.AnnPodNameKey] = podName
@@ -433,13 +433,13 @@ func newScaleInPVCForStatefulSet(set *apps.StatefulSet, memberType v1alpha1.Memb
 	}
 }
 
-func normalPDMember(tc *v1alpha1.TikvCluster) {
+func normalPDMember(tc *v1beta1.TikvCluster) {
 	tcName := tc.GetName()
-	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
-		ordinalPodName(v1alpha1.PDMemberType, tcName, 0): {Health: true},
-		ordinalPodName(v1alpha1.PDMemberType, tcName, 1): {Health: true},
-		ordinalPodName(v1alpha1.PDMemberType, tcName, 2): {Health: true},
-		ordinalPodName(v1alpha1.PDMemberType, tcName, 3): {Health: true},
-		ordinalPodName(v1alpha1.PDMemberType, tcName, 4): {Health: true},
+	tc.Status.PD.Members = map[string]v1beta1.PDMember{
+		ordinalPodName(v1beta1.PDMemberType, tcName, 0): {Health: true},
+		ordinalPodName(v1beta1.PDMemberType, tcName, 1): {Health: true},
+		ordinalPodName(v1beta1.PDMemberType, tcName, 2): {Health: true},
+		ordinalPodName(v1beta1.PDMemberType, tcName, 3): {Health: true},
+		ordinalPodName(v1beta1.PDMemberType, tcName, 4): {Health: true},
 	}
 }
@@ -17,7 +17,7 @@ import (
 	"fmt"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
 	apps "k8s.io/api/apps/v1"
@@ -42,18 +42,18 @@ func NewPDUpgrader(pdControl pdapi.PDControlInterface,
 	}
 }
 
-func (pu *pdUpgrader) Upgrade(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (pu *pdUpgrader) Upgrade(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	return pu.gracefulUpgrade(tc, oldSet, newSet)
 }
 
-func (pu *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (pu *pdUpgrader) gracefulUpgrade(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	if !tc.Status.PD.Synced {
 		return fmt.Errorf("tidbcluster: [%s/%s]'s pd status sync failed,can not to be upgraded", ns, tcName)
 	}
 
-	tc.Status.PD.Phase = v1alpha1.UpgradePhase
+	tc.Status.PD.Phase = v1beta1.UpgradePhase
 	if !templateEqual(newSet, oldSet) {
 		return nil
 	}
@@ -100,7 +100,7 @@ func (pu *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TikvCluster, oldSet *apps.Sta
 	return nil
 }
 
-func (pu *pdUpgrader) upgradePDPod(tc *v1alpha1.TikvCluster, ordinal int32, newSet *apps.StatefulSet) error {
+func (pu *pdUpgrader) upgradePDPod(tc *v1beta1.TikvCluster, ordinal int32, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	upgradePodName := PdPodName(tcName, ordinal)
@@ -125,7 +125,7 @@ func (pu *pdUpgrader) upgradePDPod(tc *v1alpha1.TikvCluster, ordinal int32, newS
 	return nil
 }
 
-func (pu *pdUpgrader) transferPDLeaderTo(tc *v1alpha1.TikvCluster, targetName string) error {
+func (pu *pdUpgrader) transferPDLeaderTo(tc *v1beta1.TikvCluster, targetName string) error {
 	return controller.GetPDClient(pu.pdControl, tc).TransferPDLeader(targetName)
 }
 
@@ -136,10 +136,10 @@ func NewFakePDUpgrader() Upgrader {
 	return &fakePDUpgrader{}
 }
 
-func (fpu *fakePDUpgrader) Upgrade(tc *v1alpha1.TikvCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
+func (fpu *fakePDUpgrader) Upgrade(tc *v1beta1.TikvCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
 	if !tc.Status.PD.Synced {
 		return fmt.Errorf("tidbcluster: pd status sync failed,can not to be upgraded")
 	}
-	tc.Status.PD.Phase = v1alpha1.UpgradePhase
+	tc.Status.PD.Phase = v1beta1.UpgradePhase
 	return nil
 }
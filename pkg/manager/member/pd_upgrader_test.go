@@ -18,7 +18,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
@@ -37,12 +37,12 @@ func TestPDUpgraderUpgrade(t *testing.T) {
 
 	type testcase struct {
 		name              string
-		changeFn          func(*v1alpha1.TikvCluster)
+		changeFn          func(*v1beta1.TikvCluster)
 		changePods        func(pods []*corev1.Pod)
 		changeOldSet      func(set *apps.StatefulSet)
 		transferLeaderErr bool
 		errExpectFn       func(*GomegaWithT, error)
-		expectFn          func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet)
+		expectFn          func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet)
 	}
 
 	testFn := func(test *testcase) {
@@ -90,7 +90,7 @@ func TestPDUpgraderUpgrade(t *testing.T) {
 	tests := []testcase{
 		{
 			name: "normal upgrade",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = true
 			},
 			changePods:        nil,
@@ -99,14 +99,14 @@ func TestPDUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(controller.Int32Ptr(1)))
 			},
 		},
 		{
 			name: "modify oldSet update strategy to OnDelete",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = true
 			},
 			changePods: nil,
@@ -119,14 +119,14 @@ func TestPDUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy).To(Equal(apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType}))
 			},
 		},
 		{
 			name: "set oldSet's RollingUpdate strategy to nil",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = true
 			},
 			changePods: nil,
@@ -139,14 +139,14 @@ func TestPDUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy).To(Equal(apps.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType}))
 			},
 		},
 		{
 			name: "newSet template changed",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = true
 			},
 			changePods: nil,
@@ -157,14 +157,14 @@ func TestPDUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(controller.Int32Ptr(3)))
 			},
 		},
 		{
 			name: "update revision equals current revision",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = true
 				tc.Status.PD.StatefulSet.UpdateRevision = tc.Status.PD.StatefulSet.CurrentRevision
 			},
@@ -173,46 +173,46 @@ func TestPDUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(controller.Int32Ptr(3)))
 			},
 		},
 		{
 			name: "skip to wait all members health",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = true
-				tc.Status.PD.Members[PdPodName(upgradeTcName, 2)] = v1alpha1.PDMember{Name: PdPodName(upgradeTcName, 2), Health: false}
+				tc.Status.PD.Members[PdPodName(upgradeTcName, 2)] = v1beta1.PDMember{Name: PdPodName(upgradeTcName, 2), Health: false}
 			},
 			changePods:        nil,
 			transferLeaderErr: false,
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err.Error()).To(Equal(fmt.Sprintf("tidbcluster: [default/upgrader]'s pd upgraded pod: [%s] is not ready", PdPodName(upgradeTcName, 2))))
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(controller.Int32Ptr(2)))
 			},
 		},
 		{
 			name: "transfer leader",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = true
-				tc.Status.PD.Leader = v1alpha1.PDMember{Name: PdPodName(upgradeTcName, 1), Health: true}
+				tc.Status.PD.Leader = v1beta1.PDMember{Name: PdPodName(upgradeTcName, 1), Health: true}
 			},
 			changePods:        nil,
 			transferLeaderErr: false,
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).To(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(controller.Int32Ptr(2)))
 			},
 		},
 		{
 			name: "pd sync failed",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = false
 			},
 			changePods:        nil,
@@ -220,24 +220,24 @@ func TestPDUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).To(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.NormalPhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.NormalPhase))
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(controller.Int32Ptr(3)))
 			},
 		},
 		{
 			name: "error when transfer leader",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
+			changeFn: func(tc *v1beta1.TikvCluster) {
 				tc.Status.PD.Synced = true
-				tc.Status.PD.Leader = v1alpha1.PDMember{Name: PdPodName(upgradeTcName, 1), Health: true}
+				tc.Status.PD.Leader = v1beta1.PDMember{Name: PdPodName(upgradeTcName, 1), Health: true}
 			},
 			changePods:        nil,
 			transferLeaderErr: true,
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).To(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet) {
-				g.Expect(tc.Status.PD.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.PD.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(controller.Int32Ptr(2)))
 			},
 		},
@@ -295,14 +295,14 @@ func newStatefulSetForPDUpgrader() *apps.StatefulSet {
 	}
 }
 
-func newTikvClusterForPDUpgrader() *v1alpha1.TikvCluster {
+func newTikvClusterForPDUpgrader() *v1beta1.TikvCluster {
 	podName0 := PdPodName(upgradeTcName, 0)
 	podName1 := PdPodName(upgradeTcName, 1)
 	podName2 := PdPodName(upgradeTcName, 2)
-	return &v1alpha1.TikvCluster{
+	return &v1beta1.TikvCluster{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "TikvCluster",
-			APIVersion: "tikv.org/v1alpha1",
+			APIVersion: "tikv.org/v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      upgradeTcName,
@@ -310,18 +310,19 @@ func newTikvClusterForPDUpgrader() *v1alpha1.TikvCluster {
 			UID:       types.UID(upgradeTcName),
 			Labels:    label.New().Instance(upgradeInstanceName),
 		},
-		Spec: v1alpha1.TikvClusterSpec{
-			PD: v1alpha1.PDSpec{
-				ComponentSpec: v1alpha1.ComponentSpec{
-					Image: "pd-test-image",
+		Spec: v1beta1.TikvClusterSpec{
+			PD: v1beta1.PDSpec{
+				ComponentSpec: v1beta1.ComponentSpec{
+					Version: "v1",
 				},
+				BaseImage:        "pd-test-image",
 				Replicas:         3,
 				StorageClassName: pointer.StringPtr("my-storage-class"),
 			},
 		},
-		Status: v1alpha1.TikvClusterStatus{
-			PD: v1alpha1.PDStatus{
-				Phase: v1alpha1.NormalPhase,
+		Status: v1beta1.TikvClusterStatus{
+			PD: v1beta1.PDStatus{
+				Phase: v1beta1.NormalPhase,
 				StatefulSet: &apps.StatefulSetStatus{
 					CurrentRevision: "1",
 					UpdateRevision:  "2",
@@ -330,12 +331,12 @@ func newTikvClusterForPDUpgrader() *v1alpha1.TikvCluster {
 					CurrentReplicas: 2,
 					UpdatedReplicas: 1,
 				},
-				Members: map[string]v1alpha1.PDMember{
+				Members: map[string]v1beta1.PDMember{
 					podName0: {Name: podName0, Health: true},
 					podName1: {Name: podName1, Health: true},
 					podName2: {Name: podName2, Health: true},
 				},
-				Leader: v1alpha1.PDMember{Name: podName2, Health: true},
+				Leader: v1beta1.PDMember{Name: podName2, Health: true},
 			},
 		},
 	}
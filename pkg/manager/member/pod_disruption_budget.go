@@ -0,0 +1,127 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	utiltikvcluster "github.com/tikv/tikv-operator/pkg/util/tikvcluster"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultMaxReplicas is the default max-replicas of PD replication config
+	defaultMaxReplicas = 3
+)
+
+// syncPodDisruptionBudget creates or updates the desired PodDisruptionBudget, or deletes
+// the existing one if the PodDisruptionBudget is disabled. The condition of condType tells
+// whether the PodDisruptionBudget blocks all voluntary disruptions, e.g. node drains.
+func syncPodDisruptionBudget(typedControl controller.TypedControlInterface, tc *v1beta1.TikvCluster, pdb *policyv1beta1.PodDisruptionBudget, spec *v1beta1.PodDisruptionBudgetSpec, condType v1beta1.TikvClusterConditionType) error {
+	disabled := spec != nil && spec.Enabled != nil && !*spec.Enabled
+	setDisruptionBlockedCondition(tc, condType, pdb, disabled)
+	if disabled {
+		existing := &policyv1beta1.PodDisruptionBudget{}
+		exist, err := typedControl.Exist(client.ObjectKey{Namespace: pdb.Namespace, Name: pdb.Name}, existing)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return nil
+		}
+		klog.Infof("TikvCluster: [%s/%s], PodDisruptionBudget %s is disabled, deleting it", tc.GetNamespace(), tc.GetName(), pdb.Name)
+		return typedControl.Delete(tc, existing)
+	}
+	_, err := typedControl.CreateOrUpdatePodDisruptionBudget(tc, pdb)
+	return err
+}
+
+// getPDPodDisruptionBudget returns the PodDisruptionBudget which keeps the quorum of PD members
+func getPDPodDisruptionBudget(tc *v1beta1.TikvCluster) *policyv1beta1.PodDisruptionBudget {
+	// the number of failures PD members can tolerate while keeping the quorum
+	tolerable := int(tc.Spec.PD.Replicas-1) / 2
+	maxUnavailable := getMaxUnavailable(tc.Spec.PD.PodDisruptionBudget, tolerable, tc.PDUpgrading())
+	return newPodDisruptionBudget(tc, controller.PDMemberName(tc.GetName()), label.New().Instance(tc.GetInstanceName()).PD(), maxUnavailable)
+}
+
+// getTiKVPodDisruptionBudget returns the PodDisruptionBudget which keeps the majority of region replicas
+func getTiKVPodDisruptionBudget(tc *v1beta1.TikvCluster) *policyv1beta1.PodDisruptionBudget {
+	maxReplicas := defaultMaxReplicas
+	if config := tc.Spec.PD.Config; config != nil && config.Replication != nil && config.Replication.MaxReplicas != nil {
+		maxReplicas = int(*config.Replication.MaxReplicas)
+	}
+	// the number of failures the region replicas can tolerate while keeping the raft majority
+	tolerable := (maxReplicas - 1) / 2
+	maxUnavailable := getMaxUnavailable(tc.Spec.TiKV.PodDisruptionBudget, tolerable, tc.TiKVUpgrading())
+	return newPodDisruptionBudget(tc, controller.TiKVMemberName(tc.GetName()), label.New().Instance(tc.GetInstanceName()).TiKV(), maxUnavailable)
+}
+
+// getMaxUnavailable returns maxUnavailable in the spec or the tolerable failures of the component.
+// The pod restarted by a controlled upgrade is deleted without the budget but is counted as
+// unavailable by it, so the budget is raised by one during the upgrade, but never beyond the
+// tolerable failures since the upgraded pod and the evicted pods are unavailable together.
+func getMaxUnavailable(spec *v1beta1.PodDisruptionBudgetSpec, tolerable int, upgrading bool) intstr.IntOrString {
+	var maxUnavailable intstr.IntOrString
+	if spec != nil && spec.MaxUnavailable != nil {
+		maxUnavailable = *spec.MaxUnavailable
+	} else {
+		// a component which can not tolerate any failure blocks the node drains,
+		// see setDisruptionBlockedCondition
+		maxUnavailable = intstr.FromInt(tolerable)
+	}
+	if upgrading && maxUnavailable.Type == intstr.Int && maxUnavailable.IntValue() < tolerable {
+		maxUnavailable = intstr.FromInt(maxUnavailable.IntValue() + 1)
+	}
+	return maxUnavailable
+}
+
+// setDisruptionBlockedCondition explains why the node drains are blocked if the PodDisruptionBudget
+// allows no pod to be unavailable, the condition is only reset once it has been set
+func setDisruptionBlockedCondition(tc *v1beta1.TikvCluster, condType v1beta1.TikvClusterConditionType, pdb *policyv1beta1.PodDisruptionBudget, disabled bool) {
+	maxUnavailable := pdb.Spec.MaxUnavailable
+	if !disabled && maxUnavailable.Type == intstr.Int && maxUnavailable.IntValue() == 0 {
+		msg := fmt.Sprintf("PodDisruptionBudget %s allows no unavailable pod, the voluntary evictions, e.g. node drains, are blocked, "+
+			"scale out the component or set maxUnavailable of its podDisruptionBudget to allow them", pdb.Name)
+		cond := utiltikvcluster.NewTikvClusterCondition(condType, corev1.ConditionTrue, utiltikvcluster.DisruptionNotTolerable, msg)
+		utiltikvcluster.SetTikvClusterCondition(&tc.Status, *cond)
+		return
+	}
+	if cond := utiltikvcluster.GetTikvClusterCondition(tc.Status, condType); cond != nil && cond.Status == corev1.ConditionTrue {
+		cond := utiltikvcluster.NewTikvClusterCondition(condType, corev1.ConditionFalse, utiltikvcluster.DisruptionTolerable, "")
+		utiltikvcluster.SetTikvClusterCondition(&tc.Status, *cond)
+	}
+}
+
+func newPodDisruptionBudget(tc *v1beta1.TikvCluster, name string, l label.Label, maxUnavailable intstr.IntOrString) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       tc.GetNamespace(),
+			Labels:          l.Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       l.LabelSelector(),
+		},
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	utiltikvcluster "github.com/tikv/tikv-operator/pkg/util/tikvcluster"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGetPodDisruptionBudget(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name                   string
+		update                 func(*v1beta1.TikvCluster)
+		expectPDMaxUnavailable intstr.IntOrString
+		expectKVMaxUnavailable intstr.IntOrString
+	}
+
+	tests := []testcase{
+		{
+			name:                   "default",
+			expectPDMaxUnavailable: intstr.FromInt(1),
+			expectKVMaxUnavailable: intstr.FromInt(1),
+		},
+		{
+			name: "single PD member",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.PD.Replicas = 1
+			},
+			expectPDMaxUnavailable: intstr.FromInt(0),
+			expectKVMaxUnavailable: intstr.FromInt(1),
+		},
+		{
+			name: "two PD members and two region replicas",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.PD.Replicas = 2
+				maxReplicas := uint64(2)
+				tc.Spec.PD.Config = &v1beta1.PDConfig{
+					Replication: &v1beta1.PDReplicationConfig{MaxReplicas: &maxReplicas},
+				}
+			},
+			expectPDMaxUnavailable: intstr.FromInt(0),
+			expectKVMaxUnavailable: intstr.FromInt(0),
+		},
+		{
+			name: "single PD member upgrading",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.PD.Replicas = 1
+				tc.Status.PD.Phase = v1beta1.UpgradePhase
+			},
+			expectPDMaxUnavailable: intstr.FromInt(0),
+			expectKVMaxUnavailable: intstr.FromInt(1),
+		},
+		{
+			name: "five PD members and five region replicas",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.PD.Replicas = 5
+				maxReplicas := uint64(5)
+				tc.Spec.PD.Config = &v1beta1.PDConfig{
+					Replication: &v1beta1.PDReplicationConfig{MaxReplicas: &maxReplicas},
+				}
+			},
+			expectPDMaxUnavailable: intstr.FromInt(2),
+			expectKVMaxUnavailable: intstr.FromInt(2),
+		},
+		{
+			name: "maxUnavailable set in spec",
+			update: func(tc *v1beta1.TikvCluster) {
+				pdMaxUnavailable := intstr.FromInt(0)
+				kvMaxUnavailable := intstr.FromString("20%")
+				tc.Spec.PD.PodDisruptionBudget = &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: &pdMaxUnavailable}
+				tc.Spec.TiKV.PodDisruptionBudget = &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: &kvMaxUnavailable}
+			},
+			expectPDMaxUnavailable: intstr.FromInt(0),
+			expectKVMaxUnavailable: intstr.FromString("20%"),
+		},
+		{
+			name: "three PD members and three region replicas upgrading",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.UpgradePhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
+			},
+			// the upgraded pod and an evicted pod would lose the quorum
+			expectPDMaxUnavailable: intstr.FromInt(1),
+			expectKVMaxUnavailable: intstr.FromInt(1),
+		},
+		{
+			name: "maxUnavailable set in spec upgrading",
+			update: func(tc *v1beta1.TikvCluster) {
+				maxUnavailable := intstr.FromInt(0)
+				tc.Spec.PD.PodDisruptionBudget = &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable}
+				tc.Spec.TiKV.PodDisruptionBudget = &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable}
+				tc.Status.PD.Phase = v1beta1.UpgradePhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
+			},
+			expectPDMaxUnavailable: intstr.FromInt(1),
+			expectKVMaxUnavailable: intstr.FromInt(1),
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := newTikvClusterForPD()
+		if test.update != nil {
+			test.update(tc)
+		}
+		pdPDB := getPDPodDisruptionBudget(tc)
+		g.Expect(pdPDB.Name).To(Equal(controller.PDMemberName(tc.Name)))
+		g.Expect(*pdPDB.Spec.MaxUnavailable).To(Equal(test.expectPDMaxUnavailable))
+		kvPDB := getTiKVPodDisruptionBudget(tc)
+		g.Expect(kvPDB.Name).To(Equal(controller.TiKVMemberName(tc.Name)))
+		g.Expect(*kvPDB.Spec.MaxUnavailable).To(Equal(test.expectKVMaxUnavailable))
+	}
+}
+
+func TestSyncPodDisruptionBudget(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTikvClusterForPD()
+	tc.Spec.PD.Replicas = 5
+	genericControl := controller.NewFakeGenericControl()
+	typedControl := controller.NewTypedControl(genericControl)
+	key := client.ObjectKey{Namespace: tc.Namespace, Name: controller.PDMemberName(tc.Name)}
+
+	err := syncPodDisruptionBudget(typedControl, tc, getPDPodDisruptionBudget(tc), tc.Spec.PD.PodDisruptionBudget, v1beta1.PDDisruptionBlocked)
+	g.Expect(err).NotTo(HaveOccurred())
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	g.Expect(genericControl.FakeCli.Get(context.TODO(), key, pdb)).To(Succeed())
+	g.Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(2))
+
+	maxUnavailable := intstr.FromInt(1)
+	tc.Spec.PD.PodDisruptionBudget = &v1beta1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable}
+	tc.Status.PD.Phase = v1beta1.UpgradePhase
+	err = syncPodDisruptionBudget(typedControl, tc, getPDPodDisruptionBudget(tc), tc.Spec.PD.PodDisruptionBudget, v1beta1.PDDisruptionBlocked)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(genericControl.FakeCli.Get(context.TODO(), key, pdb)).To(Succeed())
+	g.Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(2))
+
+	tc.Spec.PD.PodDisruptionBudget = &v1beta1.PodDisruptionBudgetSpec{Enabled: pointer.BoolPtr(false)}
+	err = syncPodDisruptionBudget(typedControl, tc, getPDPodDisruptionBudget(tc), tc.Spec.PD.PodDisruptionBudget, v1beta1.PDDisruptionBlocked)
+	g.Expect(err).NotTo(HaveOccurred())
+	err = genericControl.FakeCli.Get(context.TODO(), key, pdb)
+	g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+	err = syncPodDisruptionBudget(typedControl, tc, getPDPodDisruptionBudget(tc), tc.Spec.PD.PodDisruptionBudget, v1beta1.PDDisruptionBlocked)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestSyncPodDisruptionBudgetBlocked(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTikvClusterForPD()
+	tc.Spec.PD.Replicas = 1
+	typedControl := controller.NewTypedControl(controller.NewFakeGenericControl())
+
+	err := syncPodDisruptionBudget(typedControl, tc, getPDPodDisruptionBudget(tc), tc.Spec.PD.PodDisruptionBudget, v1beta1.PDDisruptionBlocked)
+	g.Expect(err).NotTo(HaveOccurred())
+	cond := utiltikvcluster.GetTikvClusterCondition(tc.Status, v1beta1.PDDisruptionBlocked)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(utiltikvcluster.DisruptionNotTolerable))
+
+	tc.Spec.PD.Replicas = 3
+	err = syncPodDisruptionBudget(typedControl, tc, getPDPodDisruptionBudget(tc), tc.Spec.PD.PodDisruptionBudget, v1beta1.PDDisruptionBlocked)
+	g.Expect(err).NotTo(HaveOccurred())
+	cond = utiltikvcluster.GetTikvClusterCondition(tc.Status, v1beta1.PDDisruptionBlocked)
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal(utiltikvcluster.DisruptionTolerable))
+
+	g.Expect(utiltikvcluster.GetTikvClusterCondition(tc.Status, v1beta1.TiKVDisruptionBlocked)).To(BeNil())
+}
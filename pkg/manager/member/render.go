@@ -0,0 +1,150 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/util"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// RenderTikvCluster returns the ConfigMaps and StatefulSets the member managers create for
+// the TikvCluster. oldPDSet and oldTiKVSet are the StatefulSets in the cluster, they can be
+// nil, the ConfigMaps in use by them are kept if the config is updated in place.
+func RenderTikvCluster(tc *v1beta1.TikvCluster, oldPDSet, oldTiKVSet *apps.StatefulSet) ([]runtime.Object, error) {
+	objects := []runtime.Object{}
+	if !tc.IsPDExternal() {
+		var pdCm *corev1.ConfigMap
+		if tc.Spec.PD.Config != nil {
+			var err error
+			if pdCm, err = getPDConfigMap(tc); err != nil {
+				return nil, err
+			}
+			keepInUseConfigMapName(pdCm, oldPDSet, tc.BasePDSpec().ConfigUpdateStrategy(), controller.PDMemberName(tc.Name))
+			objects = append(objects, withConfigMapTypeMeta(pdCm))
+		}
+		pdSet, err := getNewPDSetForTikvCluster(tc, pdCm)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, withStatefulSetTypeMeta(pdSet))
+	}
+
+	tikvCm, err := getTikVConfigMap(tc)
+	if err != nil {
+		return nil, err
+	}
+	if tikvCm != nil {
+		keepInUseConfigMapName(tikvCm, oldTiKVSet, tc.BaseTiKVSpec().ConfigUpdateStrategy(), controller.TiKVMemberName(tc.Name))
+		objects = append(objects, withConfigMapTypeMeta(tikvCm))
+	}
+	tikvSet, err := getNewTiKVSetForTikvCluster(tc, tikvCm)
+	if err != nil {
+		return nil, err
+	}
+	objects = append(objects, withStatefulSetTypeMeta(tikvSet))
+	return objects, nil
+}
+
+// DiffStatefulSet returns the changes updateStatefulSet applies to oldSet, and whether
+// the pods are restarted by the changes
+func DiffStatefulSet(newSet, oldSet *apps.StatefulSet) ([]string, bool, error) {
+	oldSpec, _, err := GetLastAppliedConfig(oldSet)
+	if err != nil {
+		return []string{"the StatefulSet is not created by the operator, it is overwritten"}, true, nil
+	}
+
+	changes := []string{}
+	restart := false
+	if !apiequality.Semantic.DeepEqual(oldSpec.Replicas, newSet.Spec.Replicas) {
+		changes = append(changes, fmt.Sprintf("replicas: %s -> %s", formatReplicas(oldSpec.Replicas), formatReplicas(newSet.Spec.Replicas)))
+	}
+	oldTemplate := oldSpec.Template.DeepCopy()
+	delete(oldTemplate.Annotations, LastAppliedConfigAnnotation)
+	if !apiequality.Semantic.DeepEqual(*oldTemplate, newSet.Spec.Template) {
+		restart = true
+		d, err := yamlDiff(*oldTemplate, newSet.Spec.Template)
+		if err != nil {
+			return nil, false, err
+		}
+		changes = append(changes, "pod template, the pods are restarted one by one:\n"+d)
+	}
+	if !apiequality.Semantic.DeepEqual(oldSpec.UpdateStrategy, newSet.Spec.UpdateStrategy) {
+		d, err := yamlDiff(oldSpec.UpdateStrategy, newSet.Spec.UpdateStrategy)
+		if err != nil {
+			return nil, false, err
+		}
+		changes = append(changes, "update strategy:\n"+d)
+	}
+	if !apiequality.Semantic.DeepEqual(oldSpec.VolumeClaimTemplates, newSet.Spec.VolumeClaimTemplates) {
+		changes = append(changes, "volume claim templates, which are immutable and NOT applied")
+	}
+	return changes, restart, nil
+}
+
+// formatReplicas returns the replicas of a StatefulSet spec, which may be unset
+func formatReplicas(replicas *int32) string {
+	if replicas == nil {
+		return "<unset>"
+	}
+	return fmt.Sprintf("%d", *replicas)
+}
+
+// DiffConfigMap returns the changes of the data of the ConfigMap
+func DiffConfigMap(newCm, oldCm *corev1.ConfigMap) []string {
+	changes := []string{}
+	keys := []string{}
+	for key := range newCm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if d := util.LineDiff(oldCm.Data[key], newCm.Data[key]); d != "" {
+			changes = append(changes, fmt.Sprintf("%s:\n%s", key, d))
+		}
+	}
+	return changes
+}
+
+func yamlDiff(a, b interface{}) (string, error) {
+	ay, err := yaml.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+	by, err := yaml.Marshal(b)
+	if err != nil {
+		return "", err
+	}
+	return util.LineDiff(string(ay), string(by)), nil
+}
+
+func withConfigMapTypeMeta(cm *corev1.ConfigMap) *corev1.ConfigMap {
+	cm.APIVersion = corev1.SchemeGroupVersion.String()
+	cm.Kind = "ConfigMap"
+	return cm
+}
+
+func withStatefulSetTypeMeta(set *apps.StatefulSet) *apps.StatefulSet {
+	set.APIVersion = apps.SchemeGroupVersion.String()
+	set.Kind = "StatefulSet"
+	return set
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestRenderTikvCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	tc.Spec.PD.Config = &v1beta1.PDConfig{}
+	tc.Spec.TiKV.Config = &v1beta1.TiKVConfig{}
+	strategy := v1beta1.ConfigUpdateStrategyInPlace
+	tc.Spec.TiKV.ConfigUpdateStrategy = &strategy
+
+	objects, err := RenderTikvCluster(tc, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	kinds := []string{}
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	g.Expect(kinds).To(Equal([]string{"ConfigMap", "StatefulSet", "ConfigMap", "StatefulSet"}))
+	tikvSet := objects[3].(*apps.StatefulSet)
+	g.Expect(tikvSet.Name).To(Equal("test-tikv"))
+
+	// the configmap in use is kept if the config is updated in place
+	oldTiKVSet := tikvSet.DeepCopy()
+	for i := range oldTiKVSet.Spec.Template.Spec.Volumes {
+		if vol := &oldTiKVSet.Spec.Template.Spec.Volumes[i]; vol.ConfigMap != nil {
+			vol.ConfigMap.Name = "test-tikv-in-use"
+		}
+	}
+	objects, err = RenderTikvCluster(tc, nil, oldTiKVSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objects[2].(*corev1.ConfigMap).Name).To(Equal("test-tikv-in-use"))
+
+	tc.Spec.PD.ExternalEndpoints = []string{"http://10.0.1.1:2379"}
+	objects, err = RenderTikvCluster(tc, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+}
+
+func TestDiffStatefulSet(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	oldSet, err := getNewTiKVSetForTikvCluster(tc, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(SetStatefulSetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+
+	changes, restart, err := DiffStatefulSet(oldSet.DeepCopy(), oldSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(BeEmpty())
+	g.Expect(restart).To(BeFalse())
+
+	newSet := oldSet.DeepCopy()
+	newSet.Spec.Replicas = pointer.Int32Ptr(*oldSet.Spec.Replicas + 2)
+	changes, restart, err = DiffStatefulSet(newSet, oldSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(Equal([]string{"replicas: 3 -> 5"}))
+	g.Expect(restart).To(BeFalse())
+
+	unsetSet := oldSet.DeepCopy()
+	unsetSet.Spec.Replicas = nil
+	changes, _, err = DiffStatefulSet(unsetSet, oldSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(Equal([]string{"replicas: 3 -> <unset>"}))
+
+	newSet.Spec.Template.Spec.Containers[0].Image = "tikv/tikv:v4.0.1"
+	changes, restart, err = DiffStatefulSet(newSet, oldSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes).To(HaveLen(2))
+	g.Expect(changes[1]).To(ContainSubstring("+    image: tikv/tikv:v4.0.1"))
+	g.Expect(restart).To(BeTrue())
+
+	delete(oldSet.Annotations, LastAppliedConfigAnnotation)
+	_, restart, err = DiffStatefulSet(newSet, oldSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restart).To(BeTrue())
+}
+
+func TestDiffConfigMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+	oldCm := &corev1.ConfigMap{Data: map[string]string{"config-file": "a = 1\nb = 2\n", "startup-script": "start"}}
+	newCm := &corev1.ConfigMap{Data: map[string]string{"config-file": "a = 1\nb = 3\n", "startup-script": "start"}}
+
+	g.Expect(DiffConfigMap(oldCm, oldCm)).To(BeEmpty())
+	g.Expect(DiffConfigMap(newCm, oldCm)).To(Equal([]string{"config-file:\n a = 1\n-b = 2\n+b = 3\n"}))
+}
@@ -18,7 +18,7 @@ import (
 	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/features"
 	"github.com/tikv/tikv-operator/pkg/label"
@@ -38,11 +38,11 @@ const (
 // Scaler implements the logic for scaling out or scaling in the cluster.
 type Scaler interface {
 	// Scale scales the cluster. It does nothing if scaling is not needed.
-	Scale(tc *v1alpha1.TikvCluster, actual *apps.StatefulSet, desired *apps.StatefulSet) error
+	Scale(tc *v1beta1.TikvCluster, actual *apps.StatefulSet, desired *apps.StatefulSet) error
 	// ScaleOut scales out the cluster
-	ScaleOut(tc *v1alpha1.TikvCluster, actual *apps.StatefulSet, desired *apps.StatefulSet) error
+	ScaleOut(tc *v1beta1.TikvCluster, actual *apps.StatefulSet, desired *apps.StatefulSet) error
 	// ScaleIn scales in the cluster
-	ScaleIn(tc *v1alpha1.TikvCluster, actual *apps.StatefulSet, desired *apps.StatefulSet) error
+	ScaleIn(tc *v1beta1.TikvCluster, actual *apps.StatefulSet, desired *apps.StatefulSet) error
 }
 
 type generalScaler struct {
@@ -51,8 +51,8 @@ type generalScaler struct {
 	pvcControl controller.PVCControlInterface
 }
 
-func (gs *generalScaler) deleteDeferDeletingPVC(tc *v1alpha1.TikvCluster,
-	setName string, memberType v1alpha1.MemberType, ordinal int32) (map[string]string, error) {
+func (gs *generalScaler) deleteDeferDeletingPVC(tc *v1beta1.TikvCluster,
+	setName string, memberType v1beta1.MemberType, ordinal int32) (map[string]string, error) {
 	ns := tc.GetNamespace()
 	// for unit test
 	skipReason := map[string]string{}
@@ -99,8 +99,8 @@ func (gs *generalScaler) deleteDeferDeletingPVC(tc *v1alpha1.TikvCluster,
 	return skipReason, nil
 }
 
-func (gs *generalScaler) updateDeferDeletingPVC(tc *v1alpha1.TikvCluster,
-	memberType v1alpha1.MemberType, ordinal int32) error {
+func (gs *generalScaler) updateDeferDeletingPVC(tc *v1beta1.TikvCluster,
+	memberType v1beta1.MemberType, ordinal int32) error {
 	ns := tc.GetNamespace()
 	podName := ordinalPodName(memberType, tc.Name, ordinal)
 
@@ -162,11 +162,11 @@ func setReplicasAndDeleteSlots(newSet *apps.StatefulSet, replicas int32, deleteS
 		newSet.GetNamespace(), newSet.GetName(), oldReplicas, replicas)
 }
 
-func ordinalPVCName(memberType v1alpha1.MemberType, setName string, ordinal int32) string {
+func ordinalPVCName(memberType v1beta1.MemberType, setName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%s-%d", memberType, setName, ordinal)
 }
 
-func ordinalPodName(memberType v1alpha1.MemberType, tcName string, ordinal int32) string {
+func ordinalPodName(memberType v1beta1.MemberType, tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%s-%d", tcName, memberType, ordinal)
 }
 
@@ -220,6 +220,26 @@ func scaleOne(actual *apps.StatefulSet, desired *apps.StatefulSet) (scaling int,
 	return
 }
 
+// scaleMulti is like scaleOne, but it allows up to maxCount pods to be created or deleted
+// in the same direction, the ordinals are in the order scaleOne would pick them one by one
+func scaleMulti(actual *apps.StatefulSet, desired *apps.StatefulSet, maxCount int) (scaling int, ordinals []int32, replicas int32, deleteSlots sets.Int32) {
+	current := actual.DeepCopy()
+	replicas = *actual.Spec.Replicas
+	deleteSlots = helper.GetDeleteSlots(actual)
+	for len(ordinals) < maxCount {
+		s, ordinal, r, d := scaleOne(current, desired)
+		if s == 0 || (scaling != 0 && s != scaling) {
+			break
+		}
+		scaling = s
+		ordinals = append(ordinals, ordinal)
+		replicas, deleteSlots = r, d
+		*current.Spec.Replicas = r
+		helper.SetDeleteSlots(current, d)
+	}
+	return
+}
+
 // normalizeDeleteSlots
 // - add redundant data if in desired delete slots
 // - remove redundant data if not in desired delete slots
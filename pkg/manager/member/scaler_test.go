@@ -21,7 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/features"
 	"github.com/tikv/tikv-operator/pkg/label"
@@ -39,7 +39,7 @@ import (
 func TestGeneralScalerDeleteAllDeferDeletingPVC(t *testing.T) {
 	type testcase struct {
 		name         string
-		memberType   v1alpha1.MemberType
+		memberType   v1beta1.MemberType
 		ordinal      int32
 		pvc          *corev1.PersistentVolumeClaim
 		deleteFailed bool
@@ -73,11 +73,11 @@ func TestGeneralScalerDeleteAllDeferDeletingPVC(t *testing.T) {
 	tests := []testcase{
 		{
 			name:       "normal",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: &corev1.PersistentVolumeClaim{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      ordinalPVCName(v1alpha1.PDMemberType, setName, 3),
+					Name:      ordinalPVCName(v1beta1.PDMemberType, setName, 3),
 					Namespace: corev1.NamespaceDefault,
 					Annotations: map[string]string{
 						label.AnnPVCDeferDeleting: "deleting-3",
@@ -92,7 +92,7 @@ func TestGeneralScalerDeleteAllDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:         "pvc is not found",
-			memberType:   v1alpha1.PDMemberType,
+			memberType:   v1beta1.PDMemberType,
 			ordinal:      3,
 			pvc:          nil,
 			deleteFailed: false,
@@ -104,11 +104,11 @@ func TestGeneralScalerDeleteAllDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:       "pvc annotations is nil",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: &corev1.PersistentVolumeClaim{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      ordinalPVCName(v1alpha1.PDMemberType, setName, 3),
+					Name:      ordinalPVCName(v1beta1.PDMemberType, setName, 3),
 					Namespace: corev1.NamespaceDefault,
 				},
 			},
@@ -121,11 +121,11 @@ func TestGeneralScalerDeleteAllDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:       "pvc annotations defer deleting is empty",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: &corev1.PersistentVolumeClaim{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:        ordinalPVCName(v1alpha1.PDMemberType, setName, 3),
+					Name:        ordinalPVCName(v1beta1.PDMemberType, setName, 3),
 					Namespace:   corev1.NamespaceDefault,
 					Annotations: map[string]string{},
 				},
@@ -139,11 +139,11 @@ func TestGeneralScalerDeleteAllDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:       "pvc delete failed",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: &corev1.PersistentVolumeClaim{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      ordinalPVCName(v1alpha1.PDMemberType, setName, 3),
+					Name:      ordinalPVCName(v1beta1.PDMemberType, setName, 3),
 					Namespace: corev1.NamespaceDefault,
 					Annotations: map[string]string{
 						label.AnnPVCDeferDeleting: "deleting-3",
@@ -641,10 +641,109 @@ func TestScaleOne(t *testing.T) {
 	}
 }
 
+func TestScaleMulti(t *testing.T) {
+	newSet := func(replicas int32, deleteSlots string) *apps.StatefulSet {
+		set := &apps.StatefulSet{Spec: apps.StatefulSetSpec{Replicas: controller.Int32Ptr(replicas)}}
+		if deleteSlots != "" {
+			set.Annotations = map[string]string{helper.DeleteSlotsAnn: deleteSlots}
+		}
+		return set
+	}
+	tests := []struct {
+		name        string
+		actual      *apps.StatefulSet
+		desired     *apps.StatefulSet
+		maxCount    int
+		scaling     int
+		ordinals    []int32
+		replicas    int32
+		deleteSlots sets.Int32
+	}{
+		{
+			name:        "no scaling",
+			actual:      newSet(3, ""),
+			desired:     newSet(3, ""),
+			maxCount:    2,
+			scaling:     0,
+			ordinals:    nil,
+			replicas:    3,
+			deleteSlots: sets.NewInt32(),
+		},
+		{
+			name:        "scale out by max count",
+			actual:      newSet(3, ""),
+			desired:     newSet(7, ""),
+			maxCount:    2,
+			scaling:     1,
+			ordinals:    []int32{3, 4},
+			replicas:    5,
+			deleteSlots: sets.NewInt32(),
+		},
+		{
+			name:        "scale out to the desired replicas",
+			actual:      newSet(3, ""),
+			desired:     newSet(5, ""),
+			maxCount:    10,
+			scaling:     1,
+			ordinals:    []int32{3, 4},
+			replicas:    5,
+			deleteSlots: sets.NewInt32(),
+		},
+		{
+			name:        "scale in by max count from the last ordinal",
+			actual:      newSet(5, ""),
+			desired:     newSet(1, ""),
+			maxCount:    3,
+			scaling:     -1,
+			ordinals:    []int32{4, 3, 2},
+			replicas:    2,
+			deleteSlots: sets.NewInt32(),
+		},
+		{
+			name:        "scale in with delete slots",
+			actual:      newSet(5, ""),
+			desired:     newSet(3, "[1]"),
+			maxCount:    3,
+			scaling:     -1,
+			ordinals:    []int32{4, 1},
+			replicas:    3,
+			deleteSlots: sets.NewInt32(1),
+		},
+		{
+			name:        "scale out before scaling in",
+			actual:      newSet(4, "[1]"),
+			desired:     newSet(4, "[2]"),
+			maxCount:    3,
+			scaling:     1,
+			ordinals:    []int32{1},
+			replicas:    5,
+			deleteSlots: sets.NewInt32(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scaling, ordinals, replicas, deleteSlots := scaleMulti(tt.actual, tt.desired, tt.maxCount)
+			if diff := cmp.Diff(tt.scaling, scaling); diff != "" {
+				t.Errorf("unexpected scaling (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.ordinals, ordinals); diff != "" {
+				t.Errorf("unexpected ordinals (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.replicas, replicas); diff != "" {
+				t.Errorf("unexpected replicas (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.deleteSlots, deleteSlots); diff != "" {
+				t.Errorf("unexpected delete slots (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
 func TestGeneralScalerDeleteMultiDeferDeletingPVC(t *testing.T) {
 	type testcase struct {
 		name         string
-		memberType   v1alpha1.MemberType
+		memberType   v1beta1.MemberType
 		ordinal      int32
 		pvc          []*corev1.PersistentVolumeClaim
 		deleteFailed bool
@@ -676,7 +775,7 @@ func TestGeneralScalerDeleteMultiDeferDeletingPVC(t *testing.T) {
 	tests := []testcase{
 		{
 			name:       "normal",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: []*corev1.PersistentVolumeClaim{
 				newPVC(tc, "0", "normal"),
@@ -690,7 +789,7 @@ func TestGeneralScalerDeleteMultiDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:         "pvc is not found",
-			memberType:   v1alpha1.PDMemberType,
+			memberType:   v1beta1.PDMemberType,
 			ordinal:      3,
 			pvc:          nil,
 			deleteFailed: false,
@@ -702,7 +801,7 @@ func TestGeneralScalerDeleteMultiDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:       "pvc annotations is nil",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: []*corev1.PersistentVolumeClaim{
 				newPVC(tc, "0", "nil"),
@@ -718,7 +817,7 @@ func TestGeneralScalerDeleteMultiDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:       "pvc annotations defer deleting is empty",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: []*corev1.PersistentVolumeClaim{
 				newPVC(tc, "0", "empty"),
@@ -734,7 +833,7 @@ func TestGeneralScalerDeleteMultiDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:       "pvc delete failed",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: []*corev1.PersistentVolumeClaim{
 				newPVC(tc, "0", "normal"),
@@ -757,7 +856,7 @@ func TestGeneralScalerDeleteMultiDeferDeletingPVC(t *testing.T) {
 func TestGeneralScalerUpdateDeferDeletingPVC(t *testing.T) {
 	type testcase struct {
 		name         string
-		memberType   v1alpha1.MemberType
+		memberType   v1beta1.MemberType
 		ordinal      int32
 		pvc          []*corev1.PersistentVolumeClaim
 		updateFailed bool
@@ -788,7 +887,7 @@ func TestGeneralScalerUpdateDeferDeletingPVC(t *testing.T) {
 	tests := []testcase{
 		{
 			name:       "normal",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: []*corev1.PersistentVolumeClaim{
 				newPVC(tc, "0", "nil"),
@@ -809,7 +908,7 @@ func TestGeneralScalerUpdateDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:         "pvc is not found",
-			memberType:   v1alpha1.PDMemberType,
+			memberType:   v1beta1.PDMemberType,
 			ordinal:      3,
 			pvc:          nil,
 			updateFailed: false,
@@ -820,7 +919,7 @@ func TestGeneralScalerUpdateDeferDeletingPVC(t *testing.T) {
 		},
 		{
 			name:       "pvc update failed",
-			memberType: v1alpha1.PDMemberType,
+			memberType: v1beta1.PDMemberType,
 			ordinal:    3,
 			pvc: []*corev1.PersistentVolumeClaim{
 				newPVC(tc, "0", "nil"),
@@ -839,7 +938,7 @@ func TestGeneralScalerUpdateDeferDeletingPVC(t *testing.T) {
 	}
 }
 
-func newPVC(tc *v1alpha1.TikvCluster, index string, anno string) *corev1.PersistentVolumeClaim {
+func newPVC(tc *v1beta1.TikvCluster, index string, anno string) *corev1.PersistentVolumeClaim {
 	var podAnno map[string]string
 	if anno == "empty" {
 		podAnno = map[string]string{}
@@ -122,8 +122,8 @@ POD_NAME=${POD_NAME:-$HOSTNAME}
 # the general form of variable PEER_SERVICE_NAME is: "<clusterName>-pd-peer"
 cluster_name=` + "`" + `echo ${PEER_SERVICE_NAME} | sed 's/-pd-peer//'` + "`" +
 	`
-domain="${POD_NAME}.${PEER_SERVICE_NAME}.${NAMESPACE}.svc"
-discovery_url="${cluster_name}-discovery.${NAMESPACE}.svc:10261"
+domain="${POD_NAME}.${PEER_SERVICE_NAME}.${NAMESPACE}.svc{{ if .ClusterDomain }}.{{ .ClusterDomain }}{{ end }}"
+discovery_url="${cluster_name}-discovery.${NAMESPACE}.svc{{ if .ClusterDomain }}.{{ .ClusterDomain }}{{ end }}:10261"
 encoded_domain_url=` + "`" + `echo ${domain}:2380 | base64 | tr "\n" " " | sed "s/ //g"` + "`" +
 	`
 elapseTime=0
@@ -183,6 +183,8 @@ exec /pd-server ${ARGS}
 
 type PDStartScriptModel struct {
 	Scheme string
+	// ClusterDomain is appended to the advertised domain of PD if it is not empty
+	ClusterDomain string
 }
 
 func RenderPDStartScript(model *PDStartScriptModel) (string, error) {
@@ -219,7 +221,11 @@ fi
 
 # Use HOSTNAME if POD_NAME is unset for backward compatibility.
 POD_NAME=${POD_NAME:-$HOSTNAME}
+{{- if .PDAddresses }}
+ARGS="--pd={{ .PDAddresses }} \
+{{- else }}
 ARGS="--pd={{ .Scheme }}://${CLUSTER_NAME}-pd:2379 \
+{{- end }}
 --advertise-addr=${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc:20160 \
 --addr=0.0.0.0:20160 \
 --status-addr=0.0.0.0:20180 \
@@ -240,6 +246,9 @@ exec /tikv-server ${ARGS}
 
 type TiKVStartScriptModel struct {
 	Scheme string
+	// PDAddresses are the comma-separated client urls of the external PD cluster,
+	// the PD service of the cluster is used if it is empty
+	PDAddresses string
 }
 
 func RenderTiKVStartScript(model *TiKVStartScriptModel) (string, error) {
@@ -0,0 +1,164 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// tikvEncryptionKeyPath is where the master keys are mounted
+	tikvEncryptionKeyPath = "/var/lib/tikv-encryption"
+	// tikvMasterKeySecretKey is the key of the master key in the Secrets
+	tikvMasterKeySecretKey = "master-key"
+	// tikvPreviousMasterKeySecretKey is the key of the previous master key in the managed Secret
+	tikvPreviousMasterKeySecretKey = "previous-master-key"
+	// defaultTiKVDataEncryptionMethod is used if data-encryption-method is not configured
+	defaultTiKVDataEncryptionMethod = "aes256-ctr"
+)
+
+// syncTiKVEncryption copies the master key from the Secret referenced by spec.tikv.encryption
+// to the Secret mounted to TiKV. When the key is changed, the current key is kept as the
+// previous key and the generation is increased, which restarts the TiKV pods one by one.
+func (tkmm *tikvMemberManager) syncTiKVEncryption(tc *v1beta1.TikvCluster) error {
+	if tc.Spec.TiKV.Encryption == nil {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	secretName := tc.Spec.TiKV.Encryption.MasterKeySecretRef.Name
+	secret := &corev1.Secret{}
+	exist, err := tkmm.typedControl.Exist(client.ObjectKey{Namespace: ns, Name: secretName}, secret)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("tikvcluster: [%s/%s], master key secret %s does not exist", ns, tcName, secretName)
+	}
+	key := secret.Data[tikvMasterKeySecretKey]
+	if len(key) == 0 {
+		return fmt.Errorf("tikvcluster: [%s/%s], master key secret %s has no key %s", ns, tcName, secretName, tikvMasterKeySecretKey)
+	}
+	hash := tikvMasterKeyHash(key)
+
+	managed := &corev1.Secret{}
+	managedName := controller.TiKVEncryptionSecretName(tcName)
+	managedExist, err := tkmm.typedControl.Exist(client.ObjectKey{Namespace: ns, Name: managedName}, managed)
+	if err != nil {
+		return err
+	}
+
+	status := tc.Status.TiKV.Encryption
+	newStatus := status.DeepCopy()
+	data := map[string][]byte{}
+	switch {
+	case status == nil:
+		newStatus = &v1beta1.TiKVEncryptionStatus{KeyGeneration: 1, KeyHash: hash}
+		data[tikvMasterKeySecretKey] = key
+	case status.KeyHash == hash:
+		if !managedExist {
+			return fmt.Errorf("tikvcluster: [%s/%s], secret %s of the master keys is lost", ns, tcName, managedName)
+		}
+		data = managed.Data
+	default:
+		// the stores which haven't been restarted can only be decrypted by the previous key
+		if !tc.TiKVMasterKeyRotated() {
+			klog.Infof("tikvcluster: [%s/%s], waiting for all stores to run with master key generation %d before rotating the master key",
+				ns, tcName, status.KeyGeneration)
+			return nil
+		}
+		if !managedExist || len(managed.Data[tikvMasterKeySecretKey]) == 0 {
+			return fmt.Errorf("tikvcluster: [%s/%s], secret %s of the master keys is lost, can't rotate the master key", ns, tcName, managedName)
+		}
+		newStatus.PreviousKeyGeneration = status.KeyGeneration
+		newStatus.KeyGeneration = status.KeyGeneration + 1
+		newStatus.KeyHash = hash
+		newStatus.LastRotationTime = metav1.Now()
+		data[tikvMasterKeySecretKey] = key
+		data[tikvPreviousMasterKeySecretKey] = managed.Data[tikvMasterKeySecretKey]
+		klog.Infof("tikvcluster: [%s/%s], rotating the master key to generation %d", ns, tcName, newStatus.KeyGeneration)
+	}
+
+	desired := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      managedName,
+			Namespace: ns,
+			Labels:    labelTiKV(tc).Labels(),
+		},
+		Data: data,
+	}
+	if _, err := tkmm.typedControl.CreateOrUpdateSecret(tc, desired); err != nil {
+		return err
+	}
+	tc.Status.TiKV.Encryption = newStatus
+	return nil
+}
+
+// setTiKVEncryptionConfig points the master keys of the config to the mounted Secret
+func setTiKVEncryptionConfig(tc *v1beta1.TikvCluster, config *v1beta1.TiKVConfig) {
+	status := tc.Status.TiKV.Encryption
+	if tc.Spec.TiKV.Encryption == nil || status == nil {
+		return
+	}
+	if config.Security == nil {
+		config.Security = &v1beta1.TiKVSecurityConfig{}
+	}
+	if config.Security.Encryption == nil {
+		config.Security.Encryption = &v1beta1.TiKVSecurityConfigEncryption{}
+	}
+	encryption := config.Security.Encryption
+	if encryption.DataEncryptionMethod == nil {
+		encryption.DataEncryptionMethod = pointer.StringPtr(defaultTiKVDataEncryptionMethod)
+	}
+	encryption.MasterKey = &v1beta1.TiKVSecurityConfigEncryptionMasterKey{
+		Type: pointer.StringPtr("file"),
+		MasterKeyFileConfig: v1beta1.MasterKeyFileConfig{
+			Path: pointer.StringPtr(path.Join(tikvEncryptionKeyPath, tikvMasterKeySecretKey)),
+		},
+	}
+	if status.PreviousKeyGeneration > 0 {
+		encryption.PreviousMasterKey = &v1beta1.TiKVSecurityConfigEncryptionPreviousMasterKey{
+			Type: pointer.StringPtr("file"),
+			MasterKeyFileConfig: v1beta1.MasterKeyFileConfig{
+				Path: pointer.StringPtr(path.Join(tikvEncryptionKeyPath, tikvPreviousMasterKeySecretKey)),
+			},
+		}
+	}
+}
+
+// getTiKVMasterKeyGeneration returns the generation of the master key the pod is started with
+func getTiKVMasterKeyGeneration(pod *corev1.Pod, annKey string) int64 {
+	generation, err := strconv.ParseInt(pod.Annotations[annKey], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return generation
+}
+
+// tikvMasterKeyHash returns the hash to detect the changes of the master key
+func tikvMasterKeyHash(key []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(key))[:16]
+}
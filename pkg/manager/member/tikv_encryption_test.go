@@ -0,0 +1,212 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestTiKVMemberManagerSyncEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name               string
+		userKey            string
+		managedKeys        map[string]string
+		status             *v1beta1.TiKVEncryptionStatus
+		storeGenerations   []int64
+		errExpectFn        func(*GomegaWithT, error)
+		expectGeneration   int64
+		expectPrevious     int64
+		expectManagedKeys  map[string]string
+		expectRotationTime bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTikvClusterForPD()
+		tc.Spec.TiKV.Encryption = &v1beta1.TiKVEncryption{
+			MasterKeySecretRef: corev1.LocalObjectReference{Name: "master-key"},
+		}
+		tc.Status.TiKV.Encryption = test.status
+		tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+		for i, generation := range test.storeGenerations {
+			id := string(rune('1' + i))
+			tc.Status.TiKV.Stores[id] = v1beta1.TiKVStore{ID: id, MasterKeyGeneration: generation}
+		}
+
+		tkmm, _, _, _, _, _ := newFakeTiKVMemberManager(tc)
+		genericControl := controller.NewFakeGenericControl()
+		tkmm.typedControl = controller.NewTypedControl(genericControl)
+		if test.userKey != "" {
+			g.Expect(genericControl.FakeCli.Create(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "master-key", Namespace: tc.Namespace},
+				Data:       map[string][]byte{tikvMasterKeySecretKey: []byte(test.userKey)},
+			})).To(Succeed())
+		}
+		if test.managedKeys != nil {
+			data := map[string][]byte{}
+			for k, v := range test.managedKeys {
+				data[k] = []byte(v)
+			}
+			g.Expect(genericControl.FakeCli.Create(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: controller.TiKVEncryptionSecretName(tc.Name), Namespace: tc.Namespace},
+				Data:       data,
+			})).To(Succeed())
+		}
+
+		err := tkmm.syncTiKVEncryption(tc)
+		test.errExpectFn(g, err)
+		if err != nil {
+			return
+		}
+
+		status := tc.Status.TiKV.Encryption
+		g.Expect(status.KeyGeneration).To(Equal(test.expectGeneration))
+		g.Expect(status.PreviousKeyGeneration).To(Equal(test.expectPrevious))
+		g.Expect(status.LastRotationTime.IsZero()).To(Equal(!test.expectRotationTime))
+
+		managed := &corev1.Secret{}
+		g.Expect(genericControl.FakeCli.Get(context.TODO(), client.ObjectKey{Namespace: tc.Namespace, Name: controller.TiKVEncryptionSecretName(tc.Name)}, managed)).To(Succeed())
+		g.Expect(managed.Data).To(HaveLen(len(test.expectManagedKeys)))
+		for k, v := range test.expectManagedKeys {
+			g.Expect(string(managed.Data[k])).To(Equal(v))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:              "first sync",
+			userKey:           "key1",
+			errExpectFn:       errExpectNil,
+			expectGeneration:  1,
+			expectManagedKeys: map[string]string{tikvMasterKeySecretKey: "key1"},
+		},
+		{
+			name:              "key is not changed",
+			userKey:           "key1",
+			managedKeys:       map[string]string{tikvMasterKeySecretKey: "key1"},
+			status:            &v1beta1.TiKVEncryptionStatus{KeyGeneration: 1, KeyHash: tikvMasterKeyHash([]byte("key1"))},
+			storeGenerations:  []int64{1, 1},
+			errExpectFn:       errExpectNil,
+			expectGeneration:  1,
+			expectManagedKeys: map[string]string{tikvMasterKeySecretKey: "key1"},
+		},
+		{
+			name:               "key is rotated",
+			userKey:            "key2",
+			managedKeys:        map[string]string{tikvMasterKeySecretKey: "key1"},
+			status:             &v1beta1.TiKVEncryptionStatus{KeyGeneration: 1, KeyHash: tikvMasterKeyHash([]byte("key1"))},
+			storeGenerations:   []int64{1, 1},
+			errExpectFn:        errExpectNil,
+			expectGeneration:   2,
+			expectPrevious:     1,
+			expectRotationTime: true,
+			expectManagedKeys:  map[string]string{tikvMasterKeySecretKey: "key2", tikvPreviousMasterKeySecretKey: "key1"},
+		},
+		{
+			name:        "previous rotation is not finished",
+			userKey:     "key3",
+			managedKeys: map[string]string{tikvMasterKeySecretKey: "key2", tikvPreviousMasterKeySecretKey: "key1"},
+			status: &v1beta1.TiKVEncryptionStatus{
+				KeyGeneration:         2,
+				PreviousKeyGeneration: 1,
+				KeyHash:               tikvMasterKeyHash([]byte("key2")),
+			},
+			storeGenerations:  []int64{2, 1},
+			errExpectFn:       errExpectNil,
+			expectGeneration:  2,
+			expectPrevious:    1,
+			expectManagedKeys: map[string]string{tikvMasterKeySecretKey: "key2", tikvPreviousMasterKeySecretKey: "key1"},
+		},
+		{
+			name: "master key secret does not exist",
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "does not exist")).To(BeTrue())
+			},
+		},
+		{
+			name:    "managed secret is lost",
+			userKey: "key2",
+			status:  &v1beta1.TiKVEncryptionStatus{KeyGeneration: 1, KeyHash: tikvMasterKeyHash([]byte("key1"))},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "is lost")).To(BeTrue())
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestGetTiKVConfigMapWithEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	tc.Spec.TiKV.Encryption = &v1beta1.TiKVEncryption{
+		MasterKeySecretRef: corev1.LocalObjectReference{Name: "master-key"},
+	}
+	tc.Status.TiKV.Encryption = &v1beta1.TiKVEncryptionStatus{KeyGeneration: 1}
+
+	cm, err := getTikVConfigMap(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	config := cm.Data["config-file"]
+	g.Expect(config).To(ContainSubstring(`data-encryption-method = "aes256-ctr"`))
+	g.Expect(config).To(ContainSubstring(`path = "/var/lib/tikv-encryption/master-key"`))
+	g.Expect(config).NotTo(ContainSubstring("previous-master-key"))
+	g.Expect(tc.Spec.TiKV.Config).To(BeNil())
+
+	tc.Status.TiKV.Encryption = &v1beta1.TiKVEncryptionStatus{KeyGeneration: 2, PreviousKeyGeneration: 1}
+	cm, err = getTikVConfigMap(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data["config-file"]).To(ContainSubstring(`path = "/var/lib/tikv-encryption/previous-master-key"`))
+
+	set, err := getNewTiKVSetForTikvCluster(tc, cm)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Template.Annotations[label.AnnTiKVMasterKeyGeneration]).To(Equal("2"))
+	var secretName string
+	for _, vol := range set.Spec.Template.Spec.Volumes {
+		if vol.Name == "tikv-encryption" {
+			secretName = vol.Secret.SecretName
+		}
+	}
+	g.Expect(secretName).To(Equal(controller.TiKVEncryptionSecretName(tc.Name)))
+}
+
+func TestTiKVMemberManagerSyncConfigMapWithEncryptionOnly(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	tc.Spec.TiKV.Config = nil
+	tc.Spec.TiKV.Encryption = &v1beta1.TiKVEncryption{
+		MasterKeySecretRef: corev1.LocalObjectReference{Name: "master-key"},
+	}
+	tc.Status.TiKV.Encryption = &v1beta1.TiKVEncryptionStatus{KeyGeneration: 1}
+
+	tkmm, _, _, _, _, _ := newFakeTiKVMemberManager(tc)
+	cm, err := tkmm.syncTiKVConfigMap(tc, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm).NotTo(BeNil())
+	g.Expect(cm.Data["config-file"]).To(ContainSubstring(`path = "/var/lib/tikv-encryption/master-key"`))
+}
@@ -15,27 +15,51 @@ package member
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
 	"github.com/tikv/tikv-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 )
 
 type tikvFailover struct {
+	pdControl          pdapi.PDControlInterface
 	tikvFailoverPeriod time.Duration
+	podLister          corelisters.PodLister
+	podControl         controller.PodControlInterface
+	pvcLister          corelisters.PersistentVolumeClaimLister
+	pvcControl         controller.PVCControlInterface
 	recorder           record.EventRecorder
 }
 
-// NewTiKVFailover returns a tikv Failover
-func NewTiKVFailover(tikvFailoverPeriod time.Duration, recorder record.EventRecorder) Failover {
-	return &tikvFailover{tikvFailoverPeriod, recorder}
+// NewTiKVFailover returns a tikv Failover, tikvFailoverPeriod is used for the clusters
+// which don't set the failover period of TiKV
+func NewTiKVFailover(pdControl pdapi.PDControlInterface,
+	tikvFailoverPeriod time.Duration,
+	podLister corelisters.PodLister,
+	podControl controller.PodControlInterface,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	pvcControl controller.PVCControlInterface,
+	recorder record.EventRecorder) Failover {
+	return &tikvFailover{
+		pdControl,
+		tikvFailoverPeriod,
+		podLister,
+		podControl,
+		pvcLister,
+		pvcControl,
+		recorder}
 }
 
-func (tf *tikvFailover) isPodDesired(tc *v1alpha1.TikvCluster, podName string) bool {
+func (tf *tikvFailover) isPodDesired(tc *v1beta1.TikvCluster, podName string) bool {
 	ordinals := tc.TiKVStsDesiredOrdinals(true)
 	ordinal, err := util.GetOrdinalFromPodName(podName)
 	if err != nil {
@@ -45,7 +69,7 @@ func (tf *tikvFailover) isPodDesired(tc *v1alpha1.TikvCluster, podName string) b
 	return ordinals.Has(ordinal)
 }
 
-func (tf *tikvFailover) Failover(tc *v1alpha1.TikvCluster) error {
+func (tf *tikvFailover) Failover(tc *v1beta1.TikvCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
@@ -60,7 +84,7 @@ func (tf *tikvFailover) Failover(tc *v1alpha1.TikvCluster) error {
 			// (before it enters into Offline/Tombstone state)
 			continue
 		}
-		deadline := store.LastTransitionTime.Add(tf.tikvFailoverPeriod)
+		deadline := store.LastTransitionTime.Add(tc.TiKVFailoverPeriod(tf.tikvFailoverPeriod))
 		exist := false
 		for _, failureStore := range tc.Status.TiKV.FailureStores {
 			if failureStore.PodName == podName {
@@ -68,37 +92,149 @@ func (tf *tikvFailover) Failover(tc *v1alpha1.TikvCluster) error {
 				break
 			}
 		}
-		if store.State == v1alpha1.TiKVStateDown && time.Now().After(deadline) && !exist {
+		if store.State == v1beta1.TiKVStateDown && time.Now().After(deadline) && !exist {
 			if tc.Status.TiKV.FailureStores == nil {
-				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{}
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{}
 			}
-			if tc.Spec.TiKV.MaxFailoverCount != nil && *tc.Spec.TiKV.MaxFailoverCount > 0 {
-				maxFailoverCount := *tc.Spec.TiKV.MaxFailoverCount
+			if maxFailoverCount := tc.TiKVMaxFailoverCount(); maxFailoverCount > 0 {
 				if len(tc.Status.TiKV.FailureStores) >= int(maxFailoverCount) {
-					klog.Warningf("%s/%s failure stores count reached the limit: %d", ns, tcName, tc.Spec.TiKV.MaxFailoverCount)
-					return nil
+					klog.Warningf("%s/%s failure stores count reached the limit: %d", ns, tcName, maxFailoverCount)
+					break
 				}
-				tc.Status.TiKV.FailureStores[storeID] = v1alpha1.TiKVFailureStore{
+				failureStore := v1beta1.TiKVFailureStore{
 					PodName:   podName,
 					StoreID:   store.ID,
 					CreatedAt: metav1.Now(),
 				}
+				if tc.TiKVReplaceFailedStore() {
+					pvc, err := tf.getPVC(tc, podName)
+					if err != nil {
+						return err
+					}
+					failureStore.Replace = true
+					failureStore.PVCUID = pvc.UID
+				}
+				tc.Status.TiKV.FailureStores[storeID] = failureStore
 				msg := fmt.Sprintf("store[%s] is Down", store.ID)
 				tf.recorder.Event(tc, corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tikv", podName, msg))
 			}
 		}
 	}
 
+	return tf.replaceFailureStores(tc)
+}
+
+// replaceFailureStores replaces the failure stores by the new stores of the same pods. A failure
+// store is deleted from the PD cluster first, once it becomes Tombstone, its PVC and pod are
+// deleted, so that the statefulset recreates the pod with a new PVC and the pod joins the cluster
+// as a new store. If the new pod is created before the old PVC is gone, it is left pending
+// without PVC and deleted by the orphan pods cleaner. A store whose regions can't be replicated
+// to the remaining stores is not deleted, it falls back to the failover adding a new replica.
+func (tf *tikvFailover) replaceFailureStores(tc *v1beta1.TikvCluster) error {
+	ns := tc.GetNamespace()
+
+	for key, failureStore := range tc.Status.TiKV.FailureStores {
+		if !failureStore.Replace || failureStore.PVCDeleted {
+			continue
+		}
+		podName := failureStore.PodName
+		if _, ok := tc.Status.TiKV.TombstoneStores[failureStore.StoreID]; !ok {
+			if failureStore.StoreDeleted {
+				klog.Infof("tikv failover: waiting for store %s of %s/%s to become tombstone", failureStore.StoreID, ns, podName)
+				continue
+			}
+			id, err := strconv.ParseUint(failureStore.StoreID, 10, 64)
+			if err != nil {
+				return err
+			}
+			pdClient := controller.GetPDClient(tf.pdControl, tc)
+			storesInfo, err := pdClient.GetStores()
+			if err != nil {
+				return fmt.Errorf("tikv failover: failed to get the stores of TikvCluster %s/%s, %v", ns, tc.GetName(), err)
+			}
+			config, err := pdClient.GetConfig()
+			if err != nil {
+				return fmt.Errorf("tikv failover: failed to get the config of PD of TikvCluster %s/%s, %v", ns, tc.GetName(), err)
+			}
+			if err := checkScaleIn(storesInfo.Stores, config.Replication, map[uint64]bool{id: true}, tc.TiKVScaleInHighWaterMarkPercent()); err != nil {
+				msg := fmt.Sprintf("%s(%d) can not be deleted from cluster to be replaced, a new replica is added instead: %v", podName, id, err)
+				klog.Warningf("tikv failover: TikvCluster %s/%s, %s", ns, tc.GetName(), msg)
+				tf.recorder.Event(tc, corev1.EventTypeWarning, "TiKVStoreReplaceBlocked", msg)
+				failureStore.Replace = false
+				failureStore.PVCUID = ""
+				tc.Status.TiKV.FailureStores[key] = failureStore
+				continue
+			}
+			if err := pdClient.DeleteStore(id); err != nil {
+				klog.Errorf("tikv failover: failed to delete store %d, %v", id, err)
+				return err
+			}
+			klog.Infof("tikv failover: delete store %d of %s/%s successfully", id, ns, podName)
+			tf.recorder.Eventf(tc, corev1.EventTypeWarning, "TiKVStoreDeleted",
+				"%s(%d) deleted from cluster to be replaced", podName, id)
+			failureStore.StoreDeleted = true
+			tc.Status.TiKV.FailureStores[key] = failureStore
+			continue
+		}
+
+		pod, err := tf.podLister.Pods(ns).Get(podName)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		pvc, err := tf.getPVC(tc, podName)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if pod != nil && pod.DeletionTimestamp == nil {
+			if err := tf.podControl.DeletePod(tc, pod); err != nil {
+				return err
+			}
+		}
+		if pvc != nil && pvc.DeletionTimestamp == nil && pvc.GetUID() == failureStore.PVCUID {
+			if err := tf.pvcControl.DeletePVC(tc, pvc); err != nil {
+				klog.Errorf("tikv failover: failed to delete pvc: %s/%s, %v", ns, pvc.Name, err)
+				return err
+			}
+			klog.Infof("tikv failover: delete pvc: %s/%s successfully", ns, pvc.Name)
+		}
+		tf.recorder.Eventf(tc, corev1.EventTypeWarning, "TiKVStoreReplacing",
+			"%s(%s) is tombstone, its pvc and pod are deleted", podName, failureStore.StoreID)
+		failureStore.PVCDeleted = true
+		tc.Status.TiKV.FailureStores[key] = failureStore
+	}
 	return nil
 }
 
-func (tf *tikvFailover) Recover(tc *v1alpha1.TikvCluster) {
+func (tf *tikvFailover) getPVC(tc *v1beta1.TikvCluster, podName string) (*corev1.PersistentVolumeClaim, error) {
+	ordinal, err := util.GetOrdinalFromPodName(podName)
+	if err != nil {
+		return nil, err
+	}
+	pvcName := ordinalPVCName(v1beta1.TiKVMemberType, controller.TiKVMemberName(tc.GetName()), ordinal)
+	return tf.pvcLister.PersistentVolumeClaims(tc.GetNamespace()).Get(pvcName)
+}
+
+func (tf *tikvFailover) Recover(tc *v1beta1.TikvCluster) {
 	for key, failureStore := range tc.Status.TiKV.FailureStores {
 		if !tf.isPodDesired(tc, failureStore.PodName) {
 			// If we delete the pods, e.g. by using advanced statefulset delete
 			// slots feature. We should remove the record of undesired pods,
 			// otherwise an extra replacement pod will be created.
 			delete(tc.Status.TiKV.FailureStores, key)
+			continue
+		}
+		if failureStore.Replace && failureStore.PVCDeleted {
+			// the replacement completes once the pod joins the cluster as a new store
+			for _, store := range tc.Status.TiKV.Stores {
+				if store.PodName == failureStore.PodName && store.ID != failureStore.StoreID {
+					klog.Infof("tikv failover: store %s of %s/%s is replaced by store %s",
+						failureStore.StoreID, tc.GetNamespace(), failureStore.PodName, store.ID)
+					tf.recorder.Eventf(tc, corev1.EventTypeNormal, "TiKVStoreReplaced",
+						"%s(%s) replaced by store %s", failureStore.PodName, failureStore.StoreID, store.ID)
+					delete(tc.Status.TiKV.FailureStores, key)
+					break
+				}
+			}
 		}
 	}
 }
@@ -110,10 +246,10 @@ func NewFakeTiKVFailover() Failover {
 	return &fakeTiKVFailover{}
 }
 
-func (ftf *fakeTiKVFailover) Failover(_ *v1alpha1.TikvCluster) error {
+func (ftf *fakeTiKVFailover) Failover(_ *v1beta1.TikvCluster) error {
 	return nil
 }
 
-func (ftf *fakeTiKVFailover) Recover(_ *v1alpha1.TikvCluster) {
+func (ftf *fakeTiKVFailover) Recover(_ *v1beta1.TikvCluster) {
 	return
 }
@@ -18,8 +18,15 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 )
@@ -27,89 +34,89 @@ import (
 func TestTiKVFailoverFailover(t *testing.T) {
 	tests := []struct {
 		name     string
-		update   func(*v1alpha1.TikvCluster)
+		update   func(*v1beta1.TikvCluster)
 		err      bool
-		expectFn func(t *testing.T, tc *v1alpha1.TikvCluster)
+		expectFn func(t *testing.T, tc *v1beta1.TikvCluster)
 	}{
 		{
 			name: "normal",
-			update: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 					"1": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-1",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 					"2": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-2",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-61 * time.Minute)},
 					},
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(2))
 			},
 		},
 		{
 			name: "tikv state is not Down",
-			update: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
-					"1": {State: v1alpha1.TiKVStateUp, PodName: "tikv-1"},
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+					"1": {State: v1beta1.TiKVStateUp, PodName: "tikv-1"},
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(0))
 			},
 		},
 		{
 			name: "deadline not exceed",
-			update: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 					"1": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-1",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-30 * time.Minute)},
 					},
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(0))
 			},
 		},
 		{
 			name: "lastTransitionTime is zero",
-			update: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 					"1": {
-						State:   v1alpha1.TiKVStateDown,
+						State:   v1beta1.TiKVStateDown,
 						PodName: "tikv-1",
 					},
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(0))
 			},
 		},
 		{
 			name: "exist in failureStores",
-			update: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 					"1": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-1",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 				}
-				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
 					"1": {
 						PodName: "tikv-1",
 						StoreID: "1",
@@ -117,32 +124,32 @@ func TestTiKVFailoverFailover(t *testing.T) {
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(1))
 			},
 		},
 		{
 			name: "not exceed max failover count",
-			update: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 					"3": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-0",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 					"4": {
-						State:              v1alpha1.TiKVStateUp,
+						State:              v1beta1.TiKVStateUp,
 						PodName:            "tikv-4",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 					"5": {
-						State:              v1alpha1.TiKVStateUp,
+						State:              v1beta1.TiKVStateUp,
 						PodName:            "tikv-5",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-61 * time.Minute)},
 					},
 				}
-				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
 					"1": {
 						PodName: "tikv-1",
 						StoreID: "1",
@@ -154,32 +161,32 @@ func TestTiKVFailoverFailover(t *testing.T) {
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(3))
 			},
 		},
 		{
 			name: "exceed max failover count1",
-			update: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 					"3": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-3",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 					"4": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-4",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 					"5": {
-						State:              v1alpha1.TiKVStateUp,
+						State:              v1beta1.TiKVStateUp,
 						PodName:            "tikv-5",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-61 * time.Minute)},
 					},
 				}
-				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
 					"1": {
 						PodName: "tikv-1",
 						StoreID: "1",
@@ -191,32 +198,32 @@ func TestTiKVFailoverFailover(t *testing.T) {
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(3))
 			},
 		},
 		{
 			name: "exceed max failover count2",
-			update: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 					"0": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-0",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 					"4": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-4",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-61 * time.Minute)},
 					},
 					"5": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-5",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 				}
-				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
 					"1": {
 						PodName: "tikv-1",
 						StoreID: "1",
@@ -232,33 +239,33 @@ func TestTiKVFailoverFailover(t *testing.T) {
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(3))
 			},
 		},
 		{
 			name: "exceed max failover count2 but maxFailoverCount = 0",
-			update: func(tc *v1alpha1.TikvCluster) {
+			update: func(tc *v1beta1.TikvCluster) {
 				tc.Spec.TiKV.MaxFailoverCount = pointer.Int32Ptr(0)
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 					"12": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-12",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 					"13": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-13",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-61 * time.Minute)},
 					},
 					"14": {
-						State:              v1alpha1.TiKVStateDown,
+						State:              v1beta1.TiKVStateDown,
 						PodName:            "tikv-14",
 						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
 					},
 				}
-				tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
 					"1": {
 						PodName: "tikv-1",
 						StoreID: "1",
@@ -274,11 +281,72 @@ func TestTiKVFailoverFailover(t *testing.T) {
 				}
 			},
 			err: false,
-			expectFn: func(t *testing.T, tc *v1alpha1.TikvCluster) {
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
 				g := NewGomegaWithT(t)
 				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(3))
 			},
 		},
+		{
+			name: "deadline not exceed the period of the cluster",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Failover = &v1beta1.FailoverSpec{Period: &metav1.Duration{Duration: 2 * time.Hour}}
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+					"1": {
+						State:              v1beta1.TiKVStateDown,
+						PodName:            "tikv-1",
+						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
+					},
+				}
+			},
+			err: false,
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
+				g := NewGomegaWithT(t)
+				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(0))
+			},
+		},
+		{
+			name: "deadline exceed the period of the cluster",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Failover = &v1beta1.FailoverSpec{Period: &metav1.Duration{Duration: 10 * time.Minute}}
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+					"1": {
+						State:              v1beta1.TiKVStateDown,
+						PodName:            "tikv-1",
+						LastTransitionTime: metav1.Time{Time: time.Now().Add(-30 * time.Minute)},
+					},
+				}
+			},
+			err: false,
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
+				g := NewGomegaWithT(t)
+				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(1))
+			},
+		},
+		{
+			name: "exceed the max count of the cluster",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Failover = &v1beta1.FailoverSpec{MaxCount: pointer.Int32Ptr(1)}
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+					"1": {
+						State:              v1beta1.TiKVStateDown,
+						PodName:            "tikv-1",
+						LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
+					},
+				}
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
+					"2": {
+						PodName: "tikv-2",
+						StoreID: "2",
+					},
+				}
+			},
+			err: false,
+			expectFn: func(t *testing.T, tc *v1beta1.TikvCluster) {
+				g := NewGomegaWithT(t)
+				g.Expect(len(tc.Status.TiKV.FailureStores)).To(Equal(1))
+				g.Expect(tc.Status.TiKV.FailureStores).To(HaveKey("2"))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -287,7 +355,7 @@ func TestTiKVFailoverFailover(t *testing.T) {
 			tc.Spec.TiKV.Replicas = 6
 			tc.Spec.TiKV.MaxFailoverCount = pointer.Int32Ptr(3)
 			tt.update(tc)
-			tikvFailover := newFakeTiKVFailover()
+			tikvFailover, _, _, _ := newFakeTiKVFailover()
 
 			err := tikvFailover.Failover(tc)
 			if tt.err {
@@ -300,7 +368,160 @@ func TestTiKVFailoverFailover(t *testing.T) {
 	}
 }
 
-func newFakeTiKVFailover() *tikvFailover {
+func TestTiKVFailoverReplaceFailedStore(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	tc.Spec.TiKV.Replicas = 3
+	tc.Spec.TiKV.Failover = &v1beta1.FailoverSpec{ReplaceFailedStore: pointer.BoolPtr(true)}
+	podName := TikvPodName(tc.GetName(), 1)
+	tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+		"1": {
+			ID:                 "1",
+			State:              v1beta1.TiKVStateDown,
+			PodName:            podName,
+			LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
+		},
+	}
+
+	tikvFailover, podIndexer, pvcIndexer, pdControl := newFakeTiKVFailover()
+	podIndexer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: metav1.NamespaceDefault}})
+	pvcName := ordinalPVCName(v1beta1.TiKVMemberType, controller.TiKVMemberName(tc.GetName()), 1)
+	pvcIndexer.Add(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: metav1.NamespaceDefault, UID: "pvc-1"}})
+	deletedStores := []uint64{}
+	pdClient := controller.NewFakePDClient(pdControl, tc)
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{
+			newStoreInfo(1, v1beta1.TiKVStateDown, 100, 90),
+			newStoreInfo(2, v1beta1.TiKVStateUp, 100, 90),
+			newStoreInfo(3, v1beta1.TiKVStateUp, 100, 90),
+			newStoreInfo(4, v1beta1.TiKVStateUp, 100, 90),
+		}}, nil
+	})
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{Replication: &pdapi.PDReplicationConfig{}}, nil
+	})
+	pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+		deletedStores = append(deletedStores, action.ID)
+		return nil, nil
+	})
+
+	// the failed store is deleted from the PD cluster without adding a new replica
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.FailureStores).To(HaveKey("1"))
+	failureStore := tc.Status.TiKV.FailureStores["1"]
+	g.Expect(failureStore.Replace).To(BeTrue())
+	g.Expect(failureStore.PVCUID).To(Equal(types.UID("pvc-1")))
+	g.Expect(failureStore.StoreDeleted).To(BeTrue())
+	g.Expect(deletedStores).To(Equal([]uint64{1}))
+	g.Expect(tc.TiKVStsDesiredReplicas()).To(Equal(int32(3)))
+
+	// waiting for the store to become tombstone
+	store := tc.Status.TiKV.Stores["1"]
+	store.State = v1beta1.TiKVStateOffline
+	tc.Status.TiKV.Stores["1"] = store
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(deletedStores).To(HaveLen(1))
+	g.Expect(tc.Status.TiKV.FailureStores["1"].PVCDeleted).To(BeFalse())
+	g.Expect(podIndexer.ListKeys()).To(HaveLen(1))
+
+	// the PVC and the pod of the tombstone store are deleted
+	delete(tc.Status.TiKV.Stores, "1")
+	store.State = v1beta1.TiKVStateTombstone
+	tc.Status.TiKV.TombstoneStores = map[string]v1beta1.TiKVStore{"1": store}
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.FailureStores["1"].PVCDeleted).To(BeTrue())
+	g.Expect(podIndexer.ListKeys()).To(BeEmpty())
+	g.Expect(pvcIndexer.ListKeys()).To(BeEmpty())
+
+	// the new PVC of the recreated pod is kept
+	pvcIndexer.Add(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: metav1.NamespaceDefault, UID: "pvc-2"}})
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(pvcIndexer.ListKeys()).To(HaveLen(1))
+
+	// the replacement completes once the pod joins the cluster as a new store
+	tikvFailover.Recover(tc)
+	g.Expect(tc.Status.TiKV.FailureStores).To(HaveKey("1"))
+	tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+		"7": {ID: "7", State: v1beta1.TiKVStateUp, PodName: podName},
+	}
+	tikvFailover.Recover(tc)
+	g.Expect(tc.Status.TiKV.FailureStores).To(BeEmpty())
+}
+
+func TestTiKVFailoverReplaceFailedStoreBlocked(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	tc.Spec.TiKV.Replicas = 3
+	tc.Spec.TiKV.Failover = &v1beta1.FailoverSpec{ReplaceFailedStore: pointer.BoolPtr(true)}
+	podName := TikvPodName(tc.GetName(), 1)
+	tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+		"1": {
+			ID:                 "1",
+			State:              v1beta1.TiKVStateDown,
+			PodName:            podName,
+			LastTransitionTime: metav1.Time{Time: time.Now().Add(-70 * time.Minute)},
+		},
+	}
+
+	tikvFailover, podIndexer, pvcIndexer, pdControl := newFakeTiKVFailover()
+	podIndexer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: metav1.NamespaceDefault}})
+	pvcName := ordinalPVCName(v1beta1.TiKVMemberType, controller.TiKVMemberName(tc.GetName()), 1)
+	pvcIndexer.Add(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: metav1.NamespaceDefault, UID: "pvc-1"}})
+	deletedStores := []uint64{}
+	pdClient := controller.NewFakePDClient(pdControl, tc)
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{
+			newStoreInfo(1, v1beta1.TiKVStateDown, 100, 90),
+			newStoreInfo(2, v1beta1.TiKVStateUp, 100, 90),
+			newStoreInfo(3, v1beta1.TiKVStateUp, 100, 90),
+		}}, nil
+	})
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{Replication: &pdapi.PDReplicationConfig{}}, nil
+	})
+	pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+		deletedStores = append(deletedStores, action.ID)
+		return nil, nil
+	})
+
+	// fewer Up stores than max-replicas would remain, a new replica is added instead
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.FailureStores).To(HaveKey("1"))
+	failureStore := tc.Status.TiKV.FailureStores["1"]
+	g.Expect(failureStore.Replace).To(BeFalse())
+	g.Expect(failureStore.PVCUID).To(BeEmpty())
+	g.Expect(failureStore.StoreDeleted).To(BeFalse())
+	g.Expect(deletedStores).To(BeEmpty())
+	g.Expect(tc.TiKVStsDesiredReplicas()).To(Equal(int32(4)))
+	events := collectEvents(tikvFailover.recorder.(*record.FakeRecorder).Events)
+	g.Expect(events).To(HaveLen(2))
+	g.Expect(events[1]).To(ContainSubstring("TiKVStoreReplaceBlocked"))
+
+	// the pod and the PVC of the failure store are kept
+	g.Expect(tikvFailover.Failover(tc)).To(Succeed())
+	g.Expect(deletedStores).To(BeEmpty())
+	g.Expect(podIndexer.ListKeys()).To(HaveLen(1))
+	g.Expect(pvcIndexer.ListKeys()).To(HaveLen(1))
+}
+
+func newFakeTiKVFailover() (*tikvFailover, cache.Indexer, cache.Indexer, *pdapi.FakePDControl) {
+	kubeCli := kubefake.NewSimpleClientset()
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	podControl := controller.NewFakePodControl(podInformer)
+	pvcControl := controller.NewFakePVCControl(pvcInformer)
 	recorder := record.NewFakeRecorder(100)
-	return &tikvFailover{1 * time.Hour, recorder}
+	return &tikvFailover{
+			pdControl,
+			1 * time.Hour,
+			podInformer.Lister(),
+			podControl,
+			pvcInformer.Lister(),
+			pvcControl,
+			recorder},
+		podInformer.Informer().GetIndexer(),
+		pvcInformer.Informer().GetIndexer(),
+		pdControl
 }
@@ -14,27 +14,35 @@
 package member
 
 import (
+	"encoding/json"
 	"fmt"
+	"path"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/kvproto/pkg/metapb"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/manager"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
 	"github.com/tikv/tikv-operator/pkg/util"
+	utiltikvcluster "github.com/tikv/tikv-operator/pkg/util/tikvcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	v1 "k8s.io/client-go/listers/apps/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog"
+	"k8s.io/utils/pointer"
 )
 
 const (
@@ -42,24 +50,30 @@ const (
 	tikvClusterCertPath = "/var/lib/tikv-tls"
 
 	//find a better way to manage store only managed by tikv in Operator
-	tikvStoreLimitPattern = `%s-tikv-\d+\.%s-tikv-peer\.%s\.svc\:\d+`
+	tikvStoreLimitPattern = `^%s-tikv-\d+\.%s-tikv-peer\.%s\.svc\:\d+$`
 )
 
 // tikvMemberManager implements manager.Manager.
 type tikvMemberManager struct {
 	setControl                   controller.StatefulSetControlInterface
 	svcControl                   controller.ServiceControlInterface
+	podControl                   controller.PodControlInterface
 	pdControl                    pdapi.PDControlInterface
 	typedControl                 controller.TypedControlInterface
+	pvcControl                   controller.PVCControlInterface
 	setLister                    v1.StatefulSetLister
 	svcLister                    corelisters.ServiceLister
 	podLister                    corelisters.PodLister
+	pvcLister                    corelisters.PersistentVolumeClaimLister
 	nodeLister                   corelisters.NodeLister
 	autoFailover                 bool
 	tikvFailover                 Failover
 	tikvScaler                   Scaler
 	tikvUpgrader                 Upgrader
-	tikvStatefulSetIsUpgradingFn func(corelisters.PodLister, pdapi.PDControlInterface, *apps.StatefulSet, *v1alpha1.TikvCluster) (bool, error)
+	tikvUnsafeRecovery           TiKVUnsafeRecovery
+	tikvSnapshotter              TiKVSnapshotter
+	snapshotControl              controller.VolumeSnapshotControlInterface
+	tikvStatefulSetIsUpgradingFn func(corelisters.PodLister, pdapi.PDControlInterface, *apps.StatefulSet, *v1beta1.TikvCluster) (bool, error)
 }
 
 // NewTiKVMemberManager returns a *tikvMemberManager
@@ -67,28 +81,40 @@ func NewTiKVMemberManager(
 	pdControl pdapi.PDControlInterface,
 	setControl controller.StatefulSetControlInterface,
 	svcControl controller.ServiceControlInterface,
+	podControl controller.PodControlInterface,
 	typedControl controller.TypedControlInterface,
+	pvcControl controller.PVCControlInterface,
 	setLister v1.StatefulSetLister,
 	svcLister corelisters.ServiceLister,
 	podLister corelisters.PodLister,
+	pvcLister corelisters.PersistentVolumeClaimLister,
 	nodeLister corelisters.NodeLister,
 	autoFailover bool,
 	tikvFailover Failover,
 	tikvScaler Scaler,
-	tikvUpgrader Upgrader) manager.Manager {
+	tikvUpgrader Upgrader,
+	tikvUnsafeRecovery TiKVUnsafeRecovery,
+	tikvSnapshotter TiKVSnapshotter,
+	snapshotControl controller.VolumeSnapshotControlInterface) manager.Manager {
 	kvmm := tikvMemberManager{
-		pdControl:    pdControl,
-		podLister:    podLister,
-		nodeLister:   nodeLister,
-		setControl:   setControl,
-		svcControl:   svcControl,
-		typedControl: typedControl,
-		setLister:    setLister,
-		svcLister:    svcLister,
-		autoFailover: autoFailover,
-		tikvFailover: tikvFailover,
-		tikvScaler:   tikvScaler,
-		tikvUpgrader: tikvUpgrader,
+		pdControl:          pdControl,
+		podLister:          podLister,
+		pvcLister:          pvcLister,
+		nodeLister:         nodeLister,
+		setControl:         setControl,
+		svcControl:         svcControl,
+		podControl:         podControl,
+		typedControl:       typedControl,
+		pvcControl:         pvcControl,
+		setLister:          setLister,
+		svcLister:          svcLister,
+		autoFailover:       autoFailover,
+		tikvFailover:       tikvFailover,
+		tikvScaler:         tikvScaler,
+		tikvUpgrader:       tikvUpgrader,
+		tikvUnsafeRecovery: tikvUnsafeRecovery,
+		tikvSnapshotter:    tikvSnapshotter,
+		snapshotControl:    snapshotControl,
 	}
 	kvmm.tikvStatefulSetIsUpgradingFn = tikvStatefulSetIsUpgrading
 	return &kvmm
@@ -104,7 +130,7 @@ type SvcConfig struct {
 }
 
 // Sync fulfills the manager.Manager interface
-func (tkmm *tikvMemberManager) Sync(tc *v1alpha1.TikvCluster) error {
+func (tkmm *tikvMemberManager) Sync(tc *v1beta1.TikvCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
@@ -126,10 +152,13 @@ func (tkmm *tikvMemberManager) Sync(tc *v1alpha1.TikvCluster) error {
 			return err
 		}
 	}
+	if err := syncPodDisruptionBudget(tkmm.typedControl, tc, getTiKVPodDisruptionBudget(tc), tc.Spec.TiKV.PodDisruptionBudget, v1beta1.TiKVDisruptionBlocked); err != nil {
+		return err
+	}
 	return tkmm.syncStatefulSetForTikvCluster(tc)
 }
 
-func (tkmm *tikvMemberManager) syncServiceForTikvCluster(tc *v1alpha1.TikvCluster, svcConfig SvcConfig) error {
+func (tkmm *tikvMemberManager) syncServiceForTikvCluster(tc *v1beta1.TikvCluster, svcConfig SvcConfig) error {
 	if tc.Spec.Paused {
 		klog.V(4).Infof("tikv cluster %s/%s is paused, skip syncing for tikv service", tc.GetNamespace(), tc.GetName())
 		return nil
@@ -173,7 +202,7 @@ func (tkmm *tikvMemberManager) syncServiceForTikvCluster(tc *v1alpha1.TikvCluste
 	return nil
 }
 
-func (tkmm *tikvMemberManager) syncStatefulSetForTikvCluster(tc *v1alpha1.TikvCluster) error {
+func (tkmm *tikvMemberManager) syncStatefulSetForTikvCluster(tc *v1beta1.TikvCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
@@ -194,6 +223,27 @@ func (tkmm *tikvMemberManager) syncStatefulSetForTikvCluster(tc *v1alpha1.TikvCl
 		return nil
 	}
 
+	if err := tkmm.deleteExternalStores(tc); err != nil {
+		return err
+	}
+
+	if err := tkmm.endEvictLeaderOfRecreatedPods(tc); err != nil {
+		return err
+	}
+
+	// the statefulset is not synced until the unsafe recovery finishes
+	if err := tkmm.tikvUnsafeRecovery.Sync(tc); err != nil {
+		return err
+	}
+
+	if err := tkmm.tikvSnapshotter.Sync(tc); err != nil {
+		return err
+	}
+
+	if err := tkmm.syncTiKVEncryption(tc); err != nil {
+		return err
+	}
+
 	cm, err := tkmm.syncTiKVConfigMap(tc, oldSet)
 	if err != nil {
 		return err
@@ -209,6 +259,11 @@ func (tkmm *tikvMemberManager) syncStatefulSetForTikvCluster(tc *v1alpha1.TikvCl
 		return err
 	}
 	if setNotExist {
+		if tc.IsTiKVCloned() {
+			if err := tkmm.createClonedPVCs(tc, newSet); err != nil {
+				return err
+			}
+		}
 		err = SetStatefulSetLastAppliedConfigAnnotation(newSet)
 		if err != nil {
 			return err
@@ -225,17 +280,26 @@ func (tkmm *tikvMemberManager) syncStatefulSetForTikvCluster(tc *v1alpha1.TikvCl
 		return err
 	}
 
-	if !templateEqual(newSet, oldSet) || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
-		if err := tkmm.tikvUpgrader.Upgrade(tc, oldSet, newSet); err != nil {
+	if !templateEqual(newSet, oldSet) || tc.Status.TiKV.Phase == v1beta1.UpgradePhase {
+		if !allowDisruption(tc, v1beta1.TiKVUpgradeOperation) {
+			if err := holdUpgrade(oldSet, newSet); err != nil {
+				return err
+			}
+			if err := tkmm.endEvictLeaderOfHeldUpgrade(tc, oldSet); err != nil {
+				return err
+			}
+		} else if err := tkmm.tikvUpgrader.Upgrade(tc, oldSet, newSet); err != nil {
 			return err
 		}
 	}
 
-	if err := tkmm.tikvScaler.Scale(tc, oldSet, newSet); err != nil {
+	if scaling, _, _, _ := scaleOne(oldSet, newSet); scaling < 0 && !allowDisruption(tc, v1beta1.TiKVScaleInOperation) {
+		resetReplicas(newSet, oldSet)
+	} else if err := tkmm.tikvScaler.Scale(tc, oldSet, newSet); err != nil {
 		return err
 	}
 
-	if tkmm.autoFailover && tc.Spec.TiKV.MaxFailoverCount != nil {
+	if tc.TiKVFailoverEnabled(tkmm.autoFailover) {
 		if tc.TiKVAllPodsStarted() && !tc.TiKVAllStoresReady() {
 			if err := tkmm.tikvFailover.Failover(tc); err != nil {
 				return err
@@ -246,28 +310,61 @@ func (tkmm *tikvMemberManager) syncStatefulSetForTikvCluster(tc *v1alpha1.TikvCl
 	return updateStatefulSet(tkmm.setControl, tc, newSet, oldSet)
 }
 
-func (tkmm *tikvMemberManager) syncTiKVConfigMap(tc *v1alpha1.TikvCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
-	// For backward compatibility, only sync tidb configmap when .tikv.config is non-nil
-	if tc.Spec.TiKV.Config == nil {
-		return nil, nil
+// createClonedPVCs creates the pvcs of the statefulset from the snapshots in the order of
+// the store ids once the pd cluster has recovered the cluster id and alloc id of them
+func (tkmm *tikvMemberManager) createClonedPVCs(tc *v1beta1.TikvCluster, set *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	r := tc.Status.PD.Recovery
+	if r != nil && r.Phase == v1beta1.PDRecoveryFailed {
+		return fmt.Errorf("TikvCluster: [%s/%s], the pd cluster failed to be cloned", ns, tcName)
 	}
-	newCm, err := getTikVConfigMap(tc)
+	if r == nil || (r.Phase != v1beta1.PDRecoveryRejoin && r.Phase != v1beta1.PDRecoveryComplete) {
+		return controller.RequeueErrorf("TikvCluster: [%s/%s], waiting for the pd cluster to be cloned", ns, tcName)
+	}
+
+	snapshots, err := getTiKVDataSourceSnapshots(tkmm.snapshotControl, tc)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if set != nil && tc.BaseTiKVSpec().ConfigUpdateStrategy() == v1alpha1.ConfigUpdateStrategyInPlace {
-		inUseName := FindConfigMapVolume(&set.Spec.Template.Spec, func(name string) bool {
-			return strings.HasPrefix(name, controller.TiKVMemberName(tc.Name))
-		})
-		if inUseName != "" {
-			newCm.Name = inUseName
+	if len(snapshots) > int(*set.Spec.Replicas) {
+		return fmt.Errorf("TikvCluster: [%s/%s], can't clone %d stores to %d replicas", ns, tcName, len(snapshots), *set.Spec.Replicas)
+	}
+	template := set.Spec.VolumeClaimTemplates[0]
+	for i, snapshot := range snapshots {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ordinalPVCName(v1beta1.TiKVMemberType, set.GetName(), int32(i)),
+				Namespace: ns,
+				Labels:    labelTiKV(tc).Labels(),
+			},
+			Spec: *template.Spec.DeepCopy(),
+		}
+		pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			APIGroup: pointer.StringPtr(controller.VolumeSnapshotGroupVersionKind.Group),
+			Kind:     controller.VolumeSnapshotGroupVersionKind.Kind,
+			Name:     snapshot.GetName(),
 		}
+		if _, err := tkmm.typedControl.CreateOrUpdatePVC(tc, pvc, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tkmm *tikvMemberManager) syncTiKVConfigMap(tc *v1beta1.TikvCluster, set *apps.StatefulSet) (*corev1.ConfigMap, error) {
+	// For backward compatibility, only sync tidb configmap when .tikv.config or .tikv.encryption is non-nil
+	newCm, err := getTikVConfigMap(tc)
+	if err != nil || newCm == nil {
+		return nil, err
 	}
+	keepInUseConfigMapName(newCm, set, tc.BaseTiKVSpec().ConfigUpdateStrategy(), controller.TiKVMemberName(tc.Name))
 
 	return tkmm.typedControl.CreateOrUpdateConfigMap(tc, newCm)
 }
 
-func getNewServiceForTikvCluster(tc *v1alpha1.TikvCluster, svcConfig SvcConfig) *corev1.Service {
+func getNewServiceForTikvCluster(tc *v1beta1.TikvCluster, svcConfig SvcConfig) *corev1.Service {
 	ns := tc.Namespace
 	tcName := tc.Name
 	instanceName := tc.GetInstanceName()
@@ -302,12 +399,12 @@ func getNewServiceForTikvCluster(tc *v1alpha1.TikvCluster, svcConfig SvcConfig)
 	return &svc
 }
 
-func getNewTiKVSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+func getNewTiKVSetForTikvCluster(tc *v1beta1.TikvCluster, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	baseTiKVSpec := tc.BaseTiKVSpec()
 
-	tikvConfigMap := controller.MemberConfigMapName(tc, v1alpha1.TiKVMemberType)
+	tikvConfigMap := controller.MemberConfigMapName(tc, v1beta1.TiKVMemberType)
 	if cm != nil {
 		tikvConfigMap = cm.Name
 	}
@@ -315,15 +412,21 @@ func getNewTiKVSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap)
 	annMount, annVolume := annotationsMountVolume()
 	volMounts := []corev1.VolumeMount{
 		annMount,
-		{Name: v1alpha1.TiKVMemberType.String(), MountPath: "/var/lib/tikv"},
+		{Name: v1beta1.TiKVMemberType.String(), MountPath: "/var/lib/tikv"},
 		{Name: "config", ReadOnly: true, MountPath: "/etc/tikv"},
 		{Name: "startup-script", ReadOnly: true, MountPath: "/usr/local/bin"},
 	}
-	if tc.IsTLSClusterEnabled() {
+	if tc.IsTLSClusterEnabled() || tc.IsPDExternalTLSEnabled() {
 		volMounts = append(volMounts, corev1.VolumeMount{
 			Name: "tikv-tls", ReadOnly: true, MountPath: "/var/lib/tikv-tls",
 		})
 	}
+	encryptionEnabled := tc.Spec.TiKV.Encryption != nil && tc.Status.TiKV.Encryption != nil
+	if encryptionEnabled {
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: "tikv-encryption", ReadOnly: true, MountPath: tikvEncryptionKeyPath,
+		})
+	}
 
 	vols := []corev1.Volume{
 		annVolume,
@@ -353,6 +456,25 @@ func getNewTiKVSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap)
 			},
 		})
 	}
+	if tc.IsPDExternalTLSEnabled() {
+		// the certificate to access the external PD cluster is used as the cluster certificate
+		vols = append(vols, corev1.Volume{
+			Name: "tikv-tls", VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: *tc.Spec.PD.ExternalTLSClientSecretName,
+				},
+			},
+		})
+	}
+	if encryptionEnabled {
+		vols = append(vols, corev1.Volume{
+			Name: "tikv-encryption", VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: controller.TiKVEncryptionSecretName(tcName),
+				},
+			},
+		})
+	}
 
 	sysctls := "sysctl -w"
 	var initContainers []corev1.Container
@@ -395,6 +517,10 @@ func getNewTiKVSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap)
 	tikvLabel := labelTiKV(tc)
 	setName := controller.TiKVMemberName(tcName)
 	podAnnotations := CombineAnnotations(controller.AnnProm(20180), baseTiKVSpec.Annotations())
+	if encryptionEnabled {
+		// changing the generation restarts the pods to load the rotated master key
+		podAnnotations[label.AnnTiKVMasterKeyGeneration] = strconv.FormatInt(tc.Status.TiKV.Encryption.KeyGeneration, 10)
+	}
 	stsAnnotations := getStsAnnotations(tc, label.TiKVLabelVal)
 	capacity := controller.TiKVCapacity(tc.Spec.TiKV.Limits)
 	headlessSvcName := controller.TiKVPeerMemberName(tcName)
@@ -426,7 +552,7 @@ func getNewTiKVSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap)
 		},
 	}
 	tikvContainer := corev1.Container{
-		Name:            v1alpha1.TiKVMemberType.String(),
+		Name:            v1beta1.TiKVMemberType.String(),
 		Image:           tc.TiKVImage(),
 		ImagePullPolicy: baseTiKVSpec.ImagePullPolicy(),
 		Command:         []string{"/bin/sh", "/usr/local/bin/tikv_start_script.sh"},
@@ -481,7 +607,7 @@ func getNewTiKVSetForTikvCluster(tc *v1alpha1.TikvCluster, cm *corev1.ConfigMap)
 				Spec: podSpec,
 			},
 			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
-				volumeClaimTemplate(storageRequest, v1alpha1.TiKVMemberType.String(), tc.Spec.TiKV.StorageClassName),
+				volumeClaimTemplate(storageRequest, v1beta1.TiKVMemberType.String(), tc.Spec.TiKV.StorageClassName),
 			},
 			ServiceName:         headlessSvcName,
 			PodManagementPolicy: apps.ParallelPodManagement,
@@ -509,19 +635,40 @@ func volumeClaimTemplate(r corev1.ResourceRequirements, metaName string, storage
 	}
 }
 
-func getTikVConfigMap(tc *v1alpha1.TikvCluster) (*corev1.ConfigMap, error) {
+func getTikVConfigMap(tc *v1beta1.TikvCluster) (*corev1.ConfigMap, error) {
 
 	config := tc.Spec.TiKV.Config
-	if config == nil {
+	if config == nil && tc.Spec.TiKV.Encryption == nil {
 		return nil, nil
 	}
+	if config == nil {
+		config = &v1beta1.TiKVConfig{}
+	} else if tc.IsPDExternalTLSEnabled() || tc.Spec.TiKV.Encryption != nil {
+		config = config.DeepCopy()
+	}
+	setTiKVEncryptionConfig(tc, config)
+	if tc.IsPDExternalTLSEnabled() {
+		if config.Security == nil {
+			config.Security = &v1beta1.TiKVSecurityConfig{}
+		}
+		if config.Security.CAPath == nil {
+			config.Security.CAPath = pointer.StringPtr(path.Join(tikvClusterCertPath, corev1.ServiceAccountRootCAKey))
+		}
+		if config.Security.CertPath == nil {
+			config.Security.CertPath = pointer.StringPtr(path.Join(tikvClusterCertPath, corev1.TLSCertKey))
+		}
+		if config.Security.KeyPath == nil {
+			config.Security.KeyPath = pointer.StringPtr(path.Join(tikvClusterCertPath, corev1.TLSPrivateKeyKey))
+		}
+	}
 
 	confText, err := MarshalTOML(config)
 	if err != nil {
 		return nil, err
 	}
 	startScript, err := RenderTiKVStartScript(&TiKVStartScriptModel{
-		Scheme: tc.Scheme(),
+		Scheme:      tc.Scheme(),
+		PDAddresses: strings.Join(tc.Spec.PD.ExternalEndpoints, ","),
 	})
 	if err != nil {
 		return nil, err
@@ -541,7 +688,7 @@ func getTikVConfigMap(tc *v1alpha1.TikvCluster) (*corev1.ConfigMap, error) {
 		},
 	}
 
-	if tc.BaseTiKVSpec().ConfigUpdateStrategy() == v1alpha1.ConfigUpdateStrategyRollingUpdate {
+	if tc.BaseTiKVSpec().ConfigUpdateStrategy() == v1beta1.ConfigUpdateStrategyRollingUpdate {
 		if err := AddConfigMapDigestSuffix(cm); err != nil {
 			return nil, err
 		}
@@ -550,30 +697,37 @@ func getTikVConfigMap(tc *v1alpha1.TikvCluster) (*corev1.ConfigMap, error) {
 	return cm, nil
 }
 
-func labelTiKV(tc *v1alpha1.TikvCluster) label.Label {
+func labelTiKV(tc *v1beta1.TikvCluster) label.Label {
 	instanceName := tc.GetInstanceName()
 	return label.New().Instance(instanceName).TiKV()
 }
 
-func (tkmm *tikvMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, set *apps.StatefulSet) error {
+func (tkmm *tikvMemberManager) syncTikvClusterStatus(tc *v1beta1.TikvCluster, set *apps.StatefulSet) error {
 	if set == nil {
 		// skip if not created yet
 		return nil
 	}
 	tc.Status.TiKV.StatefulSet = &set.Status
+	tc.Status.TiKV.Replicas = tc.TiKVActualReplicasWithoutFailover()
+	selector, err := labelTiKV(tc).Selector()
+	if err != nil {
+		return err
+	}
+	tc.Status.TiKV.Selector = selector.String()
 	upgrading, err := tkmm.tikvStatefulSetIsUpgradingFn(tkmm.podLister, tkmm.pdControl, set, tc)
 	if err != nil {
 		return err
 	}
-	if upgrading && tc.Status.PD.Phase != v1alpha1.UpgradePhase {
-		tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+	if upgrading && tc.Status.PD.Phase != v1beta1.UpgradePhase {
+		tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 	} else {
-		tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+		tc.Status.TiKV.Phase = v1beta1.NormalPhase
 	}
 
 	previousStores := tc.Status.TiKV.Stores
-	stores := map[string]v1alpha1.TiKVStore{}
-	tombstoneStores := map[string]v1alpha1.TiKVStore{}
+	stores := map[string]v1beta1.TiKVStore{}
+	tombstoneStores := map[string]v1beta1.TiKVStore{}
+	externalStores := map[string]v1beta1.TiKVStore{}
 
 	pdCli := controller.GetPDClient(tkmm.pdControl, tc)
 	// This only returns Up/Down/Offline stores
@@ -591,6 +745,7 @@ func (tkmm *tikvMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, s
 		// In theory, the external tikv can join the cluster, and the operator would only manage the internal tikv.
 		// So we check the store owner to make sure it.
 		if store.Store != nil && !pattern.Match([]byte(store.Store.Address)) {
+			tkmm.collectExternalStore(tc, store, externalStores)
 			continue
 		}
 		status := tkmm.getTiKVStore(store)
@@ -612,6 +767,14 @@ func (tkmm *tikvMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, s
 			status.LastTransitionTime = oldStore.LastTransitionTime
 		}
 
+		if tc.Spec.TiKV.Encryption != nil {
+			if pod, err := tkmm.podLister.Pods(tc.GetNamespace()).Get(status.PodName); err == nil {
+				status.MasterKeyGeneration = getTiKVMasterKeyGeneration(pod, label.AnnTiKVMasterKeyGeneration)
+			} else if exist {
+				status.MasterKeyGeneration = oldStore.MasterKeyGeneration
+			}
+		}
+
 		stores[status.ID] = *status
 	}
 
@@ -623,6 +786,7 @@ func (tkmm *tikvMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, s
 	}
 	for _, store := range tombstoneStoresInfo.Stores {
 		if store.Store != nil && !pattern.Match([]byte(store.Store.Address)) {
+			tkmm.collectExternalStore(tc, store, externalStores)
 			continue
 		}
 		status := tkmm.getTiKVStore(store)
@@ -635,6 +799,10 @@ func (tkmm *tikvMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, s
 	tc.Status.TiKV.Synced = true
 	tc.Status.TiKV.Stores = stores
 	tc.Status.TiKV.TombstoneStores = tombstoneStores
+	tc.Status.TiKV.ExternalStores = nil
+	if tc.Spec.TiKV.ManageExternalStores {
+		tc.Status.TiKV.ExternalStores = externalStores
+	}
 	tc.Status.TiKV.Image = ""
 	c := filterContainer(set, "tikv")
 	if c != nil {
@@ -643,7 +811,251 @@ func (tkmm *tikvMemberManager) syncTikvClusterStatus(tc *v1alpha1.TikvCluster, s
 	return nil
 }
 
-func (tkmm *tikvMemberManager) getTiKVStore(store *pdapi.StoreInfo) *v1alpha1.TiKVStore {
+// collectExternalStore adds the store which is not created by the operator to externalStores
+// if the external stores are managed
+func (tkmm *tikvMemberManager) collectExternalStore(tc *v1beta1.TikvCluster, store *pdapi.StoreInfo, externalStores map[string]v1beta1.TiKVStore) {
+	if !tc.Spec.TiKV.ManageExternalStores {
+		return
+	}
+	status := tkmm.getTiKVStore(store)
+	if status == nil {
+		return
+	}
+	// the store is not served by a pod of the cluster
+	status.PodName = ""
+	status.LastTransitionTime = metav1.Now()
+	if oldStore, exist := tc.Status.TiKV.ExternalStores[status.ID]; exist && status.State == oldStore.State {
+		status.LastTransitionTime = oldStore.LastTransitionTime
+	}
+	externalStores[status.ID] = *status
+}
+
+// deleteExternalStores deletes the external stores listed in the annotation, only the pd
+// is requested and the pods of the stores are never touched.
+func (tkmm *tikvMemberManager) deleteExternalStores(tc *v1beta1.TikvCluster) error {
+	if !tc.Spec.TiKV.ManageExternalStores {
+		return nil
+	}
+	value, ok := tc.Annotations[label.AnnTiKVDeleteExternalStores]
+	if !ok {
+		resetExternalStoresDeletionBlockedCondition(tc)
+		return nil
+	}
+	var storeIDs []uint64
+	if err := json.Unmarshal([]byte(value), &storeIDs); err != nil {
+		return fmt.Errorf("tikvcluster: [%s/%s], failed to parse annotation %s: %s, %v",
+			tc.GetNamespace(), tc.GetName(), label.AnnTiKVDeleteExternalStores, value, err)
+	}
+
+	ids := []uint64{}
+	for _, id := range storeIDs {
+		storeID := strconv.FormatUint(id, 10)
+		store, ok := tc.Status.TiKV.ExternalStores[storeID]
+		if !ok {
+			klog.Warningf("tikvcluster: [%s/%s], store %s is not an external store, skip deleting it", tc.GetNamespace(), tc.GetName(), storeID)
+			continue
+		}
+		if store.State == v1beta1.TiKVStateOffline || store.State == v1beta1.TiKVStateTombstone {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		resetExternalStoresDeletionBlockedCondition(tc)
+		return nil
+	}
+
+	pdCli := controller.GetPDClient(tkmm.pdControl, tc)
+	deletable, err := preflightDeleteExternalStores(pdCli, tc, ids)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, id := range ids {
+		if !deletable[id] {
+			continue
+		}
+		storeID := strconv.FormatUint(id, 10)
+		if err := pdCli.DeleteStore(id); err != nil {
+			errs = append(errs, fmt.Errorf("tikvcluster: [%s/%s], failed to delete external store %s, %v", tc.GetNamespace(), tc.GetName(), storeID, err))
+			continue
+		}
+		klog.Infof("tikvcluster: [%s/%s], external store %s (%s) is deleted", tc.GetNamespace(), tc.GetName(), storeID, tc.Status.TiKV.ExternalStores[storeID].IP)
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+// preflightDeleteExternalStores runs the pre-flight checks of the scale-in against the external
+// stores to delete, the remaining external stores and the stores of the TikvCluster are counted
+// as the remaining stores. It returns the stores which can be deleted, the stores are deleted in
+// the given order and the deletion stops at the first store which fails the checks.
+func preflightDeleteExternalStores(pdCli pdapi.PDClient, tc *v1beta1.TikvCluster, ids []uint64) (map[uint64]bool, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	storesInfo, err := pdCli.GetStores()
+	if err != nil {
+		return nil, fmt.Errorf("tikvcluster: [%s/%s], failed to get the stores to delete external stores, %v", ns, tcName, err)
+	}
+	config, err := pdCli.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("tikvcluster: [%s/%s], failed to get the config of PD to delete external stores, %v", ns, tcName, err)
+	}
+	deletable := map[uint64]bool{}
+	for _, id := range ids {
+		deleting := map[uint64]bool{id: true}
+		for deletableID := range deletable {
+			deleting[deletableID] = true
+		}
+		if err := checkScaleIn(storesInfo.Stores, config.Replication, deleting, tc.TiKVScaleInHighWaterMarkPercent()); err != nil {
+			msg := fmt.Sprintf("external store %d can not be deleted: %v", id, err)
+			klog.Warningf("tikvcluster: [%s/%s], the deletion of external stores is blocked by the pre-flight checks, %s", ns, tcName, msg)
+			setExternalStoresDeletionBlockedCondition(tc, corev1.ConditionTrue, utiltikvcluster.ScaleInPreflightCheckFailed, msg)
+			return deletable, nil
+		}
+		deletable[id] = true
+	}
+	setExternalStoresDeletionBlockedCondition(tc, corev1.ConditionFalse, utiltikvcluster.ScaleInPreflightCheckPassed, "the remaining stores can hold the data and the region replicas")
+	return deletable, nil
+}
+
+func setExternalStoresDeletionBlockedCondition(tc *v1beta1.TikvCluster, status corev1.ConditionStatus, reason, message string) {
+	cond := utiltikvcluster.NewTikvClusterCondition(v1beta1.ExternalStoresDeletionBlocked, status, reason, message)
+	utiltikvcluster.SetTikvClusterCondition(&tc.Status, *cond)
+}
+
+// resetExternalStoresDeletionBlockedCondition clears the blocked condition once the deletion
+// of the external stores is no longer requested
+func resetExternalStoresDeletionBlockedCondition(tc *v1beta1.TikvCluster) {
+	if cond := utiltikvcluster.GetTikvClusterCondition(tc.Status, v1beta1.ExternalStoresDeletionBlocked); cond != nil && cond.Status == corev1.ConditionTrue {
+		setExternalStoresDeletionBlockedCondition(tc, corev1.ConditionFalse, utiltikvcluster.NoExternalStoresDeletion, "no external store is requested to be deleted")
+	}
+}
+
+// endEvictLeaderOfRecreatedPods ends the leader eviction begun by the pod eviction webhook
+// once the evicted pod is recreated and its store is Up again. The webhook records the store
+// on the PVC of the pod, the leader evictions begun by others, e.g. the node maintenance
+// controller, are not recorded and kept. The recreated pod has no evictLeaderBeginTime
+// annotation.
+func (tkmm *tikvMemberManager) endEvictLeaderOfRecreatedPods(tc *v1beta1.TikvCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	pvcs := map[uint64]*corev1.PersistentVolumeClaim{}
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.State != v1beta1.TiKVStateUp {
+			continue
+		}
+		pod, err := tkmm.podLister.Pods(ns).Get(store.PodName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, evicting := pod.Annotations[label.AnnEvictLeaderBeginTime]; evicting || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		ordinal, err := util.GetOrdinalFromPodName(store.PodName)
+		if err != nil {
+			return err
+		}
+		pvc, err := tkmm.pvcLister.PersistentVolumeClaims(ns).Get(ordinalPVCName(v1beta1.TiKVMemberType, controller.TiKVMemberName(tcName), ordinal))
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if pvc.Annotations[label.AnnPVCEvictLeaderStore] != store.ID {
+			continue
+		}
+		storeID, err := strconv.ParseUint(store.ID, 10, 64)
+		if err != nil {
+			return err
+		}
+		pvcs[storeID] = pvc
+	}
+	if len(pvcs) == 0 {
+		return nil
+	}
+
+	pdCli := controller.GetPDClient(tkmm.pdControl, tc)
+	schedulers, err := pdCli.GetEvictLeaderSchedulers()
+	if err != nil {
+		return err
+	}
+	evicting := sets.NewString(schedulers...)
+	for storeID, pvc := range pvcs {
+		if evicting.Has(pdapi.GetLeaderEvictSchedulerStr(storeID)) {
+			if err := pdCli.EndEvictLeader(storeID); err != nil {
+				klog.Errorf("tikvcluster: [%s/%s], failed to end evict leader of store %d, %v", ns, tcName, storeID, err)
+				return err
+			}
+			klog.Infof("tikvcluster: [%s/%s], store %d is Up again after its pod was evicted, end evict leader", ns, tcName, storeID)
+		}
+		pvc = pvc.DeepCopy()
+		delete(pvc.Annotations, label.AnnPVCEvictLeaderStore)
+		if _, err := tkmm.pvcControl.UpdatePVC(tc, pvc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endEvictLeaderOfHeldUpgrade ends the leader eviction begun by the upgrader for the next
+// pod to upgrade when the upgrade is held until the next maintenance window, otherwise the
+// store would serve no leaders in the meantime. The evictLeaderBeginTime annotation is
+// removed so that the leaders are evicted again when the upgrade continues.
+func (tkmm *tikvMemberManager) endEvictLeaderOfHeldUpgrade(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	if tc.Status.TiKV.StatefulSet == nil {
+		return nil
+	}
+	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	for i := len(podOrdinals) - 1; i >= 0; i-- {
+		podName := TikvPodName(tcName, podOrdinals[i])
+		pod, err := tkmm.podLister.Pods(ns).Get(podName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] == tc.Status.TiKV.StatefulSet.UpdateRevision {
+			continue
+		}
+		// the upgrader upgrades the pods one by one from the highest ordinal, the
+		// evictions begun by the pod eviction webhook are ended by the webhook
+		if _, evicting := pod.Annotations[label.AnnEvictLeaderBeginTime]; !evicting {
+			return nil
+		}
+		if _, byUpgrader := pod.Annotations[label.AnnEvictLeaderByUpgrader]; !byUpgrader {
+			return nil
+		}
+		for _, store := range tc.Status.TiKV.Stores {
+			if store.PodName != podName {
+				continue
+			}
+			storeID, err := strconv.ParseUint(store.ID, 10, 64)
+			if err != nil {
+				return err
+			}
+			if err := controller.GetPDClient(tkmm.pdControl, tc).EndEvictLeader(storeID); err != nil {
+				klog.Errorf("tikvcluster: [%s/%s], failed to end evict leader of store %d, %v", ns, tcName, storeID, err)
+				return err
+			}
+			klog.Infof("tikvcluster: [%s/%s], the upgrade of pod %s is held, end evict leader of store %d", ns, tcName, podName, storeID)
+		}
+		pod = pod.DeepCopy()
+		delete(pod.Annotations, label.AnnEvictLeaderBeginTime)
+		delete(pod.Annotations, label.AnnEvictLeaderByUpgrader)
+		_, err = tkmm.podControl.UpdatePod(tc, pod)
+		return err
+	}
+	return nil
+}
+
+func (tkmm *tikvMemberManager) getTiKVStore(store *pdapi.StoreInfo) *v1beta1.TiKVStore {
 	if store.Store == nil || store.Status == nil {
 		return nil
 	}
@@ -651,7 +1063,7 @@ func (tkmm *tikvMemberManager) getTiKVStore(store *pdapi.StoreInfo) *v1alpha1.Ti
 	ip := strings.Split(store.Store.GetAddress(), ":")[0]
 	podName := strings.Split(ip, ".")[0]
 
-	return &v1alpha1.TiKVStore{
+	return &v1beta1.TiKVStore{
 		ID:                storeID,
 		PodName:           podName,
 		IP:                ip,
@@ -661,7 +1073,7 @@ func (tkmm *tikvMemberManager) getTiKVStore(store *pdapi.StoreInfo) *v1alpha1.Ti
 	}
 }
 
-func (tkmm *tikvMemberManager) setStoreLabelsForTiKV(tc *v1alpha1.TikvCluster) (int, error) {
+func (tkmm *tikvMemberManager) setStoreLabelsForTiKV(tc *v1beta1.TikvCluster) (int, error) {
 	ns := tc.GetNamespace()
 	// for unit test
 	setCount := 0
@@ -764,7 +1176,7 @@ func (tkmm *tikvMemberManager) storeLabelsEqualNodeLabels(storeLabels []*metapb.
 	return reflect.DeepEqual(ls, nodeLabels)
 }
 
-func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi.PDControlInterface, set *apps.StatefulSet, tc *v1alpha1.TikvCluster) (bool, error) {
+func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi.PDControlInterface, set *apps.StatefulSet, tc *v1beta1.TikvCluster) (bool, error) {
 	if statefulSetIsUpgrading(set) {
 		return true, nil
 	}
@@ -802,7 +1214,7 @@ func (ftmm *FakeTiKVMemberManager) SetSyncError(err error) {
 	ftmm.err = err
 }
 
-func (ftmm *FakeTiKVMemberManager) Sync(tc *v1alpha1.TikvCluster) error {
+func (ftmm *FakeTiKVMemberManager) Sync(tc *v1beta1.TikvCluster) error {
 	if ftmm.err != nil {
 		return ftmm.err
 	}
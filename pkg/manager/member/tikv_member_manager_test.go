@@ -14,6 +14,7 @@
 package member
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -22,31 +23,35 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/metapb"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
 	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
+	utiltikvcluster "github.com/tikv/tikv-operator/pkg/util/tikvcluster"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	kubeinformers "k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestTiKVMemberManagerSyncCreate(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                         string
-		prepare                      func(cluster *v1alpha1.TikvCluster)
+		prepare                      func(cluster *v1beta1.TikvCluster)
 		errWhenCreateStatefulSet     bool
 		errWhenCreateTiKVPeerService bool
 		errWhenGetStores             bool
@@ -61,7 +66,7 @@ func TestTiKVMemberManagerSyncCreate(t *testing.T) {
 		t.Log(test.name)
 
 		tc := newTikvClusterForPD()
-		tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		tc.Status.PD.Members = map[string]v1beta1.PDMember{
 			"pd-0": {Name: "pd-0", Health: true},
 			"pd-1": {Name: "pd-1", Health: true},
 			"pd-2": {Name: "pd-2", Health: true},
@@ -77,8 +82,8 @@ func TestTiKVMemberManagerSyncCreate(t *testing.T) {
 
 		tkmm, fakeSetControl, fakeSvcControl, pdClient, _, _ := newFakeTiKVMemberManager(tc)
 		pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
-			return &v1alpha1.PDConfig{
-				Replication: &v1alpha1.PDReplicationConfig{
+			return &v1beta1.PDConfig{
+				Replication: &v1beta1.PDReplicationConfig{
 					LocationLabels: []string{"region", "zone", "rack", "host"},
 				},
 			}, nil
@@ -146,8 +151,8 @@ func TestTiKVMemberManagerSyncCreate(t *testing.T) {
 		},
 		{
 			name: "pd is not available",
-			prepare: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Members = map[string]v1alpha1.PDMember{}
+			prepare: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Members = map[string]v1beta1.PDMember{}
 			},
 			errWhenCreateStatefulSet:     false,
 			errWhenCreateTiKVPeerService: false,
@@ -190,7 +195,7 @@ func TestTiKVMemberManagerSyncUpdate(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                         string
-		modify                       func(cluster *v1alpha1.TikvCluster)
+		modify                       func(cluster *v1beta1.TikvCluster)
 		pdStores                     *pdapi.StoresInfo
 		tombstoneStores              *pdapi.StoresInfo
 		errWhenUpdateStatefulSet     bool
@@ -200,14 +205,14 @@ func TestTiKVMemberManagerSyncUpdate(t *testing.T) {
 		err                          bool
 		expectTiKVPeerServiceFn      func(*GomegaWithT, *corev1.Service, error)
 		expectStatefulSetFn          func(*GomegaWithT, *apps.StatefulSet, error)
-		expectTikvClusterFn          func(*GomegaWithT, *v1alpha1.TikvCluster)
+		expectTikvClusterFn          func(*GomegaWithT, *v1beta1.TikvCluster)
 	}
 
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
 
 		tc := newTikvClusterForPD()
-		tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		tc.Status.PD.Members = map[string]v1beta1.PDMember{
 			"pd-0": {Name: "pd-0", Health: true},
 			"pd-1": {Name: "pd-1", Health: true},
 			"pd-2": {Name: "pd-2", Health: true},
@@ -294,9 +299,9 @@ func TestTiKVMemberManagerSyncUpdate(t *testing.T) {
 	tests := []testcase{
 		{
 			name: "normal",
-			modify: func(tc *v1alpha1.TikvCluster) {
+			modify: func(tc *v1beta1.TikvCluster) {
 				tc.Spec.TiKV.Replicas = 5
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.PD.Phase = v1beta1.NormalPhase
 			},
 			// TODO add unit test for status sync
 			pdStores:                     &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
@@ -310,17 +315,17 @@ func TestTiKVMemberManagerSyncUpdate(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 				g.Expect(int(*set.Spec.Replicas)).To(Equal(4))
 			},
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.TiKV.StatefulSet.ObservedGeneration).To(Equal(int64(1)))
-				g.Expect(tc.Status.TiKV.Stores).To(Equal(map[string]v1alpha1.TiKVStore{}))
-				g.Expect(tc.Status.TiKV.TombstoneStores).To(Equal(map[string]v1alpha1.TiKVStore{}))
+				g.Expect(tc.Status.TiKV.Stores).To(Equal(map[string]v1beta1.TiKVStore{}))
+				g.Expect(tc.Status.TiKV.TombstoneStores).To(Equal(map[string]v1beta1.TiKVStore{}))
 			},
 		},
 		{
 			name: "error when update statefulset",
-			modify: func(tc *v1alpha1.TikvCluster) {
+			modify: func(tc *v1beta1.TikvCluster) {
 				tc.Spec.TiKV.Replicas = 5
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.PD.Phase = v1beta1.NormalPhase
 			},
 			pdStores:                     &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
 			tombstoneStores:              &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
@@ -334,9 +339,9 @@ func TestTiKVMemberManagerSyncUpdate(t *testing.T) {
 		},
 		{
 			name: "error when sync tikv status",
-			modify: func(tc *v1alpha1.TikvCluster) {
+			modify: func(tc *v1beta1.TikvCluster) {
 				tc.Spec.TiKV.Replicas = 5
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.PD.Phase = v1beta1.NormalPhase
 			},
 			pdStores:                     &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
 			tombstoneStores:              &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
@@ -349,10 +354,47 @@ func TestTiKVMemberManagerSyncUpdate(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 				g.Expect(int(*set.Spec.Replicas)).To(Equal(3))
 			},
-			expectTikvClusterFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(0))
 			},
 		},
+		{
+			name: "scale in waits for the maintenance window",
+			modify: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Replicas = 2
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.Maintenance = &v1beta1.MaintenanceStatus{InWindow: false}
+			},
+			pdStores:        &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
+			tombstoneStores: &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
+			err:             false,
+			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(int(*set.Spec.Replicas)).To(Equal(3))
+			},
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.Maintenance.PendingOperations).To(ConsistOf(v1beta1.TiKVScaleInOperation))
+			},
+		},
+		{
+			name: "upgrade waits for the maintenance window",
+			modify: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Version = "v2"
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.Maintenance = &v1beta1.MaintenanceStatus{InWindow: false}
+			},
+			pdStores:        &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
+			tombstoneStores: &pdapi.StoresInfo{Count: 0, Stores: []*pdapi.StoreInfo{}},
+			err:             false,
+			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(set.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-test-image:v1"))
+			},
+			expectTikvClusterFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.Maintenance.PendingOperations).To(ConsistOf(v1beta1.TiKVUpgradeOperation))
+				g.Expect(tc.Status.TiKV.Phase).NotTo(Equal(v1beta1.UpgradePhase))
+			},
+		},
 	}
 
 	for i := range tests {
@@ -362,6 +404,106 @@ func TestTiKVMemberManagerSyncUpdate(t *testing.T) {
 	}
 }
 
+// TestTiKVMemberManagerScaleInBySubresource checks that the scale subresource, which only
+// writes .spec.tikv.replicas, scales in TiKV through tikvScaler: the store is deleted from
+// PD first and the StatefulSet is shrunk only after the store becomes tombstone
+func TestTiKVMemberManagerScaleInBySubresource(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	tc.Spec.TiKV.Replicas = 4
+	tc.Status.PD.Members = map[string]v1beta1.PDMember{
+		"pd-0": {Name: "pd-0", Health: true},
+		"pd-1": {Name: "pd-1", Health: true},
+		"pd-2": {Name: "pd-2", Health: true},
+	}
+	tc.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+	tc.Status.PD.Phase = v1beta1.NormalPhase
+
+	tkmm, fakeSetControl, _, pdClient, podIndexer, _ := newFakeTiKVMemberManager(tc)
+	pvcInformer := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0).Core().V1().PersistentVolumeClaims()
+	pvcControl := controller.NewFakePVCControl(pvcInformer)
+	tkmm.tikvScaler = &tikvScaler{generalScaler{tkmm.pdControl, pvcInformer.Lister(), pvcControl}, tkmm.podLister, record.NewFakeRecorder(10)}
+	fakeSetControl.SetStatusChange(func(set *apps.StatefulSet) {
+		set.Status.Replicas = *set.Spec.Replicas
+		set.Status.CurrentRevision = "tikv-1"
+		set.Status.UpdateRevision = "tikv-1"
+		set.Status.ObservedGeneration = 1
+	})
+
+	tombstone := false
+	deleted := []uint64{}
+	storeInfo := func(ordinal int, state string) *pdapi.StoreInfo {
+		store := newStoreInfo(uint64(ordinal+1), state, 100, 90)
+		store.Store.Address = fmt.Sprintf("%s-tikv-%d.%s-tikv-peer.%s.svc:20160", tc.Name, ordinal, tc.Name, tc.Namespace)
+		store.Status.LastHeartbeatTS = time.Now()
+		return store
+	}
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		storesInfo := &pdapi.StoresInfo{}
+		for ordinal := 0; ordinal < 4; ordinal++ {
+			if ordinal == 3 && tombstone {
+				continue
+			}
+			storesInfo.Stores = append(storesInfo.Stores, storeInfo(ordinal, v1beta1.TiKVStateUp))
+		}
+		return storesInfo, nil
+	})
+	pdClient.AddReaction(pdapi.GetTombStoneStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		storesInfo := &pdapi.StoresInfo{}
+		if tombstone {
+			storesInfo.Stores = append(storesInfo.Stores, storeInfo(3, v1beta1.TiKVStateTombstone))
+		}
+		return storesInfo, nil
+	})
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{Replication: &pdapi.PDReplicationConfig{}}, nil
+	})
+	pdClient.AddReaction(pdapi.SetStoreLabelsActionType, func(action *pdapi.Action) (interface{}, error) {
+		return true, nil
+	})
+	pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+		deleted = append(deleted, action.ID)
+		return nil, nil
+	})
+
+	g.Expect(tkmm.Sync(tc)).To(Succeed())
+	set, err := tkmm.setLister.StatefulSets(tc.Namespace).Get(controller.TiKVMemberName(tc.Name))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*set.Spec.Replicas).To(Equal(int32(4)))
+	for ordinal := 0; ordinal < 4; ordinal++ {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      TikvPodName(tc.Name, int32(ordinal)),
+				Namespace: tc.Namespace,
+				Labels:    map[string]string{label.StoreIDLabelKey: fmt.Sprintf("%d", ordinal+1)},
+			},
+		}
+		readyPodFunc(pod)
+		podIndexer.Add(pod)
+	}
+	pvcInformer.Informer().GetIndexer().Add(newScaleInPVCForStatefulSet(set, v1beta1.TiKVMemberType, tc.Name))
+	// sync the status of the stores
+	g.Expect(tkmm.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.TiKV.Stores).To(HaveLen(4))
+	g.Expect(tc.Status.TiKV.Replicas).To(Equal(int32(4)))
+
+	// kubectl scale tc/test --replicas=3
+	tc.Spec.TiKV.Replicas = 3
+	err = tkmm.Sync(tc)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(deleted).To(Equal([]uint64{4}))
+	set, err = tkmm.setLister.StatefulSets(tc.Namespace).Get(controller.TiKVMemberName(tc.Name))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*set.Spec.Replicas).To(Equal(int32(4)))
+
+	tombstone = true
+	g.Expect(tkmm.Sync(tc)).To(Succeed())
+	set, err = tkmm.setLister.StatefulSets(tc.Namespace).Get(controller.TiKVMemberName(tc.Name))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*set.Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(deleted).To(Equal([]uint64{4}))
+}
+
 func TestTiKVMemberManagerTiKVStatefulSetIsUpgrading(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
@@ -392,7 +534,7 @@ func TestTiKVMemberManagerTiKVStatefulSetIsUpgrading(t *testing.T) {
 		if test.hasPod {
 			pod := &corev1.Pod{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:        ordinalPodName(v1alpha1.TiKVMemberType, tc.GetName(), 0),
+					Name:        ordinalPodName(v1beta1.TiKVMemberType, tc.GetName(), 0),
 					Namespace:   metav1.NamespaceDefault,
 					Annotations: map[string]string{},
 					Labels:      label.New().Instance(tc.GetInstanceName()).TiKV().Labels(),
@@ -783,14 +925,14 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 	g := NewGomegaWithT(t)
 	type testcase struct {
 		name                      string
-		updateTC                  func(*v1alpha1.TikvCluster)
-		upgradingFn               func(corelisters.PodLister, pdapi.PDControlInterface, *apps.StatefulSet, *v1alpha1.TikvCluster) (bool, error)
+		updateTC                  func(*v1beta1.TikvCluster)
+		upgradingFn               func(corelisters.PodLister, pdapi.PDControlInterface, *apps.StatefulSet, *v1beta1.TikvCluster) (bool, error)
 		errWhenGetStores          bool
 		storeInfo                 *pdapi.StoresInfo
 		errWhenGetTombstoneStores bool
 		tombstoneStoreInfo        *pdapi.StoresInfo
 		errExpectFn               func(*GomegaWithT, error)
-		tcExpectFn                func(*GomegaWithT, *v1alpha1.TikvCluster)
+		tcExpectFn                func(*GomegaWithT, *v1beta1.TikvCluster)
 	}
 	status := apps.StatefulSetStatus{
 		Replicas: int32(3),
@@ -798,7 +940,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 	now := metav1.Time{Time: time.Now()}
 	testFn := func(test *testcase, t *testing.T) {
 		tc := newTikvClusterForPD()
-		tc.Status.PD.Phase = v1alpha1.NormalPhase
+		tc.Status.PD.Phase = v1beta1.NormalPhase
 		set := &apps.StatefulSet{
 			Status: status,
 		}
@@ -841,7 +983,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		{
 			name:     "whether statefulset is upgrading returns failed",
 			updateTC: nil,
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, fmt.Errorf("whether upgrading failed")
 			},
 			errWhenGetStores:          false,
@@ -852,14 +994,35 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "whether upgrading failed")).To(BeTrue())
 			},
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.TiKV.StatefulSet.Replicas).To(Equal(int32(3)))
+			},
+		},
+		{
+			name: "replicas exclude the failover replicas",
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.Replicas = 2
+				tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
+					"1": {PodName: "test-tikv-1", StoreID: "1"},
+					"2": {PodName: "test-tikv-0", StoreID: "2", Replace: true},
+				}
+			},
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
+				return false, nil
+			},
+			errWhenGetStores: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.TiKV.StatefulSet.Replicas).To(Equal(int32(3)))
+				g.Expect(tc.Status.TiKV.Replicas).To(Equal(int32(2)))
 			},
 		},
 		{
 			name:     "statefulset is upgrading",
 			updateTC: nil,
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return true, nil
 			},
 			errWhenGetStores:          false,
@@ -867,17 +1030,17 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 			errWhenGetTombstoneStores: false,
 			tombstoneStoreInfo:        nil,
 			errExpectFn:               nil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.TiKV.StatefulSet.Replicas).To(Equal(int32(3)))
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.UpgradePhase))
 			},
 		},
 		{
 			name: "statefulset is upgrading but pd is upgrading",
-			updateTC: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.UpgradePhase
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.UpgradePhase
 			},
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return true, nil
 			},
 			errWhenGetStores:          false,
@@ -885,15 +1048,15 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 			errWhenGetTombstoneStores: false,
 			tombstoneStoreInfo:        nil,
 			errExpectFn:               nil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.TiKV.StatefulSet.Replicas).To(Equal(int32(3)))
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.NormalPhase))
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.NormalPhase))
 			},
 		},
 		{
 			name:     "statefulset is not upgrading",
 			updateTC: nil,
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores:          false,
@@ -901,15 +1064,16 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 			errWhenGetTombstoneStores: false,
 			tombstoneStoreInfo:        nil,
 			errExpectFn:               nil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.TiKV.StatefulSet.Replicas).To(Equal(int32(3)))
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.NormalPhase))
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.NormalPhase))
+				g.Expect(tc.Status.TiKV.Selector).To(Equal("app.kubernetes.io/component=tikv,app.kubernetes.io/instance=test,app.kubernetes.io/managed-by=tikv-operator,app.kubernetes.io/name=tikv-cluster"))
 			},
 		},
 		{
 			name:     "get stores failed",
 			updateTC: nil,
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores:          true,
@@ -920,7 +1084,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "failed to get stores")).To(BeTrue())
 			},
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(tc.Status.TiKV.StatefulSet.Replicas).To(Equal(int32(3)))
 				g.Expect(tc.Status.TiKV.Synced).To(BeFalse())
 			},
@@ -928,7 +1092,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		{
 			name:     "stores is empty",
 			updateTC: nil,
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -940,7 +1104,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(0))
 				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(0))
 				g.Expect(tc.Status.TiKV.Synced).To(BeTrue())
@@ -949,7 +1113,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		{
 			name:     "store is nil",
 			updateTC: nil,
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -965,7 +1129,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(0))
 				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(0))
 				g.Expect(tc.Status.TiKV.Synced).To(BeTrue())
@@ -974,7 +1138,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		{
 			name:     "status is nil",
 			updateTC: nil,
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -990,22 +1154,119 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(0))
 				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(0))
 				g.Expect(tc.Status.TiKV.Synced).To(BeTrue())
 			},
 		},
+		{
+			name: "external stores are managed",
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Spec.TiKV.ManageExternalStores = true
+			},
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
+				return false, nil
+			},
+			errWhenGetStores: false,
+			storeInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      333,
+								Address: fmt.Sprintf("%s-tikv-1.%s-tikv-peer.%s.svc:20160", "test", "test", "default"),
+							},
+							StateName: "Up",
+						},
+						Status: &pdapi.StoreStatus{
+							LastHeartbeatTS: time.Now(),
+						},
+					},
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      1,
+								Address: "10.0.1.1:20160",
+							},
+							StateName: "Up",
+						},
+						Status: &pdapi.StoreStatus{
+							LastHeartbeatTS: time.Now(),
+						},
+					},
+				},
+			},
+			errWhenGetTombstoneStores: false,
+			tombstoneStoreInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      2,
+								Address: "10.0.1.2:20160",
+							},
+							StateName: "Tombstone",
+						},
+						Status: &pdapi.StoreStatus{
+							LastHeartbeatTS: time.Now(),
+						},
+					},
+				},
+			},
+			errExpectFn: errExpectNil,
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
+				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(0))
+				g.Expect(len(tc.Status.TiKV.ExternalStores)).To(Equal(2))
+				g.Expect(tc.Status.TiKV.ExternalStores["1"].State).To(Equal(v1beta1.TiKVStateUp))
+				g.Expect(tc.Status.TiKV.ExternalStores["1"].IP).To(Equal("10.0.1.1"))
+				g.Expect(tc.Status.TiKV.ExternalStores["1"].PodName).To(BeEmpty())
+				g.Expect(tc.Status.TiKV.ExternalStores["2"].State).To(Equal(v1beta1.TiKVStateTombstone))
+			},
+		},
+		{
+			name: "external stores are not managed",
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
+				return false, nil
+			},
+			errWhenGetStores: false,
+			storeInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					{
+						Store: &pdapi.MetaStore{
+							Store: &metapb.Store{
+								Id:      1,
+								Address: "10.0.1.1:20160",
+							},
+							StateName: "Up",
+						},
+						Status: &pdapi.StoreStatus{
+							LastHeartbeatTS: time.Now(),
+						},
+					},
+				},
+			},
+			errWhenGetTombstoneStores: false,
+			tombstoneStoreInfo: &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{},
+			},
+			errExpectFn: errExpectNil,
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(0))
+				g.Expect(tc.Status.TiKV.ExternalStores).To(BeNil())
+			},
+		},
 		{
 			name: "LastHeartbeatTS is zero, TikvClulster LastHeartbeatTS is not zero",
-			updateTC: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
-				tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+				tc.Status.TiKV.Stores["333"] = v1beta1.TiKVStore{
 					LastHeartbeatTime: now,
 				}
 			},
 
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -1040,7 +1301,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(time.Time{}.IsZero()).To(BeTrue())
 				g.Expect(tc.Status.TiKV.Stores["333"].LastHeartbeatTime.Time.IsZero()).To(BeFalse())
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
@@ -1050,13 +1311,13 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		},
 		{
 			name: "LastHeartbeatTS is zero, TikvClulster LastHeartbeatTS is zero",
-			updateTC: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
-				tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+				tc.Status.TiKV.Stores["333"] = v1beta1.TiKVStore{
 					LastHeartbeatTime: metav1.Time{Time: time.Time{}},
 				}
 			},
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -1091,7 +1352,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(time.Time{}.IsZero()).To(BeTrue())
 				g.Expect(tc.Status.TiKV.Stores["333"].LastHeartbeatTime.Time.IsZero()).To(BeTrue())
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
@@ -1101,13 +1362,13 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		},
 		{
 			name: "LastHeartbeatTS is not zero, TikvClulster LastHeartbeatTS is zero",
-			updateTC: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
-				tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+				tc.Status.TiKV.Stores["333"] = v1beta1.TiKVStore{
 					LastHeartbeatTime: metav1.Time{Time: time.Time{}},
 				}
 			},
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -1142,7 +1403,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(time.Time{}.IsZero()).To(BeTrue())
 				g.Expect(tc.Status.TiKV.Stores["333"].LastHeartbeatTime.Time.IsZero()).To(BeFalse())
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
@@ -1152,11 +1413,11 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		},
 		{
 			name: "set LastTransitionTime first time",
-			updateTC: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
-				// tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{}
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+				// tc.Status.TiKV.Stores["333"] = v1beta1.TiKVStore{}
 			},
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -1191,7 +1452,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
 				g.Expect(tc.Status.TiKV.Stores["333"].LastTransitionTime.Time.IsZero()).To(BeFalse())
 				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(0))
@@ -1200,14 +1461,14 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		},
 		{
 			name: "state not change, LastTransitionTime not change",
-			updateTC: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
-				tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+				tc.Status.TiKV.Stores["333"] = v1beta1.TiKVStore{
 					LastTransitionTime: now,
-					State:              v1alpha1.TiKVStateUp,
+					State:              v1beta1.TiKVStateUp,
 				}
 			},
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -1243,7 +1504,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
 				g.Expect(tc.Status.TiKV.Stores["333"].LastTransitionTime).To(Equal(now))
 				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(0))
@@ -1252,14 +1513,14 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		},
 		{
 			name: "state change, LastTransitionTime change",
-			updateTC: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
-				tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+				tc.Status.TiKV.Stores["333"] = v1beta1.TiKVStore{
 					LastTransitionTime: now,
-					State:              v1alpha1.TiKVStateUp,
+					State:              v1beta1.TiKVStateUp,
 				}
 			},
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -1295,7 +1556,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				Stores: []*pdapi.StoreInfo{},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
 				g.Expect(tc.Status.TiKV.Stores["333"].LastTransitionTime).NotTo(Equal(now))
 				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(0))
@@ -1304,14 +1565,14 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		},
 		{
 			name: "get tombstone stores failed",
-			updateTC: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
-				tc.Status.TiKV.Stores["333"] = v1alpha1.TiKVStore{
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+				tc.Status.TiKV.Stores["333"] = v1beta1.TiKVStore{
 					LastTransitionTime: now,
-					State:              v1alpha1.TiKVStateUp,
+					State:              v1beta1.TiKVStateUp,
 				}
 			},
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -1350,7 +1611,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(strings.Contains(err.Error(), "failed to get tombstone stores")).To(BeTrue())
 			},
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
 				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(0))
 				g.Expect(tc.Status.TiKV.Synced).To(BeFalse())
@@ -1359,7 +1620,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 		{
 			name:     "all works",
 			updateTC: nil,
-			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1alpha1.TikvCluster) (bool, error) {
+			upgradingFn: func(lister corelisters.PodLister, controlInterface pdapi.PDControlInterface, set *apps.StatefulSet, cluster *v1beta1.TikvCluster) (bool, error) {
 				return false, nil
 			},
 			errWhenGetStores: false,
@@ -1420,7 +1681,7 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 				},
 			},
 			errExpectFn: errExpectNil,
-			tcExpectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster) {
+			tcExpectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
 				g.Expect(len(tc.Status.TiKV.Stores)).To(Equal(1))
 				g.Expect(len(tc.Status.TiKV.TombstoneStores)).To(Equal(1))
 				g.Expect(tc.Status.TiKV.Synced).To(BeTrue())
@@ -1434,7 +1695,420 @@ func TestTiKVMemberManagerSyncTikvClusterStatus(t *testing.T) {
 	}
 }
 
-func newFakeTiKVMemberManager(tc *v1alpha1.TikvCluster) (
+func TestTiKVMemberManagerDeleteExternalStores(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name             string
+		manage           bool
+		annotation       string
+		deleteStoreErr   bool
+		upStores         int
+		blocked          bool
+		errExpectFn      func(*GomegaWithT, error)
+		expectDeletedIDs []uint64
+		expectCondition  corev1.ConditionStatus
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTikvClusterForPD()
+		tc.Spec.TiKV.ManageExternalStores = test.manage
+		if test.annotation != "" {
+			tc.Annotations = map[string]string{label.AnnTiKVDeleteExternalStores: test.annotation}
+		}
+		tc.Status.TiKV.ExternalStores = map[string]v1beta1.TiKVStore{
+			"1": {ID: "1", IP: "10.0.1.1", State: v1beta1.TiKVStateUp},
+			"2": {ID: "2", IP: "10.0.1.2", State: v1beta1.TiKVStateOffline},
+		}
+		tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+			"333": {ID: "333", PodName: "test-tikv-0", State: v1beta1.TiKVStateUp},
+		}
+		if test.blocked {
+			cond := utiltikvcluster.NewTikvClusterCondition(v1beta1.ExternalStoresDeletionBlocked, corev1.ConditionTrue, utiltikvcluster.ScaleInPreflightCheckFailed, "")
+			utiltikvcluster.SetTikvClusterCondition(&tc.Status, *cond)
+		}
+		tkmm, _, _, pdClient, _, _ := newFakeTiKVMemberManager(tc)
+		addHealthyStoresReactions(pdClient, test.upStores)
+		var deletedIDs []uint64
+		pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			if test.deleteStoreErr {
+				return nil, fmt.Errorf("delete store failed")
+			}
+			deletedIDs = append(deletedIDs, action.ID)
+			return nil, nil
+		})
+
+		err := tkmm.deleteExternalStores(tc)
+		test.errExpectFn(g, err)
+		g.Expect(deletedIDs).To(Equal(test.expectDeletedIDs))
+		cond := utiltikvcluster.GetTikvClusterCondition(tc.Status, v1beta1.ExternalStoresDeletionBlocked)
+		if test.expectCondition == "" {
+			g.Expect(cond).To(BeNil())
+		} else {
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(test.expectCondition))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:        "external stores are not managed",
+			manage:      false,
+			annotation:  "[1]",
+			errExpectFn: errExpectNil,
+		},
+		{
+			name:        "no annotation",
+			manage:      true,
+			errExpectFn: errExpectNil,
+		},
+		{
+			name:       "invalid annotation",
+			manage:     true,
+			annotation: "1,2",
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+		},
+		{
+			name:             "delete the external stores which are not offline",
+			manage:           true,
+			annotation:       "[1,2,333]",
+			upStores:         4,
+			errExpectFn:      errExpectNil,
+			expectDeletedIDs: []uint64{1},
+			expectCondition:  corev1.ConditionFalse,
+		},
+		{
+			name:            "the remaining stores are fewer than max-replicas",
+			manage:          true,
+			annotation:      "[1]",
+			upStores:        3,
+			errExpectFn:     errExpectNil,
+			expectCondition: corev1.ConditionTrue,
+		},
+		{
+			name:            "the blocked condition is cleared once the annotation is removed",
+			manage:          true,
+			blocked:         true,
+			errExpectFn:     errExpectNil,
+			expectCondition: corev1.ConditionFalse,
+		},
+		{
+			name:           "delete store failed",
+			manage:         true,
+			annotation:     "[1]",
+			deleteStoreErr: true,
+			upStores:       4,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "delete store failed")).To(BeTrue())
+			},
+			expectCondition: corev1.ConditionFalse,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVMemberManagerEndEvictLeaderOfRecreatedPods(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name          string
+		state         string
+		annotated     bool
+		marked        bool
+		phase         corev1.PodPhase
+		schedulers    []string
+		expectEndIDs  []uint64
+		expectGetCall bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTikvClusterForPD()
+		tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+			"1": {ID: "1", PodName: "test-tikv-0", State: test.state},
+		}
+		tkmm, _, _, pdClient, podIndexer, _ := newFakeTiKVMemberManager(tc)
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "tikv-test-tikv-0", Namespace: tc.GetNamespace()},
+		}
+		if test.marked {
+			pvc.Annotations = map[string]string{label.AnnPVCEvictLeaderStore: "1"}
+		}
+		pvcIndexer := tkmm.pvcControl.(*controller.FakePVCControl).PVCIndexer
+		g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-tikv-0", Namespace: tc.GetNamespace()},
+			Status:     corev1.PodStatus{Phase: test.phase},
+		}
+		if test.annotated {
+			pod.Annotations = map[string]string{label.AnnEvictLeaderBeginTime: time.Now().Format(time.RFC3339)}
+		}
+		g.Expect(podIndexer.Add(pod)).To(Succeed())
+
+		getCalled := false
+		pdClient.AddReaction(pdapi.GetEvictLeaderSchedulersActionType, func(action *pdapi.Action) (interface{}, error) {
+			getCalled = true
+			return test.schedulers, nil
+		})
+		var endIDs []uint64
+		pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			endIDs = append(endIDs, action.ID)
+			return nil, nil
+		})
+
+		g.Expect(tkmm.endEvictLeaderOfRecreatedPods(tc)).To(Succeed())
+		g.Expect(getCalled).To(Equal(test.expectGetCall))
+		g.Expect(endIDs).To(Equal(test.expectEndIDs))
+		pvc, err := tkmm.pvcLister.PersistentVolumeClaims(tc.GetNamespace()).Get("tikv-test-tikv-0")
+		g.Expect(err).NotTo(HaveOccurred())
+		_, marked := pvc.Annotations[label.AnnPVCEvictLeaderStore]
+		g.Expect(marked).To(Equal(test.marked && !test.expectGetCall))
+	}
+
+	tests := []testcase{
+		{
+			name:          "recreated pod is up",
+			state:         v1beta1.TiKVStateUp,
+			marked:        true,
+			phase:         corev1.PodRunning,
+			schedulers:    []string{"evict-leader-scheduler-1"},
+			expectEndIDs:  []uint64{1},
+			expectGetCall: true,
+		},
+		{
+			name:          "no evict leader scheduler",
+			state:         v1beta1.TiKVStateUp,
+			marked:        true,
+			phase:         corev1.PodRunning,
+			schedulers:    []string{"evict-leader-scheduler-2"},
+			expectGetCall: true,
+		},
+		{
+			// the store evicted by the node maintenance controller or kubectl-tikv is not marked
+			name:       "leaders are evicted by node maintenance",
+			state:      v1beta1.TiKVStateUp,
+			phase:      corev1.PodRunning,
+			schedulers: []string{"evict-leader-scheduler-1"},
+		},
+		{
+			name:       "pod is not recreated yet",
+			state:      v1beta1.TiKVStateUp,
+			annotated:  true,
+			marked:     true,
+			phase:      corev1.PodRunning,
+			schedulers: []string{"evict-leader-scheduler-1"},
+		},
+		{
+			name:       "store is not up",
+			state:      v1beta1.TiKVStateDown,
+			marked:     true,
+			phase:      corev1.PodRunning,
+			schedulers: []string{"evict-leader-scheduler-1"},
+		},
+		{
+			name:       "pod is not running",
+			state:      v1beta1.TiKVStateUp,
+			marked:     true,
+			phase:      corev1.PodPending,
+			schedulers: []string{"evict-leader-scheduler-1"},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVMemberManagerEndEvictLeaderOfHeldUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name string
+		// revisions and the beginners of the leader evictions of the pods by ordinal,
+		// "upgrader", "webhook" or empty if the leaders are not being evicted
+		revisions       []string
+		evictedBy       []string
+		expectEndIDs    []uint64
+		expectAnnotated []bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTikvClusterForPD()
+		tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "tikv-1", UpdateRevision: "tikv-2"}
+		tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+		tkmm, _, _, pdClient, podIndexer, _ := newFakeTiKVMemberManager(tc)
+		for i, revision := range test.revisions {
+			podName := TikvPodName(tc.GetName(), int32(i))
+			storeID := fmt.Sprintf("%d", i+1)
+			tc.Status.TiKV.Stores[storeID] = v1beta1.TiKVStore{ID: storeID, PodName: podName, State: v1beta1.TiKVStateUp}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: tc.GetNamespace(),
+					Labels:    map[string]string{apps.ControllerRevisionHashLabelKey: revision},
+				},
+			}
+			switch test.evictedBy[i] {
+			case "upgrader":
+				pod.Annotations = map[string]string{
+					label.AnnEvictLeaderBeginTime:  time.Now().Format(time.RFC3339),
+					label.AnnEvictLeaderByUpgrader: "true",
+				}
+			case "webhook":
+				pod.Annotations = map[string]string{label.AnnEvictLeaderBeginTime: time.Now().Format(time.RFC3339)}
+			}
+			g.Expect(podIndexer.Add(pod)).To(Succeed())
+		}
+		var endIDs []uint64
+		pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			endIDs = append(endIDs, action.ID)
+			return nil, nil
+		})
+
+		oldSet := &apps.StatefulSet{Spec: apps.StatefulSetSpec{Replicas: pointer.Int32Ptr(int32(len(test.revisions)))}}
+		g.Expect(tkmm.endEvictLeaderOfHeldUpgrade(tc, oldSet)).To(Succeed())
+		g.Expect(endIDs).To(Equal(test.expectEndIDs))
+		for i := range test.revisions {
+			pod, err := tkmm.podLister.Pods(tc.GetNamespace()).Get(TikvPodName(tc.GetName(), int32(i)))
+			g.Expect(err).NotTo(HaveOccurred())
+			_, annotated := pod.Annotations[label.AnnEvictLeaderBeginTime]
+			g.Expect(annotated).To(Equal(test.expectAnnotated[i]), "pod %d", i)
+			_, byUpgrader := pod.Annotations[label.AnnEvictLeaderByUpgrader]
+			g.Expect(byUpgrader).To(Equal(test.expectAnnotated[i] && test.evictedBy[i] == "upgrader"), "pod %d", i)
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:            "the next pod to upgrade is evicting leaders",
+			revisions:       []string{"tikv-1", "tikv-1", "tikv-2"},
+			evictedBy:       []string{"upgrader", "upgrader", ""},
+			expectEndIDs:    []uint64{2},
+			expectAnnotated: []bool{true, false, false},
+		},
+		{
+			name:            "the next pod to upgrade is evicting leaders by the pod eviction webhook",
+			revisions:       []string{"tikv-1", "tikv-1", "tikv-2"},
+			evictedBy:       []string{"upgrader", "webhook", ""},
+			expectAnnotated: []bool{true, true, false},
+		},
+		{
+			name:            "the next pod to upgrade is not evicting leaders",
+			revisions:       []string{"tikv-1", "tikv-1", "tikv-2"},
+			evictedBy:       []string{"upgrader", "", ""},
+			expectAnnotated: []bool{true, false, false},
+		},
+		{
+			name:            "all pods are upgraded",
+			revisions:       []string{"tikv-2", "tikv-2", "tikv-2"},
+			evictedBy:       []string{"", "", ""},
+			expectAnnotated: []bool{false, false, false},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVMemberManagerCreateClonedPVCs(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name        string
+		pdPhase     v1beta1.PDRecoveryPhase
+		replicas    int32
+		errExpectFn func(*GomegaWithT, error)
+		expectPVCs  map[string]string
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTikvClusterForPD()
+		tc.Spec.TiKV.DataSource = &v1beta1.TiKVDataSource{ClusterName: "src", SnapshotSet: "s1"}
+		tc.Status.PD.Recovery = &v1beta1.PDRecoveryStatus{Trigger: "clone/src/s1", Phase: test.pdPhase}
+
+		tkmm, _, _, _, _, _ := newFakeTiKVMemberManager(tc)
+		genericControl := controller.NewFakeGenericControl()
+		tkmm.typedControl = controller.NewTypedControl(genericControl)
+		snapshotControl := tkmm.snapshotControl.(*controller.FakeVolumeSnapshotControl)
+		for _, snapshot := range []*unstructured.Unstructured{
+			newTiKVSnapshotForPDRecovery("tikv-src-tikv-0-s1", "6", "10", "100000010", true),
+			newTiKVSnapshotForPDRecovery("tikv-src-tikv-1-s1", "6", "4", "100000010", true),
+		} {
+			g.Expect(snapshotControl.CreateVolumeSnapshot(tc, snapshot)).To(Succeed())
+		}
+
+		set := &apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-tikv", Namespace: corev1.NamespaceDefault},
+			Spec: apps.StatefulSetSpec{
+				Replicas: pointer.Int32Ptr(test.replicas),
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					volumeClaimTemplate(corev1.ResourceRequirements{}, v1beta1.TiKVMemberType.String(), nil),
+				},
+			},
+		}
+		err := tkmm.createClonedPVCs(tc, set)
+		test.errExpectFn(g, err)
+
+		for name, snapshotName := range test.expectPVCs {
+			pvc := &corev1.PersistentVolumeClaim{}
+			g.Expect(genericControl.FakeCli.Get(context.TODO(), client.ObjectKey{Namespace: corev1.NamespaceDefault, Name: name}, pvc)).To(Succeed())
+			g.Expect(pvc.Labels).To(Equal(labelTiKV(tc).Labels()))
+			g.Expect(pvc.Spec.DataSource.Kind).To(Equal("VolumeSnapshot"))
+			g.Expect(pvc.Spec.DataSource.Name).To(Equal(snapshotName))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:     "pd cluster is being cloned",
+			pdPhase:  v1beta1.PDRecoveryRestart,
+			replicas: 3,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+		},
+		{
+			name:     "pd cluster failed to be cloned",
+			pdPhase:  v1beta1.PDRecoveryFailed,
+			replicas: 3,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeFalse())
+			},
+		},
+		{
+			name:     "more snapshots than replicas",
+			pdPhase:  v1beta1.PDRecoveryRejoin,
+			replicas: 1,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("can't clone 2 stores to 1 replicas"))
+			},
+		},
+		{
+			name:        "pvcs are created in the order of store ids",
+			pdPhase:     v1beta1.PDRecoveryRejoin,
+			replicas:    3,
+			errExpectFn: errExpectNil,
+			expectPVCs: map[string]string{
+				"tikv-test-tikv-0": "tikv-src-tikv-1-s1",
+				"tikv-test-tikv-1": "tikv-src-tikv-0-s1",
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func newFakeTiKVMemberManager(tc *v1beta1.TikvCluster) (
 	*tikvMemberManager, *controller.FakeStatefulSetControl,
 	*controller.FakeServiceControl, *pdapi.FakePDClient, cache.Indexer, cache.Indexer) {
 	cli := fake.NewSimpleClientset()
@@ -1444,26 +2118,33 @@ func newFakeTiKVMemberManager(tc *v1alpha1.TikvCluster) (
 	setInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Apps().V1().StatefulSets()
 	svcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Services()
 	epsInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Endpoints()
-	tcInformer := informers.NewSharedInformerFactory(cli, 0).Tikv().V1alpha1().TikvClusters()
+	tcInformer := informers.NewSharedInformerFactory(cli, 0).Tikv().V1beta1().TikvClusters()
 	setControl := controller.NewFakeStatefulSetControl(setInformer, tcInformer)
 	svcControl := controller.NewFakeServiceControl(svcInformer, epsInformer, tcInformer)
 	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
 	nodeInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Nodes()
+	pvcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().PersistentVolumeClaims()
 	tikvScaler := NewFakeTiKVScaler()
 	tikvUpgrader := NewFakeTiKVUpgrader()
 	genericControl := controller.NewFakeGenericControl()
 
 	tmm := &tikvMemberManager{
-		pdControl:    pdControl,
-		podLister:    podInformer.Lister(),
-		nodeLister:   nodeInformer.Lister(),
-		setControl:   setControl,
-		svcControl:   svcControl,
-		typedControl: controller.NewTypedControl(genericControl),
-		setLister:    setInformer.Lister(),
-		svcLister:    svcInformer.Lister(),
-		tikvScaler:   tikvScaler,
-		tikvUpgrader: tikvUpgrader,
+		pdControl:          pdControl,
+		podLister:          podInformer.Lister(),
+		pvcLister:          pvcInformer.Lister(),
+		nodeLister:         nodeInformer.Lister(),
+		setControl:         setControl,
+		svcControl:         svcControl,
+		podControl:         controller.NewFakePodControl(podInformer),
+		typedControl:       controller.NewTypedControl(genericControl),
+		pvcControl:         controller.NewFakePVCControl(pvcInformer),
+		setLister:          setInformer.Lister(),
+		svcLister:          svcInformer.Lister(),
+		tikvScaler:         tikvScaler,
+		tikvUpgrader:       tikvUpgrader,
+		tikvUnsafeRecovery: NewFakeTiKVUnsafeRecovery(),
+		tikvSnapshotter:    NewFakeTiKVSnapshotter(),
+		snapshotControl:    controller.NewFakeVolumeSnapshotControl(),
 	}
 	tmm.tikvStatefulSetIsUpgradingFn = tikvStatefulSetIsUpgrading
 	return tmm, setControl, svcControl, pdClient, podInformer.Informer().GetIndexer(), nodeInformer.Informer().GetIndexer()
@@ -1472,13 +2153,13 @@ func newFakeTiKVMemberManager(tc *v1alpha1.TikvCluster) (
 func TestGetNewServiceForTikvCluster(t *testing.T) {
 	tests := []struct {
 		name      string
-		tc        v1alpha1.TikvCluster
+		tc        v1beta1.TikvCluster
 		svcConfig SvcConfig
 		expected  corev1.Service
 	}{
 		{
 			name: "basic",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
@@ -1503,7 +2184,7 @@ func TestGetNewServiceForTikvCluster(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -1552,13 +2233,13 @@ func TestGetNewTiKVSetForTikvCluster(t *testing.T) {
 	enable := true
 	tests := []struct {
 		name    string
-		tc      v1alpha1.TikvCluster
+		tc      v1beta1.TikvCluster
 		wantErr bool
 		testSts func(sts *apps.StatefulSet)
 	}{
 		{
 			name: "tikv network is not host",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
@@ -1568,14 +2249,14 @@ func TestGetNewTiKVSetForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "tikv network is host",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							HostNetwork: &enable,
 						},
 					},
@@ -1585,14 +2266,14 @@ func TestGetNewTiKVSetForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "tikv network is not host when pd is host",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					PD: v1alpha1.PDSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							HostNetwork: &enable,
 						},
 					},
@@ -1602,7 +2283,7 @@ func TestGetNewTiKVSetForTikvCluster(t *testing.T) {
 		},
 		{
 			name: "tikv delete slots",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
@@ -1610,21 +2291,21 @@ func TestGetNewTiKVSetForTikvCluster(t *testing.T) {
 						label.AnnTiKVDeleteSlots: "[0,1]",
 					},
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{},
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{},
 				},
 			},
 			testSts: testAnnotations(t, map[string]string{"delete-slots": "[0,1]"}),
 		},
 		{
 			name: "tikv should respect resources config",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
 						ResourceRequirements: corev1.ResourceRequirements{
 							Requests: corev1.ResourceList{
 								corev1.ResourceCPU:              resource.MustParse("1"),
@@ -1650,7 +2331,7 @@ func TestGetNewTiKVSetForTikvCluster(t *testing.T) {
 					},
 				}))
 				nameToContainer := MapContainers(&sts.Spec.Template.Spec)
-				tikvContainer := nameToContainer[v1alpha1.TiKVMemberType.String()]
+				tikvContainer := nameToContainer[v1beta1.TiKVMemberType.String()]
 				g.Expect(tikvContainer.Resources).To(Equal(corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
 						corev1.ResourceCPU:              resource.MustParse("1"),
@@ -1695,21 +2376,21 @@ func TestTiKVInitContainers(t *testing.T) {
 	asRoot := false
 	tests := []struct {
 		name             string
-		tc               v1alpha1.TikvCluster
+		tc               v1beta1.TikvCluster
 		wantErr          bool
 		expectedInit     []corev1.Container
 		expectedSecurity *corev1.PodSecurityContext
 	}{
 		{
 			name: "no init container",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							PodSecurityContext: &corev1.PodSecurityContext{
 								RunAsNonRoot: &asRoot,
 								Sysctls: []corev1.Sysctl{
@@ -1760,14 +2441,14 @@ func TestTiKVInitContainers(t *testing.T) {
 		},
 		{
 			name: "sysctl with init container",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							Annotations: map[string]string{
 								"tikv.org/sysctl-init": "true",
 							},
@@ -1817,14 +2498,14 @@ func TestTiKVInitContainers(t *testing.T) {
 		},
 		{
 			name: "sysctl with init container",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							Annotations: map[string]string{
 								"tikv.org/sysctl-init": "true",
 							},
@@ -1842,14 +2523,14 @@ func TestTiKVInitContainers(t *testing.T) {
 		},
 		{
 			name: "sysctl with init container",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							Annotations: map[string]string{
 								"tikv.org/sysctl-init": "true",
 							},
@@ -1863,14 +2544,14 @@ func TestTiKVInitContainers(t *testing.T) {
 		},
 		{
 			name: "sysctl without init container due to invalid annotation",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							Annotations: map[string]string{
 								"tikv.org/sysctl-init": "false",
 							},
@@ -1924,7 +2605,7 @@ func TestTiKVInitContainers(t *testing.T) {
 		},
 		{
 			name: "no init container no securityContext",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "tc",
 					Namespace: "ns",
@@ -1959,15 +2640,15 @@ func TestTiKVInitContainers(t *testing.T) {
 
 func TestGetTiKVConfigMap(t *testing.T) {
 	g := NewGomegaWithT(t)
-	updateStrategy := v1alpha1.ConfigUpdateStrategyInPlace
+	updateStrategy := v1beta1.ConfigUpdateStrategyInPlace
 	testCases := []struct {
 		name     string
-		tc       v1alpha1.TikvCluster
+		tc       v1beta1.TikvCluster
 		expected *corev1.ConfigMap
 	}{
 		{
 			name: "TiKV config is nil",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
@@ -1977,22 +2658,22 @@ func TestGetTiKVConfigMap(t *testing.T) {
 		},
 		{
 			name: "basic",
-			tc: v1alpha1.TikvCluster{
+			tc: v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "foo",
 					Namespace: "ns",
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
-						ComponentSpec: v1alpha1.ComponentSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
+						ComponentSpec: v1beta1.ComponentSpec{
 							ConfigUpdateStrategy: &updateStrategy,
 						},
-						Config: &v1alpha1.TiKVConfig{
-							Raftstore: &v1alpha1.TiKVRaftstoreConfig{
+						Config: &v1beta1.TiKVConfig{
+							Raftstore: &v1beta1.TiKVRaftstoreConfig{
 								SyncLog:              pointer.BoolPtr(false),
 								RaftBaseTickInterval: pointer.StringPtr("1s"),
 							},
-							Server: &v1alpha1.TiKVServerConfig{
+							Server: &v1beta1.TiKVServerConfig{
 								GrpcKeepaliveTimeout: pointer.StringPtr("30s"),
 							},
 						},
@@ -2011,7 +2692,7 @@ func TestGetTiKVConfigMap(t *testing.T) {
 					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
-							APIVersion: "tikv.org/v1alpha1",
+							APIVersion: "tikv.org/v1beta1",
 							Kind:       "TikvCluster",
 							Name:       "foo",
 							UID:        "",
@@ -2032,6 +2713,62 @@ func TestGetTiKVConfigMap(t *testing.T) {
 [raftstore]
   sync-log = false
   raft-base-tick-interval = "1s"
+`,
+				},
+			},
+		},
+		{
+			name: "external PD over https",
+			tc: v1beta1.TikvCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "ns",
+				},
+				Spec: v1beta1.TikvClusterSpec{
+					PD: v1beta1.PDSpec{
+						ExternalEndpoints:           []string{"https://10.0.1.1:2379"},
+						ExternalTLSClientSecretName: pointer.StringPtr("external-pd-client"),
+					},
+					TiKV: v1beta1.TiKVSpec{
+						Config: &v1beta1.TiKVConfig{
+							Security: &v1beta1.TiKVSecurityConfig{
+								CAPath: pointer.StringPtr("/etc/ca.crt"),
+							},
+						},
+					},
+				},
+			},
+			expected: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-tikv",
+					Namespace: "ns",
+					Labels: map[string]string{
+						"app.kubernetes.io/name":       "tikv-cluster",
+						"app.kubernetes.io/managed-by": "tikv-operator",
+						"app.kubernetes.io/instance":   "foo",
+						"app.kubernetes.io/component":  "tikv",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "tikv.org/v1beta1",
+							Kind:       "TikvCluster",
+							Name:       "foo",
+							UID:        "",
+							Controller: func(b bool) *bool {
+								return &b
+							}(true),
+							BlockOwnerDeletion: func(b bool) *bool {
+								return &b
+							}(true),
+						},
+					},
+				},
+				Data: map[string]string{
+					"startup-script": "",
+					"config-file": `[security]
+  ca-path = "/etc/ca.crt"
+  cert-path = "/var/lib/tikv-tls/tls.crt"
+  key-path = "/var/lib/tikv-tls/tls.key"
 `,
 				},
 			},
@@ -0,0 +1,99 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// checkScaleIn checks that the remaining stores can hold the data and the region replicas
+// once the deleting stores are deleted from the PD cluster:
+// - the used size of all stores stays under the high-water mark of the capacity of the remaining Up stores
+// - the remaining Up stores are not fewer than max-replicas
+// - the remaining Up stores span enough values of the top location label to isolate the replicas
+// The replica checks are skipped if the placement rules are enabled, max-replicas and
+// location-labels are not used then.
+func checkScaleIn(stores []*pdapi.StoreInfo, replication *pdapi.PDReplicationConfig, deleting map[uint64]bool, highWaterMarkPercent int32) error {
+	var used, capacity uint64
+	var upBefore, upAfter []*pdapi.StoreInfo
+	for _, store := range stores {
+		if store.Store == nil || store.Status == nil || store.Store.StateName == v1beta1.TiKVStateTombstone {
+			continue
+		}
+		// the data of the Offline and Down stores has to be moved to the remaining stores too
+		if store.Status.Capacity > store.Status.Available {
+			used += uint64(store.Status.Capacity - store.Status.Available)
+		}
+		if store.Store.StateName != v1beta1.TiKVStateUp {
+			continue
+		}
+		upBefore = append(upBefore, store)
+		if deleting[store.Store.GetId()] {
+			continue
+		}
+		upAfter = append(upAfter, store)
+		capacity += uint64(store.Status.Capacity)
+	}
+	if used*100 > capacity*uint64(highWaterMarkPercent) {
+		return fmt.Errorf("the used size %s would exceed %d%% of the capacity %s of the remaining Up stores",
+			bytesString(used), highWaterMarkPercent, bytesString(capacity))
+	}
+
+	if replication != nil && replication.EnablePlacementRules != nil && *replication.EnablePlacementRules {
+		return nil
+	}
+	maxReplicas := defaultMaxReplicas
+	if replication != nil && replication.MaxReplicas != nil {
+		maxReplicas = int(*replication.MaxReplicas)
+	}
+	if len(upAfter) < maxReplicas {
+		return fmt.Errorf("%d Up stores would remain, fewer than max-replicas %d", len(upAfter), maxReplicas)
+	}
+	if replication == nil || len(replication.LocationLabels) == 0 {
+		return nil
+	}
+	// the replicas are placed in the different values of the top location label first,
+	// they must stay isolated as well as before the scale-in
+	key := replication.LocationLabels[0]
+	required := locationValues(upBefore, key)
+	if required > maxReplicas {
+		required = maxReplicas
+	}
+	if remaining := locationValues(upAfter, key); remaining < required {
+		return fmt.Errorf("the remaining Up stores would span %d values of the location label %s, fewer than %d required by max-replicas %d",
+			remaining, key, required, maxReplicas)
+	}
+	return nil
+}
+
+// locationValues returns the number of the distinct values of the label among the stores
+func locationValues(stores []*pdapi.StoreInfo, key string) int {
+	values := map[string]bool{}
+	for _, store := range stores {
+		for _, l := range store.Store.GetLabels() {
+			if l.GetKey() == key {
+				values[l.GetValue()] = true
+			}
+		}
+	}
+	return len(values)
+}
+
+func bytesString(b uint64) string {
+	return resource.NewQuantity(int64(b), resource.BinarySI).String()
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	"k8s.io/utils/pointer"
+)
+
+func TestCheckScaleIn(t *testing.T) {
+	zoned := func(store *pdapi.StoreInfo, zone string) *pdapi.StoreInfo {
+		store.Store.Labels = []*metapb.StoreLabel{{Key: "zone", Value: zone}, {Key: "host", Value: "host"}}
+		return store
+	}
+	uint64Ptr := func(i uint64) *uint64 { return &i }
+	tests := []struct {
+		name          string
+		stores        []*pdapi.StoreInfo
+		replication   *pdapi.PDReplicationConfig
+		deleting      []uint64
+		highWaterMark int32
+		errContains   string
+	}{
+		{
+			name: "enough capacity and stores",
+			stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, v1beta1.TiKVStateUp, 100, 50),
+				newStoreInfo(2, v1beta1.TiKVStateUp, 100, 50),
+				newStoreInfo(3, v1beta1.TiKVStateUp, 100, 50),
+				newStoreInfo(4, v1beta1.TiKVStateUp, 100, 50),
+			},
+			deleting:      []uint64{4},
+			highWaterMark: 80,
+		},
+		{
+			name: "the data exceeds the high-water mark",
+			stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, v1beta1.TiKVStateUp, 100, 30),
+				newStoreInfo(2, v1beta1.TiKVStateUp, 100, 30),
+				newStoreInfo(3, v1beta1.TiKVStateUp, 100, 30),
+				newStoreInfo(4, v1beta1.TiKVStateUp, 100, 30),
+			},
+			deleting:      []uint64{4},
+			highWaterMark: 80,
+			errContains:   "would exceed 80% of the capacity",
+		},
+		{
+			name: "the data of the offline stores counts",
+			stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, v1beta1.TiKVStateUp, 100, 50),
+				newStoreInfo(2, v1beta1.TiKVStateUp, 100, 50),
+				newStoreInfo(3, v1beta1.TiKVStateUp, 100, 50),
+				newStoreInfo(4, v1beta1.TiKVStateUp, 100, 50),
+				newStoreInfo(5, v1beta1.TiKVStateOffline, 100, 10),
+			},
+			deleting:      []uint64{4},
+			highWaterMark: 80,
+			errContains:   "would exceed 80% of the capacity",
+		},
+		{
+			name: "fewer stores than max-replicas",
+			stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, v1beta1.TiKVStateUp, 100, 90),
+				newStoreInfo(2, v1beta1.TiKVStateUp, 100, 90),
+				newStoreInfo(3, v1beta1.TiKVStateUp, 100, 90),
+				newStoreInfo(4, "Down", 100, 90),
+			},
+			deleting:      []uint64{3},
+			highWaterMark: 80,
+			errContains:   "2 Up stores would remain, fewer than max-replicas 3",
+		},
+		{
+			name: "max-replicas is configured",
+			stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, v1beta1.TiKVStateUp, 100, 90),
+				newStoreInfo(2, v1beta1.TiKVStateUp, 100, 90),
+				newStoreInfo(3, v1beta1.TiKVStateUp, 100, 90),
+			},
+			replication:   &pdapi.PDReplicationConfig{MaxReplicas: uint64Ptr(1)},
+			deleting:      []uint64{2, 3},
+			highWaterMark: 80,
+		},
+		{
+			name: "placement rules are enabled",
+			stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, v1beta1.TiKVStateUp, 100, 90),
+				newStoreInfo(2, v1beta1.TiKVStateUp, 100, 90),
+				newStoreInfo(3, v1beta1.TiKVStateUp, 100, 90),
+			},
+			replication:   &pdapi.PDReplicationConfig{EnablePlacementRules: pointer.BoolPtr(true)},
+			deleting:      []uint64{3},
+			highWaterMark: 80,
+		},
+		{
+			name: "the zones stay satisfiable",
+			stores: []*pdapi.StoreInfo{
+				zoned(newStoreInfo(1, v1beta1.TiKVStateUp, 100, 90), "a"),
+				zoned(newStoreInfo(2, v1beta1.TiKVStateUp, 100, 90), "b"),
+				zoned(newStoreInfo(3, v1beta1.TiKVStateUp, 100, 90), "c"),
+				zoned(newStoreInfo(4, v1beta1.TiKVStateUp, 100, 90), "c"),
+			},
+			replication:   &pdapi.PDReplicationConfig{LocationLabels: pdapi.StringSlice{"zone", "host"}},
+			deleting:      []uint64{4},
+			highWaterMark: 80,
+		},
+		{
+			name: "the last store of a zone",
+			stores: []*pdapi.StoreInfo{
+				zoned(newStoreInfo(1, v1beta1.TiKVStateUp, 100, 90), "a"),
+				zoned(newStoreInfo(2, v1beta1.TiKVStateUp, 100, 90), "b"),
+				zoned(newStoreInfo(3, v1beta1.TiKVStateUp, 100, 90), "b"),
+				zoned(newStoreInfo(4, v1beta1.TiKVStateUp, 100, 90), "c"),
+			},
+			replication:   &pdapi.PDReplicationConfig{LocationLabels: pdapi.StringSlice{"zone", "host"}},
+			deleting:      []uint64{4},
+			highWaterMark: 80,
+			errContains:   "span 2 values of the location label zone, fewer than 3",
+		},
+		{
+			name: "the zones are not isolated before the scale-in",
+			stores: []*pdapi.StoreInfo{
+				zoned(newStoreInfo(1, v1beta1.TiKVStateUp, 100, 90), "a"),
+				zoned(newStoreInfo(2, v1beta1.TiKVStateUp, 100, 90), "a"),
+				zoned(newStoreInfo(3, v1beta1.TiKVStateUp, 100, 90), "b"),
+				zoned(newStoreInfo(4, v1beta1.TiKVStateUp, 100, 90), "b"),
+			},
+			replication:   &pdapi.PDReplicationConfig{LocationLabels: pdapi.StringSlice{"zone", "host"}},
+			deleting:      []uint64{4},
+			highWaterMark: 80,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			deleting := map[uint64]bool{}
+			for _, id := range tt.deleting {
+				deleting[id] = true
+			}
+			err := checkScaleIn(tt.stores, tt.replication, deleting, tt.highWaterMark)
+			if tt.errContains == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.errContains))
+			}
+		})
+	}
+}
+
+// newStoreInfo returns a store with the capacity and the available size in Gi
+func newStoreInfo(id uint64, state string, capacity, available uint64) *pdapi.StoreInfo {
+	return &pdapi.StoreInfo{
+		Store: &pdapi.MetaStore{
+			Store:     &metapb.Store{Id: id},
+			StateName: state,
+		},
+		Status: &pdapi.StoreStatus{
+			Capacity:  typeutil.ByteSize(capacity << 30),
+			Available: typeutil.ByteSize(available << 30),
+		},
+	}
+}
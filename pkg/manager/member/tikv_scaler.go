@@ -18,12 +18,15 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
+	utiltikvcluster "github.com/tikv/tikv-operator/pkg/util/tikvcluster"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 )
@@ -31,18 +34,26 @@ import (
 type tikvScaler struct {
 	generalScaler
 	podLister corelisters.PodLister
+	recorder  record.EventRecorder
 }
 
 // NewTiKVScaler returns a tikv Scaler
 func NewTiKVScaler(pdControl pdapi.PDControlInterface,
 	pvcLister corelisters.PersistentVolumeClaimLister,
 	pvcControl controller.PVCControlInterface,
-	podLister corelisters.PodLister) Scaler {
-	return &tikvScaler{generalScaler{pdControl, pvcLister, pvcControl}, podLister}
+	podLister corelisters.PodLister,
+	recorder record.EventRecorder) Scaler {
+	return &tikvScaler{generalScaler{pdControl, pvcLister, pvcControl}, podLister, recorder}
 }
 
-func (tsd *tikvScaler) Scale(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (tsd *tikvScaler) Scale(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	scaling, _, _, _ := scaleOne(oldSet, newSet)
+	if scaling >= 0 {
+		// the scale-in has completed or has been canceled
+		if cond := utiltikvcluster.GetTikvClusterCondition(tc.Status, v1beta1.TiKVScaleInBlocked); cond != nil && cond.Status == corev1.ConditionTrue {
+			setScaleInBlockedCondition(tc, corev1.ConditionFalse, utiltikvcluster.NoScaleIn, "TiKV is not scaling in")
+		}
+	}
 	if scaling > 0 {
 		return tsd.ScaleOut(tc, oldSet, newSet)
 	} else if scaling < 0 {
@@ -51,28 +62,33 @@ func (tsd *tikvScaler) Scale(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet,
 	return nil
 }
 
-func (tsd *tikvScaler) ScaleOut(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
-	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+func (tsd *tikvScaler) ScaleOut(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	_, ordinals, replicas, deleteSlots := scaleMulti(oldSet, newSet, int(tc.TiKVScaleOutParallelism()))
 	resetReplicas(newSet, oldSet)
 	if tc.TiKVUpgrading() {
 		return nil
 	}
 
-	klog.Infof("scaling out tikv statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
-	_, err := tsd.deleteDeferDeletingPVC(tc, oldSet.GetName(), v1alpha1.TiKVMemberType, ordinal)
-	if err != nil {
-		return err
+	klog.Infof("scaling out tikv statefulset %s/%s, ordinals: %v (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinals, replicas, deleteSlots.List())
+	for _, ordinal := range ordinals {
+		_, err := tsd.deleteDeferDeletingPVC(tc, oldSet.GetName(), v1beta1.TiKVMemberType, ordinal)
+		if err != nil {
+			return err
+		}
 	}
 
 	setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
 	return nil
 }
 
-func (tsd *tikvScaler) ScaleIn(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+// ScaleIn removes up to scaleInParallelism members at a time. The stores of all of them are
+// deleted from the PD cluster concurrently, but the pods are removed in the scale-in order,
+// so a member is only removed once the members before it can be removed safely.
+func (tsd *tikvScaler) ScaleIn(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	// we can only remove one member at a time when scaling in
-	_, ordinal, replicas, deleteSlots := scaleOne(oldSet, newSet)
+	desiredSet := newSet.DeepCopy()
+	_, ordinals, _, _ := scaleMulti(oldSet, desiredSet, int(tc.TiKVScaleInParallelism()))
 	resetReplicas(newSet, oldSet)
 	setName := oldSet.GetName()
 
@@ -83,12 +99,124 @@ func (tsd *tikvScaler) ScaleIn(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSe
 		return nil
 	}
 
-	klog.Infof("scaling in tikv statefulset %s/%s, ordinal: %d (replicas: %d, delete slots: %v)", oldSet.Namespace, oldSet.Name, ordinal, replicas, deleteSlots.List())
+	klog.Infof("scaling in tikv statefulset %s/%s, ordinals: %v", oldSet.Namespace, oldSet.Name, ordinals)
+	deletable, err := tsd.preflightScaleIn(tc, ordinals)
+	if err != nil {
+		return err
+	}
+	var pvcs []*corev1.PersistentVolumeClaim
+	var scaleInErr error
+	for _, ordinal := range ordinals {
+		pvc, err := tsd.prepareScaleIn(tc, setName, ordinal, deletable)
+		if err != nil {
+			if scaleInErr == nil {
+				scaleInErr = err
+			} else {
+				klog.Infof("tikv scale in: ordinal %d of %s/%s is not removable yet: %v", ordinal, ns, setName, err)
+			}
+			continue
+		}
+		if scaleInErr == nil {
+			pvcs = append(pvcs, pvc)
+		}
+	}
+	if len(pvcs) == 0 {
+		return scaleInErr
+	}
+
+	for _, pvc := range pvcs {
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		now := time.Now().Format(time.RFC3339)
+		pvc.Annotations[label.AnnPVCDeferDeleting] = now
+		_, err := tsd.pvcControl.UpdatePVC(tc, pvc)
+		if err != nil {
+			klog.Errorf("tikv scale in: failed to set pvc %s/%s annotation: %s to %s",
+				ns, pvc.Name, label.AnnPVCDeferDeleting, now)
+			return err
+		}
+		klog.Infof("tikv scale in: set pvc %s/%s annotation: %s to %s",
+			ns, pvc.Name, label.AnnPVCDeferDeleting, now)
+	}
+
+	_, _, replicas, deleteSlots := scaleMulti(oldSet, desiredSet, len(pvcs))
+	setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
+	if scaleInErr != nil {
+		klog.Infof("tikv scale in: removed %d of the ordinals %v of %s/%s, the others are not removable yet: %v",
+			len(pvcs), ordinals, ns, setName, scaleInErr)
+	}
+	return nil
+}
+
+// preflightScaleIn runs the pre-flight checks for the stores of the ordinals which have not been
+// deleted from the PD cluster. It returns the stores allowed to be deleted, which are the ones
+// in the scale-in order before the first store failing the checks.
+func (tsd *tikvScaler) preflightScaleIn(tc *v1beta1.TikvCluster, ordinals []int32) (map[uint64]bool, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	ids := []uint64{}
+	for _, ordinal := range ordinals {
+		podName := ordinalPodName(v1beta1.TiKVMemberType, tcName, ordinal)
+		for _, store := range tc.Status.TiKV.Stores {
+			if store.PodName != podName || store.State == v1beta1.TiKVStateOffline {
+				continue
+			}
+			id, err := strconv.ParseUint(store.ID, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+	}
+	deletable := map[uint64]bool{}
+	if len(ids) == 0 {
+		return deletable, nil
+	}
+
+	pdClient := controller.GetPDClient(tsd.pdControl, tc)
+	storesInfo, err := pdClient.GetStores()
+	if err != nil {
+		return nil, fmt.Errorf("tikv scale in: failed to get the stores of TikvCluster %s/%s, %v", ns, tcName, err)
+	}
+	config, err := pdClient.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("tikv scale in: failed to get the config of PD of TikvCluster %s/%s, %v", ns, tcName, err)
+	}
+	for _, id := range ids {
+		deleting := map[uint64]bool{id: true}
+		for deletableID := range deletable {
+			deleting[deletableID] = true
+		}
+		if err := checkScaleIn(storesInfo.Stores, config.Replication, deleting, tc.TiKVScaleInHighWaterMarkPercent()); err != nil {
+			msg := fmt.Sprintf("store %d can not be deleted: %v", id, err)
+			klog.Warningf("tikv scale in: TikvCluster %s/%s is blocked by the pre-flight checks, %s", ns, tcName, msg)
+			setScaleInBlockedCondition(tc, corev1.ConditionTrue, utiltikvcluster.ScaleInPreflightCheckFailed, msg)
+			tsd.recorder.Event(tc, corev1.EventTypeWarning, string(v1beta1.TiKVScaleInBlocked), msg)
+			return deletable, nil
+		}
+		deletable[id] = true
+	}
+	setScaleInBlockedCondition(tc, corev1.ConditionFalse, utiltikvcluster.ScaleInPreflightCheckPassed, "the remaining stores can hold the data and the region replicas")
+	return deletable, nil
+}
+
+func setScaleInBlockedCondition(tc *v1beta1.TikvCluster, status corev1.ConditionStatus, reason, message string) {
+	cond := utiltikvcluster.NewTikvClusterCondition(v1beta1.TiKVScaleInBlocked, status, reason, message)
+	utiltikvcluster.SetTikvClusterCondition(&tc.Status, *cond)
+}
+
+// prepareScaleIn deletes the store of the ordinal from the PD cluster if it passed the pre-flight
+// checks, and returns the PVC of the ordinal once the member can be removed safely, i.e. its
+// store becomes tombstone or it has never joined the cluster
+func (tsd *tikvScaler) prepareScaleIn(tc *v1beta1.TikvCluster, setName string, ordinal int32, deletable map[uint64]bool) (*corev1.PersistentVolumeClaim, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
 	// We need remove member from cluster before reducing statefulset replicas
-	podName := ordinalPodName(v1alpha1.TiKVMemberType, tcName, ordinal)
+	podName := ordinalPodName(v1beta1.TiKVMemberType, tcName, ordinal)
 	pod, err := tsd.podLister.Pods(ns).Get(podName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, store := range tc.Status.TiKV.Stores {
@@ -96,49 +224,33 @@ func (tsd *tikvScaler) ScaleIn(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSe
 			state := store.State
 			id, err := strconv.ParseUint(store.ID, 10, 64)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			if state != v1alpha1.TiKVStateOffline {
+			if state != v1beta1.TiKVStateOffline {
+				if !deletable[id] {
+					return nil, fmt.Errorf("TiKV %s/%s store %d can not be deleted, the scale-in is blocked by the pre-flight checks", ns, podName, id)
+				}
 				if err := controller.GetPDClient(tsd.pdControl, tc).DeleteStore(id); err != nil {
 					klog.Errorf("tikv scale in: failed to delete store %d, %v", id, err)
-					return err
+					return nil, err
 				}
 				klog.Infof("tikv scale in: delete store %d for tikv %s/%s successfully", id, ns, podName)
 			}
-			return controller.RequeueErrorf("TiKV %s/%s store %d  still in cluster, state: %s", ns, podName, id, state)
+			return nil, controller.RequeueErrorf("TiKV %s/%s store %d  still in cluster, state: %s", ns, podName, id, state)
 		}
 	}
 	for id, store := range tc.Status.TiKV.TombstoneStores {
 		if store.PodName == podName && pod.Labels[label.StoreIDLabelKey] == id {
 			id, err := strconv.ParseUint(store.ID, 10, 64)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			// TODO: double check if store is really not in Up/Offline/Down state
 			klog.Infof("TiKV %s/%s store %d becomes tombstone", ns, podName, id)
 
-			pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, setName, ordinal)
-			pvc, err := tsd.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
-			if err != nil {
-				return err
-			}
-			if pvc.Annotations == nil {
-				pvc.Annotations = map[string]string{}
-			}
-			now := time.Now().Format(time.RFC3339)
-			pvc.Annotations[label.AnnPVCDeferDeleting] = now
-			_, err = tsd.pvcControl.UpdatePVC(tc, pvc)
-			if err != nil {
-				klog.Errorf("tikv scale in: failed to set pvc %s/%s annotation: %s to %s",
-					ns, pvcName, label.AnnPVCDeferDeleting, now)
-				return err
-			}
-			klog.Infof("tikv scale in: set pvc %s/%s annotation: %s to %s",
-				ns, pvcName, label.AnnPVCDeferDeleting, now)
-
-			setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
-			return nil
+			pvcName := ordinalPVCName(v1beta1.TiKVMemberType, setName, ordinal)
+			return tsd.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
 		}
 	}
 
@@ -149,10 +261,10 @@ func (tsd *tikvScaler) ScaleIn(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSe
 	// 2. This can happen when TiKV pod has not been successfully registered in the cluster, such as always pending.
 	//    In this situation we should delete this TiKV pod immediately to avoid blocking the subsequent operations.
 	if !podutil.IsPodReady(pod) {
-		pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, setName, ordinal)
+		pvcName := ordinalPVCName(v1beta1.TiKVMemberType, setName, ordinal)
 		pvc, err := tsd.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		safeTimeDeadline := pod.CreationTimestamp.Add(5 * controller.ResyncDuration)
 		if time.Now().Before(safeTimeDeadline) {
@@ -163,26 +275,12 @@ func (tsd *tikvScaler) ScaleIn(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSe
 			// After this period of time, if there is still no information about this tikv in TikvCluster status,
 			// then we can be sure that this tikv has never been added to the tidb cluster.
 			// So we can scale in this tikv pod safely.
-			resetReplicas(newSet, oldSet)
-			return fmt.Errorf("TiKV %s/%s is not ready, wait for some resync periods to synced its status", ns, podName)
+			return nil, fmt.Errorf("TiKV %s/%s is not ready, wait for some resync periods to synced its status", ns, podName)
 		}
-		if pvc.Annotations == nil {
-			pvc.Annotations = map[string]string{}
-		}
-		now := time.Now().Format(time.RFC3339)
-		pvc.Annotations[label.AnnPVCDeferDeleting] = now
-		_, err = tsd.pvcControl.UpdatePVC(tc, pvc)
-		if err != nil {
-			klog.Errorf("pod %s not ready, tikv scale in: failed to set pvc %s/%s annotation: %s to %s",
-				podName, ns, pvcName, label.AnnPVCDeferDeleting, now)
-			return err
-		}
-		klog.Infof("pod %s not ready, tikv scale in: set pvc %s/%s annotation: %s to %s",
-			podName, ns, pvcName, label.AnnPVCDeferDeleting, now)
-		setReplicasAndDeleteSlots(newSet, replicas, deleteSlots)
-		return nil
+		klog.Infof("pod %s not ready and not found in the cluster, tikv scale in: remove it", podName)
+		return pvc, nil
 	}
-	return fmt.Errorf("TiKV %s/%s not found in cluster", ns, podName)
+	return nil, fmt.Errorf("TiKV %s/%s not found in cluster", ns, podName)
 }
 
 type fakeTiKVScaler struct{}
@@ -192,7 +290,7 @@ func NewFakeTiKVScaler() Scaler {
 	return &fakeTiKVScaler{}
 }
 
-func (fsd *fakeTiKVScaler) Scale(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (fsd *fakeTiKVScaler) Scale(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	if *newSet.Spec.Replicas > *oldSet.Spec.Replicas {
 		return fsd.ScaleOut(tc, oldSet, newSet)
 	} else if *newSet.Spec.Replicas < *oldSet.Spec.Replicas {
@@ -201,12 +299,12 @@ func (fsd *fakeTiKVScaler) Scale(tc *v1alpha1.TikvCluster, oldSet *apps.Stateful
 	return nil
 }
 
-func (fsd *fakeTiKVScaler) ScaleOut(_ *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (fsd *fakeTiKVScaler) ScaleOut(_ *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas+1, nil)
 	return nil
 }
 
-func (fsd *fakeTiKVScaler) ScaleIn(_ *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (fsd *fakeTiKVScaler) ScaleIn(_ *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	setReplicasAndDeleteSlots(newSet, *oldSet.Spec.Replicas-1, nil)
 	return nil
 }
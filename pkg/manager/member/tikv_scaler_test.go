@@ -19,16 +19,18 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
+	utiltikvcluster "github.com/tikv/tikv-operator/pkg/util/tikvcluster"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestTiKVScalerScaleOut(t *testing.T) {
@@ -49,7 +51,7 @@ func TestTiKVScalerScaleOut(t *testing.T) {
 		tc := newTikvClusterForPD()
 
 		if test.tikvUpgrading {
-			tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 		}
 
 		oldSet := newStatefulSetForPDScale()
@@ -58,8 +60,8 @@ func TestTiKVScalerScaleOut(t *testing.T) {
 
 		scaler, _, pvcIndexer, _, pvcControl := newFakeTiKVScaler()
 
-		pvc := newPVCForStatefulSet(oldSet, v1alpha1.TiKVMemberType, tc.Name)
-		pvc.Name = ordinalPVCName(v1alpha1.TiKVMemberType, oldSet.GetName(), *oldSet.Spec.Replicas)
+		pvc := newPVCForStatefulSet(oldSet, v1beta1.TiKVMemberType, tc.Name)
+		pvc.Name = ordinalPVCName(v1beta1.TiKVMemberType, oldSet.GetName(), *oldSet.Spec.Replicas)
 		if !test.annoIsNil {
 			pvc.Annotations = map[string]string{}
 		}
@@ -147,7 +149,7 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 	type testcase struct {
 		name          string
 		tikvUpgrading bool
-		storeFun      func(tc *v1alpha1.TikvCluster)
+		storeFun      func(tc *v1beta1.TikvCluster)
 		delStoreErr   bool
 		hasPVC        bool
 		storeIDSynced bool
@@ -166,7 +168,7 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		test.storeFun(tc)
 
 		if test.tikvUpgrading {
-			tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 		}
 
 		oldSet := newStatefulSetForPDScale()
@@ -194,7 +196,7 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		scaler, pdControl, pvcIndexer, podIndexer, pvcControl := newFakeTiKVScaler()
 
 		if test.hasPVC {
-			pvc := newScaleInPVCForStatefulSet(oldSet, v1alpha1.TiKVMemberType, tc.Name)
+			pvc := newScaleInPVCForStatefulSet(oldSet, v1beta1.TiKVMemberType, tc.Name)
 			pvcIndexer.Add(pvc)
 		}
 
@@ -205,6 +207,7 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		podIndexer.Add(pod)
 
 		pdClient := controller.NewFakePDClient(pdControl, tc)
+		addHealthyStoresReactions(pdClient, 6)
 
 		if test.delStoreErr {
 			pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
@@ -267,8 +270,8 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		{
 			name:          "status.TiKV.Stores is empty",
 			tikvUpgrading: false,
-			storeFun: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+			storeFun: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
 			},
 			delStoreErr:   false,
 			hasPVC:        true,
@@ -282,8 +285,8 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		{
 			name:          "tikv pod is not ready now, not sure if the status has been synced",
 			tikvUpgrading: false,
-			storeFun: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+			storeFun: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
 			},
 			delStoreErr:   false,
 			hasPVC:        true,
@@ -297,8 +300,8 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		{
 			name:          "tikv pod is not ready now, make sure the status has been synced",
 			tikvUpgrading: false,
-			storeFun: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+			storeFun: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
 			},
 			delStoreErr:   false,
 			hasPVC:        true,
@@ -312,7 +315,7 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		{
 			name:          "podName not match",
 			tikvUpgrading: false,
-			storeFun: func(tc *v1alpha1.TikvCluster) {
+			storeFun: func(tc *v1beta1.TikvCluster) {
 				normalStoreFun(tc)
 				store := tc.Status.TiKV.Stores["1"]
 				store.PodName = "xxx"
@@ -330,7 +333,7 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		{
 			name:          "store id is not integer",
 			tikvUpgrading: false,
-			storeFun: func(tc *v1alpha1.TikvCluster) {
+			storeFun: func(tc *v1beta1.TikvCluster) {
 				normalStoreFun(tc)
 				store := tc.Status.TiKV.Stores["1"]
 				store.ID = "not integer"
@@ -348,10 +351,10 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		{
 			name:          "store state is offline",
 			tikvUpgrading: false,
-			storeFun: func(tc *v1alpha1.TikvCluster) {
+			storeFun: func(tc *v1beta1.TikvCluster) {
 				normalStoreFun(tc)
 				store := tc.Status.TiKV.Stores["1"]
-				store.State = v1alpha1.TiKVStateOffline
+				store.State = v1beta1.TiKVStateOffline
 				tc.Status.TiKV.Stores["1"] = store
 			},
 			delStoreErr:   false,
@@ -392,7 +395,7 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 		{
 			name:          "store state is tombstone, id is not integer",
 			tikvUpgrading: false,
-			storeFun: func(tc *v1alpha1.TikvCluster) {
+			storeFun: func(tc *v1beta1.TikvCluster) {
 				tombstoneStoreFun(tc)
 				store := tc.Status.TiKV.TombstoneStores["1"]
 				store.ID = "not integer"
@@ -427,6 +430,227 @@ func TestTiKVScalerScaleIn(t *testing.T) {
 	}
 }
 
+func TestTiKVScalerScaleOutParallel(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	tc.Spec.TiKV.ScalePolicy = &v1beta1.ScalePolicy{ScaleOutParallelism: controller.Int32Ptr(3)}
+
+	oldSet := newStatefulSetForPDScale()
+	newSet := oldSet.DeepCopy()
+	newSet.Spec.Replicas = controller.Int32Ptr(10)
+
+	scaler, _, pvcIndexer, _, _ := newFakeTiKVScaler()
+	for ordinal := int32(5); ordinal < 10; ordinal++ {
+		pvc := newPVCForStatefulSet(oldSet, v1beta1.TiKVMemberType, tc.Name)
+		pvc.Name = ordinalPVCName(v1beta1.TiKVMemberType, oldSet.GetName(), ordinal)
+		pvc.Labels[label.AnnPodNameKey] = ordinalPodName(v1beta1.TiKVMemberType, tc.Name, ordinal)
+		pvc.Annotations = map[string]string{label.AnnPVCDeferDeleting: time.Now().Format(time.RFC3339)}
+		pvcIndexer.Add(pvc)
+	}
+
+	err := scaler.ScaleOut(tc, oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(int(*newSet.Spec.Replicas)).To(Equal(8))
+	// the defer deleting PVCs of the new members are deleted
+	for ordinal := int32(5); ordinal < 10; ordinal++ {
+		_, err := scaler.pvcLister.PersistentVolumeClaims(metav1.NamespaceDefault).Get(ordinalPVCName(v1beta1.TiKVMemberType, oldSet.GetName(), ordinal))
+		g.Expect(errors.IsNotFound(err)).To(Equal(ordinal < 8), "ordinal %d", ordinal)
+	}
+}
+
+func TestTiKVScalerScaleInParallel(t *testing.T) {
+	type testcase struct {
+		name            string
+		states          map[int32]string
+		errExpectFn     func(*GomegaWithT, error)
+		deletedStores   []uint64
+		replicas        int
+		deferDeletePVCs []int32
+	}
+
+	controller.ResyncDuration = 0
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		g := NewGomegaWithT(t)
+		tc := newTikvClusterForPD()
+		tc.Spec.TiKV.ScalePolicy = &v1beta1.ScalePolicy{ScaleInParallelism: controller.Int32Ptr(3)}
+		tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{}
+		tc.Status.TiKV.TombstoneStores = map[string]v1beta1.TiKVStore{}
+
+		oldSet := newStatefulSetForPDScale()
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = controller.Int32Ptr(1)
+
+		scaler, pdControl, pvcIndexer, podIndexer, _ := newFakeTiKVScaler()
+		for ordinal, state := range test.states {
+			id := fmt.Sprintf("%d", ordinal+1)
+			podName := ordinalPodName(v1beta1.TiKVMemberType, tc.GetName(), ordinal)
+			store := v1beta1.TiKVStore{ID: id, PodName: podName, State: state}
+			if state == v1beta1.TiKVStateTombstone {
+				tc.Status.TiKV.TombstoneStores[id] = store
+			} else {
+				tc.Status.TiKV.Stores[id] = store
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: corev1.NamespaceDefault,
+					Labels:    map[string]string{label.StoreIDLabelKey: id},
+				},
+			}
+			readyPodFunc(pod)
+			podIndexer.Add(pod)
+			pvc := newPVCForStatefulSet(oldSet, v1beta1.TiKVMemberType, tc.Name)
+			pvc.Name = ordinalPVCName(v1beta1.TiKVMemberType, oldSet.GetName(), ordinal)
+			pvcIndexer.Add(pvc)
+		}
+
+		deletedStores := []uint64{}
+		pdClient := controller.NewFakePDClient(pdControl, tc)
+		addHealthyStoresReactions(pdClient, 6)
+		pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			deletedStores = append(deletedStores, action.ID)
+			return nil, nil
+		})
+
+		err := scaler.ScaleIn(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		g.Expect(deletedStores).To(ConsistOf(test.deletedStores))
+		g.Expect(int(*newSet.Spec.Replicas)).To(Equal(test.replicas))
+		deferDeletePVCs := []int32{}
+		for ordinal := range test.states {
+			pvc, err := scaler.pvcLister.PersistentVolumeClaims(metav1.NamespaceDefault).Get(ordinalPVCName(v1beta1.TiKVMemberType, oldSet.GetName(), ordinal))
+			g.Expect(err).NotTo(HaveOccurred())
+			if _, ok := pvc.Annotations[label.AnnPVCDeferDeleting]; ok {
+				deferDeletePVCs = append(deferDeletePVCs, ordinal)
+			}
+		}
+		g.Expect(deferDeletePVCs).To(ConsistOf(test.deferDeletePVCs))
+	}
+
+	tests := []testcase{
+		{
+			name: "delete the stores concurrently",
+			states: map[int32]string{
+				4: v1beta1.TiKVStateUp,
+				3: v1beta1.TiKVStateUp,
+				2: v1beta1.TiKVStateOffline,
+			},
+			errExpectFn:     errExpectRequeue,
+			deletedStores:   []uint64{5, 4},
+			replicas:        5,
+			deferDeletePVCs: []int32{},
+		},
+		{
+			name: "remove the tombstone stores",
+			states: map[int32]string{
+				4: v1beta1.TiKVStateTombstone,
+				3: v1beta1.TiKVStateTombstone,
+				2: v1beta1.TiKVStateTombstone,
+			},
+			errExpectFn:     errExpectNil,
+			deletedStores:   []uint64{},
+			replicas:        2,
+			deferDeletePVCs: []int32{4, 3, 2},
+		},
+		{
+			name: "remove the tombstone stores in the scale-in order",
+			states: map[int32]string{
+				4: v1beta1.TiKVStateTombstone,
+				3: v1beta1.TiKVStateUp,
+				2: v1beta1.TiKVStateTombstone,
+			},
+			errExpectFn:     errExpectNil,
+			deletedStores:   []uint64{4},
+			replicas:        4,
+			deferDeletePVCs: []int32{4},
+		},
+		{
+			name: "wait for the last store",
+			states: map[int32]string{
+				4: v1beta1.TiKVStateOffline,
+				3: v1beta1.TiKVStateTombstone,
+				2: v1beta1.TiKVStateTombstone,
+			},
+			errExpectFn:     errExpectRequeue,
+			deletedStores:   []uint64{},
+			replicas:        5,
+			deferDeletePVCs: []int32{},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVScalerScaleInPreflight(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvClusterForPD()
+	normalStoreFun(tc)
+
+	oldSet := newStatefulSetForPDScale()
+	newSet := oldSet.DeepCopy()
+	newSet.Spec.Replicas = controller.Int32Ptr(3)
+
+	scaler, pdControl, pvcIndexer, podIndexer, _ := newFakeTiKVScaler()
+	recorder := record.NewFakeRecorder(10)
+	scaler.recorder = recorder
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TikvPodName(tc.GetName(), 4),
+			Namespace: corev1.NamespaceDefault,
+			Labels:    map[string]string{label.StoreIDLabelKey: "1"},
+		},
+	}
+	readyPodFunc(pod)
+	podIndexer.Add(pod)
+	pvcIndexer.Add(newScaleInPVCForStatefulSet(oldSet, v1beta1.TiKVMemberType, tc.Name))
+
+	deleted := false
+	pdClient := controller.NewFakePDClient(pdControl, tc)
+	// only 3 Up stores with the default max-replicas 3
+	addHealthyStoresReactions(pdClient, 3)
+	pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+		deleted = true
+		return nil, nil
+	})
+
+	err := scaler.Scale(tc, oldSet, newSet)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(deleted).To(BeFalse())
+	g.Expect(int(*newSet.Spec.Replicas)).To(Equal(5))
+	cond := utiltikvcluster.GetTikvClusterCondition(tc.Status, v1beta1.TiKVScaleInBlocked)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(utiltikvcluster.ScaleInPreflightCheckFailed))
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(ContainSubstring("fewer than max-replicas 3"))
+
+	// the condition is cleared once the scale-in is canceled
+	newSet.Spec.Replicas = controller.Int32Ptr(5)
+	g.Expect(scaler.Scale(tc, oldSet, newSet)).To(Succeed())
+	cond = utiltikvcluster.GetTikvClusterCondition(tc.Status, v1beta1.TiKVScaleInBlocked)
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal(utiltikvcluster.NoScaleIn))
+}
+
+// addHealthyStoresReactions adds the reactions of a PD cluster with the default config and
+// the Up stores 1 to n, each of them has 10Gi data in the capacity of 100Gi
+func addHealthyStoresReactions(pdClient *pdapi.FakePDClient, n int) {
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		storesInfo := &pdapi.StoresInfo{}
+		for i := 1; i <= n; i++ {
+			storesInfo.Stores = append(storesInfo.Stores, newStoreInfo(uint64(i), v1beta1.TiKVStateUp, 100, 90))
+		}
+		return storesInfo, nil
+	})
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{}, nil
+	})
+}
+
 func newFakeTiKVScaler() (*tikvScaler, *pdapi.FakePDControl, cache.Indexer, cache.Indexer, *controller.FakePVCControl) {
 	kubeCli := kubefake.NewSimpleClientset()
 
@@ -436,26 +660,26 @@ func newFakeTiKVScaler() (*tikvScaler, *pdapi.FakePDControl, cache.Indexer, cach
 	pdControl := pdapi.NewFakePDControl(kubeCli)
 	pvcControl := controller.NewFakePVCControl(pvcInformer)
 
-	return &tikvScaler{generalScaler{pdControl, pvcInformer.Lister(), pvcControl}, podInformer.Lister()},
+	return &tikvScaler{generalScaler{pdControl, pvcInformer.Lister(), pvcControl}, podInformer.Lister(), record.NewFakeRecorder(10)},
 		pdControl, pvcInformer.Informer().GetIndexer(), podInformer.Informer().GetIndexer(), pvcControl
 }
 
-func normalStoreFun(tc *v1alpha1.TikvCluster) {
-	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+func normalStoreFun(tc *v1beta1.TikvCluster) {
+	tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
 		"1": {
 			ID:      "1",
-			PodName: ordinalPodName(v1alpha1.TiKVMemberType, tc.GetName(), 4),
-			State:   v1alpha1.TiKVStateUp,
+			PodName: ordinalPodName(v1beta1.TiKVMemberType, tc.GetName(), 4),
+			State:   v1beta1.TiKVStateUp,
 		},
 	}
 }
 
-func tombstoneStoreFun(tc *v1alpha1.TikvCluster) {
-	tc.Status.TiKV.TombstoneStores = map[string]v1alpha1.TiKVStore{
+func tombstoneStoreFun(tc *v1beta1.TikvCluster) {
+	tc.Status.TiKV.TombstoneStores = map[string]v1beta1.TiKVStore{
 		"1": {
 			ID:      "1",
-			PodName: ordinalPodName(v1alpha1.TiKVMemberType, tc.GetName(), 4),
-			State:   v1alpha1.TiKVStateTombstone,
+			PodName: ordinalPodName(v1beta1.TiKVMemberType, tc.GetName(), 4),
+			State:   v1beta1.TiKVStateTombstone,
 		},
 	}
 }
@@ -0,0 +1,414 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+)
+
+// tikvSnapshotPauseTTL is the ttl in seconds of the service gc safe point, the paused
+// schedulers and the disabled region merge kept while the snapshots are being created,
+// it is renewed in every sync so that they are resumed soon if the operator stops.
+const tikvSnapshotPauseTTL int64 = 600
+
+// tikvSnapshotPausedSchedulers are the schedulers moving regions between the stores,
+// they are paused while the snapshots are being created as pd-ctl does for the volume
+// snapshot backup
+var tikvSnapshotPausedSchedulers = map[string]bool{
+	"balance-leader-scheduler":     true,
+	"balance-region-scheduler":     true,
+	"balance-hot-region-scheduler": true,
+	"shuffle-leader-scheduler":     true,
+	"shuffle-region-scheduler":     true,
+	"shuffle-hot-region-scheduler": true,
+}
+
+// tikvSnapshotScheduleConfig disables the region merge while the snapshots are being created
+var tikvSnapshotScheduleConfig = map[string]interface{}{
+	"merge-schedule-limit": 0,
+}
+
+// TiKVSnapshotter creates the CSI VolumeSnapshots of all the TiKV volumes with the gc,
+// the region balance and the region merge paused, so that a new cluster can be cloned
+// from them by spec.tikv.dataSource. The volumes are not snapshotted at the same instant,
+// so a clone is only crash consistent per store and may be inconsistent across stores.
+type TiKVSnapshotter interface {
+	// Sync drives the snapshot set triggered by the annotation tikv.org/volume-snapshot
+	Sync(tc *v1beta1.TikvCluster) error
+}
+
+type tikvSnapshotter struct {
+	pdControl       pdapi.PDControlInterface
+	pvcLister       corelisters.PersistentVolumeClaimLister
+	snapshotControl controller.VolumeSnapshotControlInterface
+	recorder        record.EventRecorder
+}
+
+// NewTiKVSnapshotter returns a TiKVSnapshotter
+func NewTiKVSnapshotter(pdControl pdapi.PDControlInterface,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	snapshotControl controller.VolumeSnapshotControlInterface,
+	recorder record.EventRecorder) TiKVSnapshotter {
+	return &tikvSnapshotter{
+		pdControl,
+		pvcLister,
+		snapshotControl,
+		recorder}
+}
+
+func (ts *tikvSnapshotter) Sync(tc *v1beta1.TikvCluster) error {
+	if name := tc.TiKVSnapshotSetCreating(); name != "" {
+		return ts.track(tc, name)
+	}
+
+	name := tc.GetAnnotations()[label.AnnTiKVVolumeSnapshot]
+	if name == "" {
+		return nil
+	}
+	if _, ok := tc.Status.TiKV.SnapshotSets[name]; ok {
+		return nil
+	}
+	return ts.start(tc, name)
+}
+
+func (ts *tikvSnapshotter) start(tc *v1beta1.TikvCluster, name string) error {
+	set := v1beta1.TiKVSnapshotSet{
+		ClusterID: tc.Status.ClusterID,
+		StartTime: metav1.Now(),
+		Snapshots: map[string]v1beta1.TiKVVolumeSnapshot{},
+	}
+	if tc.Status.TiKV.SnapshotSets == nil {
+		tc.Status.TiKV.SnapshotSets = map[string]v1beta1.TiKVSnapshotSet{}
+	}
+
+	pvcs, err := ts.getStorePVCs(tc)
+	if err != nil {
+		return err
+	}
+	if err := validateTiKVSnapshotSet(tc, pvcs); err != nil {
+		tc.Status.TiKV.SnapshotSets[name] = set
+		ts.setPhase(tc, name, v1beta1.TiKVSnapshotSetFailed, err.Error())
+		return nil
+	}
+	for storeID, pvc := range pvcs {
+		set.Snapshots[storeID] = v1beta1.TiKVVolumeSnapshot{
+			Name:    fmt.Sprintf("%s-%s", pvc.GetName(), name),
+			PVCName: pvc.GetName(),
+		}
+	}
+
+	// the gc safe point of the service is registered as the current minimal one,
+	// the snapshot set is retried in the next round if pd can't be reached
+	pdCli := controller.GetPDClient(ts.pdControl, tc)
+	serviceID := tikvSnapshotServiceID(tc)
+	safePoint, err := pdCli.UpdateServiceGCSafePoint(serviceID, tikvSnapshotPauseTTL, 0)
+	if err != nil {
+		return fmt.Errorf("tikvcluster: [%s/%s], failed to get the gc safe point, %v", tc.GetNamespace(), tc.GetName(), err)
+	}
+	if _, err := pdCli.UpdateServiceGCSafePoint(serviceID, tikvSnapshotPauseTTL, safePoint); err != nil {
+		return fmt.Errorf("tikvcluster: [%s/%s], failed to pause gc at %d, %v", tc.GetNamespace(), tc.GetName(), safePoint, err)
+	}
+	if err := pauseTiKVSnapshotScheduling(pdCli, tikvSnapshotPauseTTL); err != nil {
+		return fmt.Errorf("tikvcluster: [%s/%s], failed to pause scheduling, %v", tc.GetNamespace(), tc.GetName(), err)
+	}
+	set.GCSafePoint = safePoint
+	tc.Status.TiKV.SnapshotSets[name] = set
+	ts.setPhase(tc, name, v1beta1.TiKVSnapshotSetCreating, fmt.Sprintf("gc is paused at %d, region balance and merge are paused", safePoint))
+	return ts.track(tc, name)
+}
+
+func (ts *tikvSnapshotter) track(tc *v1beta1.TikvCluster, name string) error {
+	ns := tc.GetNamespace()
+	set := tc.Status.TiKV.SnapshotSets[name]
+
+	// renew the ttl of the service gc safe point and the paused scheduling
+	pdCli := controller.GetPDClient(ts.pdControl, tc)
+	if _, err := pdCli.UpdateServiceGCSafePoint(tikvSnapshotServiceID(tc), tikvSnapshotPauseTTL, set.GCSafePoint); err != nil {
+		return err
+	}
+	if err := pauseTiKVSnapshotScheduling(pdCli, tikvSnapshotPauseTTL); err != nil {
+		return err
+	}
+
+	storeIDs := []string{}
+	for storeID := range set.Snapshots {
+		storeIDs = append(storeIDs, storeID)
+	}
+	sort.Strings(storeIDs)
+
+	allReady := true
+	snapshots := map[string]*unstructured.Unstructured{}
+	for _, storeID := range storeIDs {
+		s := set.Snapshots[storeID]
+		snapshot, err := ts.snapshotControl.GetVolumeSnapshot(ns, s.Name)
+		if errors.IsNotFound(err) {
+			allReady = false
+			if err := ts.snapshotControl.CreateVolumeSnapshot(tc, newTiKVVolumeSnapshot(tc, name, set, storeID)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if msg := controller.VolumeSnapshotError(snapshot); msg != "" {
+			return ts.finish(tc, name, v1beta1.TiKVSnapshotSetFailed, fmt.Sprintf("VolumeSnapshot %s failed: %s", s.Name, msg))
+		}
+		s.ReadyToUse = controller.VolumeSnapshotReadyToUse(snapshot)
+		set.Snapshots[storeID] = s
+		snapshots[storeID] = snapshot
+		allReady = allReady && s.ReadyToUse
+	}
+	if !allReady {
+		klog.V(4).Infof("tikvcluster: [%s/%s], waiting for the VolumeSnapshots of set %s to be ready", ns, tc.GetName(), name)
+		return nil
+	}
+	if err := ts.recordAllocID(tc, name, storeIDs, snapshots); err != nil {
+		return err
+	}
+	return ts.finish(tc, name, v1beta1.TiKVSnapshotSetReady, fmt.Sprintf("%d VolumeSnapshots are ready", len(set.Snapshots)))
+}
+
+// recordAllocID records the alloc id of pd on the ready VolumeSnapshots, the data of the
+// ready VolumeSnapshots has been cut, so the alloc id read now is larger than the ids of
+// all the regions and peers in them. It is read once and retried until all the
+// VolumeSnapshots are updated.
+func (ts *tikvSnapshotter) recordAllocID(tc *v1beta1.TikvCluster, name string, storeIDs []string, snapshots map[string]*unstructured.Unstructured) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	set := tc.Status.TiKV.SnapshotSets[name]
+	if set.AllocID == "" {
+		clusterID, err := strconv.ParseUint(set.ClusterID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("tikvcluster: [%s/%s], snapshot set %s has invalid cluster id, %v", ns, tcName, name, err)
+		}
+		etcdClient, err := ts.pdControl.GetPDEtcdClient(pdapi.Namespace(ns), tcName, tc.IsTLSClusterEnabled())
+		if err != nil {
+			return fmt.Errorf("tikvcluster: [%s/%s], failed to get the pd etcd client, %v", ns, tcName, err)
+		}
+		allocID, err := etcdClient.GetAllocID(clusterID)
+		if err != nil {
+			return fmt.Errorf("tikvcluster: [%s/%s], failed to get the alloc id of snapshot set %s, %v", ns, tcName, name, err)
+		}
+		set.AllocID = strconv.FormatUint(allocID, 10)
+		tc.Status.TiKV.SnapshotSets[name] = set
+	}
+
+	for _, storeID := range storeIDs {
+		snapshot := snapshots[storeID]
+		annotations := snapshot.GetAnnotations()
+		if annotations[label.AnnPDRecoveryAllocID] == set.AllocID {
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[label.AnnPDRecoveryAllocID] = set.AllocID
+		snapshot.SetAnnotations(annotations)
+		if err := ts.snapshotControl.UpdateVolumeSnapshot(tc, snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish resumes the gc and the scheduling and enters the final phase
+func (ts *tikvSnapshotter) finish(tc *v1beta1.TikvCluster, name string, phase v1beta1.TiKVSnapshotSetPhase, msg string) error {
+	pdCli := controller.GetPDClient(ts.pdControl, tc)
+	if _, err := pdCli.UpdateServiceGCSafePoint(tikvSnapshotServiceID(tc), 0, 0); err != nil {
+		return fmt.Errorf("tikvcluster: [%s/%s], failed to resume gc, %v", tc.GetNamespace(), tc.GetName(), err)
+	}
+	if err := pauseTiKVSnapshotScheduling(pdCli, 0); err != nil {
+		return fmt.Errorf("tikvcluster: [%s/%s], failed to resume scheduling, %v", tc.GetNamespace(), tc.GetName(), err)
+	}
+	ts.setPhase(tc, name, phase, msg)
+	return nil
+}
+
+func (ts *tikvSnapshotter) setPhase(tc *v1beta1.TikvCluster, name string, phase v1beta1.TiKVSnapshotSetPhase, msg string) {
+	set := tc.Status.TiKV.SnapshotSets[name]
+	set.Phase = phase
+	if phase == v1beta1.TiKVSnapshotSetFailed {
+		set.Message = msg
+	}
+	tc.Status.TiKV.SnapshotSets[name] = set
+	eventType := corev1.EventTypeNormal
+	if phase == v1beta1.TiKVSnapshotSetFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	ts.recorder.Eventf(tc, eventType, "TiKVSnapshotSet"+string(phase), "snapshot set %s: %s", name, msg)
+	klog.Infof("tikv snapshot: tikvcluster: [%s/%s] snapshot set %s enters phase %s: %s", tc.GetNamespace(), tc.GetName(), name, phase, msg)
+}
+
+// getStorePVCs returns the TiKV pvcs keyed by the store id labeled by the meta manager
+func (ts *tikvSnapshotter) getStorePVCs(tc *v1beta1.TikvCluster) (map[string]*corev1.PersistentVolumeClaim, error) {
+	selector, err := label.New().Instance(tc.GetInstanceName()).TiKV().Selector()
+	if err != nil {
+		return nil, err
+	}
+	pvcs, err := ts.pvcLister.PersistentVolumeClaims(tc.GetNamespace()).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	storePVCs := map[string]*corev1.PersistentVolumeClaim{}
+	for _, pvc := range pvcs {
+		if storeID := pvc.Labels[label.StoreIDLabelKey]; storeID != "" {
+			storePVCs[storeID] = pvc
+		}
+	}
+	return storePVCs, nil
+}
+
+// validateTiKVSnapshotSet returns an error if any store is not Up or has no pvc
+func validateTiKVSnapshotSet(tc *v1beta1.TikvCluster, pvcs map[string]*corev1.PersistentVolumeClaim) error {
+	if tc.Status.ClusterID == "" {
+		return fmt.Errorf("the cluster id is unknown")
+	}
+	// the alloc id is read from the etcd of the PD members
+	if tc.IsPDExternal() {
+		return fmt.Errorf("the alloc id of the external PD cluster can't be read")
+	}
+	if len(tc.Status.TiKV.Stores) == 0 {
+		return fmt.Errorf("there are no stores to snapshot")
+	}
+	for id, store := range tc.Status.TiKV.Stores {
+		if store.State != v1beta1.TiKVStateUp {
+			return fmt.Errorf("store %s is %s, not %s", id, store.State, v1beta1.TiKVStateUp)
+		}
+		if _, ok := pvcs[id]; !ok {
+			return fmt.Errorf("the pvc of store %s is not found", id)
+		}
+	}
+	return nil
+}
+
+// pauseTiKVSnapshotScheduling pauses the existing schedulers moving regions and disables
+// the region merge for ttl seconds, they are resumed if ttl is not positive
+func pauseTiKVSnapshotScheduling(pdCli pdapi.PDClient, ttl int64) error {
+	schedulers, err := pdCli.GetSchedulers()
+	if err != nil {
+		return err
+	}
+	for _, scheduler := range schedulers {
+		if !tikvSnapshotPausedSchedulers[scheduler] {
+			continue
+		}
+		if err := pdCli.PauseScheduler(scheduler, ttl); err != nil {
+			return err
+		}
+	}
+	return pdCli.UpdateScheduleConfigWithTTL(tikvSnapshotScheduleConfig, ttl)
+}
+
+// tikvSnapshotServiceID returns the id of the service gc safe point of the TikvCluster
+func tikvSnapshotServiceID(tc *v1beta1.TikvCluster) string {
+	return fmt.Sprintf("tikv-operator-snapshot-%s-%s", tc.GetNamespace(), tc.GetName())
+}
+
+// newTiKVVolumeSnapshot returns the VolumeSnapshot of the store, the ids a clone needs to
+// recover the PD cluster are recorded on it since the source cluster may be deleted, the
+// alloc id is recorded once the VolumeSnapshot is ready
+func newTiKVVolumeSnapshot(tc *v1beta1.TikvCluster, name string, set v1beta1.TiKVSnapshotSet, storeID string) *unstructured.Unstructured {
+	s := set.Snapshots[storeID]
+	labels := label.New().Instance(tc.GetInstanceName()).TiKV().Labels()
+	labels[label.StoreIDLabelKey] = storeID
+	labels[label.ClusterIDLabelKey] = set.ClusterID
+	labels[label.SnapshotSetLabelKey] = name
+	return controller.NewVolumeSnapshot(tc.GetNamespace(), s.Name, s.PVCName, tc.Spec.TiKV.VolumeSnapshotClassName, labels, nil)
+}
+
+// getTiKVDataSourceSnapshots returns the VolumeSnapshots of the snapshot set the TiKV
+// volumes are cloned from sorted by the store id, all of them must be ready to use
+func getTiKVDataSourceSnapshots(snapshotControl controller.VolumeSnapshotControlInterface, tc *v1beta1.TikvCluster) ([]*unstructured.Unstructured, error) {
+	ds := tc.Spec.TiKV.DataSource
+	l := label.New().Instance(ds.ClusterName).TiKV()
+	l[label.SnapshotSetLabelKey] = ds.SnapshotSet
+	selector, err := l.Selector()
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := snapshotControl.ListVolumeSnapshots(tc.GetNamespace(), selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no VolumeSnapshots of snapshot set %s/%s are found", ds.ClusterName, ds.SnapshotSet)
+	}
+
+	storeIDs := map[string]uint64{}
+	for _, snapshot := range snapshots {
+		if !controller.VolumeSnapshotReadyToUse(snapshot) {
+			return nil, fmt.Errorf("VolumeSnapshot %s is not ready to use", snapshot.GetName())
+		}
+		storeID, err := strconv.ParseUint(snapshot.GetLabels()[label.StoreIDLabelKey], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("VolumeSnapshot %s has invalid store id, %v", snapshot.GetName(), err)
+		}
+		storeIDs[snapshot.GetName()] = storeID
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return storeIDs[snapshots[i].GetName()] < storeIDs[snapshots[j].GetName()]
+	})
+	return snapshots, nil
+}
+
+// getTiKVDataSourceIDs returns the cluster id and the alloc id recorded on the VolumeSnapshots
+func getTiKVDataSourceIDs(snapshots []*unstructured.Unstructured) (uint64, uint64, error) {
+	clusterID := ""
+	var allocID uint64
+	for _, snapshot := range snapshots {
+		id := snapshot.GetLabels()[label.ClusterIDLabelKey]
+		if clusterID != "" && clusterID != id {
+			return 0, 0, fmt.Errorf("the VolumeSnapshots belong to different clusters: %s, %s", clusterID, id)
+		}
+		clusterID = id
+		v, err := strconv.ParseUint(snapshot.GetAnnotations()[label.AnnPDRecoveryAllocID], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("VolumeSnapshot %s has invalid alloc id, %v", snapshot.GetName(), err)
+		}
+		if v > allocID {
+			allocID = v
+		}
+	}
+	id, err := strconv.ParseUint(clusterID, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("the VolumeSnapshots have invalid cluster id, %v", err)
+	}
+	return id, allocID, nil
+}
+
+type fakeTiKVSnapshotter struct{}
+
+// NewFakeTiKVSnapshotter returns a fake TiKVSnapshotter
+func NewFakeTiKVSnapshotter() TiKVSnapshotter {
+	return &fakeTiKVSnapshotter{}
+}
+
+func (fts *fakeTiKVSnapshotter) Sync(_ *v1beta1.TikvCluster) error {
+	return nil
+}
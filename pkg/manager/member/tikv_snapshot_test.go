@@ -0,0 +1,363 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTiKVSnapshotterSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	triggered := func(tc *v1beta1.TikvCluster) {
+		tc.Annotations = map[string]string{label.AnnTiKVVolumeSnapshot: "s1"}
+		tc.Status.ClusterID = "6"
+		tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+			"1": {ID: "1", State: v1beta1.TiKVStateUp},
+			"4": {ID: "4", State: v1beta1.TiKVStateUp},
+		}
+	}
+	creating := func(tc *v1beta1.TikvCluster) {
+		triggered(tc)
+		tc.Status.TiKV.SnapshotSets = map[string]v1beta1.TiKVSnapshotSet{
+			"s1": {
+				Phase:       v1beta1.TiKVSnapshotSetCreating,
+				ClusterID:   "6",
+				GCSafePoint: 400,
+				Snapshots: map[string]v1beta1.TiKVVolumeSnapshot{
+					"1": {Name: "tikv-test-tikv-0-s1", PVCName: "tikv-test-tikv-0"},
+					"4": {Name: "tikv-test-tikv-1-s1", PVCName: "tikv-test-tikv-1"},
+				},
+			},
+		}
+	}
+
+	// balance-leader-scheduler is paused and the region merge is disabled for ttl seconds
+	pauseCalls := func(ttls ...int64) []pdapi.Action {
+		var calls []pdapi.Action
+		for _, ttl := range ttls {
+			calls = append(calls,
+				pdapi.Action{Name: "balance-leader-scheduler", TTL: ttl},
+				pdapi.Action{Config: tikvSnapshotScheduleConfig, TTL: ttl})
+		}
+		return calls
+	}
+
+	type testcase struct {
+		name            string
+		update          func(*v1beta1.TikvCluster)
+		snapshotFn      func(*v1beta1.TikvCluster, *controller.FakeVolumeSnapshotControl)
+		gcErr           bool
+		pauseErr        bool
+		allocIDErr      bool
+		errExpectFn     func(*GomegaWithT, error)
+		expectGCCall    []pdapi.Action
+		expectPauseCall []pdapi.Action
+		expectFn        func(*GomegaWithT, *v1beta1.TikvCluster, *controller.FakeVolumeSnapshotControl)
+	}
+
+	tests := []testcase{
+		{
+			name:        "not triggered",
+			update:      func(tc *v1beta1.TikvCluster) {},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, _ *controller.FakeVolumeSnapshotControl) {
+				g.Expect(tc.Status.TiKV.SnapshotSets).To(BeEmpty())
+			},
+		},
+		{
+			name: "snapshot set has been created",
+			update: func(tc *v1beta1.TikvCluster) {
+				triggered(tc)
+				tc.Status.TiKV.SnapshotSets = map[string]v1beta1.TiKVSnapshotSet{
+					"s1": {Phase: v1beta1.TiKVSnapshotSetReady},
+				}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				g.Expect(tc.Status.TiKV.SnapshotSets["s1"].Phase).To(Equal(v1beta1.TiKVSnapshotSetReady))
+				g.Expect(snapshots.Snapshots).To(BeEmpty())
+			},
+		},
+		{
+			name: "store is not up",
+			update: func(tc *v1beta1.TikvCluster) {
+				triggered(tc)
+				tc.Status.TiKV.Stores["4"] = v1beta1.TiKVStore{ID: "4", State: v1beta1.TiKVStateDown}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				set := tc.Status.TiKV.SnapshotSets["s1"]
+				g.Expect(set.Phase).To(Equal(v1beta1.TiKVSnapshotSetFailed))
+				g.Expect(set.Message).To(ContainSubstring("store 4 is Down"))
+				g.Expect(snapshots.Snapshots).To(BeEmpty())
+			},
+		},
+		{
+			name:        "gc is paused and snapshots are created",
+			update:      triggered,
+			errExpectFn: errExpectNil,
+			expectGCCall: []pdapi.Action{
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 0},
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 400},
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 400},
+			},
+			expectPauseCall: pauseCalls(tikvSnapshotPauseTTL, tikvSnapshotPauseTTL),
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				set := tc.Status.TiKV.SnapshotSets["s1"]
+				g.Expect(set.Phase).To(Equal(v1beta1.TiKVSnapshotSetCreating))
+				g.Expect(set.GCSafePoint).To(Equal(uint64(400)))
+				g.Expect(set.AllocID).To(BeEmpty())
+				g.Expect(set.Snapshots["4"]).To(Equal(v1beta1.TiKVVolumeSnapshot{Name: "tikv-test-tikv-1-s1", PVCName: "tikv-test-tikv-1"}))
+				g.Expect(tc.TiKVSnapshotSetCreating()).To(Equal("s1"))
+
+				g.Expect(snapshots.Snapshots).To(HaveLen(2))
+				snapshot, err := snapshots.GetVolumeSnapshot(tc.Namespace, "tikv-test-tikv-1-s1")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(snapshot.GetLabels()).To(HaveKeyWithValue(label.StoreIDLabelKey, "4"))
+				g.Expect(snapshot.GetLabels()).To(HaveKeyWithValue(label.ClusterIDLabelKey, "6"))
+				g.Expect(snapshot.GetLabels()).To(HaveKeyWithValue(label.SnapshotSetLabelKey, "s1"))
+				g.Expect(snapshot.GetAnnotations()).NotTo(HaveKey(label.AnnPDRecoveryAllocID))
+			},
+		},
+		{
+			name: "pd is external",
+			update: func(tc *v1beta1.TikvCluster) {
+				triggered(tc)
+				tc.Spec.PD.ExternalEndpoints = []string{"http://10.0.1.1:2379"}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				set := tc.Status.TiKV.SnapshotSets["s1"]
+				g.Expect(set.Phase).To(Equal(v1beta1.TiKVSnapshotSetFailed))
+				g.Expect(set.Message).To(ContainSubstring("external PD cluster"))
+				g.Expect(snapshots.Snapshots).To(BeEmpty())
+			},
+		},
+		{
+			name:   "failed to pause gc",
+			update: triggered,
+			gcErr:  true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				g.Expect(tc.Status.TiKV.SnapshotSets).NotTo(HaveKey("s1"))
+				g.Expect(snapshots.Snapshots).To(BeEmpty())
+			},
+		},
+		{
+			name:     "failed to pause scheduling",
+			update:   triggered,
+			pauseErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("failed to pause scheduling"))
+			},
+			expectGCCall: []pdapi.Action{
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 0},
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 400},
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				g.Expect(tc.Status.TiKV.SnapshotSets).NotTo(HaveKey("s1"))
+				g.Expect(snapshots.Snapshots).To(BeEmpty())
+			},
+		},
+		{
+			name:   "waiting for snapshots to be ready",
+			update: creating,
+			snapshotFn: func(tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				for _, storeID := range []string{"1", "4"} {
+					set := tc.Status.TiKV.SnapshotSets["s1"]
+					snapshots.CreateVolumeSnapshot(tc, newTiKVVolumeSnapshot(tc, "s1", set, storeID))
+				}
+				snapshots.SetReadyToUse(tc.Namespace, "tikv-test-tikv-0-s1")
+			},
+			errExpectFn: errExpectNil,
+			expectGCCall: []pdapi.Action{
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 400},
+			},
+			expectPauseCall: pauseCalls(tikvSnapshotPauseTTL),
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, _ *controller.FakeVolumeSnapshotControl) {
+				set := tc.Status.TiKV.SnapshotSets["s1"]
+				g.Expect(set.Phase).To(Equal(v1beta1.TiKVSnapshotSetCreating))
+				g.Expect(set.Snapshots["1"].ReadyToUse).To(BeTrue())
+				g.Expect(set.Snapshots["4"].ReadyToUse).To(BeFalse())
+			},
+		},
+		{
+			name:   "snapshots are ready",
+			update: creating,
+			snapshotFn: func(tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				for _, storeID := range []string{"1", "4"} {
+					set := tc.Status.TiKV.SnapshotSets["s1"]
+					snapshot := newTiKVVolumeSnapshot(tc, "s1", set, storeID)
+					snapshots.CreateVolumeSnapshot(tc, snapshot)
+					snapshots.SetReadyToUse(tc.Namespace, snapshot.GetName())
+				}
+			},
+			errExpectFn: errExpectNil,
+			expectGCCall: []pdapi.Action{
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 400},
+				{Name: "tikv-operator-snapshot-default-test", TTL: 0, SafePoint: 0},
+			},
+			expectPauseCall: pauseCalls(tikvSnapshotPauseTTL, 0),
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				set := tc.Status.TiKV.SnapshotSets["s1"]
+				g.Expect(set.Phase).To(Equal(v1beta1.TiKVSnapshotSetReady))
+				g.Expect(set.AllocID).To(Equal("5000"))
+				g.Expect(tc.TiKVSnapshotSetCreating()).To(BeEmpty())
+				for _, name := range []string{"tikv-test-tikv-0-s1", "tikv-test-tikv-1-s1"} {
+					snapshot, err := snapshots.GetVolumeSnapshot(tc.Namespace, name)
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(snapshot.GetAnnotations()).To(HaveKeyWithValue(label.AnnPDRecoveryAllocID, "5000"))
+				}
+			},
+		},
+		{
+			name:   "failed to get the alloc id",
+			update: creating,
+			snapshotFn: func(tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				for _, storeID := range []string{"1", "4"} {
+					set := tc.Status.TiKV.SnapshotSets["s1"]
+					snapshot := newTiKVVolumeSnapshot(tc, "s1", set, storeID)
+					snapshots.CreateVolumeSnapshot(tc, snapshot)
+					snapshots.SetReadyToUse(tc.Namespace, snapshot.GetName())
+				}
+			},
+			allocIDErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("failed to get the alloc id"))
+			},
+			expectGCCall: []pdapi.Action{
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 400},
+			},
+			expectPauseCall: pauseCalls(tikvSnapshotPauseTTL),
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				set := tc.Status.TiKV.SnapshotSets["s1"]
+				g.Expect(set.Phase).To(Equal(v1beta1.TiKVSnapshotSetCreating))
+				g.Expect(set.AllocID).To(BeEmpty())
+				snapshot, err := snapshots.GetVolumeSnapshot(tc.Namespace, "tikv-test-tikv-0-s1")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(snapshot.GetAnnotations()).NotTo(HaveKey(label.AnnPDRecoveryAllocID))
+			},
+		},
+		{
+			name:   "snapshot failed",
+			update: creating,
+			snapshotFn: func(tc *v1beta1.TikvCluster, snapshots *controller.FakeVolumeSnapshotControl) {
+				set := tc.Status.TiKV.SnapshotSets["s1"]
+				snapshots.CreateVolumeSnapshot(tc, newTiKVVolumeSnapshot(tc, "s1", set, "1"))
+				snapshots.SetError(tc.Namespace, "tikv-test-tikv-0-s1", "quota exceeded")
+			},
+			errExpectFn: errExpectNil,
+			expectGCCall: []pdapi.Action{
+				{Name: "tikv-operator-snapshot-default-test", TTL: tikvSnapshotPauseTTL, SafePoint: 400},
+				{Name: "tikv-operator-snapshot-default-test", TTL: 0, SafePoint: 0},
+			},
+			expectPauseCall: pauseCalls(tikvSnapshotPauseTTL, 0),
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, _ *controller.FakeVolumeSnapshotControl) {
+				set := tc.Status.TiKV.SnapshotSets["s1"]
+				g.Expect(set.Phase).To(Equal(v1beta1.TiKVSnapshotSetFailed))
+				g.Expect(set.Message).To(ContainSubstring("quota exceeded"))
+			},
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTikvClusterForPD()
+			tt.update(tc)
+
+			ts, pdControl, pvcIndexer, snapshotControl := newFakeTiKVSnapshotter()
+			for i, storeID := range []string{"1", "4"} {
+				pvc := &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("tikv-test-tikv-%d", i),
+						Namespace: tc.Namespace,
+						Labels:    label.New().Instance(tc.GetInstanceName()).TiKV().Labels(),
+					},
+				}
+				pvc.Labels[label.StoreIDLabelKey] = storeID
+				g.Expect(pvcIndexer.Add(pvc)).To(Succeed())
+			}
+			if tt.snapshotFn != nil {
+				tt.snapshotFn(tc, snapshotControl)
+			}
+
+			etcdClient := pdapi.NewFakePDEtcdClient()
+			etcdClient.Keys["/pd/6/alloc_id"] = "5000"
+			if tt.allocIDErr {
+				etcdClient.SetError(fmt.Errorf("etcd is unavailable"))
+			}
+			pdControl.SetPDEtcdClient(pdapi.Namespace(tc.Namespace), tc.Name, etcdClient)
+
+			pdClient := controller.NewFakePDClient(pdControl, tc)
+			var gcCalls []pdapi.Action
+			pdClient.AddReaction(pdapi.UpdateServiceGCSafePointActionType, func(action *pdapi.Action) (interface{}, error) {
+				if tt.gcErr {
+					return nil, fmt.Errorf("pd is unavailable")
+				}
+				gcCalls = append(gcCalls, *action)
+				return uint64(400), nil
+			})
+
+			var schedulingCalls []pdapi.Action
+			pdClient.AddReaction(pdapi.GetSchedulersActionType, func(action *pdapi.Action) (interface{}, error) {
+				return []string{"balance-leader-scheduler", "evict-leader-scheduler-1"}, nil
+			})
+			pdClient.AddReaction(pdapi.PauseSchedulerActionType, func(action *pdapi.Action) (interface{}, error) {
+				if tt.pauseErr {
+					return nil, fmt.Errorf("pd is unavailable")
+				}
+				schedulingCalls = append(schedulingCalls, *action)
+				return nil, nil
+			})
+			pdClient.AddReaction(pdapi.UpdateScheduleConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+				schedulingCalls = append(schedulingCalls, *action)
+				return nil, nil
+			})
+
+			err := ts.Sync(tc)
+			tt.errExpectFn(g, err)
+			g.Expect(gcCalls).To(Equal(tt.expectGCCall))
+			g.Expect(schedulingCalls).To(Equal(tt.expectPauseCall))
+			tt.expectFn(g, tc, snapshotControl)
+		})
+	}
+}
+
+func newFakeTiKVSnapshotter() (TiKVSnapshotter, *pdapi.FakePDControl, cache.Indexer, *controller.FakeVolumeSnapshotControl) {
+	kubeCli := kubefake.NewSimpleClientset()
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	pvcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().PersistentVolumeClaims()
+	snapshotControl := controller.NewFakeVolumeSnapshotControl()
+	return NewTiKVSnapshotter(pdControl, pvcInformer.Lister(), snapshotControl, record.NewFakeRecorder(100)),
+		pdControl, pvcInformer.Informer().GetIndexer(), snapshotControl
+}
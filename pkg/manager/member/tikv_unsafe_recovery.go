@@ -0,0 +1,177 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+)
+
+// TiKVUnsafeRecovery removes the permanently failed stores from the regions which
+// lost the majority by the online unsafe recovery of PD.
+type TiKVUnsafeRecovery interface {
+	// Sync drives the recovery triggered by the annotation tikv.org/tikv-unsafe-recovery
+	Sync(tc *v1beta1.TikvCluster) error
+}
+
+type tikvUnsafeRecovery struct {
+	pdControl pdapi.PDControlInterface
+	recorder  record.EventRecorder
+}
+
+// NewTiKVUnsafeRecovery returns a TiKVUnsafeRecovery
+func NewTiKVUnsafeRecovery(pdControl pdapi.PDControlInterface, recorder record.EventRecorder) TiKVUnsafeRecovery {
+	return &tikvUnsafeRecovery{pdControl, recorder}
+}
+
+func (tur *tikvUnsafeRecovery) Sync(tc *v1beta1.TikvCluster) error {
+	if tc.TiKVUnsafeRecovering() {
+		return tur.track(tc)
+	}
+
+	trigger := tc.GetAnnotations()[label.AnnTiKVUnsafeRecovery]
+	if trigger == "" {
+		return nil
+	}
+	if r := tc.Status.TiKV.UnsafeRecovery; r != nil && r.Trigger == trigger {
+		return nil
+	}
+	return tur.start(tc, trigger)
+}
+
+func (tur *tikvUnsafeRecovery) start(tc *v1beta1.TikvCluster, trigger string) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	r := &v1beta1.TiKVUnsafeRecoveryStatus{
+		Trigger:   trigger,
+		StartTime: metav1.Now(),
+	}
+
+	storeIDs, err := getUnsafeRecoveryFailedStores(tc, time.Now())
+	if err != nil {
+		tc.Status.TiKV.UnsafeRecovery = r
+		tur.setPhase(tc, v1beta1.TiKVUnsafeRecoveryFailed, err.Error())
+		return nil
+	}
+	for _, id := range storeIDs {
+		r.FailedStores = append(r.FailedStores, strconv.FormatUint(id, 10))
+	}
+
+	// the recovery is retried in the next round if the request fails
+	if err := controller.GetPDClient(tur.pdControl, tc).RemoveFailedStores(storeIDs); err != nil {
+		return fmt.Errorf("tikvcluster: [%s/%s], failed to start unsafe recovery for stores %v, %v", ns, tcName, r.FailedStores, err)
+	}
+	tc.Status.TiKV.UnsafeRecovery = r
+	tur.setPhase(tc, v1beta1.TiKVUnsafeRecoveryRunning, fmt.Sprintf("removing failed stores %v", r.FailedStores))
+	return nil
+}
+
+func (tur *tikvUnsafeRecovery) track(tc *v1beta1.TikvCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	r := tc.Status.TiKV.UnsafeRecovery
+	pdCli := controller.GetPDClient(tur.pdControl, tc)
+
+	stages, err := pdCli.GetUnsafeRecoveryProgress()
+	if err != nil {
+		return err
+	}
+	if len(stages) == 0 {
+		return controller.RequeueErrorf("tikvcluster: [%s/%s], waiting for unsafe recovery to start", ns, tcName)
+	}
+	last := stages[len(stages)-1]
+	r.Progress = last.Info
+	if last.IsFailed() {
+		tur.setPhase(tc, v1beta1.TiKVUnsafeRecoveryFailed, last.Info)
+		return nil
+	}
+	if !last.IsFinished() {
+		return controller.RequeueErrorf("tikvcluster: [%s/%s], unsafe recovery is in progress: %s", ns, tcName, last.Info)
+	}
+
+	count, err := pdCli.GetDownPeerRegionCount()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return controller.RequeueErrorf("tikvcluster: [%s/%s], waiting for %d regions with down peers to be healthy", ns, tcName, count)
+	}
+	tur.setPhase(tc, v1beta1.TiKVUnsafeRecoveryComplete, fmt.Sprintf("failed stores %v are removed", r.FailedStores))
+	return nil
+}
+
+func (tur *tikvUnsafeRecovery) setPhase(tc *v1beta1.TikvCluster, phase v1beta1.TiKVUnsafeRecoveryPhase, msg string) {
+	r := tc.Status.TiKV.UnsafeRecovery
+	r.Phase = phase
+	r.Message = msg
+	r.LastTransitionTime = metav1.Now()
+	eventType := corev1.EventTypeNormal
+	if phase == v1beta1.TiKVUnsafeRecoveryFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	tur.recorder.Eventf(tc, eventType, "TiKVUnsafeRecovery"+string(phase), msg)
+	klog.Infof("tikv unsafe recovery: tikvcluster: [%s/%s] enters phase %s: %s", tc.GetNamespace(), tc.GetName(), phase, msg)
+}
+
+// getUnsafeRecoveryFailedStores returns the ids of the failure stores, it returns an
+// error if any of them has not been Down longer than the threshold.
+func getUnsafeRecoveryFailedStores(tc *v1beta1.TikvCluster, now time.Time) ([]uint64, error) {
+	if len(tc.Status.TiKV.FailureStores) == 0 {
+		return nil, fmt.Errorf("there are no failure stores to remove")
+	}
+	threshold := tc.TiKVUnsafeRecoveryDownThreshold()
+	storeIDs := []uint64{}
+	for id := range tc.Status.TiKV.FailureStores {
+		store, ok := tc.Status.TiKV.Stores[id]
+		if !ok {
+			return nil, fmt.Errorf("failure store %s is not found", id)
+		}
+		if store.State != v1beta1.TiKVStateDown {
+			return nil, fmt.Errorf("failure store %s is %s, not %s", id, store.State, v1beta1.TiKVStateDown)
+		}
+		if down := now.Sub(store.LastTransitionTime.Time); down < threshold {
+			return nil, fmt.Errorf("failure store %s has been %s for %s, less than the threshold %s",
+				id, v1beta1.TiKVStateDown, down.Round(time.Second), threshold)
+		}
+		storeID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		storeIDs = append(storeIDs, storeID)
+	}
+	sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+	return storeIDs, nil
+}
+
+type fakeTiKVUnsafeRecovery struct{}
+
+// NewFakeTiKVUnsafeRecovery returns a fake TiKVUnsafeRecovery
+func NewFakeTiKVUnsafeRecovery() TiKVUnsafeRecovery {
+	return &fakeTiKVUnsafeRecovery{}
+}
+
+func (ftur *fakeTiKVUnsafeRecovery) Sync(_ *v1beta1.TikvCluster) error {
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTiKVUnsafeRecoverySync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	downSince := func(d time.Duration) metav1.Time {
+		return metav1.NewTime(time.Now().Add(-d))
+	}
+	failureStores := func(tc *v1beta1.TikvCluster) {
+		tc.Annotations = map[string]string{label.AnnTiKVUnsafeRecovery: "1"}
+		tc.Status.TiKV.Stores = map[string]v1beta1.TiKVStore{
+			"1": {ID: "1", State: v1beta1.TiKVStateUp},
+			"4": {ID: "4", State: v1beta1.TiKVStateDown, LastTransitionTime: downSince(2 * time.Hour)},
+			"5": {ID: "5", State: v1beta1.TiKVStateDown, LastTransitionTime: downSince(3 * time.Hour)},
+		}
+		tc.Status.TiKV.FailureStores = map[string]v1beta1.TiKVFailureStore{
+			"4": {PodName: "test-tikv-1", StoreID: "4"},
+			"5": {PodName: "test-tikv-2", StoreID: "5"},
+		}
+	}
+	running := func(tc *v1beta1.TikvCluster) {
+		failureStores(tc)
+		tc.Status.TiKV.UnsafeRecovery = &v1beta1.TiKVUnsafeRecoveryStatus{
+			Trigger:      "1",
+			Phase:        v1beta1.TiKVUnsafeRecoveryRunning,
+			FailedStores: []string{"4", "5"},
+		}
+	}
+
+	type testcase struct {
+		name            string
+		update          func(*v1beta1.TikvCluster)
+		removeErr       bool
+		stages          []pdapi.UnsafeRecoveryStage
+		downPeerRegions int
+		errExpectFn     func(*GomegaWithT, error)
+		expectRemoved   []uint64
+		expectFn        func(*GomegaWithT, *v1beta1.TikvCluster)
+	}
+
+	tests := []testcase{
+		{
+			name:        "not triggered",
+			update:      func(tc *v1beta1.TikvCluster) {},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.TiKV.UnsafeRecovery).To(BeNil())
+			},
+		},
+		{
+			name: "trigger has been handled",
+			update: func(tc *v1beta1.TikvCluster) {
+				failureStores(tc)
+				tc.Status.TiKV.UnsafeRecovery = &v1beta1.TiKVUnsafeRecoveryStatus{
+					Trigger: "1",
+					Phase:   v1beta1.TiKVUnsafeRecoveryComplete,
+				}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.TiKV.UnsafeRecovery.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryComplete))
+			},
+		},
+		{
+			name: "no failure stores",
+			update: func(tc *v1beta1.TikvCluster) {
+				tc.Annotations = map[string]string{label.AnnTiKVUnsafeRecovery: "1"}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.TiKV.UnsafeRecovery
+				g.Expect(r.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryFailed))
+				g.Expect(r.Message).To(ContainSubstring("no failure stores"))
+			},
+		},
+		{
+			name: "failure store is not down",
+			update: func(tc *v1beta1.TikvCluster) {
+				failureStores(tc)
+				store := tc.Status.TiKV.Stores["4"]
+				store.State = v1beta1.TiKVStateUp
+				tc.Status.TiKV.Stores["4"] = store
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.TiKV.UnsafeRecovery
+				g.Expect(r.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryFailed))
+				g.Expect(r.Message).To(ContainSubstring("failure store 4 is Up"))
+			},
+		},
+		{
+			name: "failure store has not been down longer than the threshold",
+			update: func(tc *v1beta1.TikvCluster) {
+				failureStores(tc)
+				tc.Spec.TiKV.UnsafeRecoveryDownThreshold = &metav1.Duration{Duration: 150 * time.Minute}
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.TiKV.UnsafeRecovery
+				g.Expect(r.Trigger).To(Equal("1"))
+				g.Expect(r.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryFailed))
+				g.Expect(r.Message).To(ContainSubstring("less than the threshold 2h30m0s"))
+			},
+		},
+		{
+			name:        "failed stores are removed",
+			update:      failureStores,
+			errExpectFn: errExpectNil,
+			expectRemoved: []uint64{
+				4, 5,
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.TiKV.UnsafeRecovery
+				g.Expect(r.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryRunning))
+				g.Expect(r.FailedStores).To(Equal([]string{"4", "5"}))
+				g.Expect(tc.TiKVUnsafeRecovering()).To(BeTrue())
+			},
+		},
+		{
+			name:      "failed to request pd",
+			update:    failureStores,
+			removeErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.TiKV.UnsafeRecovery).To(BeNil())
+			},
+		},
+		{
+			name:   "recovery is in progress",
+			update: running,
+			stages: []pdapi.UnsafeRecoveryStage{
+				{Info: "Unsafe recovery enters collect report stage"},
+				{Info: "Unsafe recovery enters force leader stage"},
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.TiKV.UnsafeRecovery
+				g.Expect(r.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryRunning))
+				g.Expect(r.Progress).To(Equal("Unsafe recovery enters force leader stage"))
+			},
+		},
+		{
+			name:   "recovery failed",
+			update: running,
+			stages: []pdapi.UnsafeRecoveryStage{
+				{Info: "Unsafe recovery enters collect report stage"},
+				{Info: "Unsafe recovery failed: exceeds timeout"},
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				r := tc.Status.TiKV.UnsafeRecovery
+				g.Expect(r.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryFailed))
+				g.Expect(r.Message).To(ContainSubstring("exceeds timeout"))
+			},
+		},
+		{
+			name:   "waiting for regions to be healthy",
+			update: running,
+			stages: []pdapi.UnsafeRecoveryStage{
+				{Info: "Unsafe recovery finished"},
+			},
+			downPeerRegions: 3,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.TiKV.UnsafeRecovery.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryRunning))
+			},
+		},
+		{
+			name:   "recovery is complete",
+			update: running,
+			stages: []pdapi.UnsafeRecoveryStage{
+				{Info: "Unsafe recovery finished"},
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster) {
+				g.Expect(tc.Status.TiKV.UnsafeRecovery.Phase).To(Equal(v1beta1.TiKVUnsafeRecoveryComplete))
+				g.Expect(tc.TiKVUnsafeRecovering()).To(BeFalse())
+			},
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			tc := newTikvClusterForPD()
+			tt.update(tc)
+
+			pdControl := pdapi.NewFakePDControl(kubefake.NewSimpleClientset())
+			pdClient := controller.NewFakePDClient(pdControl, tc)
+			var removed []uint64
+			pdClient.AddReaction(pdapi.RemoveFailedStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+				if tt.removeErr {
+					return nil, fmt.Errorf("remove failed stores failed")
+				}
+				removed = action.StoreIDs
+				return nil, nil
+			})
+			pdClient.AddReaction(pdapi.GetUnsafeRecoveryProgressActionType, func(action *pdapi.Action) (interface{}, error) {
+				return tt.stages, nil
+			})
+			pdClient.AddReaction(pdapi.GetDownPeerRegionCountActionType, func(action *pdapi.Action) (interface{}, error) {
+				return tt.downPeerRegions, nil
+			})
+
+			tur := NewTiKVUnsafeRecovery(pdControl, record.NewFakeRecorder(10))
+			err := tur.Sync(tc)
+			tt.errExpectFn(g, err)
+			g.Expect(removed).To(Equal(tt.expectRemoved))
+			tt.expectFn(g, tc)
+		})
+	}
+}
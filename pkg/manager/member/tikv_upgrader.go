@@ -19,8 +19,9 @@ import (
 	"time"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -30,7 +31,7 @@ import (
 
 const (
 	// EvictLeaderBeginTime is the key of evict Leader begin time
-	EvictLeaderBeginTime = "evictLeaderBeginTime"
+	EvictLeaderBeginTime = label.AnnEvictLeaderBeginTime
 	// EvictLeaderTimeout is the timeout limit of evict leader
 	EvictLeaderTimeout = 3 * time.Minute
 )
@@ -52,10 +53,10 @@ func NewTiKVUpgrader(pdControl pdapi.PDControlInterface,
 	}
 }
 
-func (tku *tikvUpgrader) Upgrade(tc *v1alpha1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+func (tku *tikvUpgrader) Upgrade(tc *v1beta1.TikvCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	if tc.Status.PD.Phase == v1alpha1.UpgradePhase {
+	if tc.Status.PD.Phase == v1beta1.UpgradePhase {
 		_, podSpec, err := GetLastAppliedConfig(oldSet)
 		if err != nil {
 			return err
@@ -68,7 +69,7 @@ func (tku *tikvUpgrader) Upgrade(tc *v1alpha1.TikvCluster, oldSet *apps.Stateful
 		return fmt.Errorf("Tidbcluster: [%s/%s]'s tikv status sync failed, can not to be upgraded", ns, tcName)
 	}
 
-	tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+	tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 	if !templateEqual(newSet, oldSet) {
 		return nil
 	}
@@ -110,7 +111,7 @@ func (tku *tikvUpgrader) Upgrade(tc *v1alpha1.TikvCluster, oldSet *apps.Stateful
 			if pod.Status.Phase != corev1.PodRunning {
 				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not running", ns, tcName, podName)
 			}
-			if store.State != v1alpha1.TiKVStateUp {
+			if store.State != v1beta1.TiKVStateUp {
 				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not all ready", ns, tcName, podName)
 			}
 
@@ -123,7 +124,7 @@ func (tku *tikvUpgrader) Upgrade(tc *v1alpha1.TikvCluster, oldSet *apps.Stateful
 	return nil
 }
 
-func (tku *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TikvCluster, ordinal int32, newSet *apps.StatefulSet) error {
+func (tku *tikvUpgrader) upgradeTiKVPod(tc *v1beta1.TikvCluster, ordinal int32, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	upgradePodName := TikvPodName(tcName, ordinal)
@@ -159,7 +160,7 @@ func (tku *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TikvCluster, ordinal int32,
 	return controller.RequeueErrorf("tidbcluster: [%s/%s] no store status found for tikv pod: [%s]", ns, tcName, upgradePodName)
 }
 
-func (tku *tikvUpgrader) readyToUpgrade(upgradePod *corev1.Pod, store v1alpha1.TiKVStore) bool {
+func (tku *tikvUpgrader) readyToUpgrade(upgradePod *corev1.Pod, store v1beta1.TiKVStore) bool {
 	if store.LeaderCount == 0 {
 		return true
 	}
@@ -176,7 +177,7 @@ func (tku *tikvUpgrader) readyToUpgrade(upgradePod *corev1.Pod, store v1alpha1.T
 	return false
 }
 
-func (tku *tikvUpgrader) beginEvictLeader(tc *v1alpha1.TikvCluster, storeID uint64, pod *corev1.Pod) error {
+func (tku *tikvUpgrader) beginEvictLeader(tc *v1beta1.TikvCluster, storeID uint64, pod *corev1.Pod) error {
 	ns := tc.GetNamespace()
 	podName := pod.GetName()
 	err := controller.GetPDClient(tku.pdControl, tc).BeginEvictLeader(storeID)
@@ -191,6 +192,7 @@ func (tku *tikvUpgrader) beginEvictLeader(tc *v1alpha1.TikvCluster, storeID uint
 	}
 	now := time.Now().Format(time.RFC3339)
 	pod.Annotations[EvictLeaderBeginTime] = now
+	pod.Annotations[label.AnnEvictLeaderByUpgrader] = "true"
 	_, err = tku.podControl.UpdatePod(tc, pod)
 	if err != nil {
 		klog.Errorf("tikv upgrader: failed to set pod %s/%s annotation %s to %s, %v",
@@ -202,7 +204,7 @@ func (tku *tikvUpgrader) beginEvictLeader(tc *v1alpha1.TikvCluster, storeID uint
 	return nil
 }
 
-func (tku *tikvUpgrader) endEvictLeader(tc *v1alpha1.TikvCluster, ordinal int32) error {
+func (tku *tikvUpgrader) endEvictLeader(tc *v1beta1.TikvCluster, ordinal int32) error {
 	// wait 5 second before delete evict scheduler，it is for auto test can catch these info
 	if controller.TestMode {
 		time.Sleep(5 * time.Second)
@@ -213,7 +215,7 @@ func (tku *tikvUpgrader) endEvictLeader(tc *v1alpha1.TikvCluster, ordinal int32)
 		return err
 	}
 
-	err = tku.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsTLSClusterEnabled()).EndEvictLeader(storeID)
+	err = controller.GetPDClient(tku.pdControl, tc).EndEvictLeader(storeID)
 	if err != nil {
 		klog.Errorf("tikv upgrader: failed to end evict leader storeID: %d ordinal: %d, %v", storeID, ordinal, err)
 		return err
@@ -222,7 +224,7 @@ func (tku *tikvUpgrader) endEvictLeader(tc *v1alpha1.TikvCluster, ordinal int32)
 	return nil
 }
 
-func (tku *tikvUpgrader) getStoreByOrdinal(tc *v1alpha1.TikvCluster, ordinal int32) *v1alpha1.TiKVStore {
+func (tku *tikvUpgrader) getStoreByOrdinal(tc *v1beta1.TikvCluster, ordinal int32) *v1beta1.TiKVStore {
 	podName := TikvPodName(tc.GetName(), ordinal)
 	for _, store := range tc.Status.TiKV.Stores {
 		if store.PodName == podName {
@@ -239,7 +241,7 @@ func NewFakeTiKVUpgrader() Upgrader {
 	return &fakeTiKVUpgrader{}
 }
 
-func (tku *fakeTiKVUpgrader) Upgrade(tc *v1alpha1.TikvCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
-	tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+func (tku *fakeTiKVUpgrader) Upgrade(tc *v1beta1.TikvCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
+	tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 	return nil
 }
@@ -20,7 +20,7 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/pdapi"
@@ -44,14 +44,14 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 
 	type testcase struct {
 		name                string
-		changeFn            func(*v1alpha1.TikvCluster)
+		changeFn            func(*v1beta1.TikvCluster)
 		changeOldSet        func(set *apps.StatefulSet)
 		changePods          func([]*corev1.Pod)
 		beginEvictLeaderErr bool
 		endEvictLeaderErr   bool
 		updatePodErr        bool
 		errExpectFn         func(*GomegaWithT, error)
-		expectFn            func(*GomegaWithT, *v1alpha1.TikvCluster, *apps.StatefulSet, map[string]*corev1.Pod)
+		expectFn            func(*GomegaWithT, *v1beta1.TikvCluster, *apps.StatefulSet, map[string]*corev1.Pod)
 	}
 
 	testFn := func(test *testcase, t *testing.T) {
@@ -131,8 +131,8 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy).To(Equal(apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType}))
 			},
 		},
@@ -152,16 +152,16 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(newSet.Spec.UpdateStrategy).To(Equal(apps.StatefulSetUpdateStrategy{Type: apps.RollingUpdateStatefulSetStrategyType}))
 			},
 		},
 		{
 			name: "to upgrade the pod which ordinal is 2",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.NormalPhase
 				tc.Status.TiKV.Synced = true
 				// set leader to 0
 				store := tc.Status.TiKV.Stores["3"]
@@ -184,16 +184,16 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
 			},
 		},
 		{
 			name: "to upgrade the pod which ordinal is 1",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
 				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
@@ -221,15 +221,15 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(1)))
 			},
 		},
 		{
 			name: "newSet template changed",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.NormalPhase
 				tc.Status.TiKV.Synced = true
 			},
 			changeOldSet: func(oldSet *apps.StatefulSet) {
@@ -243,16 +243,16 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
 			},
 		},
 		{
 			name: "update revision equals current revision",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.NormalPhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.UpdateRevision = tc.Status.TiKV.StatefulSet.CurrentRevision
 			},
@@ -263,16 +263,16 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.UpgradePhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.UpgradePhase))
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
 			},
 		},
 		{
 			name: "tikv can not upgrade when pd is upgrading",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.UpgradePhase
-				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.UpgradePhase
+				tc.Status.TiKV.Phase = v1beta1.NormalPhase
 				tc.Status.TiKV.Synced = true
 			},
 			changeOldSet: func(oldSet *apps.StatefulSet) {
@@ -285,16 +285,16 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.NormalPhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.NormalPhase))
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
 			},
 		},
 		{
 			name: "get last apply config error",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.UpgradePhase
-				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.UpgradePhase
+				tc.Status.TiKV.Phase = v1beta1.NormalPhase
 				tc.Status.TiKV.Synced = true
 			},
 			changeOldSet: func(oldSet *apps.StatefulSet) {
@@ -307,16 +307,16 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).To(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
-				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.NormalPhase))
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1beta1.NormalPhase))
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
 			},
 		},
 		{
 			name: "begin evict leaders on store[2]",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
 				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
@@ -334,17 +334,18 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
 				_, exist := pods[TikvPodName(upgradeTcName, 1)].Annotations[EvictLeaderBeginTime]
 				g.Expect(exist).To(BeTrue())
+				g.Expect(pods[TikvPodName(upgradeTcName, 1)].Annotations).To(HaveKeyWithValue(label.AnnEvictLeaderByUpgrader, "true"))
 			},
 		},
 		{
 			name: "waiting leader count equals to 0",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
 				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
@@ -369,16 +370,16 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 				g.Expect(err.Error()).To(Equal("tidbcluster: [default/upgrader]'s tikv pod: [upgrader-tikv-1] is evicting leader"))
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
 			},
 		},
 
 		{
 			name: "failed to begin evict leaders on store[2]",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
 				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
@@ -396,7 +397,7 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).To(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
 				_, exist := pods[TikvPodName(upgradeTcName, 1)].Annotations[EvictLeaderBeginTime]
 				g.Expect(exist).To(BeFalse())
@@ -404,9 +405,9 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 		},
 		{
 			name: "evict leaders time out",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
 				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
@@ -430,15 +431,15 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(1)))
 			},
 		},
 		{
 			name: "end leader evict failed",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
 				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
@@ -462,15 +463,15 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).To(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
 			},
 		},
 		{
 			name: "update pod failed after begin evict leaders on store[2]",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
 				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
@@ -488,15 +489,15 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).To(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
 			},
 		},
 		{
 			name: "update pod failed",
-			changeFn: func(tc *v1alpha1.TikvCluster) {
-				tc.Status.PD.Phase = v1alpha1.NormalPhase
-				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			changeFn: func(tc *v1beta1.TikvCluster) {
+				tc.Status.PD.Phase = v1beta1.NormalPhase
+				tc.Status.TiKV.Phase = v1beta1.UpgradePhase
 				tc.Status.TiKV.Synced = true
 				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
 				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
@@ -520,7 +521,7 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			errExpectFn: func(g *GomegaWithT, err error) {
 				g.Expect(err).To(HaveOccurred())
 			},
-			expectFn: func(g *GomegaWithT, tc *v1alpha1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+			expectFn: func(g *GomegaWithT, tc *v1beta1.TikvCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
 			},
 		},
@@ -583,11 +584,11 @@ func oldStatefulSetForTiKVUpgrader() *apps.StatefulSet {
 	return set
 }
 
-func newTikvClusterForTiKVUpgrader() *v1alpha1.TikvCluster {
-	return &v1alpha1.TikvCluster{
+func newTikvClusterForTiKVUpgrader() *v1beta1.TikvCluster {
+	return &v1beta1.TikvCluster{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "TikvCluster",
-			APIVersion: "tikv.org/v1alpha1",
+			APIVersion: "tikv.org/v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      upgradeTcName,
@@ -595,26 +596,28 @@ func newTikvClusterForTiKVUpgrader() *v1alpha1.TikvCluster {
 			UID:       types.UID(upgradeTcName),
 			Labels:    label.New().Instance(upgradeInstanceName).TiKV().Labels(),
 		},
-		Spec: v1alpha1.TikvClusterSpec{
-			PD: v1alpha1.PDSpec{
-				ComponentSpec: v1alpha1.ComponentSpec{
-					Image: "pd-test-image",
+		Spec: v1beta1.TikvClusterSpec{
+			PD: v1beta1.PDSpec{
+				ComponentSpec: v1beta1.ComponentSpec{
+					Version: "v1",
 				},
+				BaseImage:        "pd-test-image",
 				Replicas:         3,
 				StorageClassName: pointer.StringPtr("my-storage-class"),
 			},
-			TiKV: v1alpha1.TiKVSpec{
-				ComponentSpec: v1alpha1.ComponentSpec{
-					Image: "tikv-test-image",
+			TiKV: v1beta1.TiKVSpec{
+				ComponentSpec: v1beta1.ComponentSpec{
+					Version: "v1",
 				},
+				BaseImage:        "tikv-test-image",
 				Replicas:         3,
 				StorageClassName: pointer.StringPtr("my-storage-class"),
 			},
 		},
-		Status: v1alpha1.TikvClusterStatus{
-			TiKV: v1alpha1.TiKVStatus{
+		Status: v1beta1.TikvClusterStatus{
+			TiKV: v1beta1.TiKVStatus{
 				Synced: true,
-				Phase:  v1alpha1.UpgradePhase,
+				Phase:  v1beta1.UpgradePhase,
 				StatefulSet: &apps.StatefulSetStatus{
 					Replicas:        3,
 					CurrentReplicas: 3,
@@ -622,7 +625,7 @@ func newTikvClusterForTiKVUpgrader() *v1alpha1.TikvCluster {
 					CurrentRevision: "1",
 					UpdateRevision:  "2",
 				},
-				Stores: map[string]v1alpha1.TiKVStore{
+				Stores: map[string]v1beta1.TiKVStore{
 					"1": {
 						ID:          "1",
 						PodName:     TikvPodName(upgradeTcName, 0),
@@ -14,12 +14,12 @@
 package member
 
 import (
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	apps "k8s.io/api/apps/v1"
 )
 
 // Upgrader implements the logic for upgrading the tidb cluster.
 type Upgrader interface {
 	// Upgrade upgrade the cluster
-	Upgrade(*v1alpha1.TikvCluster, *apps.StatefulSet, *apps.StatefulSet) error
+	Upgrade(*v1beta1.TikvCluster, *apps.StatefulSet, *apps.StatefulSet) error
 }
@@ -18,10 +18,11 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/util"
@@ -159,7 +160,7 @@ func imagePullFailed(pod *corev1.Pod) bool {
 	return false
 }
 
-func MemberPodName(tcName string, ordinal int32, memberType v1alpha1.MemberType) string {
+func MemberPodName(tcName string, ordinal int32, memberType v1beta1.MemberType) string {
 	return fmt.Sprintf("%s-%s-%d", tcName, memberType.String(), ordinal)
 }
 
@@ -183,7 +184,7 @@ func CombineAnnotations(a, b map[string]string) map[string]string {
 }
 
 // NeedForceUpgrade check if force upgrade is necessary
-func NeedForceUpgrade(tc *v1alpha1.TikvCluster) bool {
+func NeedForceUpgrade(tc *v1beta1.TikvCluster) bool {
 	// Check if annotation 'tikv.org/force-upgrade: "true"' is set
 	if tc.Annotations != nil {
 		forceVal, ok := tc.Annotations[label.AnnForceUpgradeKey]
@@ -204,6 +205,20 @@ func FindConfigMapVolume(podSpec *corev1.PodSpec, pred func(string) bool) string
 	return ""
 }
 
+// keepInUseConfigMapName keeps the name of the configmap in use by the set if the config is
+// updated in place, otherwise the name with the digest suffix triggers the rolling update
+func keepInUseConfigMapName(cm *corev1.ConfigMap, set *apps.StatefulSet, strategy v1beta1.ConfigUpdateStrategy, prefix string) {
+	if set == nil || strategy != v1beta1.ConfigUpdateStrategyInPlace {
+		return
+	}
+	inUseName := FindConfigMapVolume(&set.Spec.Template.Spec, func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+	if inUseName != "" {
+		cm.Name = inUseName
+	}
+}
+
 // MarshalTOML is a template function that try to marshal a go value to toml
 func MarshalTOML(v interface{}) ([]byte, error) {
 	buff := new(bytes.Buffer)
@@ -240,7 +255,7 @@ func AddConfigMapDigestSuffix(cm *corev1.ConfigMap) error {
 }
 
 // getStsAnnotations gets annotations for statefulset of given component.
-func getStsAnnotations(tc *v1alpha1.TikvCluster, component string) map[string]string {
+func getStsAnnotations(tc *v1beta1.TikvCluster, component string) map[string]string {
 	anns := map[string]string{}
 	tcAnns := tc.Annotations
 	if tcAnns == nil {
@@ -271,7 +286,7 @@ func MapContainers(podSpec *corev1.PodSpec) map[string]corev1.Container {
 }
 
 // updateStatefulSet is a template function to update the statefulset of components
-func updateStatefulSet(setCtl controller.StatefulSetControlInterface, tc *v1alpha1.TikvCluster, newSet, oldSet *apps.StatefulSet) error {
+func updateStatefulSet(setCtl controller.StatefulSetControlInterface, tc *v1beta1.TikvCluster, newSet, oldSet *apps.StatefulSet) error {
 	isOrphan := metav1.GetControllerOf(oldSet) == nil
 	if newSet.Annotations == nil {
 		newSet.Annotations = map[string]string{}
@@ -309,7 +324,7 @@ func updateStatefulSet(setCtl controller.StatefulSetControlInterface, tc *v1alph
 	return nil
 }
 
-func clusterSecretName(tc *v1alpha1.TikvCluster, component string) string {
+func clusterSecretName(tc *v1beta1.TikvCluster, component string) string {
 	return fmt.Sprintf("%s-%s-cluster-secret", tc.Name, component)
 }
 
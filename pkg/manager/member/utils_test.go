@@ -19,7 +19,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/label"
 	apps "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -102,13 +102,13 @@ func TestStatefulSetIsUpgrading(t *testing.T) {
 func TestGetStsAnnotations(t *testing.T) {
 	tests := []struct {
 		name      string
-		tc        *v1alpha1.TikvCluster
+		tc        *v1beta1.TikvCluster
 		component string
 		expected  map[string]string
 	}{
 		{
 			name: "nil",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: nil,
 				},
@@ -118,7 +118,7 @@ func TestGetStsAnnotations(t *testing.T) {
 		},
 		{
 			name: "empty",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{},
 				},
@@ -128,7 +128,7 @@ func TestGetStsAnnotations(t *testing.T) {
 		},
 		{
 			name: "tikv",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						label.AnnTiKVDeleteSlots: "[1,2]",
@@ -16,7 +16,7 @@ package meta
 import (
 	"errors"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	"github.com/tikv/tikv-operator/pkg/manager"
@@ -55,7 +55,7 @@ func NewMetaManager(
 	}
 }
 
-func (pmm *metaManager) Sync(tc *v1alpha1.TikvCluster) error {
+func (pmm *metaManager) Sync(tc *v1beta1.TikvCluster) error {
 	ns := tc.GetNamespace()
 
 	l, err := label.New().Selector()
@@ -145,6 +145,6 @@ func (fmm *FakeMetaManager) SetSyncError(err error) {
 	fmm.err = err
 }
 
-func (fmm *FakeMetaManager) Sync(_ *v1alpha1.TikvCluster) error {
+func (fmm *FakeMetaManager) Sync(_ *v1beta1.TikvCluster) error {
 	return fmm.err
 }
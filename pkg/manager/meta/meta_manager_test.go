@@ -18,7 +18,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/controller"
 	"github.com/tikv/tikv-operator/pkg/label"
 	corev1 "k8s.io/api/core/v1"
@@ -231,8 +231,8 @@ func TestMetaManagerSync(t *testing.T) {
 			}
 
 			if !tt.podRefPvc {
-				pod1.Spec = newPodSpec(v1alpha1.TiKVMemberType.String(), pvc1.GetName())
-				pod1.Labels[label.ComponentLabelKey] = v1alpha1.TiKVMemberType.String()
+				pod1.Spec = newPodSpec(v1beta1.TiKVMemberType.String(), pvc1.GetName())
+				pod1.Labels[label.ComponentLabelKey] = v1beta1.TiKVMemberType.String()
 			}
 
 			nmm, fakePodControl, fakePVCControl, fakePVControl, podIndexer, pvcIndexer, pvIndexer := newFakeMetaManager()
@@ -538,8 +538,8 @@ func TestMetaManagerSyncMultiPVC(t *testing.T) {
 			}
 
 			if !tt.podRefPvc {
-				pod1.Spec = newPodSpec(v1alpha1.TiKVMemberType.String(), pvc1.GetName())
-				pod1.Labels[label.ComponentLabelKey] = v1alpha1.TiKVMemberType.String()
+				pod1.Spec = newPodSpec(v1beta1.TiKVMemberType.String(), pvc1.GetName())
+				pod1.Labels[label.ComponentLabelKey] = v1beta1.TiKVMemberType.String()
 			}
 
 			nmm, fakePodControl, fakePVCControl, fakePVControl, podIndexer, pvcIndexer, pvIndexer := newFakeMetaManager()
@@ -737,7 +737,7 @@ func pvMetaInfoMatchDesire(ns string, pv *corev1.PersistentVolume) bool {
 		pv.Annotations[label.AnnPodNameKey] == controller.TestPodName
 }
 
-func newPod(tc *v1alpha1.TikvCluster) *corev1.Pod {
+func newPod(tc *v1beta1.TikvCluster) *corev1.Pod {
 	return &corev1.Pod{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Pod",
@@ -753,11 +753,11 @@ func newPod(tc *v1alpha1.TikvCluster) *corev1.Pod {
 				label.ManagedByLabelKey: controller.TestManagedByName,
 			},
 		},
-		Spec: newPodSpec(v1alpha1.PDMemberType.String(), "pvc-1"),
+		Spec: newPodSpec(v1beta1.PDMemberType.String(), "pvc-1"),
 	}
 }
 
-func newPodMultiPVC(tc *v1alpha1.TikvCluster) *corev1.Pod {
+func newPodMultiPVC(tc *v1beta1.TikvCluster) *corev1.Pod {
 	return &corev1.Pod{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Pod",
@@ -834,18 +834,18 @@ func newPodSpec(volumeName, pvcName string) corev1.PodSpec {
 	}
 }
 
-func newTikvClusterForMeta() *v1alpha1.TikvCluster {
-	return &v1alpha1.TikvCluster{
+func newTikvClusterForMeta() *v1beta1.TikvCluster {
+	return &v1beta1.TikvCluster{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "TikvCluster",
-			APIVersion: "tikv.org/v1alpha1",
+			APIVersion: "tikv.org/v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      controller.TestClusterName,
 			Namespace: corev1.NamespaceDefault,
 			UID:       types.UID("test"),
 		},
-		Spec: v1alpha1.TikvClusterSpec{},
+		Spec: v1beta1.TikvClusterSpec{},
 	}
 }
 
@@ -873,7 +873,7 @@ func newPV(index string) *corev1.PersistentVolume {
 	}
 }
 
-func newPVC(tc *v1alpha1.TikvCluster, index string) *corev1.PersistentVolumeClaim {
+func newPVC(tc *v1beta1.TikvCluster, index string) *corev1.PersistentVolumeClaim {
 	return &corev1.PersistentVolumeClaim{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "PersistentVolumeClaim",
@@ -0,0 +1,265 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"k8s.io/klog"
+)
+
+// externalPDClient is the PDClient of the external pd cluster. It requests the
+// endpoint which responded successfully last time, and tries the other endpoints
+// in turn if the request fails.
+type externalPDClient struct {
+	mutex     sync.Mutex
+	endpoints []string
+	clients   []PDClient
+	// current is the index of the endpoint to request first
+	current int
+}
+
+// NewExternalPDClient returns a PDClient of the external pd cluster which requests
+// the given endpoints in turn
+func NewExternalPDClient(endpoints []string, timeout time.Duration, tlsConfig *tls.Config) PDClient {
+	c := &externalPDClient{}
+	for _, endpoint := range endpoints {
+		url := strings.TrimSuffix(endpoint, "/")
+		c.endpoints = append(c.endpoints, url)
+		c.clients = append(c.clients, NewPDClient(url, timeout, tlsConfig))
+	}
+	return c
+}
+
+// do calls fn with the client of each endpoint in turn until it succeeds, the
+// error of the last endpoint is returned if all of them fail.
+func (c *externalPDClient) do(fn func(PDClient) error) error {
+	if len(c.clients) == 0 {
+		return fmt.Errorf("no endpoint of the external pd cluster is given")
+	}
+	c.mutex.Lock()
+	start := c.current
+	c.mutex.Unlock()
+
+	var err error
+	for i := 0; i < len(c.clients); i++ {
+		idx := (start + i) % len(c.clients)
+		if err = fn(c.clients[idx]); err == nil {
+			c.mutex.Lock()
+			c.current = idx
+			c.mutex.Unlock()
+			return nil
+		}
+		klog.Warningf("failed to request the external pd endpoint %s: %v", c.endpoints[idx], err)
+	}
+	return err
+}
+
+func (c *externalPDClient) GetHealth() (*HealthInfo, error) {
+	var result *HealthInfo
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetHealth()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) GetConfig() (*PDConfigFromAPI, error) {
+	var result *PDConfigFromAPI
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetConfig()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) GetCluster() (*metapb.Cluster, error) {
+	var result *metapb.Cluster
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetCluster()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) GetMembers() (*MembersInfo, error) {
+	var result *MembersInfo
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetMembers()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) GetStores() (*StoresInfo, error) {
+	var result *StoresInfo
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetStores()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) GetTombStoneStores() (*StoresInfo, error) {
+	var result *StoresInfo
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetTombStoneStores()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) GetStore(storeID uint64) (*StoreInfo, error) {
+	var result *StoreInfo
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetStore(storeID)
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) SetStoreLabels(storeID uint64, labels map[string]string) (bool, error) {
+	var result bool
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.SetStoreLabels(storeID, labels)
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) UpdateReplicationConfig(config PDReplicationConfig) error {
+	return c.do(func(client PDClient) error {
+		return client.UpdateReplicationConfig(config)
+	})
+}
+
+func (c *externalPDClient) DeleteStore(storeID uint64) error {
+	return c.do(func(client PDClient) error {
+		return client.DeleteStore(storeID)
+	})
+}
+
+func (c *externalPDClient) SetStoreState(storeID uint64, state string) error {
+	return c.do(func(client PDClient) error {
+		return client.SetStoreState(storeID, state)
+	})
+}
+
+func (c *externalPDClient) DeleteMember(name string) error {
+	return c.do(func(client PDClient) error {
+		return client.DeleteMember(name)
+	})
+}
+
+func (c *externalPDClient) DeleteMemberByID(memberID uint64) error {
+	return c.do(func(client PDClient) error {
+		return client.DeleteMemberByID(memberID)
+	})
+}
+
+func (c *externalPDClient) BeginEvictLeader(storeID uint64) error {
+	return c.do(func(client PDClient) error {
+		return client.BeginEvictLeader(storeID)
+	})
+}
+
+func (c *externalPDClient) EndEvictLeader(storeID uint64) error {
+	return c.do(func(client PDClient) error {
+		return client.EndEvictLeader(storeID)
+	})
+}
+
+func (c *externalPDClient) GetEvictLeaderSchedulers() ([]string, error) {
+	var result []string
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetEvictLeaderSchedulers()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) GetSchedulers() ([]string, error) {
+	var result []string
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetSchedulers()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) PauseScheduler(name string, delay int64) error {
+	return c.do(func(client PDClient) error {
+		return client.PauseScheduler(name, delay)
+	})
+}
+
+func (c *externalPDClient) UpdateScheduleConfigWithTTL(config map[string]interface{}, ttl int64) error {
+	return c.do(func(client PDClient) error {
+		return client.UpdateScheduleConfigWithTTL(config, ttl)
+	})
+}
+
+func (c *externalPDClient) GetPDLeader() (*pdpb.Member, error) {
+	var result *pdpb.Member
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetPDLeader()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) TransferPDLeader(name string) error {
+	return c.do(func(client PDClient) error {
+		return client.TransferPDLeader(name)
+	})
+}
+
+func (c *externalPDClient) RemoveFailedStores(storeIDs []uint64) error {
+	return c.do(func(client PDClient) error {
+		return client.RemoveFailedStores(storeIDs)
+	})
+}
+
+func (c *externalPDClient) GetUnsafeRecoveryProgress() ([]UnsafeRecoveryStage, error) {
+	var result []UnsafeRecoveryStage
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetUnsafeRecoveryProgress()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) GetDownPeerRegionCount() (int, error) {
+	var result int
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.GetDownPeerRegionCount()
+		return
+	})
+	return result, err
+}
+
+func (c *externalPDClient) UpdateServiceGCSafePoint(serviceID string, ttl int64, safePoint uint64) (uint64, error) {
+	var result uint64
+	err := c.do(func(client PDClient) (err error) {
+		result, err = client.UpdateServiceGCSafePoint(serviceID, ttl, safePoint)
+		return
+	})
+	return result, err
+}
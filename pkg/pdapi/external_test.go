@@ -0,0 +1,192 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExternalPDClient(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	clusterBytes, err := json.Marshal(&metapb.Cluster{Id: 1})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	type testcase struct {
+		name           string
+		healthy        []bool
+		expectErr      bool
+		expectRequests []int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		requests := make([]int, len(test.healthy))
+		endpoints := []string{}
+		for i := range test.healthy {
+			i := i
+			svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+				g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s", clusterIDPrefix)), "check url")
+				requests[i]++
+				if !test.healthy[i] {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", ContentTypeJSON)
+				w.Write(clusterBytes)
+			})
+			defer svc.Close()
+			endpoints = append(endpoints, svc.URL+"/")
+		}
+
+		pdClient := NewExternalPDClient(endpoints, DefaultTimeout, nil)
+		// the second request goes to the endpoint which responded last time
+		for i := 0; i < 2; i++ {
+			cluster, err := pdClient.GetCluster()
+			if test.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				continue
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cluster.Id).To(Equal(uint64(1)))
+		}
+		g.Expect(requests).To(Equal(test.expectRequests))
+	}
+
+	tests := []testcase{
+		{
+			name:           "the first endpoint is healthy",
+			healthy:        []bool{true, true},
+			expectRequests: []int{2, 0},
+		},
+		{
+			name:           "the first endpoint fails",
+			healthy:        []bool{false, true, true},
+			expectRequests: []int{1, 2, 0},
+		},
+		{
+			name:           "all endpoints fail",
+			healthy:        []bool{false, false},
+			expectErr:      true,
+			expectRequests: []int{2, 2},
+		},
+		{
+			name:           "no endpoint",
+			expectErr:      true,
+			expectRequests: []int{},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestGetExternalPDClient(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name          string
+		tlsSecretName string
+		secret        bool
+		expectCached  bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		kubeCli := kubefake.NewSimpleClientset()
+		if test.secret {
+			certPEM, keyPEM := newTestCertificate(g)
+			_, err := kubeCli.CoreV1().Secrets("default").Create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: test.tlsSecretName, Namespace: "default"},
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       certPEM,
+					corev1.TLSPrivateKeyKey: keyPEM,
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		pdControl := NewDefaultPDControl(kubeCli)
+		endpoints := []string{"http://pd-0:2379", "http://pd-1:2379"}
+
+		first := pdControl.GetExternalPDClient("default", endpoints, test.tlsSecretName)
+		second := pdControl.GetExternalPDClient("default", endpoints, test.tlsSecretName)
+		if test.expectCached {
+			g.Expect(second).To(BeIdenticalTo(first))
+		} else {
+			g.Expect(second).NotTo(BeIdenticalTo(first))
+		}
+		// the client of other endpoints is never shared
+		other := pdControl.GetExternalPDClient("default", endpoints[:1], test.tlsSecretName)
+		g.Expect(other).NotTo(BeIdenticalTo(first))
+	}
+
+	tests := []testcase{
+		{
+			name:         "tls is not enabled",
+			expectCached: true,
+		},
+		{
+			name:          "tls is enabled",
+			tlsSecretName: "external-pd-tls",
+			secret:        true,
+			expectCached:  true,
+		},
+		{
+			name:          "tls secret is not found",
+			tlsSecretName: "external-pd-tls",
+			expectCached:  false,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func newTestCertificate(g *GomegaWithT) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tikv-operator"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	g.Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
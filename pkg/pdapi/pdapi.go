@@ -48,6 +48,9 @@ type PDControlInterface interface {
 	GetPDClient(Namespace, string, bool) PDClient
 	// GetPDEtcdClient provides PD etcd Client of the tidb cluster.
 	GetPDEtcdClient(namespace Namespace, tcName string, tlsEnabled bool) (PDEtcdClient, error)
+	// GetExternalPDClient provides PDClient of the external PD cluster which the tidb cluster joins,
+	// the client certificate is loaded from the secret in the namespace if tlsSecretName is not empty.
+	GetExternalPDClient(namespace Namespace, endpoints []string, tlsSecretName string) PDClient
 }
 
 // defaultPDControl is the default implementation of PDControlInterface.
@@ -67,7 +70,12 @@ func NewDefaultPDControl(kubeCli kubernetes.Interface) PDControlInterface {
 // GetTLSConfig returns *tls.Config for given TiDB cluster.
 // It loads in-cluster root ca if caCert is empty.
 func GetTLSConfig(kubeCli kubernetes.Interface, namespace Namespace, tcName string, caCert []byte) (*tls.Config, error) {
-	secretName := util.ClusterClientTLSSecretName(tcName)
+	return GetTLSConfigFromSecret(kubeCli, namespace, util.ClusterClientTLSSecretName(tcName), caCert)
+}
+
+// GetTLSConfigFromSecret returns *tls.Config loaded from the given secret.
+// It loads the root ca from the secret if caCert is empty.
+func GetTLSConfigFromSecret(kubeCli kubernetes.Interface, namespace Namespace, secretName string, caCert []byte) (*tls.Config, error) {
 	secret, err := kubeCli.CoreV1().Secrets(string(namespace)).Get(secretName, types.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to load certificates from secret %s/%s: %v", namespace, secretName, err)
@@ -129,6 +137,34 @@ func (pdc *defaultPDControl) GetPDClient(namespace Namespace, tcName string, tls
 	return pdc.pdClients[key]
 }
 
+// GetExternalPDClient provides a PDClient of the external pd cluster, the endpoints are
+// requested in turn until one of them responds.
+func (pdc *defaultPDControl) GetExternalPDClient(namespace Namespace, endpoints []string, tlsSecretName string) PDClient {
+	pdc.mutex.Lock()
+	defer pdc.mutex.Unlock()
+
+	if tlsSecretName != "" {
+		key := externalTLSPDClientKey(namespace, tlsSecretName, endpoints)
+		if client, ok := pdc.pdClients[key]; ok {
+			return client
+		}
+		tlsConfig, err := GetTLSConfigFromSecret(pdc.kubeCli, namespace, tlsSecretName, nil)
+		if err != nil {
+			// not cached, the tls config is loaded again on the next call
+			klog.Errorf("Unable to get tls config for external pd %v, pd client may not work: %v", endpoints, err)
+			return NewExternalPDClient(endpoints, DefaultTimeout, nil)
+		}
+		pdc.pdClients[key] = NewExternalPDClient(endpoints, DefaultTimeout, tlsConfig)
+		return pdc.pdClients[key]
+	}
+
+	key := externalPDClientKey(endpoints)
+	if _, ok := pdc.pdClients[key]; !ok {
+		pdc.pdClients[key] = NewExternalPDClient(endpoints, DefaultTimeout, nil)
+	}
+	return pdc.pdClients[key]
+}
+
 // pdClientKey returns the pd client key
 func pdClientKey(scheme string, namespace Namespace, clusterName string) string {
 	return fmt.Sprintf("%s.%s.%s", scheme, clusterName, string(namespace))
@@ -143,6 +179,14 @@ func PdClientURL(namespace Namespace, clusterName string, scheme string) string
 	return fmt.Sprintf("%s://%s-pd.%s:2379", scheme, clusterName, string(namespace))
 }
 
+func externalPDClientKey(endpoints []string) string {
+	return fmt.Sprintf("external.%s", strings.Join(endpoints, ","))
+}
+
+func externalTLSPDClientKey(namespace Namespace, tlsSecretName string, endpoints []string) string {
+	return fmt.Sprintf("external-tls.%s.%s.%s", string(namespace), tlsSecretName, strings.Join(endpoints, ","))
+}
+
 func PDEtcdClientURL(namespace Namespace, clusterName string) string {
 	return fmt.Sprintf("%s-pd.%s:2379", clusterName, string(namespace))
 }
@@ -183,10 +227,29 @@ type PDClient interface {
 	EndEvictLeader(storeID uint64) error
 	// GetEvictLeaderSchedulers gets schedulers of evict leader
 	GetEvictLeaderSchedulers() ([]string, error)
+	// GetSchedulers gets the names of all the schedulers
+	GetSchedulers() ([]string, error)
+	// PauseScheduler pauses the scheduler for delay seconds, the scheduler is
+	// resumed if delay is not positive
+	PauseScheduler(name string, delay int64) error
+	// UpdateScheduleConfigWithTTL overrides the schedule config for ttl seconds, the
+	// overrides are removed if ttl is not positive
+	UpdateScheduleConfigWithTTL(config map[string]interface{}, ttl int64) error
 	// GetPDLeader returns pd leader
 	GetPDLeader() (*pdpb.Member, error)
 	// TransferPDLeader transfers pd leader to specified member
 	TransferPDLeader(name string) error
+	// RemoveFailedStores starts the online unsafe recovery which removes the
+	// failed stores from the regions that lost the majority
+	RemoveFailedStores(storeIDs []uint64) error
+	// GetUnsafeRecoveryProgress returns the stages of the online unsafe recovery
+	GetUnsafeRecoveryProgress() ([]UnsafeRecoveryStage, error)
+	// GetDownPeerRegionCount returns the number of regions which have down peers
+	GetDownPeerRegionCount() (int, error)
+	// UpdateServiceGCSafePoint keeps the gc safe point from exceeding the safe point of the
+	// service for ttl seconds, the service is removed if ttl is not positive. It returns the
+	// minimum service gc safe point.
+	UpdateServiceGCSafePoint(serviceID string, ttl int64, safePoint uint64) (uint64, error)
 }
 
 var (
@@ -200,12 +263,15 @@ var (
 	pdLeaderPrefix         = "pd/api/v1/leader"
 	pdLeaderTransferPrefix = "pd/api/v1/leader/transfer"
 	pdReplicationPrefix    = "pd/api/v1/config/replicate"
+	unsafeRecoveryPrefix   = "pd/api/v1/admin/unsafe/remove-failed-stores"
+	downPeerRegionsPrefix  = "pd/api/v1/regions/check/down-peer"
 )
 
 // pdClient is default implementation of PDClient
 type pdClient struct {
 	url        string
 	httpClient *http.Client
+	tlsConfig  *tls.Config
 }
 
 // NewPDClient returns a new PDClient
@@ -216,6 +282,7 @@ func NewPDClient(url string, timeout time.Duration, tlsConfig *tls.Config) PDCli
 			Timeout:   timeout,
 			Transport: &http.Transport{TLSClientConfig: tlsConfig},
 		},
+		tlsConfig: tlsConfig,
 	}
 }
 
@@ -278,6 +345,34 @@ type MembersInfo struct {
 	EtcdLeader *pdpb.Member         `json:"etcd_leader,omitempty"`
 }
 
+// UnsafeRecoveryStage is a stage of the online unsafe recovery returned from PD RESTful interface
+type UnsafeRecoveryStage struct {
+	Info    string              `json:"info"`
+	Time    string              `json:"time"`
+	Actions map[string][]string `json:"actions,omitempty"`
+	Details []string            `json:"details,omitempty"`
+}
+
+const (
+	unsafeRecoveryFinishedInfo = "Unsafe recovery finished"
+	unsafeRecoveryFailedInfo   = "Unsafe recovery failed"
+)
+
+// IsFinished returns true if the online unsafe recovery is finished successfully
+func (s UnsafeRecoveryStage) IsFinished() bool {
+	return strings.HasPrefix(s.Info, unsafeRecoveryFinishedInfo)
+}
+
+// IsFailed returns true if the online unsafe recovery is failed
+func (s UnsafeRecoveryStage) IsFailed() bool {
+	return strings.HasPrefix(s.Info, unsafeRecoveryFailedInfo)
+}
+
+// regionsCount is the count of regions returned from PD RESTful interface
+type regionsCount struct {
+	Count int `json:"count"`
+}
+
 type schedulerInfo struct {
 	Name    string `json:"name"`
 	StoreID uint64 `json:"store_id"`
@@ -573,7 +668,7 @@ func (pc *pdClient) BeginEvictLeader(storeID uint64) error {
 		return err
 	}
 	for _, s := range evictLeaderSchedulers {
-		if s == getLeaderEvictSchedulerStr(storeID) {
+		if s == GetLeaderEvictSchedulerStr(storeID) {
 			return nil
 		}
 	}
@@ -583,7 +678,7 @@ func (pc *pdClient) BeginEvictLeader(storeID uint64) error {
 }
 
 func (pc *pdClient) EndEvictLeader(storeID uint64) error {
-	sName := getLeaderEvictSchedulerStr(storeID)
+	sName := GetLeaderEvictSchedulerStr(storeID)
 	apiURL := fmt.Sprintf("%s/%s/%s", pc.url, schedulersPrefix, sName)
 	req, err := http.NewRequest("DELETE", apiURL, nil)
 	if err != nil {
@@ -625,6 +720,20 @@ func (pc *pdClient) EndEvictLeader(storeID uint64) error {
 }
 
 func (pc *pdClient) GetEvictLeaderSchedulers() ([]string, error) {
+	schedulers, err := pc.GetSchedulers()
+	if err != nil {
+		return nil, err
+	}
+	evicts := []string{}
+	for _, scheduler := range schedulers {
+		if strings.HasPrefix(scheduler, "evict-leader-scheduler") {
+			evicts = append(evicts, scheduler)
+		}
+	}
+	return evicts, nil
+}
+
+func (pc *pdClient) GetSchedulers() ([]string, error) {
 	apiURL := fmt.Sprintf("%s/%s", pc.url, schedulersPrefix)
 	body, err := httputil.GetBodyOK(pc.httpClient, apiURL)
 	if err != nil {
@@ -635,13 +744,54 @@ func (pc *pdClient) GetEvictLeaderSchedulers() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	evicts := []string{}
-	for _, scheduler := range schedulers {
-		if strings.HasPrefix(scheduler, "evict-leader-scheduler") {
-			evicts = append(evicts, scheduler)
-		}
+	return schedulers, nil
+}
+
+func (pc *pdClient) PauseScheduler(name string, delay int64) error {
+	if delay < 0 {
+		delay = 0
 	}
-	return evicts, nil
+	apiURL := fmt.Sprintf("%s/%s/%s", pc.url, schedulersPrefix, name)
+	data, err := json.Marshal(map[string]int64{"delay": delay})
+	if err != nil {
+		return err
+	}
+	res, err := pc.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to pause scheduler %s: %v", res.StatusCode, name, err)
+}
+
+func (pc *pdClient) UpdateScheduleConfigWithTTL(config map[string]interface{}, ttl int64) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	// the config with ttl is keyed by the full name, e.g. schedule.merge-schedule-limit
+	ttlConfig := map[string]interface{}{}
+	for k, v := range config {
+		ttlConfig["schedule."+k] = v
+	}
+	apiURL := fmt.Sprintf("%s/%s?ttlSecond=%d", pc.url, configPrefix, ttl)
+	data, err := json.Marshal(ttlConfig)
+	if err != nil {
+		return err
+	}
+	res, err := pc.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err = httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to update schedule config: %v", res.StatusCode, err)
 }
 
 func (pc *pdClient) GetPDLeader() (*pdpb.Member, error) {
@@ -676,6 +826,52 @@ func (pc *pdClient) TransferPDLeader(memberName string) error {
 	return fmt.Errorf("failed %v to transfer pd leader to %s,error: %v", res.StatusCode, memberName, err2)
 }
 
+func (pc *pdClient) RemoveFailedStores(storeIDs []uint64) error {
+	apiURL := fmt.Sprintf("%s/%s", pc.url, unsafeRecoveryPrefix)
+	data, err := json.Marshal(map[string][]uint64{"stores": storeIDs})
+	if err != nil {
+		return err
+	}
+	res, err := pc.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err2 := httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to remove failed stores %v: %v", res.StatusCode, storeIDs, err2)
+}
+
+func (pc *pdClient) GetUnsafeRecoveryProgress() ([]UnsafeRecoveryStage, error) {
+	apiURL := fmt.Sprintf("%s/%s/show", pc.url, unsafeRecoveryPrefix)
+	body, err := httputil.GetBodyOK(pc.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	stages := []UnsafeRecoveryStage{}
+	err = json.Unmarshal(body, &stages)
+	if err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+func (pc *pdClient) GetDownPeerRegionCount() (int, error) {
+	apiURL := fmt.Sprintf("%s/%s", pc.url, downPeerRegionsPrefix)
+	body, err := httputil.GetBodyOK(pc.httpClient, apiURL)
+	if err != nil {
+		return 0, err
+	}
+	regions := &regionsCount{}
+	err = json.Unmarshal(body, regions)
+	if err != nil {
+		return 0, err
+	}
+	return regions.Count, nil
+}
+
 func (pc *pdClient) getBodyOK(apiURL string) ([]byte, error) {
 	res, err := pc.httpClient.Get(apiURL)
 	if err != nil {
@@ -697,7 +893,8 @@ func getLeaderEvictSchedulerInfo(storeID uint64) *schedulerInfo {
 	return &schedulerInfo{"evict-leader-scheduler", storeID}
 }
 
-func getLeaderEvictSchedulerStr(storeID uint64) string {
+// GetLeaderEvictSchedulerStr returns the name of the evict leader scheduler of the store
+func GetLeaderEvictSchedulerStr(storeID uint64) string {
 	return fmt.Sprintf("%s-%d", "evict-leader-scheduler", storeID)
 }
 
@@ -707,7 +904,7 @@ type FakePDControl struct {
 
 func NewFakePDControl(kubeCli kubernetes.Interface) *FakePDControl {
 	return &FakePDControl{
-		defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}},
+		defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}, pdEtcdClients: map[string]PDEtcdClient{}},
 	}
 }
 
@@ -715,27 +912,42 @@ func (fpc *FakePDControl) SetPDClient(namespace Namespace, tcName string, pdclie
 	fpc.defaultPDControl.pdClients[pdClientKey("http", namespace, tcName)] = pdclient
 }
 
+func (fpc *FakePDControl) SetPDEtcdClient(namespace Namespace, tcName string, pdEtcdClient PDEtcdClient) {
+	fpc.defaultPDControl.pdEtcdClients[pdEtcdClientKey(namespace, tcName)] = pdEtcdClient
+}
+
+func (fpc *FakePDControl) SetExternalPDClient(endpoints []string, pdclient PDClient) {
+	fpc.defaultPDControl.pdClients[externalPDClientKey(endpoints)] = pdclient
+}
+
 type ActionType string
 
 const (
-	GetHealthActionType                ActionType = "GetHealth"
-	GetConfigActionType                ActionType = "GetConfig"
-	GetClusterActionType               ActionType = "GetCluster"
-	GetMembersActionType               ActionType = "GetMembers"
-	GetStoresActionType                ActionType = "GetStores"
-	GetTombStoneStoresActionType       ActionType = "GetTombStoneStores"
-	GetStoreActionType                 ActionType = "GetStore"
-	DeleteStoreActionType              ActionType = "DeleteStore"
-	SetStoreStateActionType            ActionType = "SetStoreState"
-	DeleteMemberByIDActionType         ActionType = "DeleteMemberByID"
-	DeleteMemberActionType             ActionType = "DeleteMember "
-	SetStoreLabelsActionType           ActionType = "SetStoreLabels"
-	UpdateReplicationActionType        ActionType = "UpdateReplicationConfig"
-	BeginEvictLeaderActionType         ActionType = "BeginEvictLeader"
-	EndEvictLeaderActionType           ActionType = "EndEvictLeader"
-	GetEvictLeaderSchedulersActionType ActionType = "GetEvictLeaderSchedulers"
-	GetPDLeaderActionType              ActionType = "GetPDLeader"
-	TransferPDLeaderActionType         ActionType = "TransferPDLeader"
+	GetHealthActionType                 ActionType = "GetHealth"
+	GetConfigActionType                 ActionType = "GetConfig"
+	GetClusterActionType                ActionType = "GetCluster"
+	GetMembersActionType                ActionType = "GetMembers"
+	GetStoresActionType                 ActionType = "GetStores"
+	GetTombStoneStoresActionType        ActionType = "GetTombStoneStores"
+	GetStoreActionType                  ActionType = "GetStore"
+	DeleteStoreActionType               ActionType = "DeleteStore"
+	SetStoreStateActionType             ActionType = "SetStoreState"
+	DeleteMemberByIDActionType          ActionType = "DeleteMemberByID"
+	DeleteMemberActionType              ActionType = "DeleteMember "
+	SetStoreLabelsActionType            ActionType = "SetStoreLabels"
+	UpdateReplicationActionType         ActionType = "UpdateReplicationConfig"
+	BeginEvictLeaderActionType          ActionType = "BeginEvictLeader"
+	EndEvictLeaderActionType            ActionType = "EndEvictLeader"
+	GetEvictLeaderSchedulersActionType  ActionType = "GetEvictLeaderSchedulers"
+	GetSchedulersActionType             ActionType = "GetSchedulers"
+	PauseSchedulerActionType            ActionType = "PauseScheduler"
+	UpdateScheduleConfigActionType      ActionType = "UpdateScheduleConfig"
+	GetPDLeaderActionType               ActionType = "GetPDLeader"
+	TransferPDLeaderActionType          ActionType = "TransferPDLeader"
+	RemoveFailedStoresActionType        ActionType = "RemoveFailedStores"
+	GetUnsafeRecoveryProgressActionType ActionType = "GetUnsafeRecoveryProgress"
+	GetDownPeerRegionCountActionType    ActionType = "GetDownPeerRegionCount"
+	UpdateServiceGCSafePointActionType  ActionType = "UpdateServiceGCSafePoint"
 )
 
 type NotFoundReaction struct {
@@ -751,6 +963,10 @@ type Action struct {
 	Name        string
 	Labels      map[string]string
 	Replication PDReplicationConfig
+	StoreIDs    []uint64
+	TTL         int64
+	SafePoint   uint64
+	Config      map[string]interface{}
 }
 
 type Reaction func(action *Action) (interface{}, error)
@@ -927,6 +1143,27 @@ func (pc *FakePDClient) GetEvictLeaderSchedulers() ([]string, error) {
 	return nil, nil
 }
 
+func (pc *FakePDClient) GetSchedulers() ([]string, error) {
+	action := &Action{}
+	result, err := pc.fakeAPI(GetSchedulersActionType, action)
+	if err != nil {
+		return nil, err
+	}
+	return result.([]string), nil
+}
+
+func (pc *FakePDClient) PauseScheduler(name string, delay int64) error {
+	action := &Action{Name: name, TTL: delay}
+	_, err := pc.fakeAPI(PauseSchedulerActionType, action)
+	return err
+}
+
+func (pc *FakePDClient) UpdateScheduleConfigWithTTL(config map[string]interface{}, ttl int64) error {
+	action := &Action{Config: config, TTL: ttl}
+	_, err := pc.fakeAPI(UpdateScheduleConfigActionType, action)
+	return err
+}
+
 func (pc *FakePDClient) GetPDLeader() (*pdpb.Member, error) {
 	if reaction, ok := pc.reactions[GetPDLeaderActionType]; ok {
 		action := &Action{}
@@ -944,3 +1181,39 @@ func (pc *FakePDClient) TransferPDLeader(memberName string) error {
 	}
 	return nil
 }
+
+func (pc *FakePDClient) RemoveFailedStores(storeIDs []uint64) error {
+	if reaction, ok := pc.reactions[RemoveFailedStoresActionType]; ok {
+		action := &Action{StoreIDs: storeIDs}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
+func (pc *FakePDClient) GetUnsafeRecoveryProgress() ([]UnsafeRecoveryStage, error) {
+	action := &Action{}
+	result, err := pc.fakeAPI(GetUnsafeRecoveryProgressActionType, action)
+	if err != nil {
+		return nil, err
+	}
+	return result.([]UnsafeRecoveryStage), nil
+}
+
+func (pc *FakePDClient) GetDownPeerRegionCount() (int, error) {
+	action := &Action{}
+	result, err := pc.fakeAPI(GetDownPeerRegionCountActionType, action)
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+func (pc *FakePDClient) UpdateServiceGCSafePoint(serviceID string, ttl int64, safePoint uint64) (uint64, error) {
+	action := &Action{Name: serviceID, TTL: ttl, SafePoint: safePoint}
+	result, err := pc.fakeAPI(UpdateServiceGCSafePointActionType, action)
+	if err != nil {
+		return 0, err
+	}
+	return result.(uint64), nil
+}
@@ -21,11 +21,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -648,3 +650,125 @@ func readJSON(r io.ReadCloser, data interface{}) error {
 
 	return nil
 }
+
+func TestPauseScheduler(t *testing.T) {
+	g := NewGomegaWithT(t)
+	name := "balance-leader-scheduler"
+	tcs := []struct {
+		caseName    string
+		delay       int64
+		expectDelay int64
+		want        bool
+	}{{
+		caseName:    "success_PauseScheduler",
+		delay:       600,
+		expectDelay: 600,
+		want:        true,
+	}, {
+		caseName:    "success_ResumeScheduler",
+		delay:       -1,
+		expectDelay: 0,
+		want:        true,
+	}, {
+		caseName:    "failed_PauseScheduler",
+		delay:       600,
+		expectDelay: 600,
+		want:        false,
+	},
+	}
+
+	for _, tc := range tcs {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("POST"), "check method")
+			g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s/%s", schedulersPrefix, name)), "check url")
+
+			input := map[string]int64{}
+			err := readJSON(request.Body, &input)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(input).To(Equal(map[string]int64{"delay": tc.expectDelay}), "check delay")
+
+			if tc.want {
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+		defer svc.Close()
+
+		pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+		err := pdClient.PauseScheduler(name, tc.delay)
+		if tc.want {
+			g.Expect(err).NotTo(HaveOccurred(), tc.caseName)
+		} else {
+			g.Expect(err).To(HaveOccurred(), tc.caseName)
+		}
+	}
+}
+
+func TestUpdateScheduleConfigWithTTL(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("POST"), "check method")
+		g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s", configPrefix)), "check url")
+		g.Expect(request.URL.Query().Get("ttlSecond")).To(Equal("600"), "check ttl")
+
+		input := map[string]interface{}{}
+		err := readJSON(request.Body, &input)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(input).To(Equal(map[string]interface{}{"schedule.merge-schedule-limit": float64(0)}), "check config")
+		w.WriteHeader(http.StatusOK)
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{})
+	err := pdClient.UpdateScheduleConfigWithTTL(map[string]interface{}{"merge-schedule-limit": 0}, 600)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestUpdateServiceGCSafePoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var received *updateServiceGCSafePointRequest
+	grpcServer := grpc.NewServer(grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		g.Expect(method).To(Equal(updateServiceGCSafePointMethod))
+		req := &updateServiceGCSafePointRequest{}
+		if err := stream.RecvMsg(req); err != nil {
+			return err
+		}
+		received = req
+		return stream.SendMsg(&updateServiceGCSafePointResponse{
+			Header:       &pdpb.ResponseHeader{ClusterId: req.Header.ClusterId},
+			ServiceId:    req.ServiceId,
+			TTL:          req.TTL,
+			MinSafePoint: 100,
+		})
+	}))
+	defer grpcServer.Stop()
+
+	clusterBytes, err := json.Marshal(&metapb.Cluster{Id: 1})
+	g.Expect(err).NotTo(HaveOccurred())
+	// pd serves both the RESTful interface and grpc on the same port
+	svc := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		if request.ProtoMajor == 2 && strings.HasPrefix(request.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, request)
+			return
+		}
+		g.Expect(request.URL.Path).To(Equal(fmt.Sprintf("/%s", clusterIDPrefix)), "check url")
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.Write(clusterBytes)
+	}))
+	svc.EnableHTTP2 = true
+	svc.StartTLS()
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, DefaultTimeout, &tls.Config{InsecureSkipVerify: true})
+	minSafePoint, err := pdClient.UpdateServiceGCSafePoint("snapshot", 600, 90)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(minSafePoint).To(Equal(uint64(100)))
+	g.Expect(received.Header.ClusterId).To(Equal(uint64(1)))
+	g.Expect(string(received.ServiceId)).To(Equal("snapshot"))
+	g.Expect(received.TTL).To(Equal(int64(600)))
+	g.Expect(received.SafePoint).To(Equal(uint64(90)))
+}
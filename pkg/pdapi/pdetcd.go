@@ -16,10 +16,20 @@ package pdapi
 import (
 	"context"
 	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"path"
+	"strconv"
 	"time"
 
 	etcdclientv3 "github.com/coreos/etcd/clientv3"
 	etcdclientv3util "github.com/coreos/etcd/clientv3/clientv3util"
+	"github.com/pingcap/kvproto/pkg/metapb"
+)
+
+const (
+	pdRootPath      = "/pd"
+	pdClusterIDPath = "/pd/cluster_id"
 )
 
 type PDEtcdClient interface {
@@ -27,6 +37,13 @@ type PDEtcdClient interface {
 	PutKey(key, value string) error
 	// DeleteKey will delete key from the target pd etcd cluster
 	DeleteKey(key string) error
+	// RecoverCluster writes the cluster id and alloc id of a lost pd cluster to the target
+	// pd etcd cluster like pd-recover does, it returns false if the target pd cluster has
+	// been bootstrapped and nothing is written.
+	RecoverCluster(clusterID, allocID uint64) (bool, error)
+	// GetAllocID returns the alloc id persisted by the target pd etcd cluster, pd persists
+	// the end of the range it allocates the ids from, so it is larger than all allocated ids
+	GetAllocID(clusterID uint64) (uint64, error)
 }
 
 type pdEtcdClient struct {
@@ -71,3 +88,115 @@ func (pec *pdEtcdClient) DeleteKey(key string) error {
 	}
 	return nil
 }
+
+func (pec *pdEtcdClient) RecoverCluster(clusterID, allocID uint64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pec.timeout)
+	defer cancel()
+
+	rootPath := path.Join(pdRootPath, strconv.FormatUint(clusterID, 10))
+	clusterRootPath := path.Join(rootPath, "raft")
+	raftBootstrapTimeKey := path.Join(clusterRootPath, "status", "raft_bootstrap_time")
+
+	clusterValue, err := (&metapb.Cluster{Id: clusterID}).Marshal()
+	if err != nil {
+		return false, err
+	}
+	ops := []etcdclientv3.Op{
+		etcdclientv3.OpPut(pdClusterIDPath, string(uint64ToBytes(clusterID))),
+		etcdclientv3.OpPut(path.Join(rootPath, "alloc_id"), string(uint64ToBytes(allocID))),
+		etcdclientv3.OpPut(clusterRootPath, string(clusterValue)),
+		etcdclientv3.OpPut(raftBootstrapTimeKey, string(uint64ToBytes(uint64(time.Now().UnixNano())))),
+	}
+
+	// the recovered pd cluster must not have been bootstrapped by tikv
+	bootstrapCmp := etcdclientv3.Compare(etcdclientv3.CreateRevision(clusterRootPath), "=", 0)
+	resp, err := pec.etcdClient.Txn(ctx).If(bootstrapCmp).Then(ops...).Commit()
+	if err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+func (pec *pdEtcdClient) GetAllocID(clusterID uint64) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pec.timeout)
+	defer cancel()
+
+	key := path.Join(pdRootPath, strconv.FormatUint(clusterID, 10), "alloc_id")
+	resp, err := pec.etcdClient.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return 0, fmt.Errorf("key %s is not found", key)
+	}
+	value := resp.Kvs[0].Value
+	if len(value) != 8 {
+		return 0, fmt.Errorf("the value of key %s is not an uint64: %x", key, value)
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// uint64ToBytes encodes the uint64 in big endian as pd stores the ids
+func uint64ToBytes(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+// FakePDEtcdClient is a fake PDEtcdClient which keeps the keys in memory
+type FakePDEtcdClient struct {
+	Keys map[string]string
+	// Bootstrapped makes RecoverCluster write nothing
+	Bootstrapped bool
+	err          error
+}
+
+func NewFakePDEtcdClient() *FakePDEtcdClient {
+	return &FakePDEtcdClient{Keys: map[string]string{}}
+}
+
+func (fpec *FakePDEtcdClient) SetError(err error) {
+	fpec.err = err
+}
+
+func (fpec *FakePDEtcdClient) PutKey(key, value string) error {
+	if fpec.err != nil {
+		return fpec.err
+	}
+	fpec.Keys[key] = value
+	return nil
+}
+
+func (fpec *FakePDEtcdClient) DeleteKey(key string) error {
+	if fpec.err != nil {
+		return fpec.err
+	}
+	delete(fpec.Keys, key)
+	return nil
+}
+
+func (fpec *FakePDEtcdClient) RecoverCluster(clusterID, allocID uint64) (bool, error) {
+	if fpec.err != nil {
+		return false, fpec.err
+	}
+	if fpec.Bootstrapped {
+		return false, nil
+	}
+	rootPath := path.Join(pdRootPath, strconv.FormatUint(clusterID, 10))
+	fpec.Keys[pdClusterIDPath] = strconv.FormatUint(clusterID, 10)
+	fpec.Keys[path.Join(rootPath, "alloc_id")] = strconv.FormatUint(allocID, 10)
+	fpec.Bootstrapped = true
+	return true, nil
+}
+
+func (fpec *FakePDEtcdClient) GetAllocID(clusterID uint64) (uint64, error) {
+	if fpec.err != nil {
+		return 0, fpec.err
+	}
+	key := path.Join(pdRootPath, strconv.FormatUint(clusterID, 10), "alloc_id")
+	value, ok := fpec.Keys[key]
+	if !ok {
+		return 0, fmt.Errorf("key %s is not found", key)
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// updateServiceGCSafePointMethod is only served over grpc by pd
+const updateServiceGCSafePointMethod = "/pdpb.PD/UpdateServiceGCSafePoint"
+
+// updateServiceGCSafePointRequest and updateServiceGCSafePointResponse are copied from
+// pdpb of the newer kvproto, the vendored one doesn't have them.
+type updateServiceGCSafePointRequest struct {
+	Header    *pdpb.RequestHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	ServiceId []byte              `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	TTL       int64               `protobuf:"varint,3,opt,name=TTL,proto3" json:"TTL,omitempty"`
+	SafePoint uint64              `protobuf:"varint,4,opt,name=safe_point,json=safePoint,proto3" json:"safe_point,omitempty"`
+}
+
+func (m *updateServiceGCSafePointRequest) Reset()         { *m = updateServiceGCSafePointRequest{} }
+func (m *updateServiceGCSafePointRequest) String() string { return proto.CompactTextString(m) }
+func (*updateServiceGCSafePointRequest) ProtoMessage()    {}
+
+type updateServiceGCSafePointResponse struct {
+	Header       *pdpb.ResponseHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	ServiceId    []byte               `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	TTL          int64                `protobuf:"varint,3,opt,name=TTL,proto3" json:"TTL,omitempty"`
+	MinSafePoint uint64               `protobuf:"varint,4,opt,name=min_safe_point,json=minSafePoint,proto3" json:"min_safe_point,omitempty"`
+}
+
+func (m *updateServiceGCSafePointResponse) Reset()         { *m = updateServiceGCSafePointResponse{} }
+func (m *updateServiceGCSafePointResponse) String() string { return proto.CompactTextString(m) }
+func (*updateServiceGCSafePointResponse) ProtoMessage()    {}
+
+func (pc *pdClient) UpdateServiceGCSafePoint(serviceID string, ttl int64, safePoint uint64) (uint64, error) {
+	cluster, err := pc.GetCluster()
+	if err != nil {
+		return 0, err
+	}
+	u, err := url.Parse(pc.url)
+	if err != nil {
+		return 0, err
+	}
+	opt := grpc.WithInsecure()
+	if u.Scheme == "https" {
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(pc.tlsConfig))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pc.httpClient.Timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, u.Host, opt)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	req := &updateServiceGCSafePointRequest{
+		Header:    &pdpb.RequestHeader{ClusterId: cluster.GetId()},
+		ServiceId: []byte(serviceID),
+		TTL:       ttl,
+		SafePoint: safePoint,
+	}
+	resp := &updateServiceGCSafePointResponse{}
+	if err := conn.Invoke(ctx, updateServiceGCSafePointMethod, req, resp); err != nil {
+		return 0, err
+	}
+	if resp.Header != nil && resp.Header.Error != nil {
+		return 0, fmt.Errorf("failed to update service gc safe point of %s: %s", serviceID, resp.Header.Error.String())
+	}
+	return resp.MinSafePoint, nil
+}
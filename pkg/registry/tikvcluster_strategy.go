@@ -16,9 +16,9 @@ package registry
 import (
 	"context"
 
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1/defaulting"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1/validation"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1/defaulting"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog"
@@ -28,7 +28,7 @@ import (
 type TikvClusterStrategy struct{}
 
 func (TikvClusterStrategy) NewObject() runtime.Object {
-	return &v1alpha1.TikvCluster{}
+	return &v1beta1.TikvCluster{}
 }
 
 func (TikvClusterStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
@@ -57,8 +57,8 @@ func (TikvClusterStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.
 	return field.ErrorList{}
 }
 
-func castTikvCluster(obj runtime.Object) (*v1alpha1.TikvCluster, bool) {
-	tc, ok := obj.(*v1alpha1.TikvCluster)
+func castTikvCluster(obj runtime.Object) (*v1beta1.TikvCluster, bool) {
+	tc, ok := obj.(*v1beta1.TikvCluster)
 	if !ok {
 		// impossible for non-malicious request, this usually indicates a client error when the strategy is used by webhook,
 		// we simply ignore error requests
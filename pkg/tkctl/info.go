@@ -0,0 +1,171 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tkctl
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+)
+
+func newInfoCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "info <cluster>",
+		Short: "Show the topology and the store states of the TikvCluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.withPDClient(args[0], func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+				return printInfo(o.Out, tc, pdCli)
+			})
+		},
+	}
+}
+
+func newStoresCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stores <cluster>",
+		Short: "List the stores of the TikvCluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.withPDClient(args[0], func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+				return printStores(o.Out, pdCli)
+			})
+		},
+	}
+}
+
+func newMembersCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "members <cluster>",
+		Short: "List the PD members of the TikvCluster",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.withPDClient(args[0], func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+				return printMembers(o.Out, pdCli)
+			})
+		},
+	}
+}
+
+func printInfo(out io.Writer, tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", tc.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", tc.Namespace)
+	fmt.Fprintf(w, "Paused:\t%t\n", tc.Spec.Paused)
+	if tc.IsPDExternal() {
+		fmt.Fprintf(w, "PD:\texternal %s\n", strings.Join(tc.Spec.PD.ExternalEndpoints, ","))
+	} else {
+		fmt.Fprintf(w, "PD:\t%d/%d ready, phase %s, image %s\n",
+			readyReplicas(tc.Status.PD.StatefulSet), tc.Spec.PD.Replicas, tc.Status.PD.Phase, tc.Status.PD.Image)
+	}
+	fmt.Fprintf(w, "TiKV:\t%d/%d ready, phase %s, image %s\n",
+		readyReplicas(tc.Status.TiKV.StatefulSet), tc.Spec.TiKV.Replicas, tc.Status.TiKV.Phase, tc.Status.TiKV.Image)
+	if len(tc.Status.TiKV.FailureStores) > 0 {
+		failures := []string{}
+		for _, store := range tc.Status.TiKV.FailureStores {
+			failures = append(failures, fmt.Sprintf("%s(%s)", store.PodName, store.StoreID))
+		}
+		sort.Strings(failures)
+		fmt.Fprintf(w, "Failure stores:\t%s\n", strings.Join(failures, ","))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if !tc.IsPDExternal() {
+		fmt.Fprintln(out)
+		if err := printMembers(out, pdCli); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(out)
+	return printStores(out, pdCli)
+}
+
+func printStores(out io.Writer, pdCli pdapi.PDClient) error {
+	stores, err := pdCli.GetStores()
+	if err != nil {
+		return err
+	}
+	tombstones, err := pdCli.GetTombStoneStores()
+	if err != nil {
+		return err
+	}
+	schedulers, err := pdCli.GetEvictLeaderSchedulers()
+	if err != nil {
+		return err
+	}
+	evicting := map[string]bool{}
+	for _, scheduler := range schedulers {
+		evicting[strings.TrimPrefix(scheduler, "evict-leader-scheduler-")] = true
+	}
+
+	all := []*pdapi.StoreInfo{}
+	for _, store := range append(stores.Stores, tombstones.Stores...) {
+		if store.Store != nil && store.Status != nil {
+			all = append(all, store)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Store.GetId() < all[j].Store.GetId() })
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tADDRESS\tSTATE\tLEADERS\tREGIONS\tCAPACITY\tAVAILABLE\tEVICTING")
+	for _, store := range all {
+		id := fmt.Sprintf("%d", store.Store.GetId())
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\t%t\n", id, store.Store.GetAddress(), store.Store.StateName,
+			store.Status.LeaderCount, store.Status.RegionCount, formatBytes(uint64(store.Status.Capacity)), formatBytes(uint64(store.Status.Available)), evicting[id])
+	}
+	return w.Flush()
+}
+
+func printMembers(out io.Writer, pdCli pdapi.PDClient) error {
+	members, err := pdCli.GetMembers()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tID\tCLIENT-URLS\tLEADER")
+	for _, member := range members.Members {
+		leader := members.Leader != nil && members.Leader.GetMemberId() == member.GetMemberId()
+		fmt.Fprintf(w, "%s\t%d\t%s\t%t\n", member.GetName(), member.GetMemberId(), strings.Join(member.GetClientUrls(), ","), leader)
+	}
+	return w.Flush()
+}
+
+func readyReplicas(status *apps.StatefulSetStatus) int32 {
+	if status == nil {
+		return 0
+	}
+	return status.ReadyReplicas
+}
+
+// formatBytes formats the size in binary units, e.g. 1.5GiB
+func formatBytes(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tkctl
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+)
+
+func newEvictLeaderCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "evict-leader <cluster> <store-id|pod>",
+		Short: "Evict the region leaders from the store",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.withPDClient(args[0], func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+				storeID, err := resolveStoreID(tc, args[1])
+				if err != nil {
+					return err
+				}
+				if err := pdCli.BeginEvictLeader(storeID); err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "evicting the leaders from store %d\n", storeID)
+				return nil
+			})
+		},
+	}
+}
+
+func newEndEvictLeaderCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "end-evict-leader <cluster> <store-id|pod>",
+		Short: "Stop evicting the region leaders from the store",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.withPDClient(args[0], func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+				storeID, err := resolveStoreID(tc, args[1])
+				if err != nil {
+					return err
+				}
+				if err := pdCli.EndEvictLeader(storeID); err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "stopped evicting the leaders from store %d\n", storeID)
+				return nil
+			})
+		},
+	}
+}
+
+func newTransferPDLeaderCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "transfer-pd-leader <cluster> <member>",
+		Short: "Transfer the PD leader to the member",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.withPDClient(args[0], func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+				return transferPDLeader(o, pdCli, args[1])
+			})
+		},
+	}
+}
+
+func transferPDLeader(o *Options, pdCli pdapi.PDClient, name string) error {
+	members, err := pdCli.GetMembers()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, member := range members.Members {
+		if member.GetName() == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("PD member %s does not exist", name)
+	}
+	if members.Leader != nil && members.Leader.GetName() == name {
+		fmt.Fprintf(o.Out, "PD member %s is already the leader\n", name)
+		return nil
+	}
+	if err := pdCli.TransferPDLeader(name); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "transferred the PD leader to %s\n", name)
+	return nil
+}
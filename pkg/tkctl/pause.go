@@ -0,0 +1,50 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tkctl
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newPauseCommand returns the pause command, or the resume command if pause is false
+func newPauseCommand(o *Options, pause bool) *cobra.Command {
+	use, short := "pause", "Stop the operator from reconciling the TikvCluster"
+	if !pause {
+		use, short = "resume", "Resume reconciling the paused TikvCluster"
+	}
+	return &cobra.Command{
+		Use:   use + " <cluster>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setPaused(o, args[0], pause)
+		},
+	}
+}
+
+func setPaused(o *Options, tcName string, paused bool) error {
+	patch := fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)
+	if _, err := o.Cli.TikvV1beta1().TikvClusters(o.Namespace).Patch(tcName, types.MergePatchType, []byte(patch)); err != nil {
+		return err
+	}
+	if paused {
+		fmt.Fprintf(o.Out, "TikvCluster %s/%s paused\n", o.Namespace, tcName)
+	} else {
+		fmt.Fprintf(o.Out, "TikvCluster %s/%s resumed\n", o.Namespace, tcName)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tkctl
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+)
+
+const pdClientPort = 2379
+
+// portForwardPDClientFunc connects to the PD cluster through a port forwarded
+// to a ready PD pod, the PD services are not accessible out of the cluster
+func portForwardPDClientFunc(config *rest.Config, kubeCli kubernetes.Interface) PDClientFunc {
+	return func(tc *v1beta1.TikvCluster) (pdapi.PDClient, func(), error) {
+		ns := tc.GetNamespace()
+		if tc.IsPDExternal() {
+			var tlsConfig *tls.Config
+			if tc.IsPDExternalTLSEnabled() {
+				var err error
+				tlsConfig, err = pdapi.GetTLSConfigFromSecret(kubeCli, pdapi.Namespace(ns), *tc.Spec.PD.ExternalTLSClientSecretName, nil)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			return pdapi.NewExternalPDClient(tc.Spec.PD.ExternalEndpoints, pdapi.DefaultTimeout, tlsConfig), func() {}, nil
+		}
+
+		pod, err := getReadyPDPod(kubeCli, tc)
+		if err != nil {
+			return nil, nil, err
+		}
+		localPort, stop, err := portForward(config, kubeCli, pod)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var tlsConfig *tls.Config
+		if tc.IsTLSClusterEnabled() {
+			tlsConfig, err = pdapi.GetTLSConfig(kubeCli, pdapi.Namespace(ns), tc.GetName(), nil)
+			if err != nil {
+				stop()
+				return nil, nil, err
+			}
+			// the certificate is issued to the PD service instead of the local address
+			tlsConfig.ServerName = fmt.Sprintf("%s-pd.%s", tc.GetName(), ns)
+		}
+		url := fmt.Sprintf("%s://127.0.0.1:%d", tc.Scheme(), localPort)
+		return pdapi.NewPDClient(url, pdapi.DefaultTimeout, tlsConfig), stop, nil
+	}
+}
+
+func getReadyPDPod(kubeCli kubernetes.Interface, tc *v1beta1.TikvCluster) (*corev1.Pod, error) {
+	selector := label.New().Instance(tc.GetInstanceName()).PD().String()
+	pods, err := kubeCli.CoreV1().Pods(tc.GetNamespace()).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		if podutil.IsPodReady(&pods.Items[i]) {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no ready PD pod of TikvCluster %s/%s", tc.GetNamespace(), tc.GetName())
+}
+
+// portForward forwards a random local port to the client port of the PD pod,
+// the returned function stops the forwarding
+func portForward(config *rest.Config, kubeCli kubernetes.Interface, pod *corev1.Pod) (uint16, func(), error) {
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return 0, nil, err
+	}
+	url := kubeCli.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(pod.Namespace).Name(pod.Name).
+		SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	fw, err := portforward.New(dialer, []string{fmt.Sprintf("0:%d", pdClientPort)}, stopCh, readyCh, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return 0, nil, err
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fw.ForwardPorts()
+	}()
+	select {
+	case err := <-errCh:
+		return 0, nil, fmt.Errorf("failed to forward port of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	case <-readyCh:
+	}
+	stop := func() { close(stopCh) }
+	ports, err := fw.GetPorts()
+	if err != nil {
+		stop()
+		return 0, nil, err
+	}
+	return ports[0].Local, stop, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tkctl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1/defaulting"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1/validation"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/manager/member"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func newRenderCommand(o *Options) *cobra.Command {
+	var filename string
+	cmd := &cobra.Command{
+		Use:   "render -f <file>",
+		Short: "Render the StatefulSets and ConfigMaps the operator creates for the TikvCluster",
+		Args:  cobra.NoArgs,
+		// the objects are rendered offline
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tc, err := o.loadTikvCluster(filename)
+			if err != nil {
+				return err
+			}
+			return render(o, tc)
+		},
+	}
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "The TikvCluster manifest, - to read from stdin")
+	cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+func newPlanCommand(o *Options) *cobra.Command {
+	var filename string
+	cmd := &cobra.Command{
+		Use:   "plan -f <file>",
+		Short: "Show the changes of the StatefulSets and ConfigMaps if the TikvCluster is applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tc, err := o.loadTikvCluster(filename)
+			if err != nil {
+				return err
+			}
+			return plan(o, tc)
+		},
+	}
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "The TikvCluster manifest, - to read from stdin")
+	cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+// loadTikvCluster reads the TikvCluster from the file, then defaults and validates it
+// as the operator does. The v1alpha1 TikvCluster is converted to v1beta1.
+func (o *Options) loadTikvCluster(filename string) (*v1beta1.TikvCluster, error) {
+	var data []byte
+	var err error
+	if filename == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+	typeMeta := &metav1.TypeMeta{}
+	if err := yaml.Unmarshal(data, typeMeta); err != nil {
+		return nil, fmt.Errorf("failed to parse TikvCluster %s: %v", filename, err)
+	}
+	tc := &v1beta1.TikvCluster{}
+	if typeMeta.APIVersion == v1alpha1.SchemeGroupVersion.String() {
+		legacy := &v1alpha1.TikvCluster{}
+		if err := yaml.UnmarshalStrict(data, legacy); err != nil {
+			return nil, fmt.Errorf("failed to parse TikvCluster %s: %v", filename, err)
+		}
+		if err := legacy.ConvertTo(tc); err != nil {
+			return nil, fmt.Errorf("failed to convert TikvCluster %s: %v", filename, err)
+		}
+		tc.TypeMeta = metav1.TypeMeta{Kind: typeMeta.Kind, APIVersion: v1beta1.SchemeGroupVersion.String()}
+	} else if err := yaml.UnmarshalStrict(data, tc); err != nil {
+		return nil, fmt.Errorf("failed to parse TikvCluster %s: %v", filename, err)
+	}
+	if tc.Namespace == "" {
+		tc.Namespace = o.Namespace
+	}
+	if tc.Namespace == "" {
+		tc.Namespace = metav1.NamespaceDefault
+	}
+	defaulting.SetTikvClusterDefault(tc)
+	if errs := validation.ValidateTikvCluster(tc); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid TikvCluster %s/%s: %v", tc.Namespace, tc.Name, errs.ToAggregate())
+	}
+	return tc, nil
+}
+
+func render(o *Options, tc *v1beta1.TikvCluster) error {
+	objects, err := member.RenderTikvCluster(tc, nil, nil)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "---\n%s", data)
+	}
+	return nil
+}
+
+// plan renders the objects with the status of the TikvCluster in the cluster and
+// compares them with the objects in the cluster
+func plan(o *Options, tc *v1beta1.TikvCluster) error {
+	ns := tc.GetNamespace()
+	liveTc, err := o.Cli.TikvV1beta1().TikvClusters(ns).Get(tc.Name, metav1.GetOptions{})
+	if err == nil {
+		tc.UID = liveTc.UID
+		tc.Status = liveTc.Status
+	} else if errors.IsNotFound(err) {
+		fmt.Fprintf(o.Out, "TikvCluster %s/%s: create\n", ns, tc.Name)
+	} else {
+		return err
+	}
+
+	oldPDSet, err := o.getStatefulSet(ns, controller.PDMemberName(tc.Name))
+	if err != nil {
+		return err
+	}
+	oldTiKVSet, err := o.getStatefulSet(ns, controller.TiKVMemberName(tc.Name))
+	if err != nil {
+		return err
+	}
+	objects, err := member.RenderTikvCluster(tc, oldPDSet, oldTiKVSet)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		switch newObj := obj.(type) {
+		case *corev1.ConfigMap:
+			oldCm, err := o.KubeCli.CoreV1().ConfigMaps(ns).Get(newObj.Name, metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				printPlan(o, "ConfigMap", ns, newObj.Name, "create", nil)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			changes := member.DiffConfigMap(newObj, oldCm)
+			if len(changes) == 0 {
+				printPlan(o, "ConfigMap", ns, newObj.Name, "unchanged", nil)
+				continue
+			}
+			printPlan(o, "ConfigMap", ns, newObj.Name, "update in place, effective after the pods are restarted", changes)
+		case *apps.StatefulSet:
+			var oldSet *apps.StatefulSet
+			if newObj.Name == controller.PDMemberName(tc.Name) {
+				oldSet = oldPDSet
+			} else {
+				oldSet = oldTiKVSet
+			}
+			if oldSet == nil {
+				printPlan(o, "StatefulSet", ns, newObj.Name, "create", nil)
+				continue
+			}
+			changes, restart, err := member.DiffStatefulSet(newObj, oldSet)
+			if err != nil {
+				return err
+			}
+			switch {
+			case len(changes) == 0:
+				printPlan(o, "StatefulSet", ns, newObj.Name, "unchanged", nil)
+			case restart:
+				printPlan(o, "StatefulSet", ns, newObj.Name, "update, ROLLING RESTART", changes)
+			default:
+				printPlan(o, "StatefulSet", ns, newObj.Name, "update", changes)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *Options) getStatefulSet(ns, name string) (*apps.StatefulSet, error) {
+	set, err := o.KubeCli.AppsV1().StatefulSets(ns).Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return set, err
+}
+
+func printPlan(o *Options, kind, ns, name, action string, changes []string) {
+	fmt.Fprintf(o.Out, "%s %s/%s: %s\n", kind, ns, name, action)
+	for _, change := range changes {
+		fmt.Fprintf(o.Out, "  %s\n", strings.Replace(strings.TrimSuffix(change, "\n"), "\n", "\n    ", -1))
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tkctl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/manager/member"
+	apps "k8s.io/api/apps/v1"
+	"k8s.io/utils/pointer"
+)
+
+const testTikvClusterManifest = `apiVersion: tikv.org/v1beta1
+kind: TikvCluster
+metadata:
+  name: demo
+spec:
+  pd:
+    baseImage: pingcap/pd
+    version: v4.0.0
+    replicas: 3
+    requests:
+      storage: "1Gi"
+    config: {}
+  tikv:
+    baseImage: pingcap/tikv
+    version: v4.0.0
+    replicas: 3
+    requests:
+      storage: "1Gi"
+    config: {}
+`
+
+const testLegacyTikvClusterManifest = `apiVersion: tikv.org/v1alpha1
+kind: TikvCluster
+metadata:
+  name: demo
+spec:
+  version: v4.0.0
+  schedulerName: tikv-scheduler
+  pd:
+    baseImage: pingcap/pd
+    replicas: 3
+    requests:
+      storage: "1Gi"
+    config: {}
+  tikv:
+    image: localhost:5000/tikv:v4.0.1
+    replicas: 3
+    requests:
+      storage: "1Gi"
+    config: {}
+`
+
+func writeManifest(g *GomegaWithT, dir, manifest string) string {
+	filename := filepath.Join(dir, "tc.yaml")
+	g.Expect(ioutil.WriteFile(filename, []byte(manifest), 0644)).To(Succeed())
+	return filename
+}
+
+func TestLoadTikvCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+	dir, err := ioutil.TempDir("", "tkctl")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+	o := &Options{Namespace: "ns"}
+
+	tc, err := o.loadTikvCluster(writeManifest(g, dir, testTikvClusterManifest))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Namespace).To(Equal("ns"))
+	g.Expect(tc.TiKVImage()).To(Equal("pingcap/tikv:v4.0.0"))
+
+	tc, err = o.loadTikvCluster(writeManifest(g, dir, testLegacyTikvClusterManifest))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.APIVersion).To(Equal("tikv.org/v1beta1"))
+	g.Expect(tc.PDImage()).To(Equal("pingcap/pd:v4.0.0"))
+	g.Expect(tc.TiKVImage()).To(Equal("localhost:5000/tikv:v4.0.1"))
+	g.Expect(tc.BaseTiKVSpec().SchedulerName()).To(Equal("tikv-scheduler"))
+
+	_, err = o.loadTikvCluster(writeManifest(g, dir, testTikvClusterManifest+"  unknown: 1\n"))
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = o.loadTikvCluster(writeManifest(g, dir, testTikvClusterManifest+"  pd:\n    replicas: 3\n"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestPlan(t *testing.T) {
+	g := NewGomegaWithT(t)
+	dir, err := ioutil.TempDir("", "tkctl")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+	o := &Options{Namespace: "default"}
+
+	liveTc, err := o.loadTikvCluster(writeManifest(g, dir, testTikvClusterManifest))
+	g.Expect(err).NotTo(HaveOccurred())
+	objects, err := member.RenderTikvCluster(liveTc, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, obj := range objects {
+		if set, ok := obj.(*apps.StatefulSet); ok {
+			g.Expect(member.SetStatefulSetLastAppliedConfigAnnotation(set)).To(Succeed())
+		}
+	}
+	o, _, _, out := newFakeOptions(liveTc, objects...)
+
+	tc := liveTc.DeepCopy()
+	g.Expect(plan(o, tc)).To(Succeed())
+	g.Expect(out.String()).To(Equal(`ConfigMap default/demo-pd: unchanged
+StatefulSet default/demo-pd: unchanged
+ConfigMap default/demo-tikv: unchanged
+StatefulSet default/demo-tikv: unchanged
+`))
+
+	// the config is updated in place by default
+	out.Reset()
+	tc = liveTc.DeepCopy()
+	tc.Spec.TiKV.Replicas = 5
+	tc.Spec.TiKV.Config.LogLevel = pointer.StringPtr("debug")
+	g.Expect(plan(o, tc)).To(Succeed())
+	g.Expect(out.String()).To(ContainSubstring("ConfigMap default/demo-tikv: update in place, effective after the pods are restarted\n  config-file:\n    +log-level = \"debug\"\n"))
+	g.Expect(out.String()).To(ContainSubstring("StatefulSet default/demo-tikv: update\n  replicas: 3 -> 5\n"))
+
+	out.Reset()
+	strategy := v1beta1.ConfigUpdateStrategyRollingUpdate
+	tc.Spec.TiKV.ConfigUpdateStrategy = &strategy
+	g.Expect(plan(o, tc)).To(Succeed())
+	g.Expect(out.String()).To(MatchRegexp("ConfigMap default/demo-tikv-[0-9a-f]+: create\n"))
+	g.Expect(out.String()).To(ContainSubstring("StatefulSet default/demo-tikv: update, ROLLING RESTART\n  replicas: 3 -> 5\n"))
+
+	out.Reset()
+	o, _, _, out = newFakeOptions(&v1beta1.TikvCluster{})
+	g.Expect(plan(o, liveTc.DeepCopy())).To(Succeed())
+	g.Expect(out.String()).To(HavePrefix("TikvCluster default/demo: create\n"))
+}
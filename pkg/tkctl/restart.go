@@ -0,0 +1,115 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tkctl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+)
+
+type restartOptions struct {
+	*Options
+	evictTimeout time.Duration
+	timeout      time.Duration
+}
+
+func newRestartCommand(o *Options) *cobra.Command {
+	ro := &restartOptions{Options: o}
+	cmd := &cobra.Command{
+		Use:   "restart <cluster> <pod>",
+		Short: "Restart the TiKV pod gracefully after evicting its region leaders",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.withPDClient(args[0], func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+				return ro.restart(tc, pdCli, args[1])
+			})
+		},
+	}
+	cmd.Flags().DurationVar(&ro.evictTimeout, "evict-timeout", 5*time.Minute, "The time to wait for the leaders to be evicted, the pod is restarted anyway after it")
+	cmd.Flags().DurationVar(&ro.timeout, "timeout", 10*time.Minute, "The time to wait for the store to be up after the pod is restarted")
+	return cmd
+}
+
+// restart evicts the leaders from the store of the pod, deletes the pod and
+// waits for the store to be up again before the leaders are scheduled back
+func (ro *restartOptions) restart(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient, podName string) (err error) {
+	ns := tc.GetNamespace()
+	pod, err := ro.KubeCli.CoreV1().Pods(ns).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if pod.Labels[label.InstanceLabelKey] != tc.GetInstanceName() || pod.Labels[label.ComponentLabelKey] != label.TiKVLabelVal {
+		return fmt.Errorf("pod %s/%s is not a TiKV pod of TikvCluster %s", ns, podName, tc.GetName())
+	}
+	storeID, err := resolveStoreID(tc, podName)
+	if err != nil {
+		return err
+	}
+
+	if err := pdCli.BeginEvictLeader(storeID); err != nil {
+		return err
+	}
+	defer func() {
+		if endErr := pdCli.EndEvictLeader(storeID); endErr != nil && err == nil {
+			err = endErr
+		}
+	}()
+	fmt.Fprintf(ro.Out, "evicting the leaders from store %d\n", storeID)
+	err = wait.PollImmediate(ro.PollInterval, ro.evictTimeout, func() (bool, error) {
+		store, err := pdCli.GetStore(storeID)
+		if err != nil {
+			return false, err
+		}
+		// the status is not reported yet, keep polling
+		if store.Status == nil {
+			return false, nil
+		}
+		return store.Status.LeaderCount == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		fmt.Fprintf(ro.Out, "timed out waiting for the leaders to be evicted from store %d, restart it anyway\n", storeID)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ro.Out, "deleting pod %s/%s\n", ns, podName)
+	uid := pod.UID
+	if err := ro.KubeCli.CoreV1().Pods(ns).Delete(podName, &metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}}); err != nil {
+		return err
+	}
+	err = wait.PollImmediate(ro.PollInterval, ro.timeout, func() (bool, error) {
+		pod, err := ro.KubeCli.CoreV1().Pods(ns).Get(podName, metav1.GetOptions{})
+		if err != nil || pod.UID == uid || !podutil.IsPodReady(pod) {
+			return false, nil
+		}
+		store, err := pdCli.GetStore(storeID)
+		if err != nil || store.Store == nil {
+			return false, nil
+		}
+		return store.Store.StateName == v1beta1.TiKVStateUp, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for store %d to be up: %v", storeID, err)
+	}
+	fmt.Fprintf(ro.Out, "pod %s/%s restarted\n", ns, podName)
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tkctl implements the kubectl-tikv plugin
+package tkctl
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// PDClientFunc returns the client of the PD cluster of the TikvCluster, the
+// returned function must be called to release the client
+type PDClientFunc func(tc *v1beta1.TikvCluster) (pdapi.PDClient, func(), error)
+
+// Options are the options shared by the subcommands
+type Options struct {
+	KubeConfig string
+	Context    string
+	Namespace  string
+
+	Cli          versioned.Interface
+	KubeCli      kubernetes.Interface
+	PDClientFunc PDClientFunc
+
+	// PollInterval is the interval to check the progress of the long running operations
+	PollInterval time.Duration
+	Out          io.Writer
+}
+
+// NewTkctlCommand returns the root command of the kubectl-tikv plugin
+func NewTkctlCommand(out io.Writer) *cobra.Command {
+	o := &Options{PollInterval: 5 * time.Second, Out: out}
+	cmd := &cobra.Command{
+		Use:   "kubectl-tikv",
+		Short: "Operate TiKV clusters managed by the tikv-operator",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return o.Complete()
+		},
+		SilenceUsage: true,
+	}
+	cmd.PersistentFlags().StringVar(&o.KubeConfig, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.PersistentFlags().StringVar(&o.Context, "context", "", "The kubeconfig context to use")
+	cmd.PersistentFlags().StringVarP(&o.Namespace, "namespace", "n", "", "The namespace of the TikvCluster")
+
+	cmd.AddCommand(
+		newInfoCommand(o),
+		newStoresCommand(o),
+		newMembersCommand(o),
+		newEvictLeaderCommand(o),
+		newEndEvictLeaderCommand(o),
+		newTransferPDLeaderCommand(o),
+		newRestartCommand(o),
+		newPauseCommand(o, true),
+		newPauseCommand(o, false),
+		newRenderCommand(o),
+		newPlanCommand(o),
+	)
+	return cmd
+}
+
+// Complete creates the clients from the kubeconfig, the clients which are
+// already set are kept
+func (o *Options) Complete() error {
+	if o.Cli != nil && o.KubeCli != nil && o.PDClientFunc != nil {
+		return nil
+	}
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.KubeConfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: o.Context})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	if o.Namespace == "" {
+		if o.Namespace, _, err = clientConfig.Namespace(); err != nil {
+			return err
+		}
+	}
+	return o.completeClients(config)
+}
+
+func (o *Options) completeClients(config *rest.Config) error {
+	var err error
+	if o.Cli == nil {
+		if o.Cli, err = versioned.NewForConfig(config); err != nil {
+			return err
+		}
+	}
+	if o.KubeCli == nil {
+		if o.KubeCli, err = kubernetes.NewForConfig(config); err != nil {
+			return err
+		}
+	}
+	if o.PDClientFunc == nil {
+		o.PDClientFunc = portForwardPDClientFunc(config, o.KubeCli)
+	}
+	return nil
+}
+
+func (o *Options) getTikvCluster(name string) (*v1beta1.TikvCluster, error) {
+	return o.Cli.TikvV1beta1().TikvClusters(o.Namespace).Get(name, metav1.GetOptions{})
+}
+
+// withPDClient calls fn with the PD client of the TikvCluster
+func (o *Options) withPDClient(tcName string, fn func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error) error {
+	tc, err := o.getTikvCluster(tcName)
+	if err != nil {
+		return err
+	}
+	pdCli, release, err := o.PDClientFunc(tc)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the PD cluster of TikvCluster %s/%s: %v", tc.Namespace, tc.Name, err)
+	}
+	defer release()
+	return fn(tc, pdCli)
+}
+
+// resolveStoreID returns the store id of arg, which is either a store id or
+// the name of a TiKV pod
+func resolveStoreID(tc *v1beta1.TikvCluster, arg string) (uint64, error) {
+	if id, err := strconv.ParseUint(arg, 10, 64); err == nil {
+		return id, nil
+	}
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.PodName == arg {
+			return strconv.ParseUint(store.ID, 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("no store of pod %s in TikvCluster %s/%s", arg, tc.Namespace, tc.Name)
+}
@@ -0,0 +1,260 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tkctl
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func newTikvCluster() *v1beta1.TikvCluster {
+	return &v1beta1.TikvCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: corev1.NamespaceDefault},
+		Status: v1beta1.TikvClusterStatus{
+			TiKV: v1beta1.TiKVStatus{
+				Stores: map[string]v1beta1.TiKVStore{
+					"1": {ID: "1", PodName: "demo-tikv-0"},
+					"4": {ID: "4", PodName: "demo-tikv-1"},
+				},
+			},
+		},
+	}
+}
+
+func newFakeOptions(tc *v1beta1.TikvCluster, objects ...runtime.Object) (*Options, *pdapi.FakePDClient, *kubefake.Clientset, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	pdClient := pdapi.NewFakePDClient()
+	kubeCli := kubefake.NewSimpleClientset(objects...)
+	o := &Options{
+		Namespace: tc.Namespace,
+		Cli:       fake.NewSimpleClientset(tc),
+		KubeCli:   kubeCli,
+		PDClientFunc: func(*v1beta1.TikvCluster) (pdapi.PDClient, func(), error) {
+			return pdClient, func() {}, nil
+		},
+		PollInterval: time.Millisecond,
+		Out:          out,
+	}
+	return o, pdClient, kubeCli, out
+}
+
+func newStoreInfo(id uint64, state string, leaderCount int) *pdapi.StoreInfo {
+	return &pdapi.StoreInfo{
+		Store: &pdapi.MetaStore{
+			Store:     &metapb.Store{Id: id, Address: fmt.Sprintf("demo-tikv-%d.demo-tikv-peer.default.svc:20160", id)},
+			StateName: state,
+		},
+		Status: &pdapi.StoreStatus{LeaderCount: leaderCount, Capacity: 3 << 30},
+	}
+}
+
+func TestResolveStoreID(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvCluster()
+
+	id, err := resolveStoreID(tc, "7")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(id).To(Equal(uint64(7)))
+
+	id, err = resolveStoreID(tc, "demo-tikv-1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(id).To(Equal(uint64(4)))
+
+	_, err = resolveStoreID(tc, "demo-tikv-2")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestPrintStores(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvCluster()
+	o, pdClient, _, out := newFakeOptions(tc)
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{newStoreInfo(4, v1beta1.TiKVStateUp, 3), newStoreInfo(1, v1beta1.TiKVStateUp, 0)}}, nil
+	})
+	pdClient.AddReaction(pdapi.GetTombStoneStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{newStoreInfo(2, v1beta1.TiKVStateTombstone, 0)}}, nil
+	})
+	pdClient.AddReaction(pdapi.GetEvictLeaderSchedulersActionType, func(action *pdapi.Action) (interface{}, error) {
+		return []string{"evict-leader-scheduler-1"}, nil
+	})
+
+	g.Expect(o.withPDClient(tc.Name, func(tc *v1beta1.TikvCluster, pdCli pdapi.PDClient) error {
+		return printStores(o.Out, pdCli)
+	})).To(Succeed())
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	g.Expect(lines).To(HaveLen(4))
+	g.Expect(string(lines[1])).To(MatchRegexp(`^1 .* Up .* 3\.0GiB .* true$`))
+	g.Expect(string(lines[2])).To(MatchRegexp(`^2 .* Tombstone .* false$`))
+	g.Expect(string(lines[3])).To(MatchRegexp(`^4 .* Up +3 .* false$`))
+}
+
+func TestTransferPDLeader(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvCluster()
+	o, pdClient, _, _ := newFakeOptions(tc)
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.MembersInfo{
+			Members: []*pdpb.Member{{Name: "demo-pd-0", MemberId: 1}, {Name: "demo-pd-1", MemberId: 2}},
+			Leader:  &pdpb.Member{Name: "demo-pd-0", MemberId: 1},
+		}, nil
+	})
+	transferred := ""
+	pdClient.AddReaction(pdapi.TransferPDLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		transferred = action.Name
+		return nil, nil
+	})
+
+	g.Expect(transferPDLeader(o, pdClient, "demo-pd-2")).NotTo(Succeed())
+	g.Expect(transferPDLeader(o, pdClient, "demo-pd-0")).To(Succeed())
+	g.Expect(transferred).To(BeEmpty())
+	g.Expect(transferPDLeader(o, pdClient, "demo-pd-1")).To(Succeed())
+	g.Expect(transferred).To(Equal("demo-pd-1"))
+}
+
+func TestSetPaused(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tc := newTikvCluster()
+	o, _, _, _ := newFakeOptions(tc)
+
+	g.Expect(setPaused(o, tc.Name, true)).To(Succeed())
+	tc, err := o.getTikvCluster(tc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Spec.Paused).To(BeTrue())
+
+	g.Expect(setPaused(o, tc.Name, false)).To(Succeed())
+	tc, err = o.getTikvCluster(tc.Name)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Spec.Paused).To(BeFalse())
+}
+
+func TestRestart(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name         string
+		podName      string
+		component    string
+		leadersStuck bool
+		noStatus     bool
+		expectErr    bool
+		expectEvict  bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTikvCluster()
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      test.podName,
+				Namespace: tc.Namespace,
+				UID:       "old",
+				Labels:    map[string]string{label.InstanceLabelKey: tc.Name, label.ComponentLabelKey: test.component},
+			},
+		}
+		o, pdClient, kubeCli, _ := newFakeOptions(tc, pod)
+		// the statefulset controller recreates the pod once it is deleted
+		kubeCli.PrependReactor("delete", "pods", func(action kubetesting.Action) (bool, runtime.Object, error) {
+			newPod := pod.DeepCopy()
+			newPod.UID = types.UID("new")
+			newPod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			return true, nil, kubeCli.Tracker().Update(corev1.SchemeGroupVersion.WithResource("pods"), newPod, tc.Namespace)
+		})
+		leaderCount := 2
+		statusReported := !test.noStatus
+		pdClient.AddReaction(pdapi.GetStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			store := newStoreInfo(action.ID, v1beta1.TiKVStateUp, leaderCount)
+			if !statusReported {
+				statusReported = true
+				store.Status = nil
+				return store, nil
+			}
+			if leaderCount > 0 && !test.leadersStuck {
+				leaderCount--
+			}
+			store.Status.LeaderCount = leaderCount
+			return store, nil
+		})
+		evicting := false
+		evicted := false
+		pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			evicting, evicted = true, true
+			return nil, nil
+		})
+		pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			evicting = false
+			return nil, nil
+		})
+
+		ro := &restartOptions{Options: o, evictTimeout: 100 * time.Millisecond, timeout: time.Second}
+		err := ro.restart(tc, pdClient, test.podName)
+		if test.expectErr {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+			newPod, err := kubeCli.CoreV1().Pods(tc.Namespace).Get(test.podName, metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(newPod.UID).To(Equal(types.UID("new")))
+		}
+		g.Expect(evicted).To(Equal(test.expectEvict))
+		g.Expect(evicting).To(BeFalse())
+	}
+
+	tests := []testcase{
+		{
+			name:        "restart after the leaders are evicted",
+			podName:     "demo-tikv-1",
+			component:   label.TiKVLabelVal,
+			expectEvict: true,
+		},
+		{
+			name:         "restart after the eviction times out",
+			podName:      "demo-tikv-1",
+			component:    label.TiKVLabelVal,
+			leadersStuck: true,
+			expectEvict:  true,
+		},
+		{
+			name:        "restart after the store status is reported",
+			podName:     "demo-tikv-1",
+			component:   label.TiKVLabelVal,
+			noStatus:    true,
+			expectEvict: true,
+		},
+		{
+			name:      "not a TiKV pod",
+			podName:   "demo-pd-0",
+			component: label.PDLabelVal,
+			expectErr: true,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
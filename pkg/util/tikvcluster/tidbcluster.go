@@ -14,7 +14,7 @@
 package tikvcluster
 
 import (
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -30,11 +30,23 @@ const (
 	PDUnhealthy = "PDUnhealthy"
 	// TiKVStoreNotUp is added when one of tikv stores is not up.
 	TiKVStoreNotUp = "TiKVStoreNotUp"
+	// ScaleInPreflightCheckFailed is added when the pre-flight checks block the scale-in of tikv.
+	ScaleInPreflightCheckFailed = "PreflightCheckFailed"
+	// ScaleInPreflightCheckPassed is added when the stores pass the pre-flight checks of the scale-in.
+	ScaleInPreflightCheckPassed = "PreflightCheckPassed"
+	// NoExternalStoresDeletion is added when no external store is requested to be deleted.
+	NoExternalStoresDeletion = "NoExternalStoresDeletion"
+	// NoScaleIn is added when tikv is not scaling in any more.
+	NoScaleIn = "NoScaleIn"
+	// DisruptionNotTolerable is added when the PodDisruptionBudget allows no voluntary disruption.
+	DisruptionNotTolerable = "DisruptionNotTolerable"
+	// DisruptionTolerable is added when the PodDisruptionBudget allows voluntary disruptions again.
+	DisruptionTolerable = "DisruptionTolerable"
 )
 
 // NewTikvClusterCondition creates a new tikvcluster condition.
-func NewTikvClusterCondition(condType v1alpha1.TikvClusterConditionType, status v1.ConditionStatus, reason, message string) *v1alpha1.TikvClusterCondition {
-	return &v1alpha1.TikvClusterCondition{
+func NewTikvClusterCondition(condType v1beta1.TikvClusterConditionType, status v1.ConditionStatus, reason, message string) *v1beta1.TikvClusterCondition {
+	return &v1beta1.TikvClusterCondition{
 		Type:               condType,
 		Status:             status,
 		LastUpdateTime:     metav1.Now(),
@@ -45,7 +57,7 @@ func NewTikvClusterCondition(condType v1alpha1.TikvClusterConditionType, status
 }
 
 // GetTikvClusterCondition returns the condition with the provided type.
-func GetTikvClusterCondition(status v1alpha1.TikvClusterStatus, condType v1alpha1.TikvClusterConditionType) *v1alpha1.TikvClusterCondition {
+func GetTikvClusterCondition(status v1beta1.TikvClusterStatus, condType v1beta1.TikvClusterConditionType) *v1beta1.TikvClusterCondition {
 	for i := range status.Conditions {
 		c := status.Conditions[i]
 		if c.Type == condType {
@@ -57,7 +69,7 @@ func GetTikvClusterCondition(status v1alpha1.TikvClusterStatus, condType v1alpha
 
 // SetTikvClusterCondition updates the tikv cluster to include the provided condition. If the condition that
 // we are about to add already exists and has the same status and reason then we are not going to update.
-func SetTikvClusterCondition(status *v1alpha1.TikvClusterStatus, condition v1alpha1.TikvClusterCondition) {
+func SetTikvClusterCondition(status *v1beta1.TikvClusterStatus, condition v1beta1.TikvClusterCondition) {
 	currentCond := GetTikvClusterCondition(*status, condition.Type)
 	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason {
 		return
@@ -71,8 +83,8 @@ func SetTikvClusterCondition(status *v1alpha1.TikvClusterStatus, condition v1alp
 }
 
 // filterOutCondition returns a new slice of tikvcluster conditions without conditions with the provided type.
-func filterOutCondition(conditions []v1alpha1.TikvClusterCondition, condType v1alpha1.TikvClusterConditionType) []v1alpha1.TikvClusterCondition {
-	var newConditions []v1alpha1.TikvClusterCondition
+func filterOutCondition(conditions []v1beta1.TikvClusterCondition, condType v1beta1.TikvClusterConditionType) []v1beta1.TikvClusterCondition {
+	var newConditions []v1beta1.TikvClusterCondition
 	for _, c := range conditions {
 		if c.Type == condType {
 			continue
@@ -84,6 +96,6 @@ func filterOutCondition(conditions []v1alpha1.TikvClusterCondition, condType v1a
 
 // GetTikvClusterReadyCondition extracts the tikvcluster ready condition from the given status and returns that.
 // Returns nil if the condition is not present.
-func GetTikvClusterReadyCondition(status v1alpha1.TikvClusterStatus) *v1alpha1.TikvClusterCondition {
-	return GetTikvClusterCondition(status, v1alpha1.TikvClusterReady)
+func GetTikvClusterReadyCondition(status v1beta1.TikvClusterStatus) *v1beta1.TikvClusterCondition {
+	return GetTikvClusterCondition(status, v1beta1.TikvClusterReady)
 }
@@ -20,7 +20,7 @@ import (
 	"strings"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/features"
 	"github.com/tikv/tikv-operator/pkg/label"
 	appsv1 "k8s.io/api/apps/v1"
@@ -50,7 +50,7 @@ func IsPodOrdinalNotExceedReplicas(pod *corev1.Pod, sts *appsv1.StatefulSet) (bo
 	return ordinal < *sts.Spec.Replicas, nil
 }
 
-func getDeleteSlots(tc *v1alpha1.TikvCluster, annKey string) (deleteSlots sets.Int32) {
+func getDeleteSlots(tc *v1beta1.TikvCluster, annKey string) (deleteSlots sets.Int32) {
 	deleteSlots = sets.NewInt32()
 	annotations := tc.GetAnnotations()
 	if annotations == nil {
@@ -70,13 +70,13 @@ func getDeleteSlots(tc *v1alpha1.TikvCluster, annKey string) (deleteSlots sets.I
 }
 
 // GetPodOrdinals gets desired ordials of member in given TikvCluster.
-func GetPodOrdinals(tc *v1alpha1.TikvCluster, memberType v1alpha1.MemberType) (sets.Int32, error) {
+func GetPodOrdinals(tc *v1beta1.TikvCluster, memberType v1beta1.MemberType) (sets.Int32, error) {
 	var ann string
 	var replicas int32
-	if memberType == v1alpha1.PDMemberType {
+	if memberType == v1beta1.PDMemberType {
 		ann = label.AnnPDDeleteSlots
 		replicas = tc.Spec.PD.Replicas
-	} else if memberType == v1alpha1.TiKVMemberType {
+	} else if memberType == v1beta1.TiKVMemberType {
 		ann = label.AnnTiKVDeleteSlots
 		replicas = tc.Spec.TiKV.Replicas
 	} else {
@@ -93,7 +93,7 @@ func GetPodOrdinals(tc *v1alpha1.TikvCluster, memberType v1alpha1.MemberType) (s
 	return podOrdinals, nil
 }
 
-func OrdinalPVCName(memberType v1alpha1.MemberType, setName string, ordinal int32) string {
+func OrdinalPVCName(memberType v1beta1.MemberType, setName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%s-%d", memberType, setName, ordinal)
 }
 
@@ -110,7 +110,7 @@ func IsSubMapOf(first map[string]string, second map[string]string) bool {
 	return true
 }
 
-func GetPodName(tc *v1alpha1.TikvCluster, memberType v1alpha1.MemberType, ordinal int32) string {
+func GetPodName(tc *v1beta1.TikvCluster, memberType v1beta1.MemberType, ordinal int32) string {
 	return fmt.Sprintf("%s-%s-%d", tc.Name, memberType.String(), ordinal)
 }
 
@@ -122,7 +122,7 @@ func IsStatefulSetScaling(set *appsv1.StatefulSet) bool {
 	return !(set.Status.Replicas == *set.Spec.Replicas)
 }
 
-func GetStatefulSetName(tc *v1alpha1.TikvCluster, memberType v1alpha1.MemberType) string {
+func GetStatefulSetName(tc *v1beta1.TikvCluster, memberType v1beta1.MemberType) string {
 	return fmt.Sprintf("%s-%s", tc.Name, memberType.String())
 }
 
@@ -187,7 +187,7 @@ func IsOwnedByTikvCluster(obj metav1.Object) (bool, *metav1.OwnerReference) {
 	if err != nil {
 		return false, nil
 	}
-	return ref.Kind == "TikvCluster" && gv.Group == v1alpha1.SchemeGroupVersion.Group, ref
+	return ref.Kind == "TikvCluster" && gv.Group == v1beta1.SchemeGroupVersion.Group, ref
 }
 
 // RetainManagedFields retains the fields in the old object that are managed by kube-controller-manager, such as node ports
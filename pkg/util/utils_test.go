@@ -18,7 +18,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
-	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
 	"github.com/tikv/tikv-operator/pkg/label"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -78,40 +78,40 @@ func TestIsSubMapOf(t *testing.T) {
 func TestGetPodOrdinals(t *testing.T) {
 	tests := []struct {
 		name        string
-		tc          *v1alpha1.TikvCluster
-		memberType  v1alpha1.MemberType
+		tc          *v1beta1.TikvCluster
+		memberType  v1beta1.MemberType
 		deleteSlots sets.Int32
 	}{
 		{
 			name: "no delete slots",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{},
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
 						Replicas: 3,
 					},
 				},
 			},
-			memberType:  v1alpha1.TiKVMemberType,
+			memberType:  v1beta1.TiKVMemberType,
 			deleteSlots: sets.NewInt32(0, 1, 2),
 		},
 		{
 			name: "delete slots",
-			tc: &v1alpha1.TikvCluster{
+			tc: &v1beta1.TikvCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						label.AnnTiKVDeleteSlots: "[1,2]",
 					},
 				},
-				Spec: v1alpha1.TikvClusterSpec{
-					TiKV: v1alpha1.TiKVSpec{
+				Spec: v1beta1.TikvClusterSpec{
+					TiKV: v1beta1.TiKVSpec{
 						Replicas: 3,
 					},
 				},
 			},
-			memberType:  v1alpha1.TiKVMemberType,
+			memberType:  v1beta1.TiKVMemberType,
 			deleteSlots: sets.NewInt32(0, 3, 4),
 		},
 	}
@@ -187,3 +187,45 @@ func TestAppendEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			a:    "a\nb\nc\n",
+			b:    "a\nx\nc\n",
+			want: " a\n-b\n+x\n c\n",
+		},
+		{
+			name: "unchanged lines are skipped",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			want: "...\n 7\n 8\n 9\n+10\n",
+		},
+		{
+			name: "added from empty",
+			a:    "",
+			b:    "a\n",
+			want: "+a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, LineDiff(tt.a, tt.b)); diff != "" {
+				t.Errorf("unwant (-want, +got): %s", diff)
+			}
+		})
+	}
+}
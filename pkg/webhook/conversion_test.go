@@ -0,0 +1,89 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1alpha1"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestConversionHandler(t *testing.T) {
+	g := NewGomegaWithT(t)
+	handler, err := NewConversionHandler()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	convert := func(desiredAPIVersion string, obj interface{}) *apiextensionsv1beta1.ConversionResponse {
+		raw, err := json.Marshal(obj)
+		g.Expect(err).NotTo(HaveOccurred())
+		review := &apiextensionsv1beta1.ConversionReview{
+			Request: &apiextensionsv1beta1.ConversionRequest{
+				UID:               types.UID("test"),
+				DesiredAPIVersion: desiredAPIVersion,
+				Objects:           []runtime.RawExtension{{Raw: raw}},
+			},
+		}
+		body, err := json.Marshal(review)
+		g.Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPost, ConversionPath, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		g.Expect(w.Code).To(Equal(http.StatusOK))
+
+		review = &apiextensionsv1beta1.ConversionReview{}
+		g.Expect(json.Unmarshal(w.Body.Bytes(), review)).To(Succeed())
+		g.Expect(review.Response.UID).To(Equal(types.UID("test")))
+		return review.Response
+	}
+
+	tc := &v1alpha1.TikvCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "tikv.org/v1alpha1", Kind: "TikvCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"},
+		Spec: v1alpha1.TikvClusterSpec{
+			Version: "v4.0.0",
+			PD:      v1alpha1.PDSpec{BaseImage: "pingcap/pd"},
+			TiKV:    v1alpha1.TiKVSpec{BaseImage: "pingcap/tikv"},
+		},
+	}
+	resp := convert("tikv.org/v1beta1", tc)
+	g.Expect(resp.Result.Status).To(Equal(metav1.StatusSuccess))
+	g.Expect(resp.ConvertedObjects).To(HaveLen(1))
+	hub := &v1beta1.TikvCluster{}
+	g.Expect(json.Unmarshal(resp.ConvertedObjects[0].Raw, hub)).To(Succeed())
+	g.Expect(hub.APIVersion).To(Equal("tikv.org/v1beta1"))
+	g.Expect(hub.Name).To(Equal("demo"))
+	g.Expect(hub.Spec.PD.Version).To(Equal("v4.0.0"))
+	g.Expect(hub.Spec.TiKV.Version).To(Equal("v4.0.0"))
+
+	resp = convert("tikv.org/v1alpha1", hub)
+	g.Expect(resp.Result.Status).To(Equal(metav1.StatusSuccess))
+	restored := &v1alpha1.TikvCluster{}
+	g.Expect(json.Unmarshal(resp.ConvertedObjects[0].Raw, restored)).To(Succeed())
+	g.Expect(restored).To(Equal(tc))
+
+	// the conversion between the same version is rejected
+	resp = convert("tikv.org/v1beta1", hub)
+	g.Expect(resp.Result.Status).To(Equal(metav1.StatusFailure))
+}
@@ -0,0 +1,233 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	listers "github.com/tikv/tikv-operator/pkg/client/listers/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	"github.com/tikv/tikv-operator/pkg/util"
+	"github.com/tikv/tikv-operator/pkg/webhook"
+	admission "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog"
+)
+
+// EvictionAdmitter holds the evictions of TiKV pods until the region leaders
+// have been evicted from the stores.
+type EvictionAdmitter struct {
+	pdControl          pdapi.PDControlInterface
+	podControl         controller.PodControlInterface
+	pvcControl         controller.PVCControlInterface
+	tcLister           listers.TikvClusterLister
+	podLister          corelisters.PodLister
+	pvcLister          corelisters.PersistentVolumeClaimLister
+	evictLeaderTimeout time.Duration
+}
+
+// NewEvictionAdmitter returns an EvictionAdmitter
+func NewEvictionAdmitter(
+	pdControl pdapi.PDControlInterface,
+	podControl controller.PodControlInterface,
+	pvcControl controller.PVCControlInterface,
+	tcLister listers.TikvClusterLister,
+	podLister corelisters.PodLister,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	evictLeaderTimeout time.Duration,
+) *EvictionAdmitter {
+	return &EvictionAdmitter{
+		pdControl:          pdControl,
+		podControl:         podControl,
+		pvcControl:         pvcControl,
+		tcLister:           tcLister,
+		podLister:          podLister,
+		pvcLister:          pvcLister,
+		evictLeaderTimeout: evictLeaderTimeout,
+	}
+}
+
+// AdmitEviction denies the eviction of a TiKV pod whose store still holds region leaders,
+// the leader eviction of the store is started on the first request and the pod is allowed
+// to be evicted once no leader is left in PD or the eviction times out. The leader eviction
+// begun by the webhook is recorded on the PVC of the pod and ended by the tikv member manager
+// after the pod is recreated.
+func (ea *EvictionAdmitter) AdmitEviction(req *admission.AdmissionRequest) *admission.AdmissionResponse {
+	if req.Operation != admission.Create || req.SubResource != "eviction" {
+		return webhook.Allowed()
+	}
+	ns := req.Namespace
+	podName := req.Name
+
+	pod, err := ea.podLister.Pods(ns).Get(podName)
+	if errors.IsNotFound(err) {
+		return webhook.Allowed()
+	}
+	if err != nil {
+		return webhook.Denied(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+	}
+	l := label.Label(pod.Labels)
+	if !l.IsTiKV() || pod.Labels[label.ManagedByLabelKey] != label.TiKVOperator {
+		return webhook.Allowed()
+	}
+
+	tcName := pod.Labels[label.InstanceLabelKey]
+	tc, err := ea.tcLister.TikvClusters(ns).Get(tcName)
+	if errors.IsNotFound(err) {
+		return webhook.Allowed()
+	}
+	if err != nil {
+		return webhook.Denied(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+	}
+
+	var store *v1beta1.TiKVStore
+	for _, s := range tc.Status.TiKV.Stores {
+		if s.PodName == podName {
+			s := s
+			store = &s
+			break
+		}
+	}
+	if store == nil {
+		klog.V(4).Infof("pod eviction webhook: no store found for tikv pod %s/%s, allow the eviction", ns, podName)
+		return webhook.Allowed()
+	}
+	storeID, err := strconv.ParseUint(store.ID, 10, 64)
+	if err != nil {
+		return webhook.Denied(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+	}
+
+	pdClient := controller.GetPDClient(ea.pdControl, tc)
+	leaderCount := store.LeaderCount
+	beginTimeStr, evicting := pod.Annotations[label.AnnEvictLeaderBeginTime]
+	if evicting {
+		// the leader count in the status may be a resync period old
+		storeInfo, err := pdClient.GetStore(storeID)
+		if err != nil {
+			klog.Errorf("pod eviction webhook: failed to get store %d, %s/%s, %v", storeID, ns, podName, err)
+			return webhook.Denied(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		}
+		if storeInfo.Status != nil {
+			leaderCount = int32(storeInfo.Status.LeaderCount)
+		}
+		if ea.leadersEvicted(leaderCount, beginTimeStr) {
+			// the eviction may still be rejected after the admission, e.g. by the PodDisruptionBudget,
+			// so the leader eviction is kept until the pod is recreated and its store is Up again,
+			// it is ended by the tikv member manager then.
+			klog.Infof("pod eviction webhook: leaders of store %d have been evicted, allow the eviction of pod %s/%s", storeID, ns, podName)
+			return webhook.Allowed()
+		}
+	}
+
+	if !evicting {
+		if err := ea.markEvictLeaderStore(tc, pod, storeID); err != nil {
+			return webhook.Denied(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		}
+	}
+
+	// begin evict leader is idempotent, it is called on every request in case the
+	// scheduler has been removed since the first request
+	if err := pdClient.BeginEvictLeader(storeID); err != nil {
+		klog.Errorf("pod eviction webhook: failed to begin evict leader: %d, %s/%s, %v", storeID, ns, podName, err)
+		return webhook.Denied(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+	}
+	if !evicting {
+		if err := ea.setEvictLeaderBeginTime(tc, pod); err != nil {
+			return webhook.Denied(http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		}
+	}
+
+	msg := fmt.Sprintf("evicting region leaders of store %d from tikv pod %s/%s, %d leader(s) left", storeID, ns, podName, leaderCount)
+	return webhook.Denied(http.StatusTooManyRequests, metav1.StatusReasonTooManyRequests, msg)
+}
+
+// leadersEvicted returns true if the store has no leader left or the eviction times out
+func (ea *EvictionAdmitter) leadersEvicted(leaderCount int32, beginTimeStr string) bool {
+	if leaderCount == 0 {
+		return true
+	}
+	beginTime, err := time.Parse(time.RFC3339, beginTimeStr)
+	if err != nil {
+		klog.Errorf("parse annotation:[%s] to time failed.", label.AnnEvictLeaderBeginTime)
+		return false
+	}
+	return time.Now().After(beginTime.Add(ea.evictLeaderTimeout))
+}
+
+// markEvictLeaderStore records the store on the PVC of the pod before its leader eviction is begun,
+// so that the tikv member manager only ends the leader evictions begun by the webhook. The store
+// is not recorded if its leader eviction has been begun by others, e.g. the node maintenance
+// controller or kubectl-tikv, they end it by themselves.
+func (ea *EvictionAdmitter) markEvictLeaderStore(tc *v1beta1.TikvCluster, pod *corev1.Pod, storeID uint64) error {
+	ordinal, err := util.GetOrdinalFromPodName(pod.GetName())
+	if err != nil {
+		return err
+	}
+	pvcName := util.OrdinalPVCName(v1beta1.TiKVMemberType, controller.TiKVMemberName(tc.GetName()), ordinal)
+	pvc, err := ea.pvcLister.PersistentVolumeClaims(pod.GetNamespace()).Get(pvcName)
+	if err != nil {
+		return err
+	}
+	id := strconv.FormatUint(storeID, 10)
+	if pvc.Annotations[label.AnnPVCEvictLeaderStore] == id {
+		return nil
+	}
+	schedulers, err := controller.GetPDClient(ea.pdControl, tc).GetEvictLeaderSchedulers()
+	if err != nil {
+		klog.Errorf("pod eviction webhook: failed to get evict leader schedulers, %s/%s, %v", pod.GetNamespace(), pod.GetName(), err)
+		return err
+	}
+	for _, scheduler := range schedulers {
+		if scheduler == pdapi.GetLeaderEvictSchedulerStr(storeID) {
+			klog.Infof("pod eviction webhook: leaders of store %d are being evicted by others, %s/%s", storeID, pod.GetNamespace(), pod.GetName())
+			return nil
+		}
+	}
+
+	pvc = pvc.DeepCopy()
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Annotations[label.AnnPVCEvictLeaderStore] = id
+	if _, err := ea.pvcControl.UpdatePVC(tc, pvc); err != nil {
+		klog.Errorf("pod eviction webhook: failed to set pvc %s/%s annotation %s to %s, %v",
+			pvc.GetNamespace(), pvc.GetName(), label.AnnPVCEvictLeaderStore, id, err)
+		return err
+	}
+	return nil
+}
+
+func (ea *EvictionAdmitter) setEvictLeaderBeginTime(tc *v1beta1.TikvCluster, pod *corev1.Pod) error {
+	pod = pod.DeepCopy()
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	now := time.Now().Format(time.RFC3339)
+	pod.Annotations[label.AnnEvictLeaderBeginTime] = now
+	if _, err := ea.podControl.UpdatePod(tc, pod); err != nil {
+		klog.Errorf("pod eviction webhook: failed to set pod %s/%s annotation %s to %s, %v",
+			pod.GetNamespace(), pod.GetName(), label.AnnEvictLeaderBeginTime, now, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/tikv/tikv-operator/pkg/apis/tikv/v1beta1"
+	"github.com/tikv/tikv-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/tikv/tikv-operator/pkg/client/informers/externalversions"
+	"github.com/tikv/tikv-operator/pkg/controller"
+	"github.com/tikv/tikv-operator/pkg/label"
+	"github.com/tikv/tikv-operator/pkg/pdapi"
+	admission "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdmitEviction(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name              string
+		updatePod         func(*corev1.Pod)
+		updateTC          func(*v1beta1.TikvCluster)
+		beginEvictErr     bool
+		expectAllowed     bool
+		expectCode        int32
+		expectBeginEvicts int
+		expectEndEvicts   int
+		expectAnnotation  bool
+		expectMarked      bool
+		pdLeaderCount     int
+		schedulers        []string
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTikvClusterForEviction()
+		if test.updateTC != nil {
+			test.updateTC(tc)
+		}
+		pod := newTiKVPodForEviction(tc)
+		if test.updatePod != nil {
+			test.updatePod(pod)
+		}
+
+		cli := fake.NewSimpleClientset()
+		kubeCli := kubefake.NewSimpleClientset()
+		informerFactory := informers.NewSharedInformerFactory(cli, 0)
+		kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+		tcInformer := informerFactory.Tikv().V1beta1().TikvClusters()
+		podInformer := kubeInformerFactory.Core().V1().Pods()
+		pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+		tcInformer.Informer().GetIndexer().Add(tc)
+		podInformer.Informer().GetIndexer().Add(pod)
+		pvcInformer.Informer().GetIndexer().Add(&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "tikv-test-tikv-0", Namespace: tc.GetNamespace()},
+		})
+
+		pdControl := pdapi.NewFakePDControl(kubeCli)
+		pdClient := controller.NewFakePDClient(pdControl, tc)
+		beginEvicts, endEvicts := 0, 0
+		pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			beginEvicts++
+			if test.beginEvictErr {
+				return nil, fmt.Errorf("begin evict leader failed")
+			}
+			return nil, nil
+		})
+		pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+			endEvicts++
+			return nil, nil
+		})
+		pdClient.AddReaction(pdapi.GetStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.StoreInfo{Status: &pdapi.StoreStatus{LeaderCount: test.pdLeaderCount}}, nil
+		})
+		pdClient.AddReaction(pdapi.GetEvictLeaderSchedulersActionType, func(action *pdapi.Action) (interface{}, error) {
+			return test.schedulers, nil
+		})
+		podControl := controller.NewFakePodControl(podInformer)
+		pvcControl := controller.NewFakePVCControl(pvcInformer)
+
+		admitter := NewEvictionAdmitter(pdControl, podControl, pvcControl, tcInformer.Lister(), podInformer.Lister(), pvcInformer.Lister(), 3*time.Minute)
+		resp := admitter.AdmitEviction(&admission.AdmissionRequest{
+			Name:        pod.Name,
+			Namespace:   pod.Namespace,
+			Operation:   admission.Create,
+			SubResource: "eviction",
+		})
+
+		g.Expect(resp.Allowed).To(Equal(test.expectAllowed))
+		if !test.expectAllowed {
+			g.Expect(resp.Result.Code).To(Equal(test.expectCode))
+		}
+		g.Expect(beginEvicts).To(Equal(test.expectBeginEvicts))
+		g.Expect(endEvicts).To(Equal(test.expectEndEvicts))
+
+		updated, err := podInformer.Lister().Pods(pod.Namespace).Get(pod.Name)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, exist := updated.Annotations[label.AnnEvictLeaderBeginTime]
+		g.Expect(exist).To(Equal(test.expectAnnotation))
+		pvc, err := pvcInformer.Lister().PersistentVolumeClaims(pod.Namespace).Get("tikv-test-tikv-0")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(pvc.Annotations[label.AnnPVCEvictLeaderStore] == "1").To(Equal(test.expectMarked))
+	}
+
+	tests := []testcase{
+		{
+			name: "not a tikv pod",
+			updatePod: func(pod *corev1.Pod) {
+				pod.Labels = label.New().Instance("test").PD().Labels()
+			},
+			expectAllowed: true,
+		},
+		{
+			name: "no store for the pod",
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				tc.Status.TiKV.Stores = nil
+			},
+			expectAllowed: true,
+		},
+		{
+			name:              "first eviction",
+			expectAllowed:     false,
+			expectCode:        http.StatusTooManyRequests,
+			expectBeginEvicts: 1,
+			expectAnnotation:  true,
+			expectMarked:      true,
+		},
+		{
+			name:              "leaders are evicted by node maintenance",
+			schedulers:        []string{"evict-leader-scheduler-1"},
+			expectAllowed:     false,
+			expectCode:        http.StatusTooManyRequests,
+			expectBeginEvicts: 1,
+			expectAnnotation:  true,
+		},
+		{
+			name:              "begin evict leader failed",
+			beginEvictErr:     true,
+			expectAllowed:     false,
+			expectCode:        http.StatusInternalServerError,
+			expectBeginEvicts: 1,
+			expectMarked:      true,
+		},
+		{
+			name: "leaders are being evicted",
+			updatePod: func(pod *corev1.Pod) {
+				pod.Annotations = map[string]string{label.AnnEvictLeaderBeginTime: time.Now().Add(-1 * time.Minute).Format(time.RFC3339)}
+			},
+			pdLeaderCount:     5,
+			expectAllowed:     false,
+			expectCode:        http.StatusTooManyRequests,
+			expectBeginEvicts: 1,
+			expectAnnotation:  true,
+		},
+		{
+			name: "leaders have been evicted in PD",
+			updatePod: func(pod *corev1.Pod) {
+				pod.Annotations = map[string]string{label.AnnEvictLeaderBeginTime: time.Now().Add(-1 * time.Minute).Format(time.RFC3339)}
+			},
+			pdLeaderCount:    0,
+			expectAllowed:    true,
+			expectAnnotation: true,
+		},
+		{
+			name: "leaders are left in PD but not in the stale status",
+			updatePod: func(pod *corev1.Pod) {
+				pod.Annotations = map[string]string{label.AnnEvictLeaderBeginTime: time.Now().Add(-1 * time.Minute).Format(time.RFC3339)}
+			},
+			updateTC: func(tc *v1beta1.TikvCluster) {
+				store := tc.Status.TiKV.Stores["1"]
+				store.LeaderCount = 0
+				tc.Status.TiKV.Stores["1"] = store
+			},
+			pdLeaderCount:     3,
+			expectAllowed:     false,
+			expectCode:        http.StatusTooManyRequests,
+			expectBeginEvicts: 1,
+			expectAnnotation:  true,
+		},
+		{
+			name: "leader eviction timed out",
+			updatePod: func(pod *corev1.Pod) {
+				pod.Annotations = map[string]string{label.AnnEvictLeaderBeginTime: time.Now().Add(-5 * time.Minute).Format(time.RFC3339)}
+			},
+			pdLeaderCount:    5,
+			expectAllowed:    true,
+			expectAnnotation: true,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func newTikvClusterForEviction() *v1beta1.TikvCluster {
+	return &v1beta1.TikvCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TikvCluster",
+			APIVersion: "tikv.org/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Status: v1beta1.TikvClusterStatus{
+			TiKV: v1beta1.TiKVStatus{
+				Stores: map[string]v1beta1.TiKVStore{
+					"1": {
+						ID:          "1",
+						PodName:     "test-tikv-0",
+						LeaderCount: 10,
+						State:       v1beta1.TiKVStateUp,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTiKVPodForEviction(tc *v1beta1.TikvCluster) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tikv-0",
+			Namespace: tc.GetNamespace(),
+			Labels:    label.New().Instance(tc.GetName()).TiKV().Labels(),
+		},
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+
+	admission "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// PodEvictionPath is the path of the admission webhook for pods/eviction
+	PodEvictionPath = "/pods/eviction"
+	// ConversionPath is the path of the CRD conversion webhook
+	ConversionPath = "/convert"
+
+	certFileName = "tls.crt"
+	keyFileName  = "tls.key"
+)
+
+// AdmitFunc handles an admission request and returns the admission response
+type AdmitFunc func(*admission.AdmissionRequest) *admission.AdmissionResponse
+
+// StartServer starts the webhook server over https, the certificate and private key
+// are loaded from certDir.
+func StartServer(port int, certDir string, handlers map[string]http.Handler) error {
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		mux.Handle(path, handler)
+	}
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	klog.Infof("starting webhook server, listening on 0.0.0.0:%d", port)
+	return server.ListenAndServeTLS(filepath.Join(certDir, certFileName), filepath.Join(certDir, keyFileName))
+}
+
+// NamespacedAdmitFunc returns the AdmitFunc which dispatches the admission requests to the
+// AdmitFuncs of their namespaces, the AdmitFunc of metav1.NamespaceAll handles the other
+// namespaces. The requests which no AdmitFunc handles are allowed.
+func NamespacedAdmitFunc(admits map[string]AdmitFunc) AdmitFunc {
+	return func(req *admission.AdmissionRequest) *admission.AdmissionResponse {
+		if admit, ok := admits[req.Namespace]; ok {
+			return admit(req)
+		}
+		if admit, ok := admits[metav1.NamespaceAll]; ok {
+			return admit(req)
+		}
+		return Allowed()
+	}
+}
+
+// AdmissionHandler returns the http handler of the admission webhook which calls admit
+func AdmissionHandler(admit AdmitFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, admit)
+	})
+}
+
+// serve decodes the AdmissionReview from the request, calls admit and writes back the AdmissionReview
+func serve(w http.ResponseWriter, r *http.Request, admit AdmitFunc) {
+	if r.Body == nil {
+		http.Error(w, "empty request body", http.StatusBadRequest)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+		http.Error(w, fmt.Sprintf("unexpected content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	review := admission.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		klog.Errorf("failed to decode admission review: %v", err)
+		http.Error(w, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+
+	resp := admit(review.Request)
+	resp.UID = review.Request.UID
+	review.Response = resp
+	review.Request = nil
+
+	data, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		klog.Errorf("failed to write admission response: %v", err)
+	}
+}
+
+// Allowed returns an admission response which allows the request
+func Allowed() *admission.AdmissionResponse {
+	return &admission.AdmissionResponse{Allowed: true}
+}
+
+// Denied returns an admission response which denies the request with the given code and message
+func Denied(code int32, reason metav1.StatusReason, message string) *admission.AdmissionResponse {
+	return &admission.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    code,
+			Reason:  reason,
+			Message: message,
+		},
+	}
+}
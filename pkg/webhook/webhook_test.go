@@ -0,0 +1,62 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admission "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespacedAdmitFunc(t *testing.T) {
+	deny := func(req *admission.AdmissionRequest) *admission.AdmissionResponse {
+		return Denied(http.StatusTooManyRequests, metav1.StatusReasonTooManyRequests, req.Namespace)
+	}
+	tests := []struct {
+		name          string
+		admits        map[string]AdmitFunc
+		namespace     string
+		expectAllowed bool
+	}{
+		{
+			name:          "namespace",
+			admits:        map[string]AdmitFunc{"ns1": deny},
+			namespace:     "ns1",
+			expectAllowed: false,
+		},
+		{
+			name:          "other namespace",
+			admits:        map[string]AdmitFunc{"ns1": deny},
+			namespace:     "ns2",
+			expectAllowed: true,
+		},
+		{
+			name:          "all namespaces",
+			admits:        map[string]AdmitFunc{metav1.NamespaceAll: deny},
+			namespace:     "ns2",
+			expectAllowed: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			resp := NamespacedAdmitFunc(test.admits)(&admission.AdmissionRequest{Namespace: test.namespace})
+			g.Expect(resp.Allowed).To(Equal(test.expectAllowed))
+		})
+	}
+}